@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
@@ -22,8 +23,11 @@ var awsOptions = vendors.AWSOptions{
 }
 
 var EC2Output = common.OutputOptions{
-	Output: envGet("AWS_EC2_OUTPUT", "").(string),
-	Query:  envGet("AWS_EC2_OUTPUT_QUERY", "").(string),
+	Output:   envGet("AWS_EC2_OUTPUT", "").(string),
+	Query:    envGet("AWS_EC2_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("AWS_EC2_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("AWS_EC2_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("AWS_EC2_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func NewAWSCommand() *cobra.Command {
@@ -51,6 +55,18 @@ func NewEC2Subcommand() *cobra.Command {
 	flags.BoolVar(&awsOptions.Insecure, "aws-insecure", awsOptions.Insecure, "Skip TLS verification")
 	flags.StringVar(&EC2Output.Output, "ec2-output", EC2Output.Output, "EC2 output file")
 	flags.StringVar(&EC2Output.Query, "ec2-output-query", EC2Output.Query, "EC2 output JSONata query")
+	flags.StringSliceVar(&EC2Output.QueryLib, "ec2-output-query-lib", EC2Output.QueryLib, "EC2 output JSONata query libraries")
+	flags.StringVar(&EC2Output.Format, "ec2-output-format", EC2Output.Format, "EC2 output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&EC2Output.Columns, "ec2-output-columns", EC2Output.Columns, "EC2 output columns")
+	configEnvFlags(flags, map[string]string{
+		"aws-accesskey":        "AWS_ACCESS_KEY",
+		"aws-secretkey":        "AWS_SECRET_KEY",
+		"ec2-output":           "AWS_EC2_OUTPUT",
+		"ec2-output-query":     "AWS_EC2_OUTPUT_QUERY",
+		"ec2-output-query-lib": "AWS_EC2_OUTPUT_QUERY_LIB",
+		"ec2-output-format":    "AWS_EC2_OUTPUT_FORMAT",
+		"ec2-output-columns":   "AWS_EC2_OUTPUT_COLUMNS",
+	})
 
 	getInstancesCmd := &cobra.Command{
 		Use:   "get-instances",
@@ -65,12 +81,12 @@ func NewEC2Subcommand() *cobra.Command {
 			}
 			instances, err := ec2.GetAllAWSEC2Instances()
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			b, err := json.Marshal(instances)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(EC2Output, "EC2", []interface{}{awsOptions}, b, stdout)
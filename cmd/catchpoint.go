@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/spf13/cobra"
 )
 
 var catchpointOptions = vendors.CatchpointOptions{
+	URL:      envGet("CATCHPOINT_URL", "").(string),
 	Timeout:  envGet("CATCHPOINT_TIMEOUT", 30).(int),
 	Insecure: envGet("CATCHPOINT_INSECURE", false).(bool),
 	APIToken: envGet("CATCHPOINT_API_TOKEN", "").(string),
@@ -31,8 +34,11 @@ var catchpointInstantTestWithNodeGroupOptions = vendors.CatchpointInstantTestWit
 }
 
 var catchpointOutput = common.OutputOptions{
-	Output: envGet("CATCHPOINT_OUTPUT", "").(string),
-	Query:  envGet("CATCHPOINT_OUTPUT_QUERY", "").(string),
+	Output:   envGet("CATCHPOINT_OUTPUT", "").(string),
+	Query:    envGet("CATCHPOINT_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("CATCHPOINT_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("CATCHPOINT_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("CATCHPOINT_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func catchpointNew(stdout *common.Stdout) *vendors.Catchpoint {
@@ -55,11 +61,26 @@ func NewCatchpointCommand() *cobra.Command {
 	}
 
 	flags := catchpointCmd.PersistentFlags()
+	flags.StringVar(&catchpointOptions.URL, "api-url", catchpointOptions.URL, "API URL, io.catchpoint.com by default")
 	flags.IntVar(&catchpointOptions.Timeout, "timeout", catchpointOptions.Timeout, "Timeout")
 	flags.BoolVar(&catchpointOptions.Insecure, "insecure", catchpointOptions.Insecure, "Insecure")
 	flags.StringVar(&catchpointOptions.APIToken, "api-token", catchpointOptions.APIToken, "API token")
 	flags.StringVar(&catchpointOutput.Output, "output", catchpointOutput.Output, "Output")
 	flags.StringVar(&catchpointOutput.Query, "query", catchpointOutput.Query, "Query")
+	flags.StringSliceVar(&catchpointOutput.QueryLib, "query-lib", catchpointOutput.QueryLib, "Query libraries")
+	flags.StringVar(&catchpointOutput.Format, "format", catchpointOutput.Format, "Output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&catchpointOutput.Columns, "columns", catchpointOutput.Columns, "Output columns")
+	configEnvFlags(flags, map[string]string{
+		"api-url":   "CATCHPOINT_URL",
+		"timeout":   "CATCHPOINT_TIMEOUT",
+		"insecure":  "CATCHPOINT_INSECURE",
+		"api-token": "CATCHPOINT_API_TOKEN",
+		"output":    "CATCHPOINT_OUTPUT",
+		"query":     "CATCHPOINT_OUTPUT_QUERY",
+		"query-lib": "CATCHPOINT_OUTPUT_QUERY_LIB",
+		"format":    "CATCHPOINT_OUTPUT_FORMAT",
+		"columns":   "CATCHPOINT_OUTPUT_COLUMNS",
+	})
 
 	instantTest := &cobra.Command{
 		Use:   "instant-test",
@@ -71,7 +92,7 @@ func NewCatchpointCommand() *cobra.Command {
 
 			bytes, err := catchpointNew(stdout).InstantTest(catchpointInstantTestOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(catchpointOutput, "Catchpoint", []interface{}{catchpointOptions, catchpointInstantTestOptions}, bytes, stdout)
@@ -84,6 +105,14 @@ func NewCatchpointCommand() *cobra.Command {
 	flags.IntVar(&catchpointInstantTestOptions.InstantTestType, "test-type-id", catchpointInstantTestOptions.InstantTestType, "Test type ID")
 	flags.IntVar(&catchpointInstantTestOptions.HTTPMethodType, "http-method-type-id", catchpointInstantTestOptions.HTTPMethodType, "HTTP method type ID")
 	flags.IntVar(&catchpointInstantTestOptions.MonitorType, "monitor-type-id", catchpointInstantTestOptions.MonitorType, "Monitor type ID")
+	configEnvFlags(flags, map[string]string{
+		"on-demand":           "CATCHPOINT_ON_DEMAND",
+		"url":                 "CATCHPOINT_URL",
+		"node-ids":            "CATCHPOINT_NODE_IDS",
+		"test-type-id":        "CATCHPOINT_TEST_TYPE_ID",
+		"http-method-type-id": "CATCHPOINT_HTTP_METHOD_TYPE_ID",
+		"monitor-type-id":     "CATCHPOINT_MONITOR_TYPE_ID",
+	})
 	catchpointCmd.AddCommand(instantTest)
 
 	instantTestWithNodeGroup := &cobra.Command{
@@ -96,7 +125,7 @@ func NewCatchpointCommand() *cobra.Command {
 
 			bytes, err := catchpointNew(stdout).InstantTestWithNodeGroup(catchpointInstantTestWithNodeGroupOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(catchpointOutput, "Catchpoint", []interface{}{catchpointOptions, catchpointInstantTestWithNodeGroupOptions}, bytes, stdout)
@@ -109,6 +138,14 @@ func NewCatchpointCommand() *cobra.Command {
 	flags.IntVar(&catchpointInstantTestWithNodeGroupOptions.InstantTestType, "test-type-id", catchpointInstantTestWithNodeGroupOptions.InstantTestType, "Test type ID")
 	flags.IntVar(&catchpointInstantTestWithNodeGroupOptions.HTTPMethodType, "http-method-type-id", catchpointInstantTestWithNodeGroupOptions.HTTPMethodType, "HTTP method type ID")
 	flags.IntVar(&catchpointInstantTestWithNodeGroupOptions.MonitorType, "monitor-type-id", catchpointInstantTestWithNodeGroupOptions.MonitorType, "Monitor type ID")
+	configEnvFlags(flags, map[string]string{
+		"on-demand":           "CATCHPOINT_ON_DEMAND",
+		"url":                 "CATCHPOINT_URL",
+		"node-group-id":       "CATCHPOINT_NODE_GROUP_ID",
+		"test-type-id":        "CATCHPOINT_TEST_TYPE_ID",
+		"http-method-type-id": "CATCHPOINT_HTTP_METHOD_TYPE_ID",
+		"monitor-type-id":     "CATCHPOINT_MONITOR_TYPE_ID",
+	})
 	catchpointCmd.AddCommand(instantTestWithNodeGroup)
 
 	return catchpointCmd
@@ -0,0 +1,442 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+	"github.com/spf13/cobra"
+)
+
+type CompletionOptions struct {
+	CacheDir string
+	CacheTTL int // seconds, zero disables cache
+	Timeout  int // seconds of vendor lookup
+}
+
+// completion values are looked up while shell waits, so they are read by env only and not by flags
+var completionOptions = CompletionOptions{
+	CacheDir: envGet("COMPLETION_CACHE_DIR", "").(string),
+	CacheTTL: envGet("COMPLETION_CACHE_TTL", 60).(int),
+	Timeout:  envGet("COMPLETION_TIMEOUT", 5).(int),
+}
+
+type completionFunc = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completionLookup returns values of lookup, they are "value" or "value\tdescription"
+type completionLookup func(ctx context.Context) ([]string, error)
+
+type completionCache struct {
+	Time   time.Time `json:"time"`
+	Values []string  `json:"values"`
+}
+
+// completionInit applies profile to options of command, so cache key is of its options, logs go to stderr not to break completion output
+func completionInit(cmd *cobra.Command) error {
+
+	stdoutOptions.Output = common.StdoutOutputStderr
+	stdout = common.NewStdout(stdoutOptions)
+
+	return configInit(cmd)
+}
+
+// completionLookupInit prepares http client and secrets as run of command does, it's done on cache miss only
+func completionLookupInit(cmd *cobra.Command) func() error {
+
+	return func() error {
+		if err := httpClientInit(); err != nil {
+			return err
+		}
+		return secretInit(cmd)
+	}
+}
+
+// completionKey is hash of name and options, so cache of other profile or URL is not used and keeps no credentials
+func completionKey(name string, options ...interface{}) string {
+
+	b, _ := json.Marshal(options)
+	h := sha256.Sum256(append([]byte(name+"\n"), b...))
+	return fmt.Sprintf("%s-%s", name, hex.EncodeToString(h[:8]))
+}
+
+func completionCacheDir() string {
+
+	if !utils.IsEmpty(completionOptions.CacheDir) {
+		return completionOptions.CacheDir
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "tools", "completion")
+}
+
+// completionCached returns values of cache until TTL expires, init and lookup are called on miss,
+// errors and empty values are not cached
+func completionCached(key string, init func() error, lookup completionLookup) ([]string, error) {
+
+	dir := completionCacheDir()
+	ttl := time.Duration(completionOptions.CacheTTL) * time.Second
+	file := filepath.Join(dir, key+".json")
+
+	if ttl > 0 && !utils.IsEmpty(dir) {
+		if b, err := os.ReadFile(file); err == nil {
+			var c completionCache
+			if json.Unmarshal(b, &c) == nil && time.Since(c.Time) < ttl {
+				return c.Values, nil
+			}
+		}
+	}
+
+	if err := init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(completionOptions.Timeout)*time.Second)
+	defer cancel()
+
+	values, err := lookup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if ttl > 0 && !utils.IsEmpty(dir) && len(values) > 0 {
+		b, err := json.Marshal(completionCache{Time: time.Now(), Values: values})
+		if err == nil && os.MkdirAll(dir, 0700) == nil {
+			if err := os.WriteFile(file, b, 0600); err != nil {
+				stdout.Debug("Completion cache %s: %v", file, err)
+			}
+		}
+	}
+	return values, nil
+}
+
+// completionValues returns completion of flag or argument, key is evaluated after options of command are set
+func completionValues(key func() string, lookup completionLookup) completionFunc {
+
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+
+		if err := completionInit(cmd); err != nil {
+			cobra.CompDebugln(err.Error(), true)
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		values, err := completionCached(key(), completionLookupInit(cmd), lookup)
+		if err != nil {
+			cobra.CompDebugln(common.RedactSensitive(err.Error()), true)
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		var r []string
+		for _, v := range values {
+			if strings.HasPrefix(v, toComplete) {
+				r = append(r, v)
+			}
+		}
+		return r, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func completionStatic(values ...string) completionFunc {
+
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completionItems returns values of field of items with description of other field, paths are like metadata.name and empty is root
+func completionItems(b []byte, items, value, description string) ([]string, error) {
+
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+
+	get := func(v interface{}, path string) interface{} {
+		for _, k := range common.RemoveEmptyStrings(strings.Split(path, ".")) {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			v = m[k]
+		}
+		return v
+	}
+
+	list, _ := get(data, items).([]interface{})
+	var r []string
+	for _, item := range list {
+
+		v := common.ValueToString(get(item, value))
+		if utils.IsEmpty(v) {
+			continue
+		}
+		if d := common.ValueToString(get(item, description)); !utils.IsEmpty(description) && !utils.IsEmpty(d) {
+			v = fmt.Sprintf("%s\t%s", v, d)
+		}
+		r = append(r, v)
+	}
+	sort.Strings(r)
+	return r, nil
+}
+
+// completionK8sResources completes names of resources, kind and namespace are usually set by flags
+func completionK8sResources(kind, namespace func() string) completionFunc {
+
+	return completionValues(func() string {
+		return completionKey("k8s-resources", k8sOptions, kind(), namespace())
+	}, func(ctx context.Context) ([]string, error) {
+
+		if utils.IsEmpty(kind()) {
+			return nil, nil
+		}
+		options := vendors.K8sResourceListOptions{K8sResourceOptions: vendors.K8sResourceOptions{Kind: kind(), Namespace: namespace()}}
+		b, err := vendors.NewK8s(k8sOptions, stdout).CustomResourceListContext(ctx, k8sOptions, options)
+		if err != nil {
+			return nil, err
+		}
+		return completionItems(b, "items", "metadata.name", "")
+	})
+}
+
+func completionGrafanaDashboards() completionFunc {
+
+	return completionValues(func() string {
+		return completionKey("grafana-dashboards", grafanaOptions)
+	}, func(ctx context.Context) ([]string, error) {
+
+		b, err := vendors.NewGrafana(grafanaOptions).CustomSearchDashboardsContext(ctx, grafanaOptions, vendors.GrafanaDashboardOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var r []string
+		var items []struct {
+			Type  string `json:"type"`
+			UID   string `json:"uid"`
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal(b, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.Type == "dash-db" && !utils.IsEmpty(item.UID) {
+				r = append(r, fmt.Sprintf("%s\t%s", item.UID, item.Title))
+			}
+		}
+		sort.Strings(r)
+		return r, nil
+	})
+}
+
+func completionGrafanaFolders() completionFunc {
+
+	return completionValues(func() string {
+		return completionKey("grafana-folders", grafanaOptions)
+	}, func(ctx context.Context) ([]string, error) {
+
+		b, err := vendors.NewGrafana(grafanaOptions).CustomGetFolderContext(ctx, grafanaOptions, vendors.GrafanaFolderOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return completionItems(b, "", "uid", "title")
+	})
+}
+
+func completionJiraProjects() completionFunc {
+
+	return completionValues(func() string {
+		return completionKey("jira-projects", jiraOptions)
+	}, func(ctx context.Context) ([]string, error) {
+
+		b, err := vendors.NewJira(jiraOptions).CustomGetProjectsContext(ctx, jiraOptions)
+		if err != nil {
+			return nil, err
+		}
+		return completionItems(b, "", "key", "name")
+	})
+}
+
+// completionJiraTransitions completes transitions of issue which is set by --jira-issue-id-or-key
+func completionJiraTransitions() completionFunc {
+
+	return completionValues(func() string {
+		return completionKey("jira-transitions", jiraOptions, JiraIssueOptions.IdOrKey)
+	}, func(ctx context.Context) ([]string, error) {
+
+		if utils.IsEmpty(JiraIssueOptions.IdOrKey) {
+			return nil, nil
+		}
+		b, err := vendors.NewJira(jiraOptions).GetIssueTransitionsContext(ctx, jiraOptions, JiraIssueOptions)
+		if err != nil {
+			return nil, err
+		}
+		return completionItems(b, "transitions", "id", "name")
+	})
+}
+
+func completionSlackChannels() completionFunc {
+
+	return completionValues(func() string {
+		return completionKey("slack-channels", slackOptions)
+	}, func(ctx context.Context) ([]string, error) {
+
+		options := vendors.SlackConversationsOptions{ExcludeArchived: true, PageOptions: vendors.PageOptions{All: true}}
+		b, err := vendors.NewSlack(slackOptions).CustomGetConversationsContext(ctx, slackOptions, options)
+		if err != nil {
+			return nil, err
+		}
+		return completionItems(b, "channels", "name", "id")
+	})
+}
+
+func completionVCenterClusters() completionFunc {
+
+	return completionValues(func() string {
+		return completionKey("vcenter-clusters", vcenterOptions)
+	}, func(ctx context.Context) ([]string, error) {
+
+		b, err := vendors.NewVCenter(vcenterOptions).CustomGetClustersContext(ctx, vcenterOptions)
+		if err != nil {
+			return nil, err
+		}
+		return completionItems(b, "value", "cluster", "name")
+	})
+}
+
+// completionVCenterHosts completes hosts of cluster which is set by flag
+func completionVCenterHosts(cluster func() string) completionFunc {
+
+	return completionValues(func() string {
+		return completionKey("vcenter-hosts", vcenterOptions, cluster())
+	}, func(ctx context.Context) ([]string, error) {
+
+		b, err := vendors.NewVCenter(vcenterOptions).CustomGetHostsContext(ctx, vcenterOptions, vendors.VCenterHostOptions{Cluster: cluster()})
+		if err != nil {
+			return nil, err
+		}
+		return completionItems(b, "value", "host", "name")
+	})
+}
+
+// completionProfiles completes profiles of config file, both full names and their environments like prod
+func completionProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+
+	if utils.IsEmpty(configOptions.File) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	config, err := common.LoadConfig(configOptions.File)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make(map[string]bool)
+	for name := range config.Profiles {
+		names[name] = true
+		if _, env, ok := strings.Cut(name, "."); ok {
+			names[env] = true
+		}
+	}
+
+	// selector is comma separated, so the last profile is completed
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, toComplete = toComplete[:i+1], toComplete[i+1:]
+	}
+	var r []string
+	for name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			r = append(r, prefix+name)
+		}
+	}
+	sort.Strings(r)
+	return r, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionFlags are completions of flags by name, flags with the same name of different commands share them
+func completionFlags() map[string]completionFunc {
+
+	grafanaFolders := completionGrafanaFolders()
+	slackChannels := completionSlackChannels()
+	vcenterClusters := completionVCenterClusters()
+
+	return map[string]completionFunc{
+		"profile":                            completionProfiles,
+		"k8s-resource-kind":                  completionStatic(vendors.K8sResourcePods, vendors.K8sResourceDeployments, vendors.K8sResourceReplicaSets, vendors.K8sResourceStatefulSets, vendors.K8sResourceDaemonSets),
+		"k8s-resource-namespace":             completionK8sResources(func() string { return "namespaces" }, func() string { return "" }),
+		"k8s-resource-name":                  completionK8sResources(func() string { return k8sResourceOptions.Kind }, func() string { return k8sResourceOptions.Namespace }),
+		"grafana-dashboard-uid":              completionGrafanaDashboards(),
+		"grafana-dashboard-folder-uid":       grafanaFolders,
+		"grafana-folder-uid":                 grafanaFolders,
+		"grafana-library-element-folder-uid": grafanaFolders,
+		"jira-issue-project-key":             completionJiraProjects(),
+		"jira-issue-status":                  completionJiraTransitions(),
+		"slack-channel":                      slackChannels,
+		"vcenter-host-cluster":               vcenterClusters,
+		"vcenter-vm-cluster":                 vcenterClusters,
+		"vcenter-vm-host":                    completionVCenterHosts(func() string { return vcenterVMOptions.Cluster }),
+	}
+}
+
+// completionRegister registers completions of flags of all commands
+func completionRegister(cmd *cobra.Command, flags map[string]completionFunc) {
+
+	register := func(name string) {
+		if fn, ok := flags[name]; ok {
+			// flag which is inherited from parent is registered already
+			_ = cmd.RegisterFlagCompletionFunc(name, fn)
+		}
+	}
+	for name := range flags {
+		if cmd.LocalNonPersistentFlags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {
+			register(name)
+		}
+	}
+	for _, c := range cmd.Commands() {
+		completionRegister(c, flags)
+	}
+}
+
+func NewCompletionCommand() *cobra.Command {
+
+	return &cobra.Command{
+		Use:   "completion bash|zsh|fish",
+		Short: "Generate shell completion script",
+		Long: `Generate shell completion script, resources like k8s namespaces, Grafana dashboards, Jira projects,
+Slack channels and vCenter clusters are completed from vendors with options of env and config profile.
+
+Lookups are cached for TOOLS_COMPLETION_CACHE_TTL seconds (60 by default, 0 disables cache)
+in TOOLS_COMPLETION_CACHE_DIR (user cache directory by default).
+
+  source <(tools completion bash)
+  tools completion zsh > "${fpath[1]}/_tools"
+  tools completion fish > ~/.config/fish/completions/tools.fish`,
+		Args:                  cobra.ExactValidArgs(1),
+		ValidArgs:             []string{"bash", "zsh", "fish"},
+		DisableFlagsInUseLine: true,
+		// script is printed only, so options and tracing are not needed
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			}
+			return fmt.Errorf("unknown shell %s", args[0])
+		},
+	}
+}
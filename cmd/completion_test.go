@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionCached(t *testing.T) {
+
+	options := completionOptions
+	t.Cleanup(func() {
+		completionOptions = options
+	})
+	completionOptions.CacheDir = t.TempDir()
+	completionOptions.CacheTTL = 60
+
+	inits, lookups := 0, 0
+	init := func() error {
+		inits++
+		return nil
+	}
+	lookup := func(ctx context.Context) ([]string, error) {
+		lookups++
+		return []string{"a", "b"}, nil
+	}
+
+	// secrets and http client are initialized on miss only
+	key := completionKey("test", "url")
+	for i := 0; i < 2; i++ {
+		values, err := completionCached(key, init, lookup)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, values)
+	}
+	assert.Equal(t, 1, inits)
+	assert.Equal(t, 1, lookups)
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type ConfigOptions struct {
+	File    string
+	Profile string
+}
+
+type configItem struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+const (
+	configSourceFlag    = "flag"
+	configSourceEnv     = "env"
+	configSourceProfile = "profile"
+	configSourceDefault = "default"
+)
+
+var configOptions = ConfigOptions{
+	File:    envGet("CONFIG", "").(string),
+	Profile: envGet("PROFILE", "").(string),
+}
+
+var configOutput = common.OutputOptions{
+	Output:   envGet("CONFIG_OUTPUT", "").(string),
+	Query:    envGet("CONFIG_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("CONFIG_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("CONFIG_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("CONFIG_OUTPUT_COLUMNS", "").(string), ","),
+}
+
+// envKeys keeps environment variables which were set when options were read
+var envKeys = make(map[string]bool)
+
+// envRead keeps all environment variables which options were read from
+var envRead = make(map[string]bool)
+
+// configEnvAnnotation keeps env variable of flag, which name differs from variable, no values means flag has no env
+const configEnvAnnotation = "tools_env"
+
+func configAddFlags(flags *pflag.FlagSet) {
+
+	flags.StringVar(&configOptions.File, "config", configOptions.File, "Config file (yaml or json) with profiles")
+	flags.StringVar(&configOptions.Profile, "profile", configOptions.Profile, "Config profiles: prod or grafana.prod,jira.cloud")
+}
+
+func configEnvName(name string) string {
+	return fmt.Sprintf("%s_%s", APPNAME, strings.ToUpper(strings.ReplaceAll(name, "-", "_")))
+}
+
+// configEnvFlags records keys passed to envGet for flags, which names don't follow their keys,
+// empty key marks flag without env variable
+func configEnvFlags(flags *pflag.FlagSet, keys map[string]string) {
+
+	for name, key := range keys {
+		values := []string{}
+		if !utils.IsEmpty(key) {
+			values = append(values, configEnvName(key))
+		}
+		flags.SetAnnotation(name, configEnvAnnotation, values)
+	}
+}
+
+// configEnvKey returns env variable of flag, empty if flag has no env
+func configEnvKey(f *pflag.Flag) string {
+
+	if values, ok := f.Annotations[configEnvAnnotation]; ok {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+	return configEnvName(f.Name)
+}
+
+func configSource(f *pflag.Flag, values map[string]string) string {
+
+	switch {
+	case f.Changed:
+		return configSourceFlag
+	case envKeys[configEnvKey(f)]:
+		return configSourceEnv
+	}
+	if _, ok := values[f.Name]; ok {
+		return configSourceProfile
+	}
+	return configSourceDefault
+}
+
+func configLoad() (map[string]string, error) {
+
+	if utils.IsEmpty(configOptions.File) {
+		return map[string]string{}, nil
+	}
+
+	config, err := common.LoadConfig(configOptions.File)
+	if err != nil {
+		return nil, err
+	}
+	return config.Values(configOptions.Profile), nil
+}
+
+// configApply sets profile values to flags which were set neither on command line nor by env
+func configApply(flags *pflag.FlagSet, values map[string]string) error {
+
+	var err error
+	flags.VisitAll(func(f *pflag.Flag) {
+
+		if err != nil || configSource(f, values) != configSourceProfile {
+			return
+		}
+		if e := f.Value.Set(values[f.Name]); e != nil {
+			err = fmt.Errorf("config %s: %v", f.Name, e)
+		}
+	})
+	return err
+}
+
+func configInit(cmd *cobra.Command) error {
+
+	values, err := configLoad()
+	if err != nil {
+		return err
+	}
+	return configApply(cmd.Flags(), values)
+}
+
+func configFlags(cmd *cobra.Command, flags map[string]*pflag.Flag) {
+
+	add := func(f *pflag.Flag) {
+		if _, ok := flags[f.Name]; !ok {
+			flags[f.Name] = f
+		}
+	}
+	cmd.PersistentFlags().VisitAll(add)
+	cmd.LocalFlags().VisitAll(add)
+
+	for _, c := range cmd.Commands() {
+		configFlags(c, flags)
+	}
+}
+
+// configInitAll applies profile values to flags of all commands and resolves their secrets,
+// so servers calling any vendor use options of its profile
+func configInitAll(root *cobra.Command) error {
+
+	values, err := configLoad()
+	if err != nil {
+		return err
+	}
+
+	flags := make(map[string]*pflag.Flag)
+	configFlags(root, flags)
+
+	all := pflag.NewFlagSet(root.Name(), pflag.ContinueOnError)
+	for _, f := range flags {
+		all.AddFlag(f)
+	}
+	if err := configApply(all, values); err != nil {
+		return err
+	}
+	return secretResolveFlags(all, false)
+}
+
+func configShow(root *cobra.Command) ([]byte, error) {
+
+	values, err := configLoad()
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make(map[string]*pflag.Flag)
+	configFlags(root, flags)
+
+	var names []string
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := []configItem{}
+	for _, name := range names {
+
+		f := flags[name]
+		source := configSource(f, values)
+		if source == configSourceProfile {
+			if err := f.Value.Set(values[name]); err != nil {
+				return nil, fmt.Errorf("config %s: %v", name, err)
+			}
+		}
+
+		value := f.Value.String()
+		if utils.IsEmpty(value) || value == "[]" || value == "map[]" {
+			continue
+		}
+		if common.IsSensitiveKey(name) {
+			value = common.SecretMask
+		}
+		items = append(items, configItem{
+			Name:   name,
+			Value:  common.RedactSensitive(value),
+			Source: source,
+		})
+	}
+	return common.JsonMarshal(items)
+}
+
+func NewConfigCommand() *cobra.Command {
+
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Config tools",
+	}
+
+	flags := configCmd.PersistentFlags()
+	flags.StringVar(&configOutput.Output, "config-output", configOutput.Output, "Config output")
+	flags.StringVar(&configOutput.Query, "config-output-query", configOutput.Query, "Config output query")
+	flags.StringSliceVar(&configOutput.QueryLib, "config-output-query-lib", configOutput.QueryLib, "Config output query libraries")
+	flags.StringVar(&configOutput.Format, "config-output-format", configOutput.Format, "Config output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&configOutput.Columns, "config-output-columns", configOutput.Columns, "Config output columns")
+
+	configCmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Show effective configuration",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			stdout.Debug("Config showing...")
+			bytes, err := configShow(cmd.Root())
+			if err != nil {
+				return err
+			}
+			common.OutputJson(configOutput, "Config", []interface{}{configOptions}, bytes, stdout)
+			return nil
+		},
+	})
+	return configCmd
+}
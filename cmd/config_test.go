@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSource(t *testing.T) {
+
+	// env key of flag doesn't follow flag name
+	t.Setenv("TOOLS_AWS_ACCESS_KEY", "env-key")
+	t.Cleanup(func() {
+		delete(envKeys, "TOOLS_AWS_ACCESS_KEY")
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		values   map[string]string
+		source   string
+		expected string
+	}{
+		{
+			name:     "Env beats profile",
+			values:   map[string]string{"aws-accesskey": "profile-key"},
+			source:   configSourceEnv,
+			expected: "env-key",
+		},
+		{
+			name:     "Flag beats env",
+			args:     []string{"--aws-accesskey=flag-key"},
+			values:   map[string]string{"aws-accesskey": "profile-key"},
+			source:   configSourceFlag,
+			expected: "flag-key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var accessKey string
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.StringVar(&accessKey, "aws-accesskey", envGet("AWS_ACCESS_KEY", "").(string), "AWS access key")
+			configEnvFlags(flags, map[string]string{"aws-accesskey": "AWS_ACCESS_KEY"})
+			require.NoError(t, flags.Parse(tt.args))
+
+			f := flags.Lookup("aws-accesskey")
+			assert.Equal(t, "TOOLS_AWS_ACCESS_KEY", configEnvKey(f))
+			assert.Equal(t, tt.source, configSource(f, tt.values))
+			require.NoError(t, configApply(flags, tt.values))
+			assert.Equal(t, tt.expected, accessKey)
+		})
+	}
+
+	t.Run("Profile beats default", func(t *testing.T) {
+
+		var limit int
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.IntVar(&limit, "limit", 0, "Limit")
+		configEnvFlags(flags, map[string]string{"limit": ""})
+		t.Setenv("TOOLS_LIMIT", "5")
+		envKeys["TOOLS_LIMIT"] = true
+		t.Cleanup(func() {
+			delete(envKeys, "TOOLS_LIMIT")
+		})
+
+		values := map[string]string{"limit": "10"}
+		f := flags.Lookup("limit")
+		assert.Empty(t, configEnvKey(f))
+		assert.Equal(t, configSourceProfile, configSource(f, values))
+		require.NoError(t, configApply(flags, values))
+		assert.Equal(t, 10, limit)
+	})
+}
+
+// every flag must resolve to env variable which its option was read from
+func TestConfigEnvKeys(t *testing.T) {
+
+	var visit func(cmd *cobra.Command)
+	visit = func(cmd *cobra.Command) {
+
+		check := func(f *pflag.Flag) {
+			key := configEnvKey(f)
+			if key == "" {
+				return
+			}
+			assert.True(t, envRead[key], "%s --%s: %s is not read by tools", cmd.CommandPath(), f.Name, key)
+		}
+		cmd.PersistentFlags().VisitAll(check)
+		cmd.LocalFlags().VisitAll(check)
+		for _, c := range cmd.Commands() {
+			visit(c)
+		}
+	}
+	visit(newRootCommand())
+}
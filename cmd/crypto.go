@@ -57,7 +57,7 @@ func NewCryptoCommand() *cobra.Command {
 
 			bytes, err := cryptoNew(stdout).CustomRCAGenerateKey(cryptoRCAKeyOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(cryptoOutput, "Crypto", []interface{}{cryptoRCAKeyOptions}, bytes, stdout)
@@ -89,7 +89,7 @@ func NewCryptoCommand() *cobra.Command {
 
 			bytes, err := cryptoNew(stdout).CustomRCAEncrypt(cryptoRCAEncryptOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(cryptoOutput, "Crypto", []interface{}{cryptoRCAEncryptOptions}, bytes, stdout)
@@ -122,7 +122,7 @@ func NewCryptoCommand() *cobra.Command {
 
 			bytes, err := cryptoNew(stdout).CustomRCADecrypt(cryptoRCADecryptOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(cryptoOutput, "Crypto", []interface{}{cryptoRCADecryptOptions}, bytes, stdout)
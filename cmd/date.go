@@ -51,7 +51,7 @@ func NewDateCommand() *cobra.Command {
 
 			time, err := dateCalculate(dateOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			ts := time.Format(dateOptions.Format)
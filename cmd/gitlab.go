@@ -16,8 +16,11 @@ var gitlabOptions = vendors.GitlabOptions{
 }
 
 var gitlabOutput = common.OutputOptions{
-	Output: envGet("GITLAB_OUTPUT", "").(string),
-	Query:  envGet("GITLAB_OUTPUT_QUERY", "").(string),
+	Output:   envGet("GITLAB_OUTPUT", "").(string),
+	Query:    envGet("GITLAB_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("GITLAB_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("GITLAB_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("GITLAB_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 var pipelineOptions = vendors.GitlabPipelineOptions{
@@ -28,7 +31,15 @@ var pipelineOptions = vendors.GitlabPipelineOptions{
 	Ref:       envGet("GITLAB_PIPELINE_REF", "").(string),
 	OrderBy:   envGet("GITLAB_PIPELINE_OREDR_BY", "updated_at").(string),
 	Sort:      envGet("GITLAB_PIPELINE_SORT", "desc").(string),
-	Limit:     envGet("GITLAB_PIPELINE_LIMIT", 1).(int),
+	PageOptions: vendors.PageOptions{
+		Limit: envGet("GITLAB_PIPELINE_LIMIT", 1).(int),
+	},
+}
+
+// pipelineListOptions are page options of pipeline list, limit of pipelines which are searched for variables is 1 by default
+var pipelineListOptions = vendors.PageOptions{
+	Limit: envGet("GITLAB_PIPELINE_LIST_LIMIT", 0).(int),
+	All:   envGet("GITLAB_PIPELINE_LIST_ALL", false).(bool),
 }
 
 var pipelineGetVariablesOptions = vendors.GitlabGetPipelineVariablesOptions{
@@ -56,6 +67,9 @@ func NewGitlabCommand() *cobra.Command {
 	flags.StringVar(&gitlabOptions.Token, "gitlab-token", gitlabOptions.Token, "Gitlab Token")
 	flags.StringVar(&gitlabOutput.Output, "gitlab-output", gitlabOutput.Output, "Gitlab Output")
 	flags.StringVar(&gitlabOutput.Query, "gitlab-output-query", gitlabOutput.Query, "Gitlab Output Query")
+	flags.StringSliceVar(&gitlabOutput.QueryLib, "gitlab-output-query-lib", gitlabOutput.QueryLib, "Gitlab Output Query libraries")
+	flags.StringVar(&gitlabOutput.Format, "gitlab-output-format", gitlabOutput.Format, "Gitlab Output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&gitlabOutput.Columns, "gitlab-output-columns", gitlabOutput.Columns, "Gitlab Output columns")
 
 	pipelineCmd := &cobra.Command{
 		Use:   "pipeline",
@@ -70,8 +84,41 @@ func NewGitlabCommand() *cobra.Command {
 	flags.StringVar(&pipelineOptions.OrderBy, "gitlab-pipeline-order-by", pipelineOptions.OrderBy, "Gitlab pipeline order by")
 	flags.StringVar(&pipelineOptions.Sort, "gitlab-pipeline-sort", pipelineOptions.Sort, "Gitlab pipeline sort")
 	flags.IntVar(&pipelineOptions.Limit, "gitlab-pipeline-limit", pipelineOptions.Limit, "Gitlab pipeline limit")
+	configEnvFlags(flags, map[string]string{
+		"gitlab-pipeline-order-by": "GITLAB_PIPELINE_OREDR_BY",
+	})
 	gitlabCmd.AddCommand(pipelineCmd)
 
+	pipelineListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Get gitlab pipelines",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Getting pipelines…")
+
+			options := pipelineOptions
+			options.PageOptions = pipelineListOptions
+			common.Debug("Gitlab", options, stdout)
+
+			streamed := pageStream(gitlabOutput, &options.PageOptions)
+			bytes, err := gitlabNew(stdout).GetPipelines(options)
+			if err != nil {
+				commandError(err)
+				return
+			}
+			if streamed {
+				return
+			}
+			common.OutputJson(gitlabOutput, "Gitlab", []interface{}{gitlabOptions, options}, bytes, stdout)
+		},
+	}
+	flags = pipelineListCmd.Flags()
+	pageAddFlags(flags, &pipelineListOptions)
+	configEnvFlags(flags, map[string]string{
+		"limit": "GITLAB_PIPELINE_LIST_LIMIT",
+		"all":   "GITLAB_PIPELINE_LIST_ALL",
+	})
+	pipelineCmd.AddCommand(pipelineListCmd)
+
 	pipelineCmd.AddCommand(&cobra.Command{
 		Use:   "last",
 		Short: "Get last successful gitlab pipeline",
@@ -81,7 +128,7 @@ func NewGitlabCommand() *cobra.Command {
 
 			bytes, err := gitlabNew(stdout).GetLastPipeline(pipelineOptions.ProjectID, pipelineOptions.Ref)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(gitlabOutput, "Gitlab", []interface{}{gitlabOptions, pipelineOptions}, bytes, stdout)
@@ -97,7 +144,7 @@ func NewGitlabCommand() *cobra.Command {
 
 			bytes, err := gitlabNew(stdout).GetLastPipelineVariables(pipelineOptions.ProjectID, pipelineOptions.Ref)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(gitlabOutput, "Gitlab", []interface{}{gitlabOptions, pipelineOptions}, bytes, stdout)
@@ -115,7 +162,7 @@ func NewGitlabCommand() *cobra.Command {
 
 			bytes, err := gitlabNew(stdout).GetPipelineVariables(pipelineOptions, pipelineGetVariablesOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(gitlabOutput, "Gitlab", []interface{}{gitlabOptions, pipelineOptions, pipelineGetVariablesOptions}, bytes, stdout)
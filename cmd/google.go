@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
@@ -60,8 +62,11 @@ type GoogleCalendarInsertEventOptions struct {
 }
 
 var googleOutput = common.OutputOptions{
-	Output: envGet("GOOGLE_OUTPUT", "").(string),
-	Query:  envGet("GOOGLE_OUTPUT_QUERY", "").(string),
+	Output:   envGet("GOOGLE_OUTPUT", "").(string),
+	Query:    envGet("GOOGLE_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("GOOGLE_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("GOOGLE_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("GOOGLE_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func googleNew(stdout *common.Stdout) *vendors.Google {
@@ -88,6 +93,9 @@ func NewGoogleCommand() *cobra.Command {
 	flags.StringVar(&googleOptions.ImpersonateEmail, "google-impersonate-email", googleOptions.ImpersonateEmail, "Google impersonate email for domain-wide delegation")
 	flags.StringVar(&googleOutput.Output, "google-output", googleOutput.Output, "Google output")
 	flags.StringVar(&googleOutput.Query, "google-output-query", googleOutput.Query, "Google output query")
+	flags.StringSliceVar(&googleOutput.QueryLib, "google-output-query-lib", googleOutput.QueryLib, "Google output query libraries")
+	flags.StringVar(&googleOutput.Format, "google-output-format", googleOutput.Format, "Google output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&googleOutput.Columns, "google-output-columns", googleOutput.Columns, "Google output columns")
 
 	// Calendar commands
 	calendarCmd := &cobra.Command{
@@ -117,7 +125,7 @@ func NewGoogleCommand() *cobra.Command {
 
 			meetResponse, err := googleNew(stdout).CreateMeetSpace(googleMeetOptions)
 			if err != nil {
-				stdout.Error("CreateMeetSpace error: %s", err)
+				commandError(fmt.Errorf("CreateMeetSpace error: %w", err))
 				return
 			}
 
@@ -144,7 +152,7 @@ func NewGoogleCommand() *cobra.Command {
 
 			bytes, err := googleNew(stdout).CalendarGetEvents(googleCalendarOptions, googleCalendarGetEventsOptions)
 			if err != nil {
-				stdout.Error("CalendarGetEvents error: %s", err)
+				commandError(fmt.Errorf("CalendarGetEvents error: %w", err))
 			}
 			common.OutputJson(googleOutput, "Google", []interface{}{googleOptions, googleCalendarOptions, googleCalendarGetEventsOptions}, bytes, stdout)
 		},
@@ -169,7 +177,7 @@ func NewGoogleCommand() *cobra.Command {
 
 			bytes, err := googleNew(stdout).CalendarInsertEvent(googleCalendarOptions, googleCalendarInsertEventOptions)
 			if err != nil {
-				stdout.Error("CalendarInsertEvent error: %s", err)
+				commandError(fmt.Errorf("CalendarInsertEvent error: %w", err))
 			}
 			common.OutputJson(googleOutput, "Google", []interface{}{googleOptions, googleCalendarOptions, googleCalendarInsertEventOptions}, bytes, stdout)
 		},
@@ -199,7 +207,7 @@ func NewGoogleCommand() *cobra.Command {
 
 			bytes, err := googleNew(stdout).CalendarDeleteEvent(googleCalendarOptions, googleCalendarDeleteEventOptions)
 			if err != nil {
-				stdout.Error("CalendarDeleteEvent error: %s", err)
+				commandError(fmt.Errorf("CalendarDeleteEvent error: %w", err))
 			}
 			common.OutputJson(googleOutput, "Google", []interface{}{googleOptions, googleCalendarOptions, googleCalendarDeleteEventOptions}, bytes, stdout)
 		},
@@ -219,7 +227,7 @@ func NewGoogleCommand() *cobra.Command {
 
 			bytes, err := googleNew(stdout).CalendarDeleteEvents(googleCalendarOptions, googleCalendarGetEventsOptions)
 			if err != nil {
-				stdout.Error("CalendarDeleteEvent error: %s", err)
+				commandError(fmt.Errorf("CalendarDeleteEvent error: %w", err))
 			}
 			common.OutputJson(googleOutput, "Google", []interface{}{googleOptions, googleCalendarOptions, googleCalendarGetEventsOptions}, bytes, stdout)
 		},
@@ -104,8 +104,11 @@ var grafanaCreateAnnotationOptions = vendors.GrafanaCreateAnnotationOptions{
 }
 
 var grafanaOutput = common.OutputOptions{
-	Output: envGet("GRAFANA_OUTPUT", "").(string),
-	Query:  envGet("GRAFANA_OUTPUT_QUERY", "").(string),
+	Output:   envGet("GRAFANA_OUTPUT", "").(string),
+	Query:    envGet("GRAFANA_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("GRAFANA_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("GRAFANA_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("GRAFANA_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func grafanaNew(stdout *common.Stdout) *vendors.Grafana {
@@ -131,6 +134,9 @@ func NewGrafanaCommand() *cobra.Command {
 
 	flags.StringVar(&grafanaOutput.Output, "grafana-output", grafanaOutput.Output, "Grafana output")
 	flags.StringVar(&grafanaOutput.Query, "grafana-output-query", grafanaOutput.Query, "Grafana output query")
+	flags.StringSliceVar(&grafanaOutput.QueryLib, "grafana-output-query-lib", grafanaOutput.QueryLib, "Grafana output query libraries")
+	flags.StringVar(&grafanaOutput.Format, "grafana-output-format", grafanaOutput.Format, "Grafana output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&grafanaOutput.Columns, "grafana-output-columns", grafanaOutput.Columns, "Grafana output columns")
 
 	getDashboardCmd := cobra.Command{
 		Use:   "get-dashboards",
@@ -140,7 +146,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).GetDashboards(grafanaDashboardOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions}, bytes, stdout)
@@ -158,7 +164,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).GetLibraryElement(grafanaLibraryElementOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions}, bytes, stdout)
@@ -176,7 +182,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).GetFolder(grafanaFolderOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions}, bytes, stdout)
@@ -196,7 +202,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).SearchDashboards(grafanaDashboardOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaDashboardOptions}, bytes, stdout)
@@ -217,7 +223,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).SearchLibraryElements(grafanaLibraryElementOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaDashboardOptions}, bytes, stdout)
@@ -238,7 +244,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).CopyDashboard(grafanaDashboardOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaDashboardOptions}, bytes, stdout)
@@ -268,7 +274,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).CopyLibraryElement(grafanaLibraryElementOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaLibraryElementOptions}, bytes, stdout)
@@ -287,6 +293,9 @@ func NewGrafanaCommand() *cobra.Command {
 	flags.StringVar(&grafanaLibraryElementOptions.Cloned.UID, "grafana-library-element-cloned-uid", grafanaLibraryElementOptions.Cloned.UID, "Grafana Dashboard cloned UID")
 	flags.StringVar(&grafanaLibraryElementOptions.Cloned.FolderUID, "grafana-library-element-cloned-folder-uid", grafanaLibraryElementOptions.Cloned.FolderUID, "Grafana library element cloned folder uid")
 	flags.IntVar(&grafanaLibraryElementOptions.Cloned.FolderID, "grafana-library-element-cloned-folder-id", grafanaLibraryElementOptions.Cloned.FolderID, "Grafana library element folder id")
+	configEnvFlags(flags, map[string]string{
+		"grafana-library-element-name": "GRAFANA_LIBRARY_ELEMENT_TITLE",
+	})
 
 	grafanaCmd.AddCommand(&copyLibraryElementCmd)
 
@@ -304,7 +313,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).CreateDashboard(grafanaDashboardOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaDashboardOptions}, bytes, stdout)
@@ -342,7 +351,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).DeleteDashboards(grafanaDashboardOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaDashboardOptions}, bytes, stdout)
@@ -361,7 +370,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).RenderImage(grafanaDashboardOptions, grafanaRenderImageOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputRaw(grafanaOutput.Output, bytes, stdout)
@@ -387,7 +396,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).GetAnnotations(grafanaDashboardOptions, grafanaGetAnnotationsOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaDashboardOptions, grafanaGetAnnotationsOptions}, bytes, stdout)
@@ -404,6 +413,11 @@ func NewGrafanaCommand() *cobra.Command {
 	flags.IntVar(&grafanaGetAnnotationsOptions.DashboardID, "grafana-annotation-dashboard", grafanaGetAnnotationsOptions.DashboardID, "Grafana annotations dashboard")
 	flags.IntVar(&grafanaGetAnnotationsOptions.PanelID, "grafana-annotation-panel", grafanaGetAnnotationsOptions.PanelID, "Grafana annotations panel")
 	flags.BoolVar(&grafanaGetAnnotationsOptions.MatchAny, "grafana-annotation-match-any", grafanaGetAnnotationsOptions.MatchAny, "Grafana annotations match any tag")
+	configEnvFlags(flags, map[string]string{
+		"grafana-annotation-alert":     "GRAFANA_ANNOTATION_ALERT_ID",
+		"grafana-annotation-dashboard": "GRAFANA_ANNOTATION_DASHBOARD_ID",
+		"grafana-annotation-panel":     "GRAFANA_ANNOTATION_PANEL_ID",
+	})
 	grafanaCmd.AddCommand(&getAnnotationsCmd)
 
 	createAnnotationCmd := cobra.Command{
@@ -420,7 +434,7 @@ func NewGrafanaCommand() *cobra.Command {
 
 			bytes, err := grafanaNew(stdout).CreateAnnotation(grafanaCreateAnnotationOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaCreateAnnotationOptions}, bytes, stdout)
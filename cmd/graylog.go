@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/devopsext/utils"
@@ -24,8 +26,11 @@ var graylogOptions = vendors.GraylogOptions{
 }
 
 var graylogOutput = common.OutputOptions{
-	Output: envGet("GRAYLOG_OUTPUT", "").(string),
-	Query:  envGet("GRAYLOG_OUTPUT_QUERY", "").(string),
+	Output:   envGet("GRAYLOG_OUTPUT", "").(string),
+	Query:    envGet("GRAYLOG_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("GRAYLOG_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("GRAYLOG_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("GRAYLOG_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func graylogNew(stdout *common.Stdout) *vendors.Graylog {
@@ -64,6 +69,9 @@ func NewGraylogCommand() *cobra.Command {
 	flags.StringVar(&graylogOptions.To, "graylog-to", graylogOptions.To, "Graylog to time")
 	flags.StringVar(&graylogOutput.Output, "graylog-output", graylogOutput.Output, "Graylog output")
 	flags.StringVar(&graylogOutput.Query, "graylog-output-query", graylogOutput.Query, "Graylog output query")
+	flags.StringSliceVar(&graylogOutput.QueryLib, "graylog-output-query-lib", graylogOutput.QueryLib, "Graylog output query libraries")
+	flags.StringVar(&graylogOutput.Format, "graylog-output-format", graylogOutput.Format, "Graylog output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&graylogOutput.Columns, "graylog-output-columns", graylogOutput.Columns, "Graylog output columns")
 
 	graylogCmd.AddCommand(&cobra.Command{
 		Use:   "get-logs",
@@ -73,7 +81,7 @@ func NewGraylogCommand() *cobra.Command {
 			stdout.Debug("Graylog getting logs...")
 			bytes, err := graylogNew(stdout).GetLogs()
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(graylogOutput, "Graylog", []interface{}{graylogOptions}, bytes, stdout)
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
+	"github.com/spf13/pflag"
+)
+
+var httpClientOptions = common.HttpClientOptions{
+	Proxy:     envGet("HTTP_CLIENT_PROXY", "").(string),
+	NoProxy:   envGet("HTTP_CLIENT_NO_PROXY", "").(string),
+	CA:        envGet("HTTP_CLIENT_CA", "").(string),
+	UserAgent: envGet("HTTP_CLIENT_USER_AGENT", "").(string),
+	Retry: common.RetryOptions{
+		Attempts: envGet("HTTP_CLIENT_RETRIES", 1).(int),
+		Jitter:   envGet("HTTP_CLIENT_RETRY_JITTER", 0.1).(float64),
+	},
+}
+
+var httpClientRetryDelay = envGet("HTTP_CLIENT_RETRY_DELAY", common.RetryDefaultDelay.String()).(string)
+var httpClientRetryMaxDelay = envGet("HTTP_CLIENT_RETRY_MAX_DELAY", common.RetryDefaultMaxDelay.String()).(string)
+var httpClientRecord = envGet("HTTP_CLIENT_RECORD", "").(string)
+var httpClientReplay = envGet("HTTP_CLIENT_REPLAY", "").(string)
+
+func httpClientAddFlags(flags *pflag.FlagSet) {
+
+	flags.StringVar(&httpClientOptions.Proxy, "http-client-proxy", httpClientOptions.Proxy, "Http client proxy, HTTP_PROXY and HTTPS_PROXY by default")
+	flags.StringVar(&httpClientOptions.NoProxy, "http-client-no-proxy", httpClientOptions.NoProxy, "Http client comma separated hosts without proxy, NO_PROXY by default")
+	flags.StringVar(&httpClientOptions.CA, "http-client-ca", httpClientOptions.CA, "Http client CA bundle file or content, added to system CAs")
+	flags.StringVar(&httpClientOptions.UserAgent, "http-client-user-agent", httpClientOptions.UserAgent, "Http client user agent, vendor name is appended")
+	flags.IntVar(&httpClientOptions.Retry.Attempts, "http-client-retries", httpClientOptions.Retry.Attempts, "Http client attempts on 429 and 5xx, POST and PATCH are retried on 429 and 503 with Retry-After only, 1 disables retries")
+	flags.StringVar(&httpClientRetryDelay, "http-client-retry-delay", httpClientRetryDelay, "Http client initial retry delay, Retry-After has priority")
+	flags.StringVar(&httpClientRetryMaxDelay, "http-client-retry-max-delay", httpClientRetryMaxDelay, "Http client max retry delay")
+	flags.Float64Var(&httpClientOptions.Retry.Jitter, "http-client-retry-jitter", httpClientOptions.Retry.Jitter, "Http client retry jitter, fraction of delay")
+	flags.StringVar(&httpClientRecord, "record", httpClientRecord, "Record vendor requests and responses to cassettes in dir, secrets are scrubbed")
+	flags.StringVar(&httpClientReplay, "replay", httpClientReplay, "Replay vendor responses from cassettes in dir without network")
+	configEnvFlags(flags, map[string]string{
+		"record": "HTTP_CLIENT_RECORD",
+		"replay": "HTTP_CLIENT_REPLAY",
+	})
+}
+
+// httpClientStop writes cassettes recorded by vendor clients
+func httpClientStop() {
+
+	if err := common.CloseCassettes(); err != nil && stdout != nil {
+		stdout.Warn("Cassettes: %s", err)
+	}
+}
+
+// httpClientInit sets defaults for vendor clients, debug level logs every request
+func httpClientInit() error {
+
+	var err error
+	if httpClientOptions.Retry.Delay, err = time.ParseDuration(httpClientRetryDelay); err != nil {
+		return fmt.Errorf("invalid http client retry delay: %v", err)
+	}
+	if httpClientOptions.Retry.MaxDelay, err = time.ParseDuration(httpClientRetryMaxDelay); err != nil {
+		return fmt.Errorf("invalid http client retry max delay: %v", err)
+	}
+	switch {
+	case !utils.IsEmpty(httpClientRecord) && !utils.IsEmpty(httpClientReplay):
+		return fmt.Errorf("record and replay cannot be used together")
+	case !utils.IsEmpty(httpClientRecord):
+		httpClientOptions.Cassette = common.CassetteOptions{Mode: common.CassetteRecord, Dir: httpClientRecord}
+	case !utils.IsEmpty(httpClientReplay):
+		httpClientOptions.Cassette = common.CassetteOptions{Mode: common.CassetteReplay, Dir: httpClientReplay}
+	}
+	if utils.IsEmpty(httpClientOptions.UserAgent) {
+		httpClientOptions.UserAgent = fmt.Sprintf("tools/%s", version)
+	}
+
+	options := httpClientOptions
+	if strings.EqualFold(stdoutOptions.Level, "debug") {
+		options.OnResponse = append(options.OnResponse, func(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+
+			u := common.RedactSensitive(req.URL.Redacted())
+			if err != nil {
+				stdout.Debug("HTTP %s %s => %s", req.Method, u, err, "duration", duration)
+				return
+			}
+			stdout.Debug("HTTP %s %s => %d", req.Method, u, resp.StatusCode, "duration", duration)
+		})
+	}
+	return common.SetHttpClientDefaults(options)
+}
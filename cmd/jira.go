@@ -28,13 +28,15 @@ var JiraIssueOptions = vendors.JiraIssueOptions{
 	IdOrKey:      envGet("JIRA_ISSUE_ID_OR_KEY", "").(string),
 	Summary:      envGet("JIRA_ISSUE_SUMMARY", "").(string),
 	Description:  envGet("JIRA_ISSUE_DESCRIPTION", "").(string),
+	Format:       envGet("JIRA_ISSUE_FORMAT", "").(string),
 	CustomFields: envGet("JIRA_ISSUE_CUSTOM_FIELDS", "").(string),
 	Labels:       strings.Split(envGet("JIRA_ISSUE_LABELS", "").(string), ","),
 	TransitionID: envGet("JIRA_ISSUE_STATUS", "").(string),
 }
 
 var jiraIssueAddCommentOptions = vendors.JiraAddIssueCommentOptions{
-	Body: envGet("JIRA_ISSUE_COMMENT_BODY", "").(string),
+	Body:   envGet("JIRA_ISSUE_COMMENT_BODY", "").(string),
+	Format: envGet("JIRA_ISSUE_COMMENT_FORMAT", "").(string),
 }
 
 var jiraIssueAddAttachmentOptions = vendors.JiraAddIssueAttachmentOptions{
@@ -50,6 +52,9 @@ var jiraIssueSearchOptions = vendors.JiraSearchIssueOptions{
 var jiraAssetSearchOptions = vendors.JiraSearchAssetOptions{
 	SearchPattern: envGet("JIRA_ASSET_SEARCH_PATTERN", "").(string),
 	ResultPerPage: envGet("JIRA_ASSET_SEARCH_RESULT_PER_PAGE", 50).(int),
+	PageOptions: vendors.PageOptions{
+		Limit: envGet("JIRA_ASSET_SEARCH_LIMIT", 0).(int),
+	},
 }
 
 var jiraAssetCreateOptions = vendors.JiraCreateAssetOptions{
@@ -63,8 +68,11 @@ var jiraAssetUpdateOptions = vendors.JiraUpdateAssetOptions{
 }
 
 var jiraOutput = common.OutputOptions{
-	Output: envGet("JIRA_OUTPUT", "").(string),
-	Query:  envGet("JIRA_OUTPUT_QUERY", "").(string),
+	Output:   envGet("JIRA_OUTPUT", "").(string),
+	Query:    envGet("JIRA_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("JIRA_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("JIRA_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("JIRA_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func jiraNew(stdout *common.Stdout) *vendors.Jira {
@@ -90,6 +98,9 @@ func NewJiraCommand() *cobra.Command {
 	flags.StringVar(&jiraOptions.AccessToken, "jira-access-token", jiraOptions.AccessToken, "Jira Personal Access Token")
 	flags.StringVar(&jiraOutput.Output, "jira-output", jiraOutput.Output, "Jira output")
 	flags.StringVar(&jiraOutput.Query, "jira-output-query", jiraOutput.Query, "Jira output query")
+	flags.StringSliceVar(&jiraOutput.QueryLib, "jira-output-query-lib", jiraOutput.QueryLib, "Jira output query libraries")
+	flags.StringVar(&jiraOutput.Format, "jira-output-format", jiraOutput.Format, "Jira output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&jiraOutput.Columns, "jira-output-columns", jiraOutput.Columns, "Jira output columns")
 
 	issueCmd := &cobra.Command{
 		Use:   "issue",
@@ -99,6 +110,7 @@ func NewJiraCommand() *cobra.Command {
 	flags.StringVar(&JiraIssueOptions.IdOrKey, "jira-issue-id-or-key", JiraIssueOptions.IdOrKey, "Jira issue ID or key")
 	flags.StringVar(&JiraIssueOptions.Summary, "jira-issue-summary", JiraIssueOptions.Summary, "Jira issue summary")
 	flags.StringVar(&JiraIssueOptions.Description, "jira-issue-description", JiraIssueOptions.Description, "Jira issue description")
+	flags.StringVar(&JiraIssueOptions.Format, "jira-issue-format", JiraIssueOptions.Format, "Jira issue description format: markdown")
 	flags.StringVar(&JiraIssueOptions.CustomFields, "jira-issue-custom-fields", JiraIssueOptions.CustomFields, "Jira issue custom fields file")
 	flags.StringSliceVar(&JiraIssueOptions.Labels, "jira-issue-labels", JiraIssueOptions.Labels, "Jira issue labels")
 	jiraCmd.AddCommand(issueCmd)
@@ -121,7 +133,7 @@ func NewJiraCommand() *cobra.Command {
 
 			bytes, err := jiraNew(stdout).CreateIssue(JiraIssueOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions}, bytes, stdout)
@@ -153,7 +165,7 @@ func NewJiraCommand() *cobra.Command {
 
 			bytes, err := jiraNew(stdout).IssueAddComment(JiraIssueOptions, jiraIssueAddCommentOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions, jiraIssueAddCommentOptions}, bytes, stdout)
@@ -161,6 +173,7 @@ func NewJiraCommand() *cobra.Command {
 	}
 	flags = issueAddCommentCmd.PersistentFlags()
 	flags.StringVar(&jiraIssueAddCommentOptions.Body, "jira-issue-comment-body", jiraIssueAddCommentOptions.Body, "Jira issue comment body")
+	flags.StringVar(&jiraIssueAddCommentOptions.Format, "jira-issue-comment-format", jiraIssueAddCommentOptions.Format, "Jira issue comment format: markdown")
 	issueCmd.AddCommand(issueAddCommentCmd)
 
 	// tools jira issue add-attachment --jira-params --issue-params --add-attachment-params
@@ -185,7 +198,7 @@ func NewJiraCommand() *cobra.Command {
 
 			bytes, err := jiraNew(stdout).AddIssueAttachment(JiraIssueOptions, jiraIssueAddAttachmentOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions, jiraIssueAddAttachmentOptions}, bytes, stdout)
@@ -213,7 +226,7 @@ func NewJiraCommand() *cobra.Command {
 
 			bytes, err := jiraNew(stdout).UpdateIssue(JiraIssueOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions}, bytes, stdout)
@@ -237,7 +250,7 @@ func NewJiraCommand() *cobra.Command {
 
 			bytes, err := jiraNew(stdout).ChangeIssueTransitions(JiraIssueOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions}, bytes, stdout)
@@ -263,7 +276,7 @@ func NewJiraCommand() *cobra.Command {
 
 			bytes, err := jiraNew(stdout).SearchIssue(jiraIssueSearchOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraIssueSearchOptions}, bytes, stdout)
@@ -293,9 +306,13 @@ func NewJiraCommand() *cobra.Command {
 			}
 			jiraAssetSearchOptions.SearchPattern = string(searchBytes)
 
+			streamed := pageStream(jiraOutput, &jiraAssetSearchOptions.PageOptions)
 			bytes, err := jiraNew(stdout).SearchAssets(jiraAssetSearchOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
+				return
+			}
+			if streamed {
 				return
 			}
 			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraAssetSearchOptions}, bytes, stdout)
@@ -304,6 +321,10 @@ func NewJiraCommand() *cobra.Command {
 	flags = assetSearchCmd.PersistentFlags()
 	flags.StringVar(&jiraAssetSearchOptions.SearchPattern, "jira-asset-search-pattern", jiraAssetSearchOptions.SearchPattern, "Jira asset search pattern")
 	flags.IntVar(&jiraAssetSearchOptions.ResultPerPage, "jira-asset-search-results-per-page", jiraAssetSearchOptions.ResultPerPage, "Jira asset result per page")
+	configEnvFlags(flags, map[string]string{
+		"jira-asset-search-results-per-page": "JIRA_ASSET_SEARCH_RESULT_PER_PAGE",
+	})
+	pageAddFlags(flags, &jiraAssetSearchOptions.PageOptions)
 	assetCmd.AddCommand(assetSearchCmd)
 
 	assetCreateCmd := &cobra.Command{
@@ -315,7 +336,7 @@ func NewJiraCommand() *cobra.Command {
 
 			bytes, err := jiraNew(stdout).CreateAsset(jiraAssetCreateOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraAssetCreateOptions}, bytes, stdout)
@@ -324,6 +345,9 @@ func NewJiraCommand() *cobra.Command {
 	flags = assetCreateCmd.PersistentFlags()
 	flags.StringVar(&jiraAssetCreateOptions.Name, "jira-asset-create-name", jiraAssetCreateOptions.Name, "Jira asset name")
 	flags.StringVar(&jiraAssetCreateOptions.Description, "jira-asset-create-rdescription", jiraAssetCreateOptions.Description, "Jira asset description")
+	configEnvFlags(flags, map[string]string{
+		"jira-asset-create-rdescription": "JIRA_ASSET_CREATE_DESCRIPTION",
+	})
 	// ... all options should be added, like value, etc.
 	assetCmd.AddCommand(assetCreateCmd)
 
@@ -342,7 +366,7 @@ func NewJiraCommand() *cobra.Command {
 
 			bytes, err := jiraNew(stdout).UpdateAsset(jiraAssetUpdateOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraAssetUpdateOptions}, bytes, stdout)
@@ -351,6 +375,10 @@ func NewJiraCommand() *cobra.Command {
 	flags = assetUpdateCmd.PersistentFlags()
 	flags.StringVar(&jiraAssetUpdateOptions.ObjectId, "jira-asset-update-object-id", jiraAssetUpdateOptions.ObjectId, "Jira asset object id")
 	flags.StringVar(&jiraAssetUpdateOptions.Json, "jira-asset-update-json", jiraAssetUpdateOptions.Json, "Jira asset json")
+	configEnvFlags(flags, map[string]string{
+		"jira-asset-update-object-id": "JIRA_ASSET_OBJECT_ID",
+		"jira-asset-update-json":      "JIRA_ASSET_JSON",
+	})
 	assetCmd.AddCommand(assetUpdateCmd)
 
 	return &jiraCmd
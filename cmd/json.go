@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/devopsext/utils"
@@ -14,8 +16,11 @@ var jsonOptions = vendors.JSONOptions{
 }
 
 var jsonOutput = common.OutputOptions{
-	Output: envGet("JSON_OUTPUT", "").(string),
-	Query:  envGet("JSON_OUTPUT_QUERY", "").(string),
+	Output:   envGet("JSON_OUTPUT", "").(string),
+	Query:    envGet("JSON_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("JSON_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("JSON_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("JSON_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func jsonNew(stdout *common.Stdout) *vendors.JSON {
@@ -45,6 +50,9 @@ func NewJSONCommand() *cobra.Command {
 	flags.StringVar(&jsonOptions.URL, "json-url", jsonOptions.URL, "JSON URL")
 	flags.StringVar(&jsonOutput.Output, "json-output", jsonOutput.Output, "JSON Output")
 	flags.StringVar(&jsonOutput.Query, "json-output-query", jsonOutput.Query, "JSON Output Query")
+	flags.StringSliceVar(&jsonOutput.QueryLib, "json-output-query-lib", jsonOutput.QueryLib, "JSON Output Query libraries")
+	flags.StringVar(&jsonOutput.Format, "json-output-format", jsonOutput.Format, "JSON Output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&jsonOutput.Columns, "json-output-columns", jsonOutput.Columns, "JSON Output columns")
 
 	jsonCmd.AddCommand(&cobra.Command{
 		Use:   "get",
@@ -53,7 +61,7 @@ func NewJSONCommand() *cobra.Command {
 			stdout.Debug("Getting JSON from URL...")
 			bytes, err := jsonNew(stdout).Get()
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(jsonOutput, "JSON", []interface{}{jsonOptions}, bytes, stdout)
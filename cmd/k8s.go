@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/spf13/cobra"
@@ -12,6 +14,8 @@ var k8sResourceOptions = vendors.K8sResourceOptions{
 	Name:      envGet("K8S_RESOURCE_NAME", "").(string),
 }
 
+var k8sResourceListOptions = vendors.K8sResourceListOptions{}
+
 var k8sResourceDescribeOptions = vendors.K8sResourceDescribeOptions{}
 
 var k8sResourceDeleteOptions = vendors.K8sResourceDeleteOptions{}
@@ -33,8 +37,11 @@ var k8sOptions = vendors.K8sOptions{
 }
 
 var k8sOutput = common.OutputOptions{
-	Output: envGet("K8S_OUTPUT", "").(string),
-	Query:  envGet("K8S_OUTPUT_QUERY", "").(string),
+	Output:   envGet("K8S_OUTPUT", "").(string),
+	Query:    envGet("K8S_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("K8S_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("K8S_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("K8S_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func k8sNew(stdout *common.Stdout) *vendors.K8s {
@@ -54,6 +61,9 @@ func NewK8sCommand() *cobra.Command {
 	flags.IntVar(&k8sOptions.Timeout, "k8s-timeout", k8sOptions.Timeout, "K8s timeout")
 	flags.StringVar(&k8sOutput.Output, "k8s-output", k8sOutput.Output, "K8s output")
 	flags.StringVar(&k8sOutput.Query, "k8s-output-query", k8sOutput.Query, "K8s output query")
+	flags.StringSliceVar(&k8sOutput.QueryLib, "k8s-output-query-lib", k8sOutput.QueryLib, "K8s output query libraries")
+	flags.StringVar(&k8sOutput.Format, "k8s-output-format", k8sOutput.Format, "K8s output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&k8sOutput.Columns, "k8s-output-columns", k8sOutput.Columns, "K8s output columns")
 
 	resourceCmd := &cobra.Command{
 		Use:   "resource",
@@ -65,6 +75,26 @@ func NewK8sCommand() *cobra.Command {
 	flags.StringVar(&k8sResourceOptions.Name, "k8s-resource-name", k8sResourceOptions.Name, "K8s Resource name")
 	k8sCmd.AddCommand(resourceCmd)
 
+	resourceListCmd := &cobra.Command{
+		Use:   "list",
+		Short: "K8s Resource list",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("K8s resource listing...")
+
+			k8sResourceListOptions.K8sResourceOptions = k8sResourceOptions
+			common.Debug("K8s", k8sResourceListOptions, stdout)
+
+			bytes, err := k8sNew(stdout).ResourceList(k8sResourceListOptions)
+			if err != nil {
+				commandError(err)
+				return
+			}
+			common.OutputJson(k8sOutput, "K8s", []interface{}{k8sOptions, k8sResourceListOptions}, bytes, stdout)
+		},
+	}
+	resourceCmd.AddCommand(resourceListCmd)
+
 	resourceDescribeCmd := &cobra.Command{
 		Use:   "describe",
 		Short: "K8s Resource describe",
@@ -77,7 +107,7 @@ func NewK8sCommand() *cobra.Command {
 
 			bytes, err := k8sNew(stdout).ResourceDescribe(k8sResourceDescribeOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(k8sOutput, "K8s", []interface{}{k8sOptions, k8sResourceDescribeOptions}, bytes, stdout)
@@ -97,7 +127,7 @@ func NewK8sCommand() *cobra.Command {
 
 			bytes, err := k8sNew(stdout).ResourceDelete(k8sResourceDeleteOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(k8sOutput, "K8s", []interface{}{k8sOptions, k8sResourceDeleteOptions}, bytes, stdout)
@@ -117,7 +147,7 @@ func NewK8sCommand() *cobra.Command {
 
 			bytes, err := k8sNew(stdout).ResourceScale(k8sResourceScaleOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(k8sOutput, "K8s", []interface{}{k8sOptions, k8sResourceScaleOptions}, bytes, stdout)
@@ -141,7 +171,7 @@ func NewK8sCommand() *cobra.Command {
 
 			bytes, err := k8sNew(stdout).ResourceRestart(k8sResourceRestartOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(k8sOutput, "K8s", []interface{}{k8sOptions, k8sResourceRestartOptions}, bytes, stdout)
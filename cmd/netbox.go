@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/devopsext/utils"
@@ -19,11 +21,17 @@ var netboxOptions = vendors.NetboxOptions{
 
 var netboxDeviceOptions = vendors.NetboxDeviceOptions{
 	DeviceID: envGet("NETBOX_DEVICE_ID", "").(string),
+	PageOptions: vendors.PageOptions{
+		Limit: envGet("NETBOX_DEVICES_LIMIT", 0).(int),
+	},
 }
 
 var netboxOutput = common.OutputOptions{
-	Output: envGet("NETBOX_OUTPUT", "").(string),
-	Query:  envGet("NETBOX_OUTPUT_QUERY", "").(string),
+	Output:   envGet("NETBOX_OUTPUT", "").(string),
+	Query:    envGet("NETBOX_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("NETBOX_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("NETBOX_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("NETBOX_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func netboxNew(stdout *common.Stdout) *vendors.Netbox {
@@ -54,6 +62,9 @@ func NewNetboxCommand() *cobra.Command {
 	flags.StringToStringVar(&netboxOptions.Filter, "netbox-filter", netboxOptions.Filter, "Netbox API filter params")
 	flags.StringVar(&netboxOutput.Output, "netbox-output", netboxOutput.Output, "Netbox output")
 	flags.StringVar(&netboxOutput.Query, "netbox-output-query", netboxOutput.Query, "Netbox output query")
+	flags.StringSliceVar(&netboxOutput.QueryLib, "netbox-output-query-lib", netboxOutput.QueryLib, "Netbox output query libraries")
+	flags.StringVar(&netboxOutput.Format, "netbox-output-format", netboxOutput.Format, "Netbox output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&netboxOutput.Columns, "netbox-output-columns", netboxOutput.Columns, "Netbox output columns")
 
 	getDeviceCmd := cobra.Command{
 		Use:   "get-devices",
@@ -62,9 +73,13 @@ func NewNetboxCommand() *cobra.Command {
 
 			stdout.Debug("Getting devices from URL...")
 
+			streamed := pageStream(netboxOutput, &netboxDeviceOptions.PageOptions)
 			bytes, err := netboxNew(stdout).GetDevices(netboxDeviceOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
+				return
+			}
+			if streamed {
 				return
 			}
 			common.OutputJson(netboxOutput, "Netbox", []interface{}{netboxOptions}, bytes, stdout)
@@ -72,6 +87,7 @@ func NewNetboxCommand() *cobra.Command {
 	}
 	flags = getDeviceCmd.PersistentFlags()
 	flags.StringVar(&netboxDeviceOptions.DeviceID, "netbox-device-id", netboxDeviceOptions.DeviceID, "Netbox device id")
+	pageAddFlags(flags, &netboxDeviceOptions.PageOptions)
 	netboxCmd.AddCommand(&getDeviceCmd)
 
 	return netboxCmd
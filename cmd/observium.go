@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/spf13/cobra"
@@ -16,8 +18,11 @@ var observiumOptions = vendors.ObserviumOptions{
 }
 
 var observiumOutput = common.OutputOptions{
-	Output: envGet("OBSERVIUM_OUTPUT", "").(string),
-	Query:  envGet("OBSERVIUM_OUTPUT_QUERY", "").(string),
+	Output:   envGet("OBSERVIUM_OUTPUT", "").(string),
+	Query:    envGet("OBSERVIUM_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("OBSERVIUM_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("OBSERVIUM_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("OBSERVIUM_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func observiumNew(stdout *common.Stdout) *vendors.Observium {
@@ -47,6 +52,9 @@ func NewObserviumCommand() *cobra.Command {
 	flags.StringVar(&observiumOptions.Token, "observium-token", observiumOptions.Token, "Observium token")
 	flags.StringVar(&observiumOutput.Output, "observium-output", observiumOutput.Output, "Observium output")
 	flags.StringVar(&observiumOutput.Query, "observium-output-query", observiumOutput.Query, "Observium output query")
+	flags.StringSliceVar(&observiumOutput.QueryLib, "observium-output-query-lib", observiumOutput.QueryLib, "Observium output query libraries")
+	flags.StringVar(&observiumOutput.Format, "observium-output-format", observiumOutput.Format, "Observium output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&observiumOutput.Columns, "observium-output-columns", observiumOutput.Columns, "Observium output columns")
 
 	observiumCmd.AddCommand(&cobra.Command{
 		Use:   "get-devices",
@@ -57,7 +65,7 @@ func NewObserviumCommand() *cobra.Command {
 
 			bytes, err := observiumNew(stdout).GetDevices()
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(observiumOutput, "Observium", []interface{}{observiumOptions}, bytes, stdout)
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+	"github.com/spf13/pflag"
+)
+
+var outputS3Region = envGet("OUTPUT_S3_REGION", "us-east-1").(string)
+
+var outputS3Options = vendors.AWSOptions{
+	AWSKeys: vendors.AWSKeys{
+		AccessKey: envGet("OUTPUT_S3_ACCESS_KEY", "").(string),
+		SecretKey: envGet("OUTPUT_S3_SECRET_KEY", "").(string),
+	},
+	Timeout:  envGet("OUTPUT_S3_TIMEOUT", 30).(int),
+	Insecure: envGet("OUTPUT_S3_INSECURE", false).(bool),
+}
+
+var outputSlackOptions = vendors.SlackOptions{
+	Token:    envGet("OUTPUT_SLACK_TOKEN", "").(string),
+	Timeout:  envGet("OUTPUT_SLACK_TIMEOUT", 30).(int),
+	Insecure: envGet("OUTPUT_SLACK_INSECURE", false).(bool),
+}
+
+func outputAddFlags(flags *pflag.FlagSet) {
+
+	flags.StringVar(&outputS3Region, "output-s3-region", outputS3Region, "Output S3 region for s3:// targets")
+	flags.StringVar(&outputS3Options.AccessKey, "output-s3-access-key", outputS3Options.AccessKey, "Output S3 access key, AWS access key by default")
+	flags.StringVar(&outputS3Options.SecretKey, "output-s3-secret-key", outputS3Options.SecretKey, "Output S3 secret key, AWS secret key by default")
+	flags.IntVar(&outputS3Options.Timeout, "output-s3-timeout", outputS3Options.Timeout, "Output S3 timeout")
+	flags.BoolVar(&outputS3Options.Insecure, "output-s3-insecure", outputS3Options.Insecure, "Output S3 insecure")
+	flags.StringVar(&outputSlackOptions.Token, "output-slack-token", outputSlackOptions.Token, "Output Slack token for slack:// targets, Slack token by default")
+	flags.IntVar(&outputSlackOptions.Timeout, "output-slack-timeout", outputSlackOptions.Timeout, "Output Slack timeout")
+	flags.BoolVar(&outputSlackOptions.Insecure, "output-slack-insecure", outputSlackOptions.Insecure, "Output Slack insecure")
+}
+
+// pageAddFlags adds flags of list commands, limit fetches pages until count of items and all fetches every page
+func pageAddFlags(flags *pflag.FlagSet, options *vendors.PageOptions) {
+
+	flags.IntVar(&options.Limit, "limit", options.Limit, "Max items, pages are fetched until limit")
+	flags.BoolVar(&options.All, "all", options.All, "Fetch all pages")
+	configEnvFlags(flags, map[string]string{"limit": "", "all": ""})
+}
+
+// pageStream makes list command write items to stdout as pages are fetched, it's done for ndjson without query and output,
+// other outputs need all items
+func pageStream(output common.OutputOptions, options *vendors.PageOptions) bool {
+
+	if !strings.EqualFold(output.Format, common.OutputFormatNdjson) || !utils.IsEmpty(output.Query) || !utils.IsEmpty(output.Output) {
+		return false
+	}
+	options.Stream = func(item interface{}) error {
+		line, err := common.FormatOutput(item, common.OutputFormatNdjson, nil)
+		if err != nil {
+			return err
+		}
+		stdout.Info(line)
+		return nil
+	}
+	return true
+}
+
+// outputInit registers sinks which need vendor credentials, clients are created on first write
+func outputInit() {
+
+	common.RegisterOutputSink("s3", common.OutputSinkFunc(func(u *url.URL, data []byte) error {
+
+		options := outputS3Options
+		if utils.IsEmpty(options.AccessKey) && utils.IsEmpty(options.SecretKey) {
+			options.AWSKeys = awsOptions.AWSKeys
+		}
+		s3, err := vendors.NewAWSS3(options)
+		if err != nil {
+			return err
+		}
+		return s3.OutputSink(outputS3Region).Write(u, data)
+	}))
+
+	common.RegisterOutputSink("slack", common.OutputSinkFunc(func(u *url.URL, data []byte) error {
+
+		options := outputSlackOptions
+		if utils.IsEmpty(options.Token) {
+			options.Token = slackOptions.Token
+		}
+		return vendors.NewSlack(options).OutputSink().Write(u, data)
+	}))
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/devopsext/utils"
@@ -15,8 +17,11 @@ var pagerDutyOptions = vendors.PagerDutyOptions{
 }
 
 var pagerDutyGetIncidentsOptions = vendors.PagerDutyGetIncidentsOptions{
-	Key:   envGet("PAGERDUTY_INCIDENT_KEY", "").(string),
-	Limit: envGet("PAGERDUTY_INCIDENTS_LIMIT", 10).(int),
+	Key: envGet("PAGERDUTY_INCIDENT_KEY", "").(string),
+	PageOptions: vendors.PageOptions{
+		Limit: envGet("PAGERDUTY_INCIDENTS_LIMIT", 10).(int),
+		All:   envGet("PAGERDUTY_INCIDENTS_ALL", false).(bool),
+	},
 }
 
 var pagerDutyCreateIncidentOptions = vendors.PagerDutyCreateIncidentOptions{
@@ -32,8 +37,11 @@ var pagerDutyIncidentOptions = vendors.PagerDutyIncidentOptions{
 }
 
 var pagerDutyOutput = common.OutputOptions{
-	Output: envGet("PAGERDUTY_OUTPUT", "").(string),
-	Query:  envGet("PAGERDUTY_OUTPUT_QUERY", "").(string),
+	Output:   envGet("PAGERDUTY_OUTPUT", "").(string),
+	Query:    envGet("PAGERDUTY_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("PAGERDUTY_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("PAGERDUTY_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("PAGERDUTY_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func pagerDutyNew(stdout *common.Stdout) *vendors.PagerDuty {
@@ -61,6 +69,9 @@ func NewPagerDutyCommand() *cobra.Command {
 	flags.StringVar(&pagerDutyOptions.Token, "pagerduty-token", pagerDutyOptions.Token, "pagerDuty token")
 	flags.StringVar(&pagerDutyOutput.Output, "pagerduty-output", pagerDutyOutput.Output, "pagerDuty output")
 	flags.StringVar(&pagerDutyOutput.Query, "pagerduty-output-query", pagerDutyOutput.Query, "pagerDuty output query")
+	flags.StringSliceVar(&pagerDutyOutput.QueryLib, "pagerduty-output-query-lib", pagerDutyOutput.QueryLib, "pagerDuty output query libraries")
+	flags.StringVar(&pagerDutyOutput.Format, "pagerduty-output-format", pagerDutyOutput.Format, "pagerDuty output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&pagerDutyOutput.Columns, "pagerduty-output-columns", pagerDutyOutput.Columns, "pagerDuty output columns")
 
 	// tools pagerduty get-incidents
 	getIncidentsCmd := &cobra.Command{
@@ -71,9 +82,13 @@ func NewPagerDutyCommand() *cobra.Command {
 			stdout.Debug("PagerDuty getting incident...")
 			common.Debug("PagerDuty", pagerDutyGetIncidentsOptions, stdout)
 
+			streamed := pageStream(pagerDutyOutput, &pagerDutyGetIncidentsOptions.PageOptions)
 			bytes, err := pagerDutyNew(stdout).GetIncidents(pagerDutyGetIncidentsOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
+				return
+			}
+			if streamed {
 				return
 			}
 			common.OutputJson(pagerDutyOutput, "PagerDuty", []interface{}{pagerDutyOptions}, bytes, stdout)
@@ -82,6 +97,7 @@ func NewPagerDutyCommand() *cobra.Command {
 	flags = getIncidentsCmd.PersistentFlags()
 	flags.StringVar(&pagerDutyGetIncidentsOptions.Key, "pagerduty-incident-key", pagerDutyGetIncidentsOptions.Key, "PagerDuty incident key")
 	flags.IntVar(&pagerDutyGetIncidentsOptions.Limit, "pagerduty-incidents-limit", pagerDutyGetIncidentsOptions.Limit, "PagerDuty incidents limit")
+	pageAddFlags(flags, &pagerDutyGetIncidentsOptions.PageOptions)
 	pagerDutyCmd.AddCommand(getIncidentsCmd)
 
 	incidentCmd := &cobra.Command{
@@ -114,7 +130,7 @@ func NewPagerDutyCommand() *cobra.Command {
 
 			bytes, err := pagerDutyNew(stdout).CreateIncident(pagerDutyIncidentOptions, pagerDutyCreateIncidentOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(pagerDutyOutput, "PagerDuty", []interface{}{pagerDutyOptions, pagerDutyIncidentOptions, pagerDutyCreateIncidentOptions}, bytes, stdout)
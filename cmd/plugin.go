@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// pluginConfig is passed to plugins as JSON in TOOLS_PLUGIN_CONFIG and, with --plugin-stdin, as the first line of stdin
+type pluginConfig struct {
+	Name    string               `json:"name"`
+	Version string               `json:"version"`
+	Profile string               `json:"profile,omitempty"`
+	Stdout  common.StdoutOptions `json:"stdout"`
+	Config  map[string]string    `json:"config"`
+}
+
+const pluginConfigEnv = "PLUGIN_CONFIG"
+
+// pluginKeysExt is extension of file next to plugin, which declares env keys it gets, one per line
+const pluginKeysExt = ".keys"
+
+var pluginStdin = envGet("PLUGIN_STDIN", false).(bool)
+
+// pluginEnvAllow are env key patterns, e.g. TOOLS_GITLAB_*, which are passed to every plugin besides declared ones
+var pluginEnvAllow = strings.Split(envGet("PLUGIN_ENV", "").(string), ",")
+
+func pluginAddFlags(flags *pflag.FlagSet) {
+
+	flags.BoolVar(&pluginStdin, "plugin-stdin", pluginStdin, "Plugin gets config JSON as the first line of stdin")
+	flags.StringSliceVar(&pluginEnvAllow, "plugin-env", pluginEnvAllow, "Plugin env key patterns, e.g. TOOLS_GITLAB_*, which plugins get besides keys of their .keys file")
+}
+
+// pluginKeys returns env key patterns declared by plugin in <path without ext>.keys and allowed by user
+func pluginKeys(path string) ([]string, error) {
+
+	keys := common.RemoveEmptyStrings(pluginEnvAllow)
+	b, err := os.ReadFile(strings.TrimSuffix(path, filepath.Ext(path)) + pluginKeysExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return keys, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if utils.IsEmpty(line) || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, line)
+	}
+	return keys, nil
+}
+
+func pluginKeyAllowed(keys []string, key string) bool {
+
+	for _, k := range keys {
+		if ok, _ := filepath.Match(strings.TrimSpace(k), key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// pluginSplitArgs splits leading tools flags from plugin arguments, flags after the first plugin argument are plugin's
+func pluginSplitArgs(flags *pflag.FlagSet, args []string) ([]string, []string) {
+
+	i := 0
+	for i < len(args) {
+
+		a := args[i]
+		if a == "--" {
+			return args[:i], args[i+1:]
+		}
+		if !strings.HasPrefix(a, "-") || a == "-" {
+			break
+		}
+
+		name, _, value := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		var f *pflag.Flag
+		if strings.HasPrefix(a, "--") {
+			f = flags.Lookup(name)
+		} else if len(name) == 1 {
+			f = flags.ShorthandLookup(name)
+		}
+		if f == nil {
+			break
+		}
+		i++
+		if !value && f.NoOptDefVal == "" && i < len(args) {
+			i++
+		}
+	}
+	return args[:i], args[i:]
+}
+
+// pluginFlagValue returns flag value as tools reads it from env, slices are comma separated
+func pluginFlagValue(f *pflag.Flag) string {
+
+	if s, ok := f.Value.(pflag.SliceValue); ok {
+		return strings.Join(s.GetSlice(), ",")
+	}
+	return f.Value.String()
+}
+
+// pluginConfigKey returns env key of config value, profile values without flag use key derived from name
+func pluginConfigKey(flags *pflag.FlagSet, name string) string {
+
+	if f := flags.Lookup(name); f != nil {
+		return configEnvKey(f)
+	}
+	return configEnvName(name)
+}
+
+// pluginConfigValues returns profile values with resolved secrets and flags set on command line, only values
+// of allowed env keys are returned, so secrets of other vendors aren't resolved and passed
+func pluginConfigValues(flags *pflag.FlagSet, keys []string) (map[string]string, map[string]string, map[string]bool, error) {
+
+	values, err := configLoad()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	r := make(map[string]string)
+	envKeys := make(map[string]string)
+	for k, v := range values {
+		key := pluginConfigKey(flags, k)
+		if utils.IsEmpty(key) || !pluginKeyAllowed(keys, key) {
+			continue
+		}
+		if r[k], err = common.ResolveSecretValue(v); err != nil {
+			return nil, nil, nil, fmt.Errorf("config %s: %v", k, err)
+		}
+		envKeys[k] = key
+	}
+
+	changed := make(map[string]bool)
+	// flags are parsed by other flag set, so they have no visit mark here
+	flags.VisitAll(func(f *pflag.Flag) {
+		key := configEnvKey(f)
+		if !f.Changed || utils.IsEmpty(key) || !pluginKeyAllowed(keys, key) {
+			return
+		}
+		r[f.Name] = pluginFlagValue(f)
+		envKeys[f.Name] = key
+		changed[f.Name] = true
+	})
+	return r, envKeys, changed, nil
+}
+
+// pluginEnv returns environment of plugin, config values are set as TOOLS_* variables like tools reads them,
+// env beats profile values and command line beats both, only keys declared by plugin or allowed by user are set
+func pluginEnv(name, path string, flags *pflag.FlagSet) ([]string, []byte, error) {
+
+	allowed, err := pluginKeys(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, envKeys, changed, err := pluginConfigValues(flags, allowed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config, err := common.JsonMarshal(pluginConfig{
+		Name:    name,
+		Version: version,
+		Profile: configOptions.Profile,
+		Stdout:  stdoutOptions,
+		Config:  values,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	env := os.Environ()
+	for _, k := range keys {
+		key := envKeys[k]
+		if _, ok := os.LookupEnv(key); ok && !changed[k] {
+			continue
+		}
+		env = append(env, fmt.Sprintf("%s=%s", key, values[k]))
+	}
+	env = append(env, fmt.Sprintf("%s=%s", configEnvName(pluginConfigEnv), config))
+	return env, config, nil
+}
+
+func pluginRun(cmd *cobra.Command, name, path string, args []string) error {
+
+	env, config, err := pluginEnv(name, path, cmd.Root().PersistentFlags())
+	if err != nil {
+		return err
+	}
+
+	c := exec.CommandContext(cmd.Context(), path, args...)
+	c.Env = env
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if pluginStdin {
+		c.Stdin = io.MultiReader(bytes.NewReader(append(config, '\n')), os.Stdin)
+	}
+
+	err = c.Run()
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		return common.NewExitError(ee.ExitCode(), nil)
+	}
+	return err
+}
+
+// newPluginCommand runs tools-<name>, tools flags before plugin arguments are applied, the rest is passed as is
+func newPluginCommand(name, path string) *cobra.Command {
+
+	var pluginArgs []string
+	return &cobra.Command{
+		Use:                name,
+		Short:              fmt.Sprintf("Plugin %s", path),
+		DisableFlagParsing: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+
+			var toolsArgs []string
+			toolsArgs, pluginArgs = pluginSplitArgs(cmd.Root().PersistentFlags(), args)
+			if err := cmd.Root().ParseFlags(toolsArgs); err != nil {
+				return err
+			}
+			// flag parsing is disabled, so tools flags are added to be seen by config and secrets
+			cmd.Flags().AddFlagSet(cmd.Root().PersistentFlags())
+			rootPreRun(cmd)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			stdout.Debug("Plugin %s running...", path)
+			return pluginRun(cmd, name, path, pluginArgs)
+		},
+	}
+}
+
+// pluginScanNeeded checks that command of args can be plugin, so PATH isn't read for built-in commands,
+// help and completion of the first word list plugins as well
+func pluginScanNeeded(reserved map[string]bool, args []string) bool {
+
+	if len(args) > 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		args = args[1:]
+		if len(args) <= 1 {
+			return true
+		}
+	}
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") {
+			continue
+		}
+		return a == "help" || !reserved[a]
+	}
+	return true
+}
+
+// pluginAddCommands adds plugins on PATH as commands, built-in commands win
+func pluginAddCommands(root *cobra.Command, args []string) {
+
+	reserved := map[string]bool{"help": true, "completion": true}
+	for _, c := range root.Commands() {
+		reserved[c.Name()] = true
+		for _, a := range c.Aliases {
+			reserved[a] = true
+		}
+	}
+	if !pluginScanNeeded(reserved, args) {
+		return
+	}
+
+	plugins := common.FindPlugins()
+	var names []string
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if reserved[name] || utils.IsEmpty(name) {
+			continue
+		}
+		root.AddCommand(newPluginCommand(name, plugins[name]))
+	}
+}
+
+// templatePlugins serve template functions of running command, they are closed when it's done
+var templatePlugins []*common.PluginFuncs
+
+// templatePluginsStart starts plugins of --template-plugins and adds their functions to template options
+func templatePluginsStart(cmd *cobra.Command) error {
+
+	for _, name := range common.RemoveEmptyStrings(templatePluginNames) {
+
+		path, err := common.FindPlugin(name)
+		if err != nil {
+			return err
+		}
+		env, _, err := pluginEnv(name, path, cmd.Root().PersistentFlags())
+		if err != nil {
+			return err
+		}
+		p, err := common.StartPluginFuncs(name, path, env)
+		if err != nil {
+			return err
+		}
+		templatePlugins = append(templatePlugins, p)
+
+		if templateOptions.Funcs == nil {
+			templateOptions.Funcs = make(map[string]any)
+		}
+		for k, v := range p.Funcs() {
+			templateOptions.Funcs[k] = v
+		}
+		stdout.Debug("Template plugin %s functions: %s", name, strings.Join(p.Names(), ", "))
+	}
+	return nil
+}
+
+func templatePluginsClose() {
+
+	for _, p := range templatePlugins {
+		p.Close()
+	}
+	templatePlugins = nil
+}
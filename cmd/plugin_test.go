@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pluginTestEnv returns TOOLS_* variables of plugin env
+func pluginTestEnv(env []string) []string {
+
+	var r []string
+	for _, e := range env {
+		if strings.HasPrefix(e, "TOOLS_") && !strings.HasPrefix(e, configEnvName(pluginConfigEnv)) {
+			r = append(r, e)
+		}
+	}
+	return r
+}
+
+func TestPluginEnv(t *testing.T) {
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("profiles:\n  aws:\n    accesskey: key\n  gitlab:\n    token: token\n"), 0600))
+	path := filepath.Join(dir, "tools-test")
+	require.NoError(t, os.WriteFile(path+pluginKeysExt, []byte("# keys of test plugin\nTOOLS_AWS_ACCESS_KEY\n"), 0600))
+
+	config, allow := configOptions.File, pluginEnvAllow
+	t.Cleanup(func() {
+		configOptions.File, pluginEnvAllow = config, allow
+	})
+	configOptions.File = file
+
+	var accessKey, token string
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringVar(&accessKey, "aws-accesskey", "", "AWS access key")
+	flags.StringVar(&token, "gitlab-token", "", "Gitlab token")
+	configEnvFlags(flags, map[string]string{"aws-accesskey": "AWS_ACCESS_KEY"})
+
+	// plugin gets only keys it declares, with env keys tools reads
+	pluginEnvAllow = nil
+	env, b, err := pluginEnv("test", path, flags)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TOOLS_AWS_ACCESS_KEY=key"}, pluginTestEnv(env))
+	assert.NotContains(t, string(b), "token")
+
+	// user allows more keys
+	pluginEnvAllow = []string{"TOOLS_GITLAB_*"}
+	env, _, err = pluginEnv("test", path, flags)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TOOLS_AWS_ACCESS_KEY=key", "TOOLS_GITLAB_TOKEN=token"}, pluginTestEnv(env))
+
+	// nothing is passed to plugin without keys
+	pluginEnvAllow = nil
+	env, b, err = pluginEnv("other", filepath.Join(dir, "tools-other"), flags)
+	require.NoError(t, err)
+	assert.Empty(t, pluginTestEnv(env))
+	assert.NotContains(t, string(b), "key")
+}
+
+func TestPluginScanNeeded(t *testing.T) {
+
+	reserved := map[string]bool{"help": true, "completion": true, "gitlab": true}
+	tests := []struct {
+		name     string
+		args     []string
+		expected bool
+	}{
+		{name: "No args", expected: true},
+		{name: "Built-in command", args: []string{"gitlab", "pipeline", "list"}},
+		{name: "Built-in command after flags", args: []string{"--debug", "gitlab"}},
+		{name: "Plugin", args: []string{"deploy", "--dry-run"}, expected: true},
+		{name: "Help", args: []string{"help", "deploy"}, expected: true},
+		{name: "Completion of built-in command", args: []string{cobra.ShellCompRequestCmd, "gitlab", ""}},
+		{name: "Completion of first word", args: []string{cobra.ShellCompRequestCmd, "de"}, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, pluginScanNeeded(reserved, tt.args))
+		})
+	}
+}
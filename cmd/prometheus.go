@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/devopsext/utils"
@@ -21,8 +23,11 @@ var prometheusOptions = vendors.PrometheusOptions{
 }
 
 var prometheusOutput = common.OutputOptions{
-	Output: envGet("PROMETHEUS_OUTPUT", "").(string),
-	Query:  envGet("PROMETHEUS_OUTPUT_QUERY", "").(string),
+	Output:   envGet("PROMETHEUS_OUTPUT", "").(string),
+	Query:    envGet("PROMETHEUS_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("PROMETHEUS_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("PROMETHEUS_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("PROMETHEUS_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func prometheusNew(stdout *common.Stdout) *vendors.Prometheus {
@@ -63,6 +68,9 @@ func NewPrometheusCommand() *cobra.Command {
 	flags.StringVar(&prometheusOptions.Password, "prometheus-password", prometheusOptions.Password, "Prometheus password")
 	flags.StringVar(&prometheusOutput.Output, "prometheus-output", prometheusOutput.Output, "Prometheus output")
 	flags.StringVar(&prometheusOutput.Query, "prometheus-output-query", prometheusOutput.Query, "Prometheus output query")
+	flags.StringSliceVar(&prometheusOutput.QueryLib, "prometheus-output-query-lib", prometheusOutput.QueryLib, "Prometheus output query libraries")
+	flags.StringVar(&prometheusOutput.Format, "prometheus-output-format", prometheusOutput.Format, "Prometheus output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&prometheusOutput.Columns, "prometheus-output-columns", prometheusOutput.Columns, "Prometheus output columns")
 
 	prometheusCmd.AddCommand(&cobra.Command{
 		Use:   "get",
@@ -73,7 +81,7 @@ func NewPrometheusCommand() *cobra.Command {
 
 			bytes, err := prometheusNew(stdout).Get()
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(prometheusOutput, "Prometheus", []interface{}{prometheusOptions}, bytes, stdout)
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -19,9 +21,10 @@ var mainWG sync.WaitGroup
 var stdoutOptions = common.StdoutOptions{
 	Format:          envGet("STDOUT_FORMAT", "template").(string),
 	Level:           envGet("STDOUT_LEVEL", "info").(string),
-	Template:        envGet("STDOUT_TEMPLATE", "{{.file}} {{.msg}}").(string),
+	Template:        envGet("STDOUT_TEMPLATE", "{{.file}} {{.msg}}{{.fields}}").(string),
 	TimestampFormat: envGet("STDOUT_TIMESTAMP_FORMAT", time.RFC3339Nano).(string),
 	TextColors:      envGet("STDOUT_TEXT_COLORS", true).(bool),
+	Output:          envGet("STDOUT_OUTPUT", common.StdoutOutputStdout).(string),
 }
 
 func getOnlyEnv(key string) string {
@@ -33,7 +36,12 @@ func getOnlyEnv(key string) string {
 }
 
 func envGet(s string, def interface{}) interface{} {
-	return utils.EnvGet(fmt.Sprintf("%s_%s", APPNAME, s), def)
+	key := configEnvName(s)
+	envRead[key] = true
+	if _, ok := os.LookupEnv(key); ok {
+		envKeys[key] = true
+	}
+	return utils.EnvGet(key, def)
 }
 
 func envStringExpand(s string, def string) string {
@@ -50,14 +58,71 @@ func envFileContentExpand(s string, def string) string {
 	return os.Expand(string(bytes), getOnlyEnv)
 }
 
-func Execute() {
+// commandErr is the last error of commands which log errors instead of returning them
+var commandErr error
+
+// commandError logs error of command, process exits with its code, so vendor errors are told apart by callers
+func commandError(err error) {
+
+	// message is logged with file of command
+	if so, ok := stdout.With().(*common.Stdout); ok {
+		so.SetCallerOffset(2)
+		so.Error(err)
+	}
+	commandErr = err
+}
+
+// exitOnPanic turns panics of stdout.Panic, which has already logged the message, into exit code
+func exitOnPanic() {
+
+	if r := recover(); r != nil {
+		if e, ok := r.(*logrus.Entry); ok {
+			httpClientStop()
+			tracingStop(fmt.Errorf("%s", e.Message))
+			os.Exit(common.ExitCodeError)
+		}
+		panic(r)
+	}
+}
+
+// rootPreRun initializes stdout, config, secrets and tracing of command
+func rootPreRun(cmd *cobra.Command) {
+
+	stdout = common.NewStdout(stdoutOptions)
+	stdout.SetCallerOffset(1)
+
+	if err := configInit(cmd); err != nil {
+		stdout.Panic(err)
+	}
+
+	if err := httpClientInit(); err != nil {
+		stdout.Panic(err)
+	}
+
+	if err := secretInit(cmd); err != nil {
+		stdout.Panic(err)
+	}
+
+	outputInit()
+
+	if err := tracingInit(cmd); err != nil {
+		stdout.Panic(err)
+	}
+}
+
+// newRootCommand creates tools command with all vendor, server and plugin commands
+func newRootCommand() *cobra.Command {
+
 	rootCmd := &cobra.Command{
-		Use:   "tools",
-		Short: "Tools",
+		Use:           "tools",
+		Short:         "Tools",
+		SilenceUsage:  true,
+		SilenceErrors: true,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			stdout = common.NewStdout(stdoutOptions)
-			stdout.SetCallerOffset(1)
+			rootPreRun(cmd)
 		},
+		// completion command of tools completes resources of vendors as well
+		CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
 	}
 
 	flags := rootCmd.PersistentFlags()
@@ -67,6 +132,14 @@ func Execute() {
 	flags.StringVar(&stdoutOptions.Template, "stdout-template", stdoutOptions.Template, "Stdout template")
 	flags.StringVar(&stdoutOptions.TimestampFormat, "stdout-timestamp-format", stdoutOptions.TimestampFormat, "Stdout timestamp format")
 	flags.BoolVar(&stdoutOptions.TextColors, "stdout-text-colors", stdoutOptions.TextColors, "Stdout text colors")
+	flags.StringVar(&stdoutOptions.Output, "stdout-output", stdoutOptions.Output, "Stdout output: stdout, stderr, file path, file:///path?rotate=10MB&keep=5, syslog://host:514?tag=tools, journald")
+
+	configAddFlags(flags)
+	httpClientAddFlags(flags)
+	secretAddFlags(flags)
+	outputAddFlags(flags)
+	tracingAddFlags(flags)
+	pluginAddFlags(flags)
 
 	rootCmd.AddCommand(&cobra.Command{
 		Use:   "version",
@@ -97,14 +170,39 @@ func Execute() {
 	rootCmd.AddCommand(NewNetboxCommand())
 	rootCmd.AddCommand(NewK8sCommand())
 	rootCmd.AddCommand(NewTeleportCommand())
+	rootCmd.AddCommand(NewVendorsCommand())
 
+	rootCmd.AddCommand(NewConfigCommand())
 	rootCmd.AddCommand(NewTemplateCommand())
 	rootCmd.AddCommand(NewDateCommand())
 
 	rootCmd.AddCommand(NewServerCommand(&mainWG))
+	rootCmd.AddCommand(NewCompletionCommand())
 
-	if err := rootCmd.Execute(); err != nil {
-		stdout.Error(err)
-		os.Exit(1)
+	pluginAddCommands(rootCmd, os.Args[1:])
+	completionRegister(rootCmd, completionFlags())
+	return rootCmd
+}
+
+func Execute() {
+
+	defer exitOnPanic()
+
+	err := newRootCommand().Execute()
+	httpClientStop()
+	tracingStop(err)
+	if err != nil {
+		if stdout == nil {
+			stdout = common.NewStdout(stdoutOptions)
+		}
+		// exit error without cause is exit code of plugin, which has reported its error already
+		var ee *common.ExitError
+		if !errors.As(err, &ee) || ee.Err != nil {
+			stdout.Error(err)
+		}
+		os.Exit(common.ExitCode(err))
+	}
+	if commandErr != nil {
+		os.Exit(common.ExitCode(commandErr))
 	}
 }
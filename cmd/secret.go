@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const secretFlagPrefix = "secret-"
+
+var secretRCAPrivateKey = envGet("SECRET_RSA_PRIVATE_KEY", "").(string)
+
+var secretVaultOptions = vendors.VaultOptions{
+	URL:       envGet("SECRET_VAULT_URL", "").(string),
+	Token:     envGet("SECRET_VAULT_TOKEN", "").(string),
+	Namespace: envGet("SECRET_VAULT_NAMESPACE", "").(string),
+	Timeout:   envGet("SECRET_VAULT_TIMEOUT", 30).(int),
+	Insecure:  envGet("SECRET_VAULT_INSECURE", false).(bool),
+}
+
+func secretAddFlags(flags *pflag.FlagSet) {
+
+	flags.StringVar(&secretRCAPrivateKey, "secret-rsa-private-key", secretRCAPrivateKey, "Secret RSA private key content or file for rsa: references")
+	flags.StringVar(&secretVaultOptions.URL, "secret-vault-url", secretVaultOptions.URL, "Secret Vault URL for vault: references")
+	flags.StringVar(&secretVaultOptions.Token, "secret-vault-token", secretVaultOptions.Token, "Secret Vault token")
+	flags.StringVar(&secretVaultOptions.Namespace, "secret-vault-namespace", secretVaultOptions.Namespace, "Secret Vault namespace")
+	flags.IntVar(&secretVaultOptions.Timeout, "secret-vault-timeout", secretVaultOptions.Timeout, "Secret Vault timeout")
+	flags.BoolVar(&secretVaultOptions.Insecure, "secret-vault-insecure", secretVaultOptions.Insecure, "Secret Vault insecure")
+}
+
+// secretResolveFlags replaces "secret:<scheme>:<ref>" values of string flags with resolved secrets
+func secretResolveFlags(flags *pflag.FlagSet, own bool) error {
+
+	var err error
+	flags.VisitAll(func(f *pflag.Flag) {
+
+		if err != nil || f.Value.Type() != "string" {
+			return
+		}
+		if strings.HasPrefix(f.Name, secretFlagPrefix) != own {
+			return
+		}
+
+		v := f.Value.String()
+		if !common.IsSecretReference(v) {
+			return
+		}
+
+		var r string
+		r, err = common.ResolveSecret(v)
+		if err != nil {
+			return
+		}
+		err = f.Value.Set(r)
+	})
+	return err
+}
+
+func secretInit(cmd *cobra.Command) error {
+
+	flags := cmd.Flags()
+
+	// secret backend options can refer to file or env secrets themselves
+	if err := secretResolveFlags(flags, true); err != nil {
+		return err
+	}
+
+	common.RegisterSecretBackend("rsa", vendors.NewCrypto().RCASecretBackend(secretRCAPrivateKey))
+	common.RegisterSecretBackend("vault", vendors.NewVault(secretVaultOptions))
+
+	return secretResolveFlags(flags, false)
+}
@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/server"
+	"github.com/devopsext/tools/vendors/vendortest"
+	"github.com/devopsext/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -22,30 +26,110 @@ var httpServerOptions = server.HttpServerOptions{
 	SensitiveFields: strings.Split(envGet("HTTP_SERVER_SENSITIVE_FIELDS", "password,user,pass,username,token,secret").(string), ","),
 }
 
-func httpServerNew(stdout *common.Stdout) *server.HttpServer {
+var serverCallOptions = server.CallOptions{
+	Allow:         strings.Split(envGet("SERVER_ALLOW", "").(string), ","),
+	CallerOptions: envGet("SERVER_CALLER_OPTIONS", false).(bool),
+	Token:         envGet("SERVER_TOKEN", "").(string),
+}
+
+var mcpServerOptions = server.McpServerOptions{
+	Name:    envGet("MCP_SERVER_NAME", "tools").(string),
+	Listen:  envGet("MCP_SERVER_LISTEN", "").(string),
+	Path:    envGet("MCP_SERVER_PATH", server.McpServerDefaultPath).(string),
+	Timeout: envGet("MCP_SERVER_TIMEOUT", 60).(int),
+}
+
+type EmulateServerOptions struct {
+	Listen string
+	Faults []string
+	Data   string
+}
+
+var emulateServerOptions = EmulateServerOptions{
+	Listen: envGet("EMULATE_SERVER_LISTEN", ":8080").(string),
+	Faults: strings.Split(envGet("EMULATE_SERVER_FAULTS", "").(string), ","),
+	Data:   envGet("EMULATE_SERVER_DATA", "").(string),
+}
+
+// emulateServerNew creates emulator with preloaded data and faults, data is file or content
+func emulateServerNew(vendor string) (*vendortest.Emulator, error) {
+
+	e, err := vendortest.NewEmulator(vendor)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.IsEmpty(emulateServerOptions.Data) {
+		data, err := utils.Content(emulateServerOptions.Data)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.Load(data); err != nil {
+			return nil, fmt.Errorf("invalid emulator data: %v", err)
+		}
+	}
+	for _, s := range common.RemoveEmptyStrings(emulateServerOptions.Faults) {
+		f, err := vendortest.ParseFault(s)
+		if err != nil {
+			return nil, err
+		}
+		e.AddFault(f)
+	}
+	return e, nil
+}
+
+// serverCallsInit applies profile to options of all vendors, servers call vendors with them
+func serverCallsInit(cmd *cobra.Command) server.CallOptions {
+
+	if err := configInitAll(cmd.Root()); err != nil {
+		stdout.Panic(err)
+	}
+	// vendor options have credentials, so they aren't logged
+	common.AddSensitive(serverCallOptions.Token)
+	common.Debug("ServerCall", serverCallOptions, stdout)
+	options := serverCallOptions
+	options.Vendors = vendorsOptions
+	return options
+}
+
+func httpServerNew(cmd *cobra.Command, stdout *common.Stdout) *server.HttpServer {
 
 	common.Debug("HttpServer", httpServerOptions, stdout)
+	httpServerOptions.Calls = serverCallsInit(cmd)
 	return server.NewHttpServer(httpServerOptions, stdout)
 }
 
+func mcpServerNew(cmd *cobra.Command, stdout *common.Stdout) *server.McpServer {
+
+	mcpServerOptions.Version = version
+	common.Debug("McpServer", mcpServerOptions, stdout)
+	mcpServerOptions.Calls = serverCallsInit(cmd)
+	return server.NewMcpServer(mcpServerOptions, stdout)
+}
+
 func NewServerCommand(wg *sync.WaitGroup) *cobra.Command {
 
 	serverCmd := &cobra.Command{
 		Use:   "server",
 		Short: "Server tools",
 	}
+	flags := serverCmd.PersistentFlags()
+	flags.StringSliceVar(&serverCallOptions.Allow, "server-allow", serverCallOptions.Allow, "Server allowed template functions and vendor operations as <vendor>.<operation>, globs, e.g. jira.*,getFileContent, http server allows all and mcp server publishes nothing if empty")
+	flags.StringVar(&serverCallOptions.Token, "server-token", serverCallOptions.Token, "Server bearer token of HTTP callers, calls are not authenticated if empty")
+	flags.BoolVar(&serverCallOptions.CallerOptions, "server-caller-options", serverCallOptions.CallerOptions, "Server allows callers to pass vendor options, otherwise options of profile are used")
 
 	httpServerCmd := &cobra.Command{
 		Use:   "http",
 		Short: "Run HTTP Server",
+		// every request has own span
+		Annotations: map[string]string{tracingAnnotationDisabled: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
 
 			stdout.Debug("Running http server...")
-			httpServerNew(stdout).Start(wg)
+			httpServerNew(cmd, stdout).Start(wg)
 			wg.Wait()
 		},
 	}
-	flags := httpServerCmd.PersistentFlags()
+	flags = httpServerCmd.PersistentFlags()
 	flags.StringVar(&httpServerOptions.ServerName, "http-server-name", httpServerOptions.ServerName, "Http server name")
 	flags.StringVar(&httpServerOptions.Listen, "http-server-listen", httpServerOptions.Listen, "Http server listen")
 	flags.BoolVar(&httpServerOptions.Tls, "http-server-tls", httpServerOptions.Tls, "Http server TLS")
@@ -59,5 +143,64 @@ func NewServerCommand(wg *sync.WaitGroup) *cobra.Command {
 
 	serverCmd.AddCommand(httpServerCmd)
 
+	mcpServerCmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Run Model Context Protocol server over stdio or streamable HTTP",
+		// every tool call has own span
+		Annotations: map[string]string{tracingAnnotationDisabled: "true"},
+		Run: func(cmd *cobra.Command, args []string) {
+
+			// stdout is protocol stream for stdio transport, so logs go to stderr
+			if utils.IsEmpty(mcpServerOptions.Listen) && (utils.IsEmpty(stdoutOptions.Output) || stdoutOptions.Output == common.StdoutOutputStdout) {
+				stdoutOptions.Output = common.StdoutOutputStderr
+				stdout = common.NewStdout(stdoutOptions)
+				stdout.SetCallerOffset(1)
+			}
+
+			stdout.Debug("Running mcp server...")
+			mcpServerNew(cmd, stdout).Start(wg)
+			wg.Wait()
+		},
+	}
+	flags = mcpServerCmd.PersistentFlags()
+	flags.StringVar(&mcpServerOptions.Name, "mcp-server-name", mcpServerOptions.Name, "MCP server name")
+	flags.StringVar(&mcpServerOptions.Listen, "mcp-server-listen", mcpServerOptions.Listen, "MCP server streamable HTTP listen, stdio is used if empty")
+	flags.StringVar(&mcpServerOptions.Path, "mcp-server-path", mcpServerOptions.Path, "MCP server streamable HTTP path")
+	flags.IntVar(&mcpServerOptions.Timeout, "mcp-server-timeout", mcpServerOptions.Timeout, "MCP server tool call timeout in seconds")
+
+	serverCmd.AddCommand(mcpServerCmd)
+
+	emulateServerCmd := &cobra.Command{
+		Use:       "emulate <vendor>",
+		Short:     "Run vendor API emulator for offline testing",
+		Long:      fmt.Sprintf("Run stateful vendor API emulator, one of: %s.\nObjects, faults and requests are managed via %s.", strings.Join(vendortest.Vendors(), ", "), vendortest.AdminPath),
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: vendortest.Vendors(),
+		// long running as http server
+		Annotations: map[string]string{tracingAnnotationDisabled: "true"},
+		Run: func(cmd *cobra.Command, args []string) {
+
+			e, err := emulateServerNew(args[0])
+			if err != nil {
+				stdout.Panic(err)
+			}
+			stdout.Info("Emulating %s on %s, point vendor URL to it, admin API is %s", e.Vendor(), emulateServerOptions.Listen, vendortest.AdminPath)
+			if err := http.ListenAndServe(emulateServerOptions.Listen, e); err != nil {
+				stdout.Panic(err)
+			}
+		},
+	}
+	flags = emulateServerCmd.PersistentFlags()
+	flags.StringVar(&emulateServerOptions.Listen, "emulate-listen", emulateServerOptions.Listen, "Emulator listen")
+	flags.StringSliceVar(&emulateServerOptions.Faults, "emulate-faults", emulateServerOptions.Faults, "Emulator faults: [METHOD ]/path=status[xTimes][@retryAfter]")
+	flags.StringVar(&emulateServerOptions.Data, "emulate-data", emulateServerOptions.Data, "Emulator JSON data file or content: {\"kind\": {\"id\": {...}}} or {\"kind\": [{...}]}")
+	configEnvFlags(flags, map[string]string{
+		"emulate-listen": "EMULATE_SERVER_LISTEN",
+		"emulate-faults": "EMULATE_SERVER_FAULTS",
+		"emulate-data":   "EMULATE_SERVER_DATA",
+	})
+
+	serverCmd.AddCommand(emulateServerCmd)
+
 	return serverCmd
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/devopsext/tools/common"
@@ -38,6 +39,8 @@ var site24x7LogReportOptions = vendors.Site24x7LogReportOptions{
 }
 
 var site24x7Options = vendors.Site24x7Options{
+	URL:          envGet("SITE24X7_URL", "").(string),
+	AuthURL:      envGet("SITE24X7_AUTH_URL", "").(string),
 	Timeout:      envGet("SITE24X7_TIMEOUT", 30).(int),
 	Insecure:     envGet("SITE24X7_INSECURE", false).(bool),
 	ClientID:     envGet("SITE24X7_CLIENT_ID", "").(string),
@@ -47,8 +50,11 @@ var site24x7Options = vendors.Site24x7Options{
 }
 
 var site24x7Output = common.OutputOptions{
-	Output: envGet("SITE24X7_OUTPUT", "").(string),
-	Query:  envGet("SITE24X7_OUTPUT_QUERY", "").(string),
+	Output:   envGet("SITE24X7_OUTPUT", "").(string),
+	Query:    envGet("SITE24X7_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("SITE24X7_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("SITE24X7_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("SITE24X7_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func site24x7New(stdout *common.Stdout) *vendors.Site24x7 {
@@ -70,6 +76,8 @@ func NewSite24x7Command() *cobra.Command {
 		Short: "Site24x7 tools",
 	}
 	flags := site24x7Cmd.PersistentFlags()
+	flags.StringVar(&site24x7Options.URL, "site24x7-url", site24x7Options.URL, "Site24x7 URL, www.site24x7.com by default")
+	flags.StringVar(&site24x7Options.AuthURL, "site24x7-auth-url", site24x7Options.AuthURL, "Site24x7 Zoho accounts URL, accounts.zoho.com by default")
 	flags.IntVar(&site24x7Options.Timeout, "site24x7-timeout", site24x7Options.Timeout, "Site24x7 timeout in seconds")
 	flags.BoolVar(&site24x7Options.Insecure, "site24x7-insecure", site24x7Options.Insecure, "Site24x7 insecure")
 	flags.StringVar(&site24x7Options.ClientID, "site24x7-client-id", site24x7Options.ClientID, "Site24x7 client ID")
@@ -78,6 +86,9 @@ func NewSite24x7Command() *cobra.Command {
 	flags.StringVar(&site24x7Options.AccessToken, "site24x7-access-token", site24x7Options.AccessToken, "Site24x7 access token")
 	flags.StringVar(&site24x7Output.Output, "site24x7-output", site24x7Output.Output, "Site24x7 output")
 	flags.StringVar(&site24x7Output.Query, "site24x7-output-query", site24x7Output.Query, "Site24x7 output query")
+	flags.StringSliceVar(&site24x7Output.QueryLib, "site24x7-output-query-lib", site24x7Output.QueryLib, "Site24x7 output query libraries")
+	flags.StringVar(&site24x7Output.Format, "site24x7-output-format", site24x7Output.Format, "Site24x7 output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&site24x7Output.Columns, "site24x7-output-columns", site24x7Output.Columns, "Site24x7 output columns")
 
 	site24x7CreateMonitorCmd := &cobra.Command{
 		Use:   "create-website-monitor",
@@ -89,7 +100,7 @@ func NewSite24x7Command() *cobra.Command {
 
 			bytes, err := site24x7New(stdout).CreateWebsiteMonitor(site24x7WebsiteMonitorOptions)
 			if err != nil {
-				stdout.Error("Error: %v %s", err, string(bytes))
+				commandError(fmt.Errorf("%w %s", err, string(bytes)))
 				return
 			}
 			common.OutputJson(site24x7Output, "site24x7", []interface{}{site24x7Options, site24x7WebsiteMonitorOptions}, bytes, stdout)
@@ -107,6 +118,11 @@ func NewSite24x7Command() *cobra.Command {
 	flags.StringSliceVar(&site24x7WebsiteMonitorOptions.UserGroupIDs, "site24x7-website-monitor-user-group-ids", site24x7WebsiteMonitorOptions.UserGroupIDs, "Site24x7 website monitor user group ids")
 	flags.StringVar(&site24x7WebsiteMonitorOptions.NotificationProfileID, "site24x7-website-monitor-notification-profile-id", site24x7WebsiteMonitorOptions.NotificationProfileID, "Site24x7 website monitor notification profile id")
 	flags.StringVar(&site24x7WebsiteMonitorOptions.ThresholdProfileID, "site24x7-website-monitor-threshold-profile-id", site24x7WebsiteMonitorOptions.ThresholdProfileID, "Site24x7 website monitor threshold profile id")
+	configEnvFlags(flags, map[string]string{
+		"site24x7-website-monitor-frequency":               "SITE24X7_WEBSITE_MONITOR_FERQUENCY",
+		"site24x7-website-monitor-notification-profile-id": "SITE24X7_WEBSITE_MONITOR_USER_AGENT",
+		"site24x7-website-monitor-threshold-profile-id":    "",
+	})
 	site24x7Cmd.AddCommand(site24x7CreateMonitorCmd)
 
 	site24x7DeleteMonitorCmd := &cobra.Command{
@@ -119,7 +135,7 @@ func NewSite24x7Command() *cobra.Command {
 
 			bytes, err := site24x7New(stdout).DeleteMonitor(site24x7MonitorOptions)
 			if err != nil {
-				stdout.Error("Error: %v %s", err, string(bytes))
+				commandError(fmt.Errorf("%w %s", err, string(bytes)))
 				return
 			}
 			common.OutputJson(site24x7Output, "site24x7", []interface{}{site24x7Options, site24x7MonitorOptions}, bytes, stdout)
@@ -139,7 +155,7 @@ func NewSite24x7Command() *cobra.Command {
 
 			bytes, err := site24x7New(stdout).PollMonitor(site24x7MonitorOptions)
 			if err != nil {
-				stdout.Error("Error: %v %s", err, string(bytes))
+				commandError(fmt.Errorf("%w %s", err, string(bytes)))
 				return
 			}
 			common.OutputJson(site24x7Output, "site24x7", []interface{}{site24x7Options, site24x7MonitorOptions}, bytes, stdout)
@@ -159,7 +175,7 @@ func NewSite24x7Command() *cobra.Command {
 
 			bytes, err := site24x7New(stdout).GetPollingStatus(site24x7MonitorOptions)
 			if err != nil {
-				stdout.Error("Error: %v %s", err, string(bytes))
+				commandError(fmt.Errorf("%w %s", err, string(bytes)))
 				return
 			}
 			common.OutputJson(site24x7Output, "site24x7", []interface{}{site24x7Options, site24x7MonitorOptions}, bytes, stdout)
@@ -179,7 +195,7 @@ func NewSite24x7Command() *cobra.Command {
 
 			bytes, err := site24x7New(stdout).GetLogReport(site24x7LogReportOptions)
 			if err != nil {
-				stdout.Error("Error: %v %s", err, string(bytes))
+				commandError(fmt.Errorf("%w %s", err, string(bytes)))
 				return
 			}
 			common.OutputJson(site24x7Output, "site24x7", []interface{}{site24x7Options, site24x7LogReportOptions}, bytes, stdout)
@@ -201,7 +217,7 @@ func NewSite24x7Command() *cobra.Command {
 
 			bytes, err := site24x7New(stdout).DeleteLocationProfile(site24x7LocationProfileOptions)
 			if err != nil {
-				stdout.Error("Error: %v %s", err, string(bytes))
+				commandError(fmt.Errorf("%w %s", err, string(bytes)))
 				return
 			}
 			common.OutputJson(site24x7Output, "site24x7", []interface{}{site24x7Options, site24x7LocationProfileOptions}, bytes, stdout)
@@ -1,15 +1,19 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/devopsext/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var slackOptions = vendors.SlackOptions{
+	URL:      envGet("SLACK_URL", "").(string),
 	Timeout:  envGet("SLACK_TIMEOUT", 30).(int),
 	Insecure: envGet("SLACK_INSECURE", false).(bool),
 	Token:    envGet("SLACK_TOKEN", "").(string),
@@ -22,6 +26,7 @@ var slackMessageOptions = vendors.SlackMessageOptions{
 	Text:        envGet("SLACK_TEXT", "").(string),
 	Attachments: envGet("SLACK_ATTACHMENTS", "").(string),
 	Blocks:      envGet("SLACK_BLOCKS", "").(string),
+	Format:      envGet("SLACK_FORMAT", "").(string),
 }
 
 var slackFileOptions = vendors.SlackFileOptions{
@@ -34,6 +39,40 @@ var slackFileOptions = vendors.SlackFileOptions{
 	Type:    envGet("SLACK_TYPE", "auto").(string),
 }
 
+// slackMessage is response of previous message command, its channel and ts are used when flags are not set
+var slackMessage = envGet("SLACK_MESSAGE", "").(string)
+
+var slackUpdateMessageOptions = vendors.SlackUpdateMessageOptions{
+	SlackMessageRef: vendors.SlackMessageRef{
+		Channel: envGet("SLACK_CHANNEL", "").(string),
+		TS:      envGet("SLACK_TS", "").(string),
+	},
+	Text:        envGet("SLACK_TEXT", "").(string),
+	Attachments: envGet("SLACK_ATTACHMENTS", "").(string),
+	Blocks:      envGet("SLACK_BLOCKS", "").(string),
+	Format:      envGet("SLACK_FORMAT", "").(string),
+}
+
+var slackMessageRef = vendors.SlackMessageRef{
+	Channel: envGet("SLACK_CHANNEL", "").(string),
+	TS:      envGet("SLACK_TS", "").(string),
+}
+
+var slackScheduleMessageOptions = vendors.SlackScheduleMessageOptions{
+	SlackMessageOptions: slackMessageOptions,
+	PostAt:              envGet("SLACK_POST_AT", "").(string),
+}
+
+var slackScheduledMessageOptions = vendors.SlackScheduledMessageOptions{
+	Channel:            envGet("SLACK_CHANNEL", "").(string),
+	ScheduledMessageID: envGet("SLACK_SCHEDULED_MESSAGE_ID", "").(string),
+}
+
+var slackEphemeralOptions = vendors.SlackEphemeralOptions{
+	SlackMessageOptions: slackMessageOptions,
+	User:                envGet("SLACK_USER", "").(string),
+}
+
 var slackReactionOptions = vendors.SlackReactionOptions{
 	Name: envGet("SLACK_REACTION_NAME", "").(string),
 }
@@ -48,8 +87,11 @@ var slackUsergroupUsers = vendors.SlackUsergroupUsers{
 }
 
 var slackOutput = common.OutputOptions{
-	Output: envGet("SLACK_OUTPUT", "").(string),
-	Query:  envGet("SLACK_OUTPUT_QUERY", "").(string),
+	Output:   envGet("SLACK_OUTPUT", "").(string),
+	Query:    envGet("SLACK_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("SLACK_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("SLACK_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("SLACK_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func slackNew(stdout *common.Stdout) *vendors.Slack {
@@ -60,6 +102,56 @@ func slackNew(stdout *common.Stdout) *vendors.Slack {
 	return vendors.NewSlack(slackOptions)
 }
 
+// slackMessageFrom returns channel, ts and scheduled message id of --slack-message
+func slackMessageFrom() (vendors.SlackMessageRef, string, error) {
+
+	if utils.IsEmpty(slackMessage) {
+		return vendors.SlackMessageRef{}, "", nil
+	}
+	b, err := utils.Content(slackMessage)
+	if err != nil {
+		return vendors.SlackMessageRef{}, "", err
+	}
+	ref, err := vendors.SlackMessageRefFrom(b)
+	if err != nil {
+		return ref, "", fmt.Errorf("invalid slack message: %v", err)
+	}
+	var scheduled struct {
+		ID string `json:"scheduled_message_id"`
+	}
+	_ = json.Unmarshal(b, &scheduled)
+	return ref, scheduled.ID, nil
+}
+
+func slackValue(value *string, def string) {
+
+	if utils.IsEmpty(*value) {
+		*value = def
+	}
+}
+
+// slackContent replaces file or content of value by its content
+func slackContent(value *string) error {
+
+	b, err := utils.Content(*value)
+	if err != nil {
+		return err
+	}
+	*value = string(b)
+	return nil
+}
+
+// slackMessageFlags adds flags of new message, they are shared by scheduled and ephemeral messages
+func slackMessageFlags(flags *pflag.FlagSet, options *vendors.SlackMessageOptions) {
+
+	flags.StringVar(&options.Channel, "slack-channel", options.Channel, "Slack channel")
+	flags.StringVar(&options.Thread, "slack-thread", options.Thread, "Slack thread")
+	flags.StringVar(&options.Text, "slack-text", options.Text, "Slack text")
+	flags.StringVar(&options.Attachments, "slack-attachments", options.Attachments, "Slack attachments json")
+	flags.StringVar(&options.Blocks, "slack-blocks", options.Blocks, "Slack blocks json")
+	flags.StringVar(&options.Format, "slack-format", options.Format, "Slack text format: markdown")
+}
+
 func NewSlackCommand() *cobra.Command {
 
 	slackCmd := &cobra.Command{
@@ -68,11 +160,15 @@ func NewSlackCommand() *cobra.Command {
 	}
 
 	flags := slackCmd.PersistentFlags()
+	flags.StringVar(&slackOptions.URL, "slack-url", slackOptions.URL, "Slack URL, slack.com by default")
 	flags.IntVar(&slackOptions.Timeout, "slack-timeout", slackOptions.Timeout, "Slack timeout")
 	flags.BoolVar(&slackOptions.Insecure, "slack-insecure", slackOptions.Insecure, "Slack insecure")
 	flags.StringVar(&slackOptions.Token, "slack-token", slackOptions.Token, "Slack token")
 	flags.StringVar(&slackOutput.Output, "slack-output", slackOutput.Output, "Slack output")
 	flags.StringVar(&slackOutput.Query, "slack-output-query", slackOutput.Query, "Slack output query")
+	flags.StringSliceVar(&slackOutput.QueryLib, "slack-output-query-lib", slackOutput.QueryLib, "Slack output query libraries")
+	flags.StringVar(&slackOutput.Format, "slack-output-format", slackOutput.Format, "Slack output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&slackOutput.Columns, "slack-output-columns", slackOutput.Columns, "Slack output columns")
 
 	sendMessage := &cobra.Command{
 		Use:   "send-message",
@@ -90,7 +186,7 @@ func NewSlackCommand() *cobra.Command {
 
 			bytes, err := slackNew(stdout).SendMessage(slackMessageOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(slackOutput, "Slack", []interface{}{slackOptions, slackMessageOptions}, bytes, stdout)
@@ -103,6 +199,7 @@ func NewSlackCommand() *cobra.Command {
 	flags.StringVar(&slackMessageOptions.Text, "slack-text", slackMessageOptions.Text, "Slack text")
 	flags.StringVar(&slackMessageOptions.Attachments, "slack-attachments", slackMessageOptions.Attachments, "Slack attachments json")
 	flags.StringVar(&slackMessageOptions.Blocks, "slack-blocks", slackMessageOptions.Blocks, "Slack blocks json")
+	flags.StringVar(&slackMessageOptions.Format, "slack-format", slackMessageOptions.Format, "Slack text format: markdown")
 	slackCmd.AddCommand(sendMessage)
 
 	sendFile := &cobra.Command{
@@ -127,7 +224,7 @@ func NewSlackCommand() *cobra.Command {
 
 			bytes, err := slackNew(stdout).SendFile(slackFileOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(slackOutput, "Slack", []interface{}{slackOptions, slackFileOptions}, bytes, stdout)
@@ -153,7 +250,7 @@ func NewSlackCommand() *cobra.Command {
 
 			bytes, err := slackNew(stdout).AddReaction(slackReactionOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(slackOutput, "Slack", []interface{}{slackOptions, slackReactionOptions}, bytes, stdout)
@@ -172,7 +269,7 @@ func NewSlackCommand() *cobra.Command {
 
 			bytes, err := slackNew(stdout).GetUser(slackUserEmail)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(slackOutput, "Slack", []interface{}{slackOptions, slackUserEmail}, bytes, stdout)
@@ -182,6 +279,202 @@ func NewSlackCommand() *cobra.Command {
 	flags.StringVar(&slackUserEmail.Email, "slack-user-email", slackUserEmail.Email, "Slack user email")
 	slackCmd.AddCommand(lookupByEmailCmd)
 
+	updateMessageCmd := &cobra.Command{
+		Use:   "update-message",
+		Short: "Update message",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Slack updating message...")
+
+			ref, _, err := slackMessageFrom()
+			if err != nil {
+				commandError(err)
+				return
+			}
+			slackValue(&slackUpdateMessageOptions.Channel, ref.Channel)
+			slackValue(&slackUpdateMessageOptions.TS, ref.TS)
+			if err := slackContent(&slackUpdateMessageOptions.Text); err != nil {
+				commandError(err)
+				return
+			}
+			common.Debug("Slack", slackUpdateMessageOptions, stdout)
+
+			bytes, err := slackNew(stdout).UpdateMessage(slackUpdateMessageOptions)
+			if err != nil {
+				commandError(err)
+				return
+			}
+			common.OutputJson(slackOutput, "Slack", []interface{}{slackOptions, slackUpdateMessageOptions}, bytes, stdout)
+		},
+	}
+	flags = updateMessageCmd.PersistentFlags()
+	flags.StringVar(&slackUpdateMessageOptions.Channel, "slack-channel", slackUpdateMessageOptions.Channel, "Slack channel")
+	flags.StringVar(&slackUpdateMessageOptions.TS, "slack-ts", slackUpdateMessageOptions.TS, "Slack message ts")
+	flags.StringVar(&slackUpdateMessageOptions.Text, "slack-text", slackUpdateMessageOptions.Text, "Slack text")
+	flags.StringVar(&slackUpdateMessageOptions.Attachments, "slack-attachments", slackUpdateMessageOptions.Attachments, "Slack attachments json")
+	flags.StringVar(&slackUpdateMessageOptions.Blocks, "slack-blocks", slackUpdateMessageOptions.Blocks, "Slack blocks json")
+	flags.StringVar(&slackUpdateMessageOptions.Format, "slack-format", slackUpdateMessageOptions.Format, "Slack text format: markdown")
+	flags.StringVar(&slackMessage, "slack-message", slackMessage, "Slack message json of previous command, its channel and ts are used")
+	slackCmd.AddCommand(updateMessageCmd)
+
+	deleteMessageCmd := &cobra.Command{
+		Use:   "delete-message",
+		Short: "Delete message",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Slack deleting message...")
+
+			ref, _, err := slackMessageFrom()
+			if err != nil {
+				commandError(err)
+				return
+			}
+			slackValue(&slackMessageRef.Channel, ref.Channel)
+			slackValue(&slackMessageRef.TS, ref.TS)
+			common.Debug("Slack", slackMessageRef, stdout)
+
+			bytes, err := slackNew(stdout).DeleteMessage(slackMessageRef)
+			if err != nil {
+				commandError(err)
+				return
+			}
+			common.OutputJson(slackOutput, "Slack", []interface{}{slackOptions, slackMessageRef}, bytes, stdout)
+		},
+	}
+	flags = deleteMessageCmd.PersistentFlags()
+	flags.StringVar(&slackMessageRef.Channel, "slack-channel", slackMessageRef.Channel, "Slack channel")
+	flags.StringVar(&slackMessageRef.TS, "slack-ts", slackMessageRef.TS, "Slack message ts")
+	flags.StringVar(&slackMessage, "slack-message", slackMessage, "Slack message json of previous command, its channel and ts are used")
+	slackCmd.AddCommand(deleteMessageCmd)
+
+	getPermalinkCmd := &cobra.Command{
+		Use:   "get-permalink",
+		Short: "Get message permalink",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Slack getting permalink...")
+
+			ref, _, err := slackMessageFrom()
+			if err != nil {
+				commandError(err)
+				return
+			}
+			slackValue(&slackMessageRef.Channel, ref.Channel)
+			slackValue(&slackMessageRef.TS, ref.TS)
+			common.Debug("Slack", slackMessageRef, stdout)
+
+			bytes, err := slackNew(stdout).GetPermalink(slackMessageRef)
+			if err != nil {
+				commandError(err)
+				return
+			}
+			common.OutputJson(slackOutput, "Slack", []interface{}{slackOptions, slackMessageRef}, bytes, stdout)
+		},
+	}
+	flags = getPermalinkCmd.PersistentFlags()
+	flags.StringVar(&slackMessageRef.Channel, "slack-channel", slackMessageRef.Channel, "Slack channel")
+	flags.StringVar(&slackMessageRef.TS, "slack-ts", slackMessageRef.TS, "Slack message ts")
+	flags.StringVar(&slackMessage, "slack-message", slackMessage, "Slack message json of previous command, its channel and ts are used")
+	slackCmd.AddCommand(getPermalinkCmd)
+
+	scheduleMessageCmd := &cobra.Command{
+		Use:   "schedule-message",
+		Short: "Schedule message",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Slack scheduling message...")
+
+			ref, _, err := slackMessageFrom()
+			if err != nil {
+				commandError(err)
+				return
+			}
+			slackValue(&slackScheduleMessageOptions.Channel, ref.Channel)
+			slackValue(&slackScheduleMessageOptions.Thread, ref.TS)
+			if err := slackContent(&slackScheduleMessageOptions.Text); err != nil {
+				commandError(err)
+				return
+			}
+			common.Debug("Slack", slackScheduleMessageOptions, stdout)
+
+			bytes, err := slackNew(stdout).ScheduleMessage(slackScheduleMessageOptions)
+			if err != nil {
+				commandError(err)
+				return
+			}
+			common.OutputJson(slackOutput, "Slack", []interface{}{slackOptions, slackScheduleMessageOptions}, bytes, stdout)
+		},
+	}
+	flags = scheduleMessageCmd.PersistentFlags()
+	slackMessageFlags(flags, &slackScheduleMessageOptions.SlackMessageOptions)
+	flags.StringVar(&slackScheduleMessageOptions.PostAt, "slack-post-at", slackScheduleMessageOptions.PostAt, "Slack post at: unix time, RFC3339 time or duration from now, e.g. 30m")
+	flags.StringVar(&slackMessage, "slack-message", slackMessage, "Slack message json of previous command, its channel and ts as thread are used")
+	slackCmd.AddCommand(scheduleMessageCmd)
+
+	deleteScheduledMessageCmd := &cobra.Command{
+		Use:   "delete-scheduled-message",
+		Short: "Delete scheduled message",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Slack deleting scheduled message...")
+
+			ref, id, err := slackMessageFrom()
+			if err != nil {
+				commandError(err)
+				return
+			}
+			slackValue(&slackScheduledMessageOptions.Channel, ref.Channel)
+			slackValue(&slackScheduledMessageOptions.ScheduledMessageID, id)
+			common.Debug("Slack", slackScheduledMessageOptions, stdout)
+
+			bytes, err := slackNew(stdout).DeleteScheduledMessage(slackScheduledMessageOptions)
+			if err != nil {
+				commandError(err)
+				return
+			}
+			common.OutputJson(slackOutput, "Slack", []interface{}{slackOptions, slackScheduledMessageOptions}, bytes, stdout)
+		},
+	}
+	flags = deleteScheduledMessageCmd.PersistentFlags()
+	flags.StringVar(&slackScheduledMessageOptions.Channel, "slack-channel", slackScheduledMessageOptions.Channel, "Slack channel")
+	flags.StringVar(&slackScheduledMessageOptions.ScheduledMessageID, "slack-scheduled-message-id", slackScheduledMessageOptions.ScheduledMessageID, "Slack scheduled message id")
+	flags.StringVar(&slackMessage, "slack-message", slackMessage, "Slack message json of schedule-message, its channel and scheduled message id are used")
+	slackCmd.AddCommand(deleteScheduledMessageCmd)
+
+	sendEphemeralCmd := &cobra.Command{
+		Use:   "send-ephemeral",
+		Short: "Send message visible to user only",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Slack sending ephemeral message...")
+
+			ref, _, err := slackMessageFrom()
+			if err != nil {
+				commandError(err)
+				return
+			}
+			slackValue(&slackEphemeralOptions.Channel, ref.Channel)
+			slackValue(&slackEphemeralOptions.Thread, ref.TS)
+			if err := slackContent(&slackEphemeralOptions.Text); err != nil {
+				commandError(err)
+				return
+			}
+			common.Debug("Slack", slackEphemeralOptions, stdout)
+
+			bytes, err := slackNew(stdout).SendEphemeral(slackEphemeralOptions)
+			if err != nil {
+				commandError(err)
+				return
+			}
+			common.OutputJson(slackOutput, "Slack", []interface{}{slackOptions, slackEphemeralOptions}, bytes, stdout)
+		},
+	}
+	flags = sendEphemeralCmd.PersistentFlags()
+	slackMessageFlags(flags, &slackEphemeralOptions.SlackMessageOptions)
+	flags.StringVar(&slackEphemeralOptions.User, "slack-user", slackEphemeralOptions.User, "Slack user id")
+	flags.StringVar(&slackMessage, "slack-message", slackMessage, "Slack message json of previous command, its channel and ts as thread are used")
+	slackCmd.AddCommand(sendEphemeralCmd)
+
 	usergroupUpdateCmd := &cobra.Command{
 		Use:   "usergroup-update",
 		Short: "Usergroup update",
@@ -191,7 +484,7 @@ func NewSlackCommand() *cobra.Command {
 
 			bytes, err := slackNew(stdout).UpdateUsergroup(slackUsergroupUsers)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(slackOutput, "Slack", []interface{}{slackOptions, slackUsergroupUsers}, bytes, stdout)
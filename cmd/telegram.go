@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"path/filepath"
+	"strings"
 
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
@@ -10,6 +11,7 @@ import (
 )
 
 var telegramOptions = vendors.TelegramOptions{
+	URL:                   envGet("TELEGRAM_URL", "").(string),
 	IDToken:               envGet("TELEGRAM_ID_TOKEN", "").(string),
 	ChatID:                envGet("TELEGRAM_CHAT_ID", "").(string),
 	Insecure:              envGet("TELEGRAM_INSECURE", false).(bool),
@@ -20,7 +22,8 @@ var telegramOptions = vendors.TelegramOptions{
 }
 
 var telegramMessageOptions = vendors.TelegramMessageOptions{
-	Text: envGet("TELEGRAM_MESSAGE_TEXT", "").(string),
+	Text:   envGet("TELEGRAM_MESSAGE_TEXT", "").(string),
+	Format: envGet("TELEGRAM_MESSAGE_FORMAT", "").(string),
 }
 
 var telegramPhotoOptions = vendors.TelegramPhotoOptions{
@@ -36,8 +39,11 @@ var telegramDocumentOptions = vendors.TelegramDocumentOptions{
 }
 
 var telegramOutput = common.OutputOptions{
-	Output: envGet("TELEGRAM_OUTPUT", "").(string),
-	Query:  envGet("TELEGRAM_OUTPUT_QUERY", "").(string),
+	Output:   envGet("TELEGRAM_OUTPUT", "").(string),
+	Query:    envGet("TELEGRAM_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("TELEGRAM_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("TELEGRAM_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("TELEGRAM_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func telegramNew(stdout *common.Stdout) *vendors.Telegram {
@@ -58,6 +64,7 @@ func NewTelegramCommand() *cobra.Command {
 	flags := telegramCmd.PersistentFlags()
 	flags.StringVar(&telegramOptions.IDToken, "telegram-id-token", telegramOptions.IDToken, "Telegram bot ID token")
 	flags.StringVar(&telegramOptions.ChatID, "telegram-chat-id", telegramOptions.ChatID, "Telegram chat ID")
+	flags.StringVar(&telegramOptions.URL, "telegram-url", telegramOptions.URL, "Telegram URL, api.telegram.org by default")
 	flags.IntVar(&telegramOptions.Timeout, "telegram-timeout", telegramOptions.Timeout, "Telegram timeout")
 	flags.BoolVar(&telegramOptions.Insecure, "telegram-insecure", telegramOptions.Insecure, "Telegram insecure")
 	flags.BoolVar(&telegramOptions.DisableNotification, "telegram-disable-notification", telegramOptions.DisableNotification, "Telegram disable notification")
@@ -65,6 +72,13 @@ func NewTelegramCommand() *cobra.Command {
 	flags.BoolVar(&telegramOptions.DisableWebPagePreview, "telegram-disable-webpage-preview", telegramOptions.DisableWebPagePreview, "Telegram disable webpage preview")
 	flags.StringVar(&telegramOutput.Output, "telegram-output", telegramOutput.Output, "Telegram output")
 	flags.StringVar(&telegramOutput.Query, "telegram-output-query", telegramOutput.Query, "Telegram output query")
+	flags.StringSliceVar(&telegramOutput.QueryLib, "telegram-output-query-lib", telegramOutput.QueryLib, "Telegram output query libraries")
+	flags.StringVar(&telegramOutput.Format, "telegram-output-format", telegramOutput.Format, "Telegram output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&telegramOutput.Columns, "telegram-output-columns", telegramOutput.Columns, "Telegram output columns")
+	configEnvFlags(flags, map[string]string{
+		"telegram-parse-node":              "TELEGRAM_PARSE_MODE",
+		"telegram-disable-webpage-preview": "TELEGRAM_DISABLE_WEB_PAGE_PREVIEW",
+	})
 
 	sendMessageCmd := &cobra.Command{
 		Use:   "send-message",
@@ -82,7 +96,7 @@ func NewTelegramCommand() *cobra.Command {
 
 			bytes, err := telegramNew(stdout).SendMessage(telegramMessageOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(telegramOutput, "Telegram", []interface{}{telegramOptions, telegramMessageOptions}, bytes, stdout)
@@ -90,6 +104,7 @@ func NewTelegramCommand() *cobra.Command {
 	}
 	flags = sendMessageCmd.PersistentFlags()
 	flags.StringVar(&telegramMessageOptions.Text, "telegram-message-text", telegramMessageOptions.Text, "Telegram message text")
+	flags.StringVar(&telegramMessageOptions.Format, "telegram-message-format", telegramMessageOptions.Format, "Telegram message format: markdown")
 	telegramCmd.AddCommand(sendMessageCmd)
 
 	sendPhotoCmd := &cobra.Command{
@@ -112,7 +127,7 @@ func NewTelegramCommand() *cobra.Command {
 
 			bytes, err := telegramNew(stdout).SendPhoto(telegramPhotoOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(telegramOutput, "Telegram", []interface{}{telegramOptions, telegramPhotoOptions}, bytes, stdout)
@@ -144,7 +159,7 @@ func NewTelegramCommand() *cobra.Command {
 
 			bytes, err := telegramNew(stdout).SendDocument(telegramDocumentOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(telegramOutput, "Telegram", []interface{}{telegramOptions, telegramDocumentOptions}, bytes, stdout)
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/spf13/cobra"
@@ -20,8 +22,11 @@ var teleportOptions = vendors.TeleportOptions{
 }
 
 var teleportOutput = common.OutputOptions{
-	Output: envGet("TELEPORT_OUTPUT", "").(string),
-	Query:  envGet("TELEPORT_OUTPUT_QUERY", "").(string),
+	Output:   envGet("TELEPORT_OUTPUT", "").(string),
+	Query:    envGet("TELEPORT_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("TELEPORT_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("TELEPORT_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("TELEPORT_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func teleportNew(stdout *common.Stdout) *vendors.Teleport {
@@ -43,6 +48,9 @@ func NewTeleportCommand() *cobra.Command {
 	flags.BoolVar(&teleportOptions.Insecure, "teleport-insecure", teleportOptions.Insecure, "Teleport insecure")
 	flags.StringVar(&teleportOutput.Output, "teleport-output", teleportOutput.Output, "Teleport output")
 	flags.StringVar(&teleportOutput.Query, "teleport-output-query", teleportOutput.Query, "Teleport output query")
+	flags.StringSliceVar(&teleportOutput.QueryLib, "teleport-output-query-lib", teleportOutput.QueryLib, "Teleport output query libraries")
+	flags.StringVar(&teleportOutput.Format, "teleport-output-format", teleportOutput.Format, "Teleport output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&teleportOutput.Columns, "teleport-output-columns", teleportOutput.Columns, "Teleport output columns")
 
 	// ping
 	pingCmd := &cobra.Command{
@@ -54,7 +62,7 @@ func NewTeleportCommand() *cobra.Command {
 
 			bytes, err := teleportNew(stdout).CustomPing(teleportOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(teleportOutput, "Teleport", []interface{}{teleportOptions}, bytes, stdout)
@@ -83,7 +91,7 @@ func NewTeleportCommand() *cobra.Command {
 
 			bytes, err := teleportNew(stdout).ResourceList(teleportResourceListOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(teleportOutput, "Teleport", []interface{}{teleportOptions, teleportResourceOptions}, bytes, stdout)
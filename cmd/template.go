@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -8,6 +13,7 @@ import (
 	"github.com/devopsext/tools/render"
 	"github.com/devopsext/utils"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var templateOptions = render.TemplateOptions{
@@ -20,10 +26,24 @@ var templateOptions = render.TemplateOptions{
 }
 
 var templateOutput = common.OutputOptions{
-	Output: envGet("TEMPLATE_OUTPUT", "").(string),
-	Query:  envGet("TEMPLATE_OUTPUT_QUERY", "").(string),
+	Output:   envGet("TEMPLATE_OUTPUT", "").(string),
+	Query:    envGet("TEMPLATE_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("TEMPLATE_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("TEMPLATE_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("TEMPLATE_OUTPUT_COLUMNS", "").(string), ","),
 }
 
+// templatePluginNames are plugins which serve template functions
+var templatePluginNames = strings.Split(envGet("TEMPLATE_PLUGINS", "").(string), ",")
+
+// templateReplHistory is file of REPL history, empty disables it
+var templateReplHistory = envGet("TEMPLATE_REPL_HISTORY", "~/.tools_history").(string)
+
+const templateReplHistoryMax = 1000
+
+// templateExpected is file or content compared with rendered text by test, usually with --replay cassettes
+var templateExpected = envGet("TEMPLATE_EXPECTED", "").(string)
+
 func textTemplateNew(stdout *common.Stdout) *render.TextTemplate {
 
 	common.Debug("Template", templateOutput, stdout)
@@ -70,6 +90,156 @@ func htmlTemplateNew(stdout *common.Stdout) *render.HtmlTemplate {
 	return template
 }
 
+// replHistory keeps REPL lines in memory and appends them to file, file is truncated to the last lines on close
+type replHistory struct {
+	lines []string
+	file  *os.File
+	path  string
+	count int // lines of file
+}
+
+func (h *replHistory) Add(line string) {
+
+	if utils.IsEmpty(strings.TrimSpace(line)) || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == line) {
+		return
+	}
+	h.lines = append(h.lines, line)
+	if len(h.lines) > templateReplHistoryMax {
+		h.lines = h.lines[len(h.lines)-templateReplHistoryMax:]
+	}
+	if h.file != nil {
+		fmt.Fprintln(h.file, line)
+		h.count++
+	}
+}
+
+func (h *replHistory) Len() int {
+	return len(h.lines)
+}
+
+func (h *replHistory) At(idx int) string {
+	return h.lines[len(h.lines)-1-idx]
+}
+
+func (h *replHistory) Close() {
+
+	if h.file == nil {
+		return
+	}
+	h.file.Close()
+	if h.count <= templateReplHistoryMax {
+		return
+	}
+	if err := os.WriteFile(h.path, []byte(strings.Join(h.lines, "\n")+"\n"), 0600); err != nil {
+		stdout.Debug("Template REPL history %s: %v", h.path, err)
+	}
+}
+
+func replHistoryOpen(path string) *replHistory {
+
+	h := &replHistory{}
+	if utils.IsEmpty(path) {
+		return h
+	}
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return h
+		}
+		path = filepath.Join(home, path[2:])
+	}
+
+	if b, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(b), "\n") {
+			if !utils.IsEmpty(line) {
+				h.count++
+			}
+			h.Add(line)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		stdout.Debug("Template REPL history %s: %v", path, err)
+		return h
+	}
+	h.file = f
+	h.path = path
+	return h
+}
+
+func replQuit(line string) bool {
+
+	line = strings.TrimSpace(line)
+	return line == ":quit" || line == ":q" || line == "exit"
+}
+
+// templateRepl reads lines from terminal with history and completion, piped stdin is evaluated line by line
+func templateRepl(repl *render.Repl) error {
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if replQuit(scanner.Text()) {
+				return nil
+			}
+			out, err := repl.Eval(scanner.Text())
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				continue
+			}
+			if !utils.IsEmpty(out) {
+				fmt.Println(out)
+			}
+		}
+		return scanner.Err()
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	history := replHistoryOpen(templateReplHistory)
+	defer history.Close()
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "> ")
+	if w, h, err := term.GetSize(fd); err == nil {
+		t.SetSize(w, h)
+	}
+	t.History = history
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return repl.Complete(line, pos)
+	}
+
+	fmt.Fprintln(t, "Template REPL, :help shows commands, tab completes functions")
+	for {
+		line, err := t.ReadLine()
+		if err == io.EOF || replQuit(line) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		out, err := repl.Eval(line)
+		if err != nil {
+			fmt.Fprintf(t, "%s%v%s\n", t.Escape.Red, err, t.Escape.Reset)
+			continue
+		}
+		if !utils.IsEmpty(out) {
+			fmt.Fprintln(t, out)
+		}
+	}
+}
+
 func NewTemplateCommand() *cobra.Command {
 
 	templateCmd := &cobra.Command{
@@ -83,40 +253,115 @@ func NewTemplateCommand() *cobra.Command {
 	flags.StringVar(&templateOptions.Object, "template-object", templateOptions.Object, "Template object: json")
 	flags.StringVar(&templateOptions.TimeFormat, "template-time-format", templateOptions.TimeFormat, "Template time format")
 	flags.StringVar(&templateOptions.Pattern, "template-pattern", templateOptions.Pattern, "Template pattern")
+	flags.StringSliceVar(&templatePluginNames, "template-plugins", templatePluginNames, "Template plugins with functions: names of tools-<name> on PATH")
 	flags.StringVar(&templateOutput.Output, "template-output", templateOutput.Output, "Template output")
 	flags.StringVar(&templateOutput.Query, "template-output-query", templateOutput.Query, "Template output query")
+	flags.StringSliceVar(&templateOutput.QueryLib, "template-output-query-lib", templateOutput.QueryLib, "Template output query libraries")
+	flags.StringVar(&templateOutput.Format, "template-output-format", templateOutput.Format, "Template output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&templateOutput.Columns, "template-output-columns", templateOutput.Columns, "Template output columns")
 
 	templateCmd.AddCommand(&cobra.Command{
 		Use:   "render-text",
 		Short: "Render text",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			stdout.Debug("Template text rendering...")
 
+			defer templatePluginsClose()
+			if err := templatePluginsStart(cmd); err != nil {
+				return err
+			}
+
 			bytes, err := textTemplateNew(stdout).Render()
 			if err != nil {
-				stdout.Error(err)
-				return
+				return err
 			}
 			common.OutputJson(templateOutput, "template", []interface{}{templateOptions}, bytes, stdout)
+			return nil
 		},
 	})
 
 	templateCmd.AddCommand(&cobra.Command{
 		Use:   "render-html",
 		Short: "Render html",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			stdout.Debug("Template html rendering...")
 
+			defer templatePluginsClose()
+			if err := templatePluginsStart(cmd); err != nil {
+				return err
+			}
+
 			bytes, err := htmlTemplateNew(stdout).Render()
 			if err != nil {
-				stdout.Error(err)
-				return
+				return err
 			}
 			common.OutputJson(templateOutput, "template", []interface{}{templateOptions}, bytes, stdout)
+			return nil
 		},
 	})
 
+	testCmd := &cobra.Command{
+		Use:   "test",
+		Short: "Render text and compare it with expected",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			stdout.Debug("Template testing...")
+
+			expected, err := utils.Content(templateExpected)
+			if err != nil {
+				return err
+			}
+			defer templatePluginsClose()
+			if err := templatePluginsStart(cmd); err != nil {
+				return err
+			}
+
+			bytes, err := textTemplateNew(stdout).Render()
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(string(bytes)) != strings.TrimSpace(string(expected)) {
+				return fmt.Errorf("template %s output differs from expected:\n%s", templateOptions.Name, string(bytes))
+			}
+			stdout.Info("Template %s output matches expected", templateOptions.Name)
+			return nil
+		},
+	}
+	testCmd.Flags().StringVar(&templateExpected, "template-expected", templateExpected, "Template expected output: file or content")
+	templateCmd.AddCommand(testCmd)
+
+	replCmd := &cobra.Command{
+		Use:   "repl",
+		Short: "Evaluate template expressions interactively",
+		// session can be long, so it has no span
+		Annotations: map[string]string{tracingAnnotationDisabled: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			stdout.Debug("Template REPL...")
+
+			defer templatePluginsClose()
+			if err := templatePluginsStart(cmd); err != nil {
+				return err
+			}
+
+			options := templateOptions
+			objectBytes, err := utils.Content(options.Object)
+			if err != nil {
+				return err
+			}
+			options.Object = string(objectBytes)
+
+			repl, err := render.NewRepl(options, stdout)
+			if err != nil {
+				return err
+			}
+			return templateRepl(repl)
+		},
+	}
+	replCmd.Flags().StringVar(&templateReplHistory, "template-repl-history", templateReplHistory, "Template REPL history file, empty disables history")
+	templateCmd.AddCommand(replCmd)
+
 	return templateCmd
 }
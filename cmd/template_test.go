@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplHistory(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "history")
+	var lines []string
+	for i := 0; i < templateReplHistoryMax+10; i++ {
+		lines = append(lines, fmt.Sprintf("line%d", i))
+	}
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600))
+
+	// history is truncated to the last lines when it's saved
+	h := replHistoryOpen(path)
+	assert.Equal(t, templateReplHistoryMax, h.Len())
+	h.Add("last")
+	h.Close()
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	saved := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Len(t, saved, templateReplHistoryMax)
+	assert.Equal(t, "last", saved[len(saved)-1])
+	assert.Equal(t, "line11", saved[0])
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/devopsext/tools/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingAnnotationDisabled disables command span for long running commands, e.g. server which traces every request
+const tracingAnnotationDisabled = "tracing-disabled"
+
+var tracingOptions = common.TracingOptions{
+	Exporter:    envGet("TRACING_EXPORTER", "").(string),
+	Endpoint:    envGet("TRACING_ENDPOINT", "").(string),
+	Headers:     strings.Split(envGet("TRACING_HEADERS", "").(string), ","),
+	Insecure:    envGet("TRACING_INSECURE", false).(bool),
+	ServiceName: envGet("TRACING_SERVICE_NAME", "tools").(string),
+}
+
+// tracingSampleRatio is set to options, so 0 of flag or env disables sampling
+var tracingSampleRatio = envGet("TRACING_SAMPLE_RATIO", 1.0).(float64)
+
+var tracingShutdown func(context.Context) error
+var tracingSpan trace.Span
+
+func tracingAddFlags(flags *pflag.FlagSet) {
+
+	flags.StringVar(&tracingOptions.Exporter, "tracing-exporter", tracingOptions.Exporter, "Tracing exporter: none, stdout, stderr, file path, file:///path?rotate=10MB&keep=5, otlp-grpc, otlp-http")
+	flags.StringVar(&tracingOptions.Endpoint, "tracing-endpoint", tracingOptions.Endpoint, "Tracing OTLP endpoint host:port, OTEL_EXPORTER_OTLP_ENDPOINT by default")
+	flags.StringSliceVar(&tracingOptions.Headers, "tracing-headers", tracingOptions.Headers, "Tracing OTLP headers key=value")
+	flags.BoolVar(&tracingOptions.Insecure, "tracing-insecure", tracingOptions.Insecure, "Tracing OTLP insecure")
+	flags.StringVar(&tracingOptions.ServiceName, "tracing-service-name", tracingOptions.ServiceName, "Tracing service name")
+	flags.Float64Var(&tracingSampleRatio, "tracing-sample-ratio", tracingSampleRatio, "Tracing sample ratio, 0 disables sampling")
+}
+
+// tracingInit sets tracer provider and starts command span, which is parent for template and vendor spans
+func tracingInit(cmd *cobra.Command) error {
+
+	tracingOptions.SampleRatio = &tracingSampleRatio
+	shutdown, err := common.InitTracing(tracingOptions)
+	if err != nil {
+		return err
+	}
+	tracingShutdown = shutdown
+
+	if !common.TracingEnabled() || cmd.Annotations[tracingAnnotationDisabled] == "true" {
+		return nil
+	}
+
+	ctx, span := common.StartSpan(context.Background(), cmd.CommandPath(),
+		attribute.String("command.name", cmd.Name()),
+	)
+	tracingSpan = span
+	common.SetTracingContext(ctx)
+	return nil
+}
+
+// tracingStop ends command span and flushes spans
+func tracingStop(err error) {
+
+	if tracingSpan != nil {
+		common.EndSpan(tracingSpan, err)
+		tracingSpan = nil
+	}
+	if tracingShutdown == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracingShutdown(ctx); err != nil && stdout != nil {
+		stdout.Warn("Tracing shutdown: %s", err)
+	}
+	tracingShutdown = nil
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/spf13/cobra"
@@ -29,8 +31,11 @@ var vcenterOptions = vendors.VCenterOptions{
 }
 
 var vcenterOutput = common.OutputOptions{
-	Output: envGet("VCENTER_OUTPUT", "").(string),
-	Query:  envGet("VCENTER_OUTPUT_QUERY", "").(string),
+	Output:   envGet("VCENTER_OUTPUT", "").(string),
+	Query:    envGet("VCENTER_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("VCENTER_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("VCENTER_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("VCENTER_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func vcenterNew(stdout *common.Stdout) *vendors.VCenter {
@@ -60,6 +65,9 @@ func NewVCenterCommand() *cobra.Command {
 	flags.StringVar(&vcenterOptions.Session, "vcenter-session", vcenterOptions.Session, "VCenter session")
 	flags.StringVar(&vcenterOutput.Output, "vcenter-output", vcenterOutput.Output, "VCenter output")
 	flags.StringVar(&vcenterOutput.Query, "vcenter-output-query", vcenterOutput.Query, "VCenter output query")
+	flags.StringSliceVar(&vcenterOutput.QueryLib, "vcenter-output-query-lib", vcenterOutput.QueryLib, "VCenter output query libraries")
+	flags.StringVar(&vcenterOutput.Format, "vcenter-output-format", vcenterOutput.Format, "VCenter output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&vcenterOutput.Columns, "vcenter-output-columns", vcenterOutput.Columns, "VCenter output columns")
 
 	vcenterGetClustersCmd := &cobra.Command{
 		Use:   "get-clusters",
@@ -70,7 +78,7 @@ func NewVCenterCommand() *cobra.Command {
 
 			bytes, err := vcenterNew(stdout).GetClusters()
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(vcenterOutput, "VCenter", []interface{}{vcenterOptions}, bytes, stdout)
@@ -88,7 +96,7 @@ func NewVCenterCommand() *cobra.Command {
 
 			bytes, err := vcenterNew(stdout).GetHosts(vcenterHostOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(vcenterOutput, "VCenter", []interface{}{vcenterOptions, vcenterHostOptions}, bytes, stdout)
@@ -108,7 +116,7 @@ func NewVCenterCommand() *cobra.Command {
 
 			bytes, err := vcenterNew(stdout).GetVMs(vcenterVMOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(vcenterOutput, "VCenter", []interface{}{vcenterOptions, vcenterVMOptions}, bytes, stdout)
@@ -129,7 +137,7 @@ func NewVCenterCommand() *cobra.Command {
 
 			bytes, err := vcenterNew(stdout).GetVMGuestIdentity(vcenterVMGuestIdentityOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(vcenterOutput, "VCenter", []interface{}{vcenterOptions, vcenterVMGuestIdentityOptions}, bytes, stdout)
@@ -137,6 +145,9 @@ func NewVCenterCommand() *cobra.Command {
 	}
 	flags = vcenterGetVMGuestIdentityCmd.PersistentFlags()
 	flags.StringVar(&vcenterVMGuestIdentityOptions.VM, "vcenter-vm-guest-identity-vm", vcenterVMGuestIdentityOptions.VM, "VCenter get vm guest identity vm")
+	configEnvFlags(flags, map[string]string{
+		"vcenter-vm-guest-identity-vm": "VCENTER_VM_GUEST_INDENTITY_VM",
+	})
 	vcenterCmd.AddCommand(vcenterGetVMGuestIdentityCmd)
 
 	return vcenterCmd
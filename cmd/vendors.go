@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+	"github.com/spf13/cobra"
+)
+
+var vendorsOptionsOverride = envGet("VENDORS_OPTIONS", "").(string)
+var vendorsInput = envGet("VENDORS_INPUT", "").(string)
+
+var vendorsOutput = common.OutputOptions{
+	Output:   envGet("VENDORS_OUTPUT", "").(string),
+	Query:    envGet("VENDORS_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("VENDORS_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("VENDORS_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("VENDORS_OUTPUT_COLUMNS", "").(string), ","),
+}
+
+// vendorsOptions are options of vendor commands, so registered vendors use the same env variables
+var vendorsOptions = map[string]interface{}{
+	"catchpoint": &catchpointOptions,
+	"gitlab":     &gitlabOptions,
+	"google":     &googleOptions,
+	"grafana":    &grafanaOptions,
+	"graylog":    &graylogOptions,
+	"jira":       &jiraOptions,
+	"k8s":        &k8sOptions,
+	"netbox":     &netboxOptions,
+	"observium":  &observiumOptions,
+	"pagerduty":  &pagerDutyOptions,
+	"prometheus": &prometheusOptions,
+	"site24x7":   &site24x7Options,
+	"slack":      &slackOptions,
+	"telegram":   &telegramOptions,
+	"teleport":   &teleportOptions,
+	"vault":      &secretVaultOptions,
+	"vcenter":    &vcenterOptions,
+	"virustotal": &virusTotalOptions,
+	"zabbix":     &zabbixOptions,
+}
+
+type vendorsCheckResult struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+	err      error
+}
+
+// vendorsNew creates vendor with options of its command, JSON of --options overrides them
+func vendorsNew(name string) (vendors.Vendor, error) {
+
+	options := vendorsOptions[name]
+	if !utils.IsEmpty(vendorsOptionsOverride) {
+		b, err := utils.Content(vendorsOptionsOverride)
+		if err != nil {
+			return nil, err
+		}
+		if options == nil {
+			options = b
+		} else if err := json.Unmarshal(b, options); err != nil {
+			return nil, fmt.Errorf("invalid %s options: %v", name, err)
+		}
+	}
+	common.Debug("Vendors", options, stdout)
+	return vendors.NewVendor(name, options, stdout)
+}
+
+func vendorsCheck(name string) vendorsCheckResult {
+
+	r := vendorsCheckResult{Name: name}
+	t := time.Now()
+
+	v, err := vendorsNew(name)
+	if err == nil {
+		err = v.Check(common.TracingContext())
+	}
+	r.Duration = time.Since(t).Round(time.Millisecond).String()
+	if err != nil {
+		r.Error = common.RedactSensitive(err.Error())
+		r.err = err
+		return r
+	}
+	r.OK = true
+	return r
+}
+
+func vendorsOperationCommand(name string, o vendors.VendorOperation) *cobra.Command {
+
+	return &cobra.Command{
+		Use:   o.Name,
+		Short: o.Description,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			stdout.Debug("Vendor %s %s...", name, o.Name)
+
+			// operation of created vendor uses its options, o describes operation only
+			v, err := vendorsNew(name)
+			if err != nil {
+				return err
+			}
+			op, err := vendors.FindVendorOperation(v, o.Name)
+			if err != nil {
+				return err
+			}
+			var input []byte
+			if !utils.IsEmpty(vendorsInput) {
+				if input, err = utils.Content(vendorsInput); err != nil {
+					return err
+				}
+			}
+
+			bytes, err := op.Call(common.TracingContext(), input)
+			if err != nil {
+				return err
+			}
+			if op.Output != vendors.VendorOutputJSON {
+				common.OutputRaw(vendorsOutput.Output, bytes, stdout)
+				return nil
+			}
+			common.OutputJson(vendorsOutput, "Vendors", []interface{}{v.Options(), string(input)}, bytes, stdout)
+			return nil
+		},
+	}
+}
+
+// NewVendorsCommand calls operations of registered vendors generically, commands of vendors, e.g. tools jira, keep their own flags
+func NewVendorsCommand() *cobra.Command {
+
+	vendorsCmd := &cobra.Command{
+		Use:   "vendors",
+		Short: "Registered vendors",
+	}
+
+	flags := vendorsCmd.PersistentFlags()
+	flags.StringVar(&vendorsOptionsOverride, "options", vendorsOptionsOverride, "Vendor options JSON or file, overrides options of vendor command")
+	flags.StringVar(&vendorsOutput.Output, "output", vendorsOutput.Output, "Output")
+	flags.StringVar(&vendorsOutput.Query, "query", vendorsOutput.Query, "Query")
+	flags.StringSliceVar(&vendorsOutput.QueryLib, "query-lib", vendorsOutput.QueryLib, "Query libraries")
+	flags.StringVar(&vendorsOutput.Format, "format", vendorsOutput.Format, "Output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&vendorsOutput.Columns, "columns", vendorsOutput.Columns, "Output columns")
+	configEnvFlags(flags, map[string]string{
+		"options":   "VENDORS_OPTIONS",
+		"output":    "VENDORS_OUTPUT",
+		"query":     "VENDORS_OUTPUT_QUERY",
+		"query-lib": "VENDORS_OUTPUT_QUERY_LIB",
+		"format":    "VENDORS_OUTPUT_FORMAT",
+		"columns":   "VENDORS_OUTPUT_COLUMNS",
+	})
+
+	vendorsCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List vendors with options and operations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			var r []vendors.VendorInfo
+			for _, name := range vendors.VendorNames() {
+				info, err := vendors.DescribeVendorName(name)
+				if err != nil {
+					return err
+				}
+				r = append(r, info)
+			}
+			bytes, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			common.OutputJson(vendorsOutput, "Vendors", []interface{}{}, bytes, stdout)
+			return nil
+		},
+	})
+
+	vendorsCmd.AddCommand(&cobra.Command{
+		Use:       "check <name>...",
+		Short:     "Check vendor credentials and connectivity",
+		Args:      cobra.MinimumNArgs(1),
+		ValidArgs: vendors.VendorNames(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			var r []vendorsCheckResult
+			var failed []string
+			var ferr error
+			for _, name := range args {
+				c := vendorsCheck(name)
+				if !c.OK {
+					failed = append(failed, name)
+					if ferr == nil {
+						ferr = c.err
+					}
+				}
+				r = append(r, c)
+			}
+			bytes, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			common.OutputJson(vendorsOutput, "Vendors", []interface{}{}, bytes, stdout)
+
+			if len(failed) > 0 {
+				// exit code is of the first failed vendor, so auth failures are told from unavailable vendors
+				return common.NewExitError(common.ExitCode(ferr), fmt.Errorf("vendors check failed: %s", strings.Join(failed, ", ")))
+			}
+			return nil
+		},
+	})
+
+	// operations are registered with vendors, vendor is created when operation is called
+	for _, name := range vendors.VendorNames() {
+
+		operations, err := vendors.VendorOperations(name)
+		if err != nil {
+			continue
+		}
+		vendorCmd := &cobra.Command{
+			Use:   name,
+			Short: fmt.Sprintf("Call %s operations", name),
+		}
+		vendorCmd.PersistentFlags().StringVar(&vendorsInput, "input", vendorsInput, "Operation input JSON or file")
+		configEnvFlags(vendorCmd.PersistentFlags(), map[string]string{
+			"input": "VENDORS_INPUT",
+		})
+		for _, o := range operations {
+			vendorCmd.AddCommand(vendorsOperationCommand(name, o))
+		}
+		vendorsCmd.AddCommand(vendorCmd)
+	}
+
+	return vendorsCmd
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	"github.com/spf13/cobra"
@@ -17,8 +19,11 @@ var virusTotalDomainReportOptions = vendors.VirusTotalDomainReportOptions{
 }
 
 var virusTotalOutput = common.OutputOptions{
-	Output: envGet("VIRUSTOTAL_OUTPUT", "").(string),
-	Query:  envGet("VIRUSTOTAL_OUTPUT_QUERY", "").(string),
+	Output:   envGet("VIRUSTOTAL_OUTPUT", "").(string),
+	Query:    envGet("VIRUSTOTAL_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("VIRUSTOTAL_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("VIRUSTOTAL_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("VIRUSTOTAL_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func virusTotalNew(stdout *common.Stdout) *vendors.VirusTotal {
@@ -46,6 +51,19 @@ func NewVirusTotalCommand() *cobra.Command {
 	flags.StringVar(&virusTotalOptions.APIKey, "api-key", virusTotalOptions.APIKey, "API key")
 	flags.StringVar(&virusTotalOutput.Output, "output", virusTotalOutput.Output, "Output")
 	flags.StringVar(&virusTotalOutput.Query, "query", virusTotalOutput.Query, "Query")
+	flags.StringSliceVar(&virusTotalOutput.QueryLib, "query-lib", virusTotalOutput.QueryLib, "Query libraries")
+	flags.StringVar(&virusTotalOutput.Format, "format", virusTotalOutput.Format, "Output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&virusTotalOutput.Columns, "columns", virusTotalOutput.Columns, "Output columns")
+	configEnvFlags(flags, map[string]string{
+		"timeout":   "VIRUSTOTAL_TIMEOUT",
+		"insecure":  "VIRUSTOTAL_INSECURE",
+		"api-key":   "VIRUSTOTAL_API_KEY",
+		"output":    "VIRUSTOTAL_OUTPUT",
+		"query":     "VIRUSTOTAL_OUTPUT_QUERY",
+		"query-lib": "VIRUSTOTAL_OUTPUT_QUERY_LIB",
+		"format":    "VIRUSTOTAL_OUTPUT_FORMAT",
+		"columns":   "VIRUSTOTAL_OUTPUT_COLUMNS",
+	})
 
 	domainReport := &cobra.Command{
 		Use:   "domain-report",
@@ -57,7 +75,7 @@ func NewVirusTotalCommand() *cobra.Command {
 
 			bytes, err := virusTotalNew(stdout).DomainReport(virusTotalDomainReportOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(virusTotalOutput, "VirusTotal", []interface{}{virusTotalOptions, virusTotalDomainReportOptions}, bytes, stdout)
@@ -66,6 +84,9 @@ func NewVirusTotalCommand() *cobra.Command {
 	flags = domainReport.PersistentFlags()
 
 	flags.StringVar(&virusTotalDomainReportOptions.Domain, "domain", virusTotalDomainReportOptions.Domain, "Domains")
+	configEnvFlags(flags, map[string]string{
+		"domain": "VIRUSTOTAL_DOMAIN",
+	})
 
 	virusTotalCmd.AddCommand(domainReport)
 
@@ -12,6 +12,9 @@ var zabbixHostOptions = vendors.ZabbixHostOptions{
 	Fields:     strings.Split(envGet("ZABBIX_HOST_FIELDS", "").(string), ","),
 	Inventory:  strings.Split(envGet("ZABBIX_HOST_INVENTORY", "").(string), ","),
 	Interfaces: strings.Split(envGet("ZABBIX_HOST_INTERFACSES", "").(string), ","),
+	PageOptions: vendors.PageOptions{
+		Limit: envGet("ZABBIX_HOST_LIMIT", 0).(int),
+	},
 }
 
 var zabbixOptions = vendors.ZabbixOptions{
@@ -24,8 +27,11 @@ var zabbixOptions = vendors.ZabbixOptions{
 }
 
 var zabbixOutput = common.OutputOptions{
-	Output: envGet("ZABBIX_OUTPUT", "").(string),
-	Query:  envGet("ZABBIX_OUTPUT_QUERY", "").(string),
+	Output:   envGet("ZABBIX_OUTPUT", "").(string),
+	Query:    envGet("ZABBIX_OUTPUT_QUERY", "").(string),
+	QueryLib: strings.Split(envGet("ZABBIX_OUTPUT_QUERY_LIB", "").(string), ","),
+	Format:   envGet("ZABBIX_OUTPUT_FORMAT", "").(string),
+	Columns:  strings.Split(envGet("ZABBIX_OUTPUT_COLUMNS", "").(string), ","),
 }
 
 func zabbixNew(stdout *common.Stdout) *vendors.Zabbix {
@@ -55,6 +61,9 @@ func NewZabbixCommand() *cobra.Command {
 	flags.StringVar(&zabbixOptions.Auth, "zabbix-auth", zabbixOptions.Auth, "Zabbix auth")
 	flags.StringVar(&zabbixOutput.Output, "zabbix-output", zabbixOutput.Output, "Zabbix output")
 	flags.StringVar(&zabbixOutput.Query, "zabbix-output-query", zabbixOutput.Query, "Zabbix output query")
+	flags.StringSliceVar(&zabbixOutput.QueryLib, "zabbix-output-query-lib", zabbixOutput.QueryLib, "Zabbix output query libraries")
+	flags.StringVar(&zabbixOutput.Format, "zabbix-output-format", zabbixOutput.Format, "Zabbix output format: json, yaml, csv, tsv, table, ndjson, go-template=...")
+	flags.StringSliceVar(&zabbixOutput.Columns, "zabbix-output-columns", zabbixOutput.Columns, "Zabbix output columns")
 
 	zabbixGetHostsCmd := &cobra.Command{
 		Use:   "get-hosts",
@@ -66,7 +75,7 @@ func NewZabbixCommand() *cobra.Command {
 
 			bytes, err := zabbixNew(stdout).GetHosts(zabbixHostOptions)
 			if err != nil {
-				stdout.Error(err)
+				commandError(err)
 				return
 			}
 			common.OutputJson(zabbixOutput, "Zabbix", []interface{}{zabbixOptions, zabbixHostOptions}, bytes, stdout)
@@ -76,6 +85,11 @@ func NewZabbixCommand() *cobra.Command {
 	flags.StringSliceVar(&zabbixHostOptions.Fields, "zabbix-host-fields", zabbixHostOptions.Fields, "Zabbix get host fields")
 	flags.StringSliceVar(&zabbixHostOptions.Inventory, "zabbix-host-inventory", zabbixHostOptions.Inventory, "Zabbix get host inventory")
 	flags.StringSliceVar(&zabbixHostOptions.Interfaces, "zabbix-host-interfaces", zabbixHostOptions.Interfaces, "Zabbix get host interfaces")
+	flags.IntVar(&zabbixHostOptions.Limit, "limit", zabbixHostOptions.Limit, "Max hosts")
+	configEnvFlags(flags, map[string]string{
+		"zabbix-host-interfaces": "ZABBIX_HOST_INTERFACSES",
+		"limit":                  "ZABBIX_HOST_LIMIT",
+	})
 	zabbixCmd.AddCommand(zabbixGetHostsCmd)
 
 	return zabbixCmd
@@ -0,0 +1,458 @@
+package common
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/devopsext/utils"
+)
+
+const (
+	CassetteRecord = "record"
+	CassetteReplay = "replay"
+
+	cassetteDefaultName = "http"
+)
+
+// cassetteSensitive matches header, query and JSON field names which are scrubbed, "author" and similar are kept
+var cassetteSensitive = regexp.MustCompile(`(?i)^(auth|authorization|proxy-authorization|cookie|set-cookie|.*(token|password|passwd|secret|api-?key|access-?key|private-?key|credential|session-?id).*)$`)
+
+type CassetteOptions struct {
+	Mode string // record or replay, empty disables cassettes
+	Dir  string // cassette per client name, e.g. <dir>/jira.json
+	Name string // vendor name, set by VendorHttpClient
+}
+
+type CassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type CassetteResponse struct {
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header,omitempty"`
+	Body     string      `json:"body,omitempty"`
+	Encoding string      `json:"encoding,omitempty"` // base64 for binary bodies
+}
+
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+type Cassette struct {
+	Interactions []*CassetteInteraction `json:"interactions"`
+}
+
+// cassettes are shared by clients with the same file, so interactions of several clients are kept in one file
+var cassettes = struct {
+	mutex sync.Mutex
+	files map[string]*cassetteFile
+}{files: make(map[string]*cassetteFile)}
+
+type cassetteFile struct {
+	mutex    sync.Mutex
+	path     string
+	record   bool
+	cassette Cassette
+	used     map[*CassetteInteraction]bool
+}
+
+func cassettePath(options CassetteOptions) string {
+
+	name := options.Name
+	if utils.IsEmpty(name) {
+		name = cassetteDefaultName
+	}
+	return filepath.Join(options.Dir, strings.ToLower(name)+".json")
+}
+
+func loadCassetteFile(options CassetteOptions) (*cassetteFile, error) {
+
+	path := cassettePath(options)
+
+	cassettes.mutex.Lock()
+	defer cassettes.mutex.Unlock()
+
+	if f, ok := cassettes.files[path]; ok {
+		return f, nil
+	}
+
+	f := &cassetteFile{path: path, used: make(map[*CassetteInteraction]bool)}
+	// recording starts from empty cassette, so interactions of previous recording are not replayed
+	if options.Mode == CassetteRecord {
+		f.record = true
+		cassettes.files[path] = f
+		return f, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &f.cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %v", path, err)
+	}
+	cassettes.files[path] = f
+	return f, nil
+}
+
+// ResetCassettes forgets loaded cassettes, so they are read again and replay starts from the beginning
+func ResetCassettes() {
+
+	cassettes.mutex.Lock()
+	defer cassettes.mutex.Unlock()
+	cassettes.files = make(map[string]*cassetteFile)
+}
+
+// CloseCassettes writes recorded cassettes and forgets loaded ones, it's called once before exit
+func CloseCassettes() error {
+
+	cassettes.mutex.Lock()
+	files := cassettes.files
+	cassettes.files = make(map[string]*cassetteFile)
+	cassettes.mutex.Unlock()
+
+	var errs []error
+	for _, f := range files {
+		if !f.record {
+			continue
+		}
+		if err := f.write(); err != nil {
+			errs = append(errs, fmt.Errorf("cassette %s: %v", f.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f *cassetteFile) add(i *CassetteInteraction) {
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.cassette.Interactions = append(f.cassette.Interactions, i)
+}
+
+func (f *cassetteFile) write() error {
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	b, err := json.MarshalIndent(&f.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, b, 0644)
+}
+
+// find returns first unused interaction with the same request, then with the same method and path, so requests with
+// generated values, e.g. timestamps or multipart boundaries, are replayed in recorded order
+func (f *cassetteFile) find(req CassetteRequest) *CassetteInteraction {
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	matches := []func(i *CassetteInteraction) bool{
+		func(i *CassetteInteraction) bool {
+			return i.Request.Method == req.Method && i.Request.URL == req.URL && i.Request.Body == req.Body
+		},
+		func(i *CassetteInteraction) bool {
+			return i.Request.Method == req.Method && cassetteURLPath(i.Request.URL) == cassetteURLPath(req.URL)
+		},
+	}
+	for _, match := range matches {
+		var last *CassetteInteraction
+		for _, i := range f.cassette.Interactions {
+			if !match(i) {
+				continue
+			}
+			last = i
+			if !f.used[i] {
+				f.used[i] = true
+				return i
+			}
+		}
+		// repeated polling gets the last response
+		if last != nil {
+			return last
+		}
+	}
+	return nil
+}
+
+func cassetteURLPath(s string) string {
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+	return u.Host + u.Path
+}
+
+func scrubCassetteHeader(h http.Header) http.Header {
+
+	r := make(http.Header)
+	for k, v := range h {
+		if cassetteSensitive.MatchString(k) {
+			r[k] = []string{SecretMask}
+			continue
+		}
+		values := make([]string, len(v))
+		for i, s := range v {
+			values[i] = RedactSensitive(s)
+		}
+		r[k] = values
+	}
+	return r
+}
+
+func scrubCassetteURL(u *url.URL) string {
+
+	c := *u
+	c.User = nil
+	q := c.Query()
+	if len(q) > 0 {
+		for k := range q {
+			if cassetteSensitive.MatchString(k) {
+				q[k] = []string{SecretMask}
+			}
+		}
+		c.RawQuery = q.Encode()
+	}
+	return RedactSensitive(c.String())
+}
+
+func scrubCassetteJson(v interface{}) interface{} {
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			if _, ok := e.(string); ok && cassetteSensitive.MatchString(k) {
+				t[k] = SecretMask
+				continue
+			}
+			t[k] = scrubCassetteJson(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = scrubCassetteJson(e)
+		}
+	}
+	return v
+}
+
+// scrubCassetteBody masks sensitive fields of JSON and form bodies, binary bodies are encoded with base64
+func scrubCassetteBody(body []byte, contentType string) (string, string) {
+
+	if len(body) == 0 {
+		return "", ""
+	}
+	if !utf8.Valid(body) {
+		return base64.StdEncoding.EncodeToString(body), "base64"
+	}
+
+	ct, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case ct == "application/x-www-form-urlencoded":
+		if q, err := url.ParseQuery(string(body)); err == nil {
+			for k := range q {
+				if cassetteSensitive.MatchString(k) {
+					q[k] = []string{SecretMask}
+				}
+			}
+			return RedactSensitive(q.Encode()), ""
+		}
+	case strings.Contains(ct, "json") || ct == "":
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err == nil {
+			if b, err := json.Marshal(scrubCassetteJson(v)); err == nil {
+				return RedactSensitive(string(b)), ""
+			}
+		}
+	}
+	return RedactSensitive(string(body)), ""
+}
+
+func readCassetteBody(body io.ReadCloser) ([]byte, io.ReadCloser, error) {
+
+	if body == nil || body == http.NoBody {
+		return nil, body, nil
+	}
+	b, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func newCassetteRequest(req *http.Request) (CassetteRequest, *http.Request, error) {
+
+	b, body, err := readCassetteBody(req.Body)
+	if err != nil {
+		return CassetteRequest{}, nil, err
+	}
+	if body != nil {
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+	r := CassetteRequest{
+		Method: req.Method,
+		URL:    scrubCassetteURL(req.URL),
+		Header: scrubCassetteHeader(req.Header),
+	}
+	r.Body, _ = scrubCassetteBody(b, req.Header.Get("Content-Type"))
+	return r, req, nil
+}
+
+// cassetteTransport records interactions or replays them without network
+type cassetteTransport struct {
+	transport http.RoundTripper
+	options   CassetteOptions
+	file      *cassetteFile
+}
+
+func (t *cassetteTransport) record(req *http.Request) (*http.Response, error) {
+
+	creq, req, err := newCassetteRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	b, body, err := readCassetteBody(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = body
+
+	cresp := CassetteResponse{
+		Status: resp.StatusCode,
+		Header: scrubCassetteHeader(resp.Header),
+	}
+	cresp.Body, cresp.Encoding = scrubCassetteBody(b, resp.Header.Get("Content-Type"))
+	t.file.add(&CassetteInteraction{Request: creq, Response: cresp})
+	return resp, nil
+}
+
+func (t *cassetteTransport) replay(req *http.Request) (*http.Response, error) {
+
+	creq, req, err := newCassetteRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	i := t.file.find(creq)
+	if i == nil {
+		return nil, fmt.Errorf("cassette %s has no interaction for %s %s", t.file.path, creq.Method, creq.URL)
+	}
+
+	body := []byte(i.Response.Body)
+	if i.Response.Encoding == "base64" {
+		if body, err = base64.StdEncoding.DecodeString(i.Response.Body); err != nil {
+			return nil, err
+		}
+	}
+	header := i.Response.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Response.Status, http.StatusText(i.Response.Status)),
+		StatusCode:    i.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if t.options.Mode == CassetteReplay {
+		return t.replay(req)
+	}
+	return t.record(req)
+}
+
+func validateCassette(options CassetteOptions) error {
+
+	switch options.Mode {
+	case "":
+		return nil
+	case CassetteRecord, CassetteReplay:
+	default:
+		return fmt.Errorf("invalid cassette mode %s, use %s or %s", options.Mode, CassetteRecord, CassetteReplay)
+	}
+	if utils.IsEmpty(options.Dir) {
+		return fmt.Errorf("cassette dir is empty")
+	}
+	if options.Mode == CassetteReplay {
+		if _, err := os.Stat(options.Dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewCassetteTransport wraps transport to record or replay interactions, it's used by tests with vendor HTTPClient
+func NewCassetteTransport(rt http.RoundTripper, options CassetteOptions) (http.RoundTripper, error) {
+
+	if err := validateCassette(options); err != nil {
+		return nil, err
+	}
+	if options.Mode == "" {
+		return rt, nil
+	}
+	f, err := loadCassetteFile(options)
+	if err != nil {
+		return nil, err
+	}
+	return &cassetteTransport{transport: rt, options: options, file: f}, nil
+}
+
+// CassetteTransport wraps transport with cassette of defaults, so clients created outside of common record and replay too
+func CassetteTransport(rt http.RoundTripper, name string) http.RoundTripper {
+
+	options := HttpClientDefaults().Cassette
+	if options.Mode == "" {
+		return rt
+	}
+	options.Name = name
+	r, err := NewCassetteTransport(rt, options)
+	if err != nil {
+		return &cassetteErrorTransport{err: err}
+	}
+	return r
+}
+
+// cassetteErrorTransport fails requests, so missing cassette doesn't fall back to network
+type cassetteErrorTransport struct {
+	err error
+}
+
+func (t *cassetteErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, t.err
+}
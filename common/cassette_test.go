@@ -0,0 +1,82 @@
+package common
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassetteRecordReplay(t *testing.T) {
+
+	defer ResetCassettes()
+	dir := t.TempDir()
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Write([]byte(`{"key":"OPS-1","author":"john","token":"xyz"}`))
+	}))
+	url := srv.URL + "/rest/api/2/issue?apiKey=secret&fields=key"
+
+	get := func(mode string) string {
+		client, err := NewHttpClientWithOptions(HttpClientOptions{
+			Timeout:  5,
+			Cassette: CassetteOptions{Mode: mode, Dir: dir, Name: "jira"},
+		})
+		require.NoError(t, err)
+		req, _ := http.NewRequest("POST", url, strings.NewReader(`{"password":"pass","summary":"test"}`))
+		req.Header.Set("Authorization", "Bearer secret-token")
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return string(b)
+	}
+
+	assert.Contains(t, get(CassetteRecord), `"token":"xyz"`)
+
+	// cassette is written on close only
+	_, err := os.Stat(filepath.Join(dir, "jira.json"))
+	assert.True(t, os.IsNotExist(err))
+	require.NoError(t, CloseCassettes())
+
+	// recording again replaces interactions of previous recording
+	get(CassetteRecord)
+	require.NoError(t, CloseCassettes())
+	assert.Equal(t, 2, calls)
+	srv.Close()
+
+	b, err := os.ReadFile(filepath.Join(dir, "jira.json"))
+	require.NoError(t, err)
+	cassette := string(b)
+	assert.Equal(t, 1, strings.Count(cassette, `"request"`))
+	for _, s := range []string{"secret-token", "apiKey=secret", "pass\\\"", "xyz", "session=abc"} {
+		assert.NotContains(t, cassette, s)
+	}
+	assert.Contains(t, cassette, "john")
+
+	ResetCassettes()
+	body := get(CassetteReplay)
+	assert.Equal(t, 2, calls)
+	assert.Contains(t, body, `"key":"OPS-1"`)
+	assert.Contains(t, body, SecretMask)
+
+	// unknown requests are not sent to network
+	client, err := NewHttpClientWithOptions(HttpClientOptions{Cassette: CassetteOptions{Mode: CassetteReplay, Dir: dir, Name: "jira"}})
+	require.NoError(t, err)
+	_, err = client.Get(srv.URL + "/unknown")
+	assert.Error(t, err)
+
+	_, err = NewHttpClientWithOptions(HttpClientOptions{Cassette: CassetteOptions{Mode: CassetteReplay, Dir: dir, Name: "grafana"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config keeps named profiles, e.g. "grafana.prod" or "jira.cloud", which hold option values by flag name
+// without vendor prefix ("url" for "grafana-url"). Profile without name ("grafana") is applied first
+// and serves as base for named ones.
+type Config struct {
+	Profile  string                            `yaml:"profile" json:"profile"`
+	Profiles map[string]map[string]interface{} `yaml:"profiles" json:"profiles"`
+}
+
+var configSensitiveKeys = regexp.MustCompile(`(?i)(token|password|passwd|secret|api-?key|access-?key|private-?key|account-key|server-key|auth|credential)`)
+
+// LoadConfig reads YAML or JSON config file
+func LoadConfig(path string) (*Config, error) {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{}
+	if err := yaml.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("config %s: %v", path, err)
+	}
+	return c, nil
+}
+
+// configKey converts apiKey, api_key or api-key into api-key
+func configKey(key string) string {
+
+	var b strings.Builder
+	for i, r := range key {
+		switch {
+		case r == '_' || r == ' ':
+			b.WriteRune('-')
+		case unicode.IsUpper(r):
+			if i > 0 {
+				b.WriteRune('-')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func configValue(v interface{}) string {
+
+	switch vt := v.(type) {
+	case []interface{}:
+		var items []string
+		for _, item := range vt {
+			items = append(items, ValueToString(item))
+		}
+		return strings.Join(items, ",")
+	case map[string]interface{}:
+		var keys []string
+		for k := range vt {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var items []string
+		for _, k := range keys {
+			items = append(items, fmt.Sprintf("%s=%s", k, ValueToString(vt[k])))
+		}
+		return strings.Join(items, ",")
+	}
+	return ValueToString(v)
+}
+
+// ProfileNames returns profiles to apply in order for selector like "prod" or "grafana.prod,jira.cloud"
+func (c *Config) ProfileNames(selector string) []string {
+
+	if c == nil {
+		return nil
+	}
+	if strings.TrimSpace(selector) == "" {
+		selector = c.Profile
+	}
+
+	var base, selected []string
+	for name := range c.Profiles {
+		if !strings.Contains(name, ".") {
+			base = append(base, name)
+		}
+	}
+	sort.Strings(base)
+
+	for _, s := range RemoveEmptyStrings(strings.Split(selector, ",")) {
+
+		s = strings.TrimSpace(s)
+		if strings.Contains(s, ".") {
+			if _, ok := c.Profiles[s]; ok {
+				selected = append(selected, s)
+			}
+			continue
+		}
+
+		var names []string
+		for name := range c.Profiles {
+			if strings.HasSuffix(name, "."+s) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		selected = append(selected, names...)
+	}
+	return append(base, selected...)
+}
+
+// Values returns option values by flag name, e.g. "grafana-url", for selected profiles
+func (c *Config) Values(selector string) map[string]string {
+
+	r := make(map[string]string)
+	if c == nil {
+		return r
+	}
+
+	for _, name := range c.ProfileNames(selector) {
+
+		vendor, _, _ := strings.Cut(name, ".")
+		vendor = configKey(vendor)
+
+		for k, v := range c.Profiles[name] {
+			// "--api-key" is taken as flag name as is
+			if strings.HasPrefix(k, "--") {
+				r[strings.TrimPrefix(k, "--")] = configValue(v)
+				continue
+			}
+			key := configKey(k)
+			if !strings.HasPrefix(key, vendor+"-") {
+				key = fmt.Sprintf("%s-%s", vendor, key)
+			}
+			r[key] = configValue(v)
+		}
+	}
+	return r
+}
+
+// IsSensitiveKey checks that option name looks like credential
+func IsSensitiveKey(name string) bool {
+	return configSensitiveKeys.MatchString(name)
+}
@@ -0,0 +1,68 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValues(t *testing.T) {
+
+	data := `
+profile: prod
+profiles:
+  grafana:
+    timeout: 10
+  grafana.prod:
+    url: https://grafana.prod
+    apiKey: secret:env:GRAFANA_KEY
+  grafana.staging:
+    url: https://grafana.staging
+  jira.prod:
+    url: https://jira.prod
+    issue_labels: [alert, prod]
+  virustotal.prod:
+    --api-key: vt
+`
+	file := filepath.Join(t.TempDir(), "tools.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(data), 0600))
+
+	config, err := LoadConfig(file)
+	require.NoError(t, err)
+
+	tests := []struct {
+		selector string
+		expected map[string]string
+	}{
+		{
+			selector: "",
+			expected: map[string]string{
+				"grafana-timeout":   "10",
+				"grafana-url":       "https://grafana.prod",
+				"grafana-api-key":   "secret:env:GRAFANA_KEY",
+				"jira-url":          "https://jira.prod",
+				"jira-issue-labels": "alert,prod",
+				"api-key":           "vt",
+			},
+		},
+		{
+			selector: "grafana.staging",
+			expected: map[string]string{
+				"grafana-timeout": "10",
+				"grafana-url":     "https://grafana.staging",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			assert.Equal(t, tt.expected, config.Values(tt.selector))
+		})
+	}
+
+	assert.True(t, IsSensitiveKey("grafana-api-key"))
+	assert.False(t, IsSensitiveKey("jira-issue-project-key"))
+}
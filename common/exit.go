@@ -0,0 +1,58 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	ExitCodeOK    = 0
+	ExitCodeError = 1
+	ExitCodeUsage = 2
+
+	// vendor errors
+	ExitCodeVendor      = 3
+	ExitCodeAuth        = 4
+	ExitCodeNotFound    = 5
+	ExitCodeRateLimit   = 6
+	ExitCodeInvalid     = 7
+	ExitCodeUnavailable = 8
+)
+
+// ExitError is returned by commands to exit with specific code instead of panicking
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit code %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+func NewExitError(code int, err error) *ExitError {
+	return &ExitError{Code: code, Err: err}
+}
+
+// ExitCode returns code for error, 0 for nil, vendor errors are mapped by kind and 1 is for errors without code
+func ExitCode(err error) int {
+
+	if err == nil {
+		return ExitCodeOK
+	}
+	var e *ExitError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	var ve *VendorError
+	if errors.As(err, &ve) {
+		return VendorErrorExitCode(ve)
+	}
+	return ExitCodeError
+}
@@ -0,0 +1,590 @@
+package common
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/devopsext/utils"
+	"github.com/hashicorp/hcl"
+	"gopkg.in/ini.v1"
+)
+
+const (
+	CsvQuoteMinimal = "minimal"
+	CsvQuoteAll     = "all"
+
+	XmlAttributePrefix = "-"
+	XmlTextKey         = "#text"
+)
+
+type CsvOptions struct {
+	Delimiter string
+	Header    bool
+	Columns   []string
+	Mapping   map[string]string
+	Quote     string
+}
+
+func csvDelimiter(options CsvOptions) (rune, error) {
+
+	if utils.IsEmpty(options.Delimiter) {
+		return ',', nil
+	}
+	d := options.Delimiter
+	if d == "\\t" || strings.EqualFold(d, "tab") {
+		return '\t', nil
+	}
+	runes := []rune(d)
+	if len(runes) != 1 {
+		return 0, fmt.Errorf("csv delimiter must be a single character. Have %q", d)
+	}
+	return runes[0], nil
+}
+
+func csvColumnName(options CsvOptions, column string) string {
+
+	if options.Mapping == nil {
+		return column
+	}
+	if v, ok := options.Mapping[column]; ok && !utils.IsEmpty(v) {
+		return v
+	}
+	return column
+}
+
+// NormalizeJson converts any Go value to its generic json representation (maps, arrays, scalars)
+func NormalizeJson(i interface{}) (interface{}, error) {
+
+	switch v := i.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		var r interface{}
+		err := json.Unmarshal(v, &r)
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
+	case map[string]interface{}, []interface{}, string, bool, float64:
+		return v, nil
+	}
+
+	data, err := JsonMarshal(i)
+	if err != nil {
+		return nil, err
+	}
+	var r interface{}
+	err = json.Unmarshal(data, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ValueToString converts scalar to plain string, objects and arrays to json
+func ValueToString(i interface{}) string {
+
+	switch v := i.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]interface{}, []interface{}:
+		b, err := JsonMarshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return strings.TrimSpace(string(b))
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// MapKeys returns union of object keys found in array items, keys of every item are added sorted
+func MapKeys(items []interface{}) []string {
+
+	keys := []string{}
+	exists := make(map[string]bool)
+
+	for _, item := range items {
+
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var added []string
+		for k := range m {
+			if exists[k] {
+				continue
+			}
+			exists[k] = true
+			added = append(added, k)
+		}
+		sort.Strings(added)
+		keys = append(keys, added...)
+	}
+	return keys
+}
+
+func FromCsv(data []byte, options CsvOptions) (interface{}, error) {
+
+	delimiter, err := csvDelimiter(options)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	r := []interface{}{}
+	columns := options.Columns
+	start := 0
+
+	if options.Header && len(records) > 0 {
+		if len(columns) == 0 {
+			columns = records[0]
+		}
+		start = 1
+	}
+
+	// no columns => array of arrays
+	if len(columns) == 0 {
+		for _, record := range records {
+			row := []interface{}{}
+			for _, v := range record {
+				row = append(row, v)
+			}
+			r = append(r, row)
+		}
+		return r, nil
+	}
+
+	for _, record := range records[start:] {
+		row := make(map[string]interface{})
+		for i, column := range columns {
+			value := ""
+			if i < len(record) {
+				value = record[i]
+			}
+			row[csvColumnName(options, strings.TrimSpace(column))] = value
+		}
+		r = append(r, row)
+	}
+	return r, nil
+}
+
+func csvQuote(s string, delimiter rune, all bool) string {
+
+	need := all
+	if !need {
+		need = strings.ContainsRune(s, delimiter) || strings.ContainsAny(s, "\"\r\n") ||
+			strings.HasPrefix(s, " ") || strings.HasSuffix(s, " ")
+	}
+	if !need {
+		return s
+	}
+	return "\"" + strings.ReplaceAll(s, "\"", "\"\"") + "\""
+}
+
+func csvWriteRecord(w io.Writer, fields []string, delimiter rune, all bool) error {
+
+	quoted := make([]string, len(fields))
+	for i, f := range fields {
+		quoted[i] = csvQuote(f, delimiter, all)
+	}
+	_, err := io.WriteString(w, strings.Join(quoted, string(delimiter))+"\n")
+	return err
+}
+
+func ToCsv(i interface{}, options CsvOptions) ([]byte, error) {
+
+	delimiter, err := csvDelimiter(options)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := NormalizeJson(i)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []interface{}
+	switch vt := v.(type) {
+	case nil:
+		return []byte{}, nil
+	case []interface{}:
+		items = vt
+	default:
+		items = []interface{}{vt}
+	}
+
+	all := strings.EqualFold(options.Quote, CsvQuoteAll)
+	columns := options.Columns
+	if len(columns) == 0 {
+		columns = MapKeys(items)
+	}
+
+	var buf bytes.Buffer
+
+	if options.Header && len(columns) > 0 {
+		header := make([]string, len(columns))
+		for k, c := range columns {
+			header[k] = csvColumnName(options, c)
+		}
+		if err := csvWriteRecord(&buf, header, delimiter, all); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, item := range items {
+
+		var fields []string
+		switch it := item.(type) {
+		case map[string]interface{}:
+			for _, c := range columns {
+				fields = append(fields, ValueToString(it[c]))
+			}
+		case []interface{}:
+			for _, f := range it {
+				fields = append(fields, ValueToString(f))
+			}
+		default:
+			fields = append(fields, ValueToString(it))
+		}
+		if err := csvWriteRecord(&buf, fields, delimiter, all); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func FromToml(data []byte) (interface{}, error) {
+
+	var r map[string]interface{}
+	err := toml.Unmarshal(data, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// json numbers are float64, integral values should be kept as integers in toml
+func tomlIntegers(i interface{}) interface{} {
+
+	switch v := i.(type) {
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v)
+		}
+		return v
+	case map[string]interface{}:
+		r := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			r[k] = tomlIntegers(item)
+		}
+		return r
+	case []interface{}:
+		r := make([]interface{}, len(v))
+		for k, item := range v {
+			r[k] = tomlIntegers(item)
+		}
+		return r
+	default:
+		return v
+	}
+}
+
+func ToToml(i interface{}) ([]byte, error) {
+
+	v, err := NormalizeJson(i)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := tomlIntegers(v).(map[string]interface{})
+	if !ok {
+		return nil, errors.New("toml requires an object on the top level")
+	}
+
+	var buf bytes.Buffer
+	err = toml.NewEncoder(&buf).Encode(m)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func xmlAddChild(m map[string]interface{}, name string, value interface{}) {
+
+	existing, ok := m[name]
+	if !ok {
+		m[name] = value
+		return
+	}
+	arr, ok := existing.([]interface{})
+	if ok {
+		m[name] = append(arr, value)
+		return
+	}
+	m[name] = []interface{}{existing, value}
+}
+
+func xmlDecodeElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+
+	m := make(map[string]interface{})
+	for _, a := range start.Attr {
+		m[XmlAttributePrefix+a.Name.Local] = a.Value
+	}
+
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			child, err := xmlDecodeElement(decoder, t)
+			if err != nil {
+				return nil, err
+			}
+			xmlAddChild(m, t.Name.Local, child)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(m) == 0 {
+				return s, nil
+			}
+			if s != "" {
+				m[XmlTextKey] = s
+			}
+			return m, nil
+		}
+	}
+}
+
+// FromXml converts xml document into map, attributes are prefixed with "-" and element text is put into "#text"
+func FromXml(data []byte) (interface{}, error) {
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	r := make(map[string]interface{})
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		v, err := xmlDecodeElement(decoder, start)
+		if err != nil {
+			return nil, err
+		}
+		xmlAddChild(r, start.Name.Local, v)
+	}
+
+	if len(r) == 0 {
+		return nil, errors.New("xml has no root element")
+	}
+	return r, nil
+}
+
+func xmlEncodeElement(encoder *xml.Encoder, name string, value interface{}) error {
+
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if err := xmlEncodeElement(encoder, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+
+		start := xml.StartElement{Name: xml.Name{Local: name}}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if strings.HasPrefix(k, XmlAttributePrefix) {
+				start.Attr = append(start.Attr, xml.Attr{
+					Name:  xml.Name{Local: strings.TrimPrefix(k, XmlAttributePrefix)},
+					Value: ValueToString(v[k]),
+				})
+			}
+		}
+		if err := encoder.EncodeToken(start); err != nil {
+			return err
+		}
+		if text, ok := v[XmlTextKey]; ok {
+			if err := encoder.EncodeToken(xml.CharData(ValueToString(text))); err != nil {
+				return err
+			}
+		}
+		for _, k := range keys {
+			if k == XmlTextKey || strings.HasPrefix(k, XmlAttributePrefix) {
+				continue
+			}
+			if err := xmlEncodeElement(encoder, k, v[k]); err != nil {
+				return err
+			}
+		}
+		return encoder.EncodeToken(start.End())
+	default:
+		return encoder.EncodeElement(ValueToString(v), xml.StartElement{Name: xml.Name{Local: name}})
+	}
+}
+
+// ToXml converts map into xml document using the same conventions as FromXml
+func ToXml(i interface{}) ([]byte, error) {
+
+	v, err := NormalizeJson(i)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("xml requires an object on the top level")
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+
+	for _, k := range keys {
+		if err := xmlEncodeElement(encoder, k, m[k]); err != nil {
+			return nil, err
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FromIni converts ini into map, keys of the default section are placed on the top level
+func FromIni(data []byte) (interface{}, error) {
+
+	f, err := ini.LoadSources(ini.LoadOptions{}, data)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make(map[string]interface{})
+	for _, section := range f.Sections() {
+
+		keys := section.KeysHash()
+		if section.Name() == ini.DefaultSection {
+			for k, v := range keys {
+				r[k] = v
+			}
+			continue
+		}
+		m := make(map[string]interface{})
+		for k, v := range keys {
+			m[k] = v
+		}
+		r[section.Name()] = m
+	}
+	return r, nil
+}
+
+func ToIni(i interface{}) ([]byte, error) {
+
+	v, err := NormalizeJson(i)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("ini requires an object on the top level")
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	f := ini.Empty()
+	for _, k := range keys {
+
+		sm, ok := m[k].(map[string]interface{})
+		if !ok {
+			if _, err := f.Section("").NewKey(k, ValueToString(m[k])); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		section, err := f.NewSection(k)
+		if err != nil {
+			return nil, err
+		}
+		skeys := make([]string, 0, len(sm))
+		for sk := range sm {
+			skeys = append(skeys, sk)
+		}
+		sort.Strings(skeys)
+		for _, sk := range skeys {
+			if _, err := section.NewKey(sk, ValueToString(sm[sk])); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	_, err = f.WriteTo(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func FromHcl(data []byte) (interface{}, error) {
+
+	var r map[string]interface{}
+	err := hcl.Unmarshal(data, &r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,137 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromCsv(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		data     string
+		options  CsvOptions
+		expected interface{}
+	}{
+		{
+			name:    "Header row",
+			data:    "name,status\nhost-1,active\nhost-2,offline\n",
+			options: CsvOptions{Header: true},
+			expected: []interface{}{
+				map[string]interface{}{"name": "host-1", "status": "active"},
+				map[string]interface{}{"name": "host-2", "status": "offline"},
+			},
+		},
+		{
+			name:    "Header mapping and delimiter",
+			data:    "Name;Status\nhost-1;active\n",
+			options: CsvOptions{Header: true, Delimiter: ";", Mapping: map[string]string{"Name": "name"}},
+			expected: []interface{}{
+				map[string]interface{}{"name": "host-1", "Status": "active"},
+			},
+		},
+		{
+			name:    "Explicit columns without header",
+			data:    "host-1,\"active, primary\"\n",
+			options: CsvOptions{Columns: []string{"name", "status"}},
+			expected: []interface{}{
+				map[string]interface{}{"name": "host-1", "status": "active, primary"},
+			},
+		},
+		{
+			name:    "No columns",
+			data:    "a,b\nc,d\n",
+			options: CsvOptions{},
+			expected: []interface{}{
+				[]interface{}{"a", "b"},
+				[]interface{}{"c", "d"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := FromCsv([]byte(tt.data), tt.options)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, r)
+		})
+	}
+}
+
+func TestToCsv(t *testing.T) {
+
+	items := []interface{}{
+		map[string]interface{}{"name": "host-1", "cpu": float64(4)},
+		map[string]interface{}{"name": "host \"2\"", "cpu": 2.5},
+	}
+
+	r, err := ToCsv(items, CsvOptions{Header: true})
+	require.NoError(t, err)
+	assert.Equal(t, "cpu,name\n4,host-1\n2.5,\"host \"\"2\"\"\"\n", string(r))
+
+	r, err = ToCsv(items, CsvOptions{Header: true, Columns: []string{"name"}, Quote: CsvQuoteAll, Delimiter: "tab"})
+	require.NoError(t, err)
+	assert.Equal(t, "\"name\"\n\"host-1\"\n\"host \"\"2\"\"\"\n", string(r))
+
+	_, err = ToCsv(items, CsvOptions{Delimiter: "::"})
+	assert.Error(t, err)
+}
+
+func TestXml(t *testing.T) {
+
+	data := `<hosts><host id="1" state="up">web-1</host><host id="2"><name>db-1</name></host></hosts>`
+
+	r, err := FromXml([]byte(data))
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"hosts": map[string]interface{}{
+			"host": []interface{}{
+				map[string]interface{}{"-id": "1", "-state": "up", "#text": "web-1"},
+				map[string]interface{}{"-id": "2", "name": "db-1"},
+			},
+		},
+	}
+	assert.Equal(t, expected, r)
+
+	b, err := ToXml(r)
+	require.NoError(t, err)
+
+	r2, err := FromXml(b)
+	require.NoError(t, err)
+	assert.Equal(t, expected, r2)
+
+	_, err = FromXml([]byte("<hosts>"))
+	assert.Error(t, err)
+}
+
+func TestTomlIniHcl(t *testing.T) {
+
+	r, err := FromToml([]byte("title = \"tools\"\n[server]\nport = 8080\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "tools", r.(map[string]interface{})["title"])
+
+	b, err := ToToml(map[string]interface{}{"server": map[string]interface{}{"port": float64(8080)}})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "port = 8080")
+
+	r, err = FromIni([]byte("level = debug\n[jira]\nurl = https://jira\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"level": "debug",
+		"jira":  map[string]interface{}{"url": "https://jira"},
+	}, r)
+
+	b, err = ToIni(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "[jira]")
+
+	r, err = FromHcl([]byte("name = \"tools\"\ncount = 2\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "tools", r.(map[string]interface{})["name"])
+
+	_, err = FromToml([]byte("= broken"))
+	assert.Error(t, err)
+}
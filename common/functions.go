@@ -0,0 +1,204 @@
+package common
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/araddon/dateparse"
+	"github.com/devopsext/utils"
+)
+
+// functions shared by template engine and jsonata extensions
+
+// regexCache keeps compiled expressions, as the same regex is usually applied to every item of payload
+var regexCache sync.Map
+
+func regexCompile(re string) (*regexp.Regexp, error) {
+
+	if v, ok := regexCache.Load(re); ok {
+		return v.(*regexp.Regexp), nil
+	}
+	compiled, err := regexp.Compile(re)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Store(re, compiled)
+	return compiled, nil
+}
+
+func RegexMatch(re, s string) (bool, error) {
+	compiled, err := regexCompile(re)
+	if err != nil {
+		return false, err
+	}
+	return compiled.MatchString(s), nil
+}
+
+func RegexReplaceAll(re, pl, s string) (string, error) {
+	compiled, err := regexCompile(re)
+	if err != nil {
+		return "", err
+	}
+	return compiled.ReplaceAllString(s, pl), nil
+}
+
+func RegexFindSubmatch(re, s string) ([]string, error) {
+	compiled, err := regexCompile(re)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.FindStringSubmatch(s), nil
+}
+
+func regexMatchFindKey(v interface{}, field, value string) bool {
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if m[field] == nil {
+		return false
+	}
+	s := fmt.Sprintf("%v", m[field])
+	match, _ := regexp.MatchString(fmt.Sprintf("^%s", s), value)
+	return match
+}
+
+// RegexMatchFindKeys returns keys (indexes for arrays) of items which field is regex matching value
+func RegexMatchFindKeys(obj interface{}, field, value string) []interface{} {
+
+	var r []interface{}
+	if obj == nil || utils.IsEmpty(field) || utils.IsEmpty(value) {
+		return r
+	}
+
+	a, ok := obj.([]interface{})
+	if ok {
+		for k, v := range a {
+			if !regexMatchFindKey(v, field, value) {
+				continue
+			}
+			r = append(r, k)
+		}
+		return r
+	}
+
+	m, ok := obj.(map[string]interface{})
+	if ok {
+		for k, v := range m {
+			if !regexMatchFindKey(v, field, value) {
+				continue
+			}
+			r = append(r, k)
+		}
+	}
+
+	return r
+}
+
+func RegexMatchFindKey(obj interface{}, field, value string) interface{} {
+
+	keys := RegexMatchFindKeys(obj, field, value)
+	if len(keys) == 0 {
+		return value
+	}
+	return keys[0]
+}
+
+// RegexMatchObjectByField returns first item which field is regex matching value, nil if nothing matches
+func RegexMatchObjectByField(obj interface{}, field, value string) interface{} {
+
+	keys := RegexMatchFindKeys(obj, field, value)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	switch v := obj.(type) {
+	case []interface{}:
+		if k, ok := keys[0].(int); ok && k < len(v) {
+			return v[k]
+		}
+	case map[string]interface{}:
+		if k, ok := keys[0].(string); ok {
+			return v[k]
+		}
+	}
+	return nil
+}
+
+func DateParse(d string) (time.Time, error) {
+	return dateparse.ParseAny(d)
+}
+
+func IsIP(obj interface{}) bool {
+
+	if obj == nil {
+		return false
+	}
+
+	a := net.ParseIP(fmt.Sprintf("%v", obj))
+	return a != nil
+}
+
+func IsIPAndPort(obj interface{}) bool {
+
+	if obj == nil {
+		return false
+	}
+	s := fmt.Sprintf("%v", obj)
+	arr := strings.Split(s, ":")
+	if len(arr) > 0 {
+		s = strings.TrimSpace(arr[0])
+	} else {
+		return false
+	}
+	return IsIP(s)
+}
+
+// IsIPInCIDR checks that IP (with or without port) belongs to one of comma separated networks
+func IsIPInCIDR(obj interface{}, cidrs string) (bool, error) {
+
+	if obj == nil {
+		return false, nil
+	}
+	s := strings.TrimSpace(fmt.Sprintf("%v", obj))
+	ip := net.ParseIP(s)
+	if ip == nil {
+		host, _, err := net.SplitHostPort(s)
+		if err != nil {
+			return false, nil
+		}
+		ip = net.ParseIP(host)
+	}
+	if ip == nil {
+		return false, nil
+	}
+
+	for _, cidr := range RemoveEmptyStrings(strings.Split(cidrs, ",")) {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return false, err
+		}
+		if network.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func Base64Encode(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func Base64Decode(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,462 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devopsext/utils"
+	"golang.org/x/net/http/httpproxy"
+)
+
+const UserAgentHeader = "User-Agent"
+
+// HttpRequestHook is called before every attempt, e.g. to add headers
+type HttpRequestHook func(req *http.Request)
+
+// HttpResponseHook is called after every attempt for logging or metrics, resp is nil if err is not nil
+type HttpResponseHook func(req *http.Request, resp *http.Response, err error, duration time.Duration)
+
+type HttpClientOptions struct {
+	Timeout    int
+	Insecure   bool
+	Proxy      string // http(s)://host:port, HTTP_PROXY, HTTPS_PROXY by default
+	NoProxy    string // comma separated hosts, NO_PROXY by default
+	CA         string // file or content of PEM bundle appended to system pool
+	UserAgent  string
+	Retry      RetryOptions // requests are retried on 429/5xx if attempts are more than 1
+	OnRequest  []HttpRequestHook
+	OnResponse []HttpResponseHook
+	Cassette   CassetteOptions // records or replays requests, name is set per vendor
+}
+
+var httpClientDefaults = struct {
+	mutex   sync.RWMutex
+	options HttpClientOptions
+}{}
+
+// SetHttpClientDefaults sets options, except timeout and insecure, for all clients created by NewHttpClient and VendorHttpClient
+func SetHttpClientDefaults(options HttpClientOptions) error {
+
+	if _, err := httpClientTransport(options); err != nil {
+		return err
+	}
+	if err := validateCassette(options.Cassette); err != nil {
+		return err
+	}
+	httpClientDefaults.mutex.Lock()
+	defer httpClientDefaults.mutex.Unlock()
+	httpClientDefaults.options = options
+	return nil
+}
+
+func HttpClientDefaults() HttpClientOptions {
+
+	httpClientDefaults.mutex.RLock()
+	defer httpClientDefaults.mutex.RUnlock()
+	return httpClientDefaults.options
+}
+
+// AddHttpClientHooks appends hooks to defaults
+func AddHttpClientHooks(onRequest HttpRequestHook, onResponse HttpResponseHook) {
+
+	httpClientDefaults.mutex.Lock()
+	defer httpClientDefaults.mutex.Unlock()
+	if onRequest != nil {
+		httpClientDefaults.options.OnRequest = append(httpClientDefaults.options.OnRequest, onRequest)
+	}
+	if onResponse != nil {
+		httpClientDefaults.options.OnResponse = append(httpClientDefaults.options.OnResponse, onResponse)
+	}
+}
+
+func httpClientProxy(options HttpClientOptions) (func(*http.Request) (*url.URL, error), error) {
+
+	if utils.IsEmpty(options.Proxy) && utils.IsEmpty(options.NoProxy) {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	config := httpproxy.FromEnvironment()
+	if !utils.IsEmpty(options.Proxy) {
+		if _, err := url.Parse(options.Proxy); err != nil {
+			return nil, fmt.Errorf("invalid proxy %s: %v", options.Proxy, err)
+		}
+		config.HTTPProxy = options.Proxy
+		config.HTTPSProxy = options.Proxy
+	}
+	if !utils.IsEmpty(options.NoProxy) {
+		config.NoProxy = options.NoProxy
+	}
+	proxy := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+func httpClientRootCAs(ca string) (*x509.CertPool, error) {
+
+	if utils.IsEmpty(ca) {
+		return nil, nil
+	}
+	pem, err := utils.Content(ca)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA %s", ca)
+	}
+	return pool, nil
+}
+
+func httpClientTransport(options HttpClientOptions) (*http.Transport, error) {
+
+	proxy, err := httpClientProxy(options)
+	if err != nil {
+		return nil, err
+	}
+	rootCAs, err := httpClientRootCAs(options.CA)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := time.Duration(options.Timeout) * time.Second
+	return &http.Transport{
+		Proxy:               proxy,
+		DialContext:         (&net.Dialer{Timeout: timeout}).DialContext,
+		TLSHandshakeTimeout: timeout,
+		TLSClientConfig: &tls.Config{
+			RootCAs:            rootCAs,
+			InsecureSkipVerify: options.Insecure,
+		},
+	}, nil
+}
+
+// httpIdempotentMethods are retried on 5xx and network errors, other methods could create objects twice
+var httpIdempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// httpRetryable checks that failed attempt can be repeated, requests like POST are retried only if server
+// tells that they are not handled: on 429 or on 503 with Retry-After
+func httpRetryable(req *http.Request, resp *http.Response, state RetryState, options RetryOptions) bool {
+
+	if !options.ShouldRetry(state) {
+		return false
+	}
+	if httpIdempotentMethods[req.Method] || utils.IsEmpty(req.Method) {
+		return true
+	}
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusServiceUnavailable && !utils.IsEmpty(resp.Header.Get(RetryAfterHeader)))
+}
+
+// httpClientRoundTripper sets user agent, calls hooks and retries idempotent requests on 429/5xx honoring Retry-After
+type httpClientRoundTripper struct {
+	transport http.RoundTripper
+	options   HttpClientOptions
+}
+
+func (t *httpClientRoundTripper) attempt(req *http.Request) (*http.Response, error) {
+
+	for _, hook := range t.options.OnRequest {
+		hook(req)
+	}
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	for _, hook := range t.options.OnResponse {
+		hook(req, resp, err, time.Since(start))
+	}
+	return resp, err
+}
+
+func (t *httpClientRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if !utils.IsEmpty(t.options.UserAgent) && req.Header.Get(UserAgentHeader) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(UserAgentHeader, t.options.UserAgent)
+	}
+
+	// body which can't be rewound is sent once
+	attempts := t.options.Retry.attempts()
+	if t.options.Retry.Attempts < 2 || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		attempts = 1
+	}
+
+	for attempt := 0; ; attempt++ {
+
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := t.attempt(req)
+		state := RetryState{Err: err}
+		if resp != nil {
+			state.StatusCode = resp.StatusCode
+			state.RetryAfter, _ = ParseRetryAfter(resp.Header.Get(RetryAfterHeader))
+		}
+		if attempt == attempts-1 || !httpRetryable(req, resp, state, t.options.Retry) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		delay := t.options.Retry.Backoff(attempt)
+		if state.RetryAfter > 0 {
+			delay = state.RetryAfter
+			if t.options.Retry.MaxDelay > 0 && delay > t.options.Retry.MaxDelay {
+				delay = t.options.Retry.MaxDelay
+			}
+		}
+		if err := SleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// NewHttpClientWithOptions creates client with proxy, CA, retries and hooks, requests are traced if tracing is enabled
+func NewHttpClientWithOptions(options HttpClientOptions) (*http.Client, error) {
+
+	transport, err := httpClientTransport(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var rt http.RoundTripper = transport
+	if rt, err = NewCassetteTransport(rt, options.Cassette); err != nil {
+		return nil, err
+	}
+	rt = TracingTransport(rt)
+	if !utils.IsEmpty(options.UserAgent) || options.Retry.Attempts > 1 || len(options.OnRequest) > 0 || len(options.OnResponse) > 0 {
+		rt = &httpClientRoundTripper{transport: rt, options: options}
+	}
+
+	return &http.Client{
+		Timeout:   time.Duration(options.Timeout) * time.Second,
+		Transport: rt,
+	}, nil
+}
+
+// NewHttpClient creates client with defaults, defaults are validated when set, so invalid proxy or CA is reported once
+func NewHttpClient(timeout int, insecure bool) *http.Client {
+	return newHttpClient("", timeout, insecure)
+}
+
+func newHttpClient(name string, timeout int, insecure bool) *http.Client {
+
+	options := HttpClientDefaults()
+	options.Timeout = timeout
+	options.Insecure = insecure
+	if !utils.IsEmpty(name) {
+		ua := options.UserAgent
+		if utils.IsEmpty(ua) {
+			ua = "tools"
+		}
+		options.UserAgent = fmt.Sprintf("%s (%s)", ua, name)
+		options.Cassette.Name = name
+	}
+
+	client, err := NewHttpClientWithOptions(options)
+	if err != nil {
+		client = utils.NewHttpClient(timeout, insecure)
+		client.Transport = TracingTransport(CassetteTransport(client.Transport, name))
+	}
+	return client
+}
+
+type contextTransport struct {
+	transport http.RoundTripper
+	ctx       context.Context
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if req.Context() == context.Background() {
+		req = req.WithContext(t.ctx)
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// HttpClientWithContext returns copy of client whose requests, created without context, are cancelled with ctx
+func HttpClientWithContext(ctx context.Context, client *http.Client) *http.Client {
+
+	if ctx == nil || ctx == context.Background() || client == nil {
+		return client
+	}
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	c := *client
+	c.Transport = &contextTransport{transport: rt, ctx: ctx}
+	return &c
+}
+
+// VendorHttpClient returns injected client as is, otherwise creates client with defaults and vendor in user agent
+func VendorHttpClient(client *http.Client, vendor string, timeout int, insecure bool) *http.Client {
+
+	if client != nil {
+		return client
+	}
+	return newHttpClient(strings.ToLower(vendor), timeout, insecure)
+}
+
+// HttpStatusError is returned by HTTP helpers for responses which are not 2xx, vendor errors take status and headers from it
+type HttpStatusError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+}
+
+func (e *HttpStatusError) Error() string {
+	return e.Status
+}
+
+func httpHeaders(contentType, authorization string) map[string]string {
+
+	headers := make(map[string]string)
+	if !utils.IsEmpty(contentType) {
+		headers["Content-Type"] = contentType
+	}
+	if !utils.IsEmpty(authorization) {
+		headers["Authorization"] = authorization
+	}
+	return headers
+}
+
+// httpRequestRaw sends request, 429 responses are retried if retries are set, retryHeader has duration to wait
+func httpRequestRaw(client *http.Client, method, URL string, headers map[string]string, raw []byte, retries int, retryHeader string) ([]byte, int, error) {
+
+	if retries < 1 {
+		retries = 1
+	}
+	for attempt := 0; attempt < retries; attempt++ {
+
+		var reader io.Reader
+		if raw != nil {
+			reader = bytes.NewReader(raw)
+		}
+		req, err := http.NewRequest(method, URL, reader)
+		if err != nil {
+			return nil, 0, err
+		}
+		for k, v := range headers {
+			if !utils.IsEmpty(v) {
+				req.Header.Set(k, v)
+			}
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, 0, err
+		}
+		b, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, resp.StatusCode, err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < retries-1 {
+			delay, err := time.ParseDuration(resp.Header.Get(retryHeader))
+			if err != nil || utils.IsEmpty(retryHeader) {
+				delay = time.Second << attempt
+			}
+			if err := SleepContext(req.Context(), delay); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return b, resp.StatusCode, &HttpStatusError{StatusCode: resp.StatusCode, Status: resp.Status, Header: resp.Header}
+		}
+		return b, resp.StatusCode, nil
+	}
+	return nil, 0, fmt.Errorf("max retries exceeded")
+}
+
+// HttpRequestRawWithHeadersOutCode is like utils.HttpRequestRawWithHeadersOutCode, error of failed response is HttpStatusError
+func HttpRequestRawWithHeadersOutCode(client *http.Client, method, URL string, headers map[string]string, raw []byte) ([]byte, int, error) {
+	return httpRequestRaw(client, method, URL, headers, raw, 1, "")
+}
+
+func HttpRequestRawWithHeaders(client *http.Client, method, URL string, headers map[string]string, raw []byte) ([]byte, error) {
+	b, _, err := httpRequestRaw(client, method, URL, headers, raw, 1, "")
+	return b, err
+}
+
+func HttpGetRawWithHeaders(client *http.Client, URL string, headers map[string]string) ([]byte, error) {
+	return HttpRequestRawWithHeaders(client, http.MethodGet, URL, headers, nil)
+}
+
+func HttpGetRaw(client *http.Client, URL, contentType, authorization string) ([]byte, error) {
+	return HttpGetRawWithHeaders(client, URL, httpHeaders(contentType, authorization))
+}
+
+func HttpGetRawRetry(client *http.Client, URL, contentType, authorization string, retries int, retryHeader string) ([]byte, error) {
+	b, _, err := httpRequestRaw(client, http.MethodGet, URL, httpHeaders(contentType, authorization), nil, retries, retryHeader)
+	return b, err
+}
+
+func HttpPostRawWithHeaders(client *http.Client, URL string, headers map[string]string, raw []byte) ([]byte, error) {
+	return HttpRequestRawWithHeaders(client, http.MethodPost, URL, headers, raw)
+}
+
+func HttpPostRaw(client *http.Client, URL, contentType, authorization string, raw []byte) ([]byte, error) {
+	return HttpPostRawWithHeaders(client, URL, httpHeaders(contentType, authorization), raw)
+}
+
+func HttpPostRawOutCode(client *http.Client, URL, contentType, authorization string, raw []byte) ([]byte, int, error) {
+	return httpRequestRaw(client, http.MethodPost, URL, httpHeaders(contentType, authorization), raw, 1, "")
+}
+
+func HttpPostRawRetry(client *http.Client, URL, contentType, authorization string, raw []byte, retries int, retryHeader string) ([]byte, error) {
+	b, _, err := httpRequestRaw(client, http.MethodPost, URL, httpHeaders(contentType, authorization), raw, retries, retryHeader)
+	return b, err
+}
+
+func HttpPutRawWithHeaders(client *http.Client, URL string, headers map[string]string, raw []byte) ([]byte, error) {
+	return HttpRequestRawWithHeaders(client, http.MethodPut, URL, headers, raw)
+}
+
+func HttpPutRaw(client *http.Client, URL, contentType, authorization string, raw []byte) ([]byte, error) {
+	return HttpPutRawWithHeaders(client, URL, httpHeaders(contentType, authorization), raw)
+}
+
+func HttpPatchRaw(client *http.Client, URL, contentType, authorization string, raw []byte) ([]byte, error) {
+	return HttpRequestRawWithHeaders(client, http.MethodPatch, URL, httpHeaders(contentType, authorization), raw)
+}
+
+func HttpDeleteRawWithHeaders(client *http.Client, URL string, headers map[string]string, raw []byte) ([]byte, error) {
+	return HttpRequestRawWithHeaders(client, http.MethodDelete, URL, headers, raw)
+}
+
+func HttpDeleteRaw(client *http.Client, URL, contentType, authorization string, raw []byte) ([]byte, error) {
+	return HttpDeleteRawWithHeaders(client, URL, httpHeaders(contentType, authorization), raw)
+}
@@ -0,0 +1,177 @@
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpClientRetry(t *testing.T) {
+
+	var delays []time.Duration
+	retryTimer = func(d time.Duration) *time.Timer {
+		delays = append(delays, d)
+		return time.NewTimer(0)
+	}
+	defer func() {
+		retryTimer = time.NewTimer
+	}()
+
+	var bodies []string
+	var agents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		agents = append(agents, r.Header.Get(UserAgentHeader))
+		switch len(bodies) {
+		case 1:
+			w.Header().Set(RetryAfterHeader, "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte("OK"))
+		}
+	}))
+	defer srv.Close()
+
+	var statuses []int
+	client, err := NewHttpClientWithOptions(HttpClientOptions{
+		Timeout:   5,
+		UserAgent: "tools/test",
+		Retry:     RetryOptions{Attempts: 3, Delay: time.Millisecond},
+		OnResponse: []HttpResponseHook{func(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+			statuses = append(statuses, resp.StatusCode)
+		}},
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []int{429, 502, 200}, statuses)
+	assert.Equal(t, []string{"payload", "payload", "payload"}, bodies)
+	assert.Equal(t, []string{"tools/test", "tools/test", "tools/test"}, agents)
+	assert.Equal(t, []time.Duration{3 * time.Second, 2 * time.Millisecond}, delays)
+}
+
+func TestHttpClientRetryPost(t *testing.T) {
+
+	retryTimer = func(d time.Duration) *time.Timer {
+		return time.NewTimer(0)
+	}
+	defer func() {
+		retryTimer = time.NewTimer
+	}()
+
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		calls      int
+	}{
+		{name: "Server error is sent once", status: http.StatusInternalServerError, calls: 1},
+		{name: "Unavailable without Retry-After is sent once", status: http.StatusServiceUnavailable, calls: 1},
+		{name: "Unavailable with Retry-After is retried", status: http.StatusServiceUnavailable, retryAfter: "1", calls: 2},
+		{name: "Too many requests is retried", status: http.StatusTooManyRequests, calls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls == 1 {
+					if tt.retryAfter != "" {
+						w.Header().Set(RetryAfterHeader, tt.retryAfter)
+					}
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Write([]byte("OK"))
+			}))
+			defer srv.Close()
+
+			client, err := NewHttpClientWithOptions(HttpClientOptions{Timeout: 5, Retry: RetryOptions{Attempts: 3, Delay: time.Millisecond}})
+			require.NoError(t, err)
+
+			resp, err := client.Post(srv.URL, "application/json", strings.NewReader(`{"text":"alert"}`))
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.calls, calls)
+		})
+	}
+}
+
+func TestHttpClientProxy(t *testing.T) {
+
+	var requested string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		w.Write([]byte("OK"))
+	}))
+	defer proxy.Close()
+
+	client, err := NewHttpClientWithOptions(HttpClientOptions{Timeout: 5, Proxy: proxy.URL, NoProxy: "skip.example.com"})
+	require.NoError(t, err)
+
+	resp, err := client.Get("http://api.example.com/v1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "http://api.example.com/v1", requested)
+
+	_, err = NewHttpClientWithOptions(HttpClientOptions{CA: "not a certificate"})
+	assert.Error(t, err)
+}
+
+func TestVendorHttpClient(t *testing.T) {
+
+	injected := &http.Client{}
+	assert.Same(t, injected, VendorHttpClient(injected, "slack", 5, false))
+
+	var agent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent = r.Header.Get(UserAgentHeader)
+	}))
+	defer srv.Close()
+
+	resp, err := VendorHttpClient(nil, "slack", 5, false).Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "tools (slack)", agent)
+}
+
+func TestHttpClientWithContext(t *testing.T) {
+
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := HttpClientWithContext(ctx, NewHttpClient(5, false))
+	_, err := client.Get(srv.URL)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.Same(t, http.DefaultClient, HttpClientWithContext(context.Background(), http.DefaultClient))
+}
@@ -1,24 +1,161 @@
 package common
 
 import (
+	"container/list"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/blues/jsonata-go"
 	"github.com/blues/jsonata-go/jtypes"
 	"github.com/devopsext/utils"
+	"github.com/google/uuid"
 )
 
 type JsonataOptions struct {
+	Libs []string // files or content with function definitions
 }
 
 type Jsonata struct {
 	options JsonataOptions
+	mutex   sync.RWMutex
+	vars    map[string]interface{}
 }
 
+// jsonataExpr is compiled expression with extensions, variables of the last evaluation stay registered,
+// so it's used by one evaluation at a time unless it has no variables
+type jsonataExpr struct {
+	expr *jsonata.Expr
+	vars string // names of registered variables
+}
+
+// jsonataEntry keeps expressions of one query, expression without variables is shared,
+// expressions with variables are taken by evaluation and returned after it
+type jsonataEntry struct {
+	mutex  sync.Mutex
+	query  string
+	shared *jsonata.Expr
+	free   []*jsonataExpr
+}
+
+// jsonataCache keeps compiled expressions by query text in LRU order
+type jsonataCache struct {
+	mutex sync.Mutex
+	size  int
+	items map[string]*list.Element
+	order *list.List
+}
+
+const (
+	jsonataCacheSize = 1024
+	jsonataFreeSize  = 16
+)
+
+var jsonataCompiled = newJsonataCache(jsonataCacheSize)
+
+func newJsonataCache(size int) *jsonataCache {
+
+	return &jsonataCache{
+		size:  size,
+		items: make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+// entry returns entry of query and moves it to front, least recently used entry is evicted when cache is full
+func (c *jsonataCache) entry(query string) *jsonataEntry {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if el, ok := c.items[query]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*jsonataEntry)
+	}
+
+	e := &jsonataEntry{query: query}
+	c.items[query] = c.order.PushFront(e)
+	if c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.items, last.Value.(*jsonataEntry).query)
+	}
+	return e
+}
+
+func (c *jsonataCache) len() int {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+func (e *jsonataEntry) sharedExpr(compile func(string) (*jsonata.Expr, error)) (*jsonata.Expr, error) {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.shared != nil {
+		return e.shared, nil
+	}
+	expr, err := compile(e.query)
+	if err != nil {
+		return nil, err
+	}
+	e.shared = expr
+	return expr, nil
+}
+
+// acquire returns free expression which has the same variable names, so no variable of other evaluation is left
+func (e *jsonataEntry) acquire(vars string, compile func(string) (*jsonata.Expr, error)) (*jsonataExpr, error) {
+
+	e.mutex.Lock()
+	for i := len(e.free) - 1; i >= 0; i-- {
+		if e.free[i].vars == vars {
+			x := e.free[i]
+			e.free = append(e.free[:i], e.free[i+1:]...)
+			e.mutex.Unlock()
+			return x, nil
+		}
+	}
+	e.mutex.Unlock()
+
+	expr, err := compile(e.query)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonataExpr{expr: expr, vars: vars}, nil
+}
+
+func (e *jsonataEntry) release(x *jsonataExpr) {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if len(e.free) >= jsonataFreeSize {
+		e.free = e.free[1:]
+	}
+	e.free = append(e.free, x)
+}
+
+// RegisterVars adds variables to this instance only, they are bound on each evaluation
 func (j *Jsonata) RegisterVars(vars map[string]interface{}) error {
-	return jsonata.RegisterVars(vars)
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.vars == nil {
+		j.vars = make(map[string]interface{}, len(vars))
+	}
+	for k, v := range vars {
+		j.vars[k] = v
+	}
+	return nil
 }
 
 func (j *Jsonata) fEnv(key string) string {
@@ -40,44 +177,47 @@ func (j *Jsonata) fHttpGet(URL, contentType string) interface{} {
 	return r
 }
 
-/*func (j *Jsonata) fRegexMatchObjectByFields(obj map[string]interface{}, field, value string) []interface{} {
+func (j *Jsonata) fDateParse(d string) (string, error) {
 
-	var r []interface{}
-	if obj == nil || utils.IsEmpty(field) {
-		return r
+	t, err := DateParse(d)
+	if err != nil {
+		return "", err
 	}
+	return t.Format(time.RFC3339Nano), nil
+}
 
-	for k, v := range obj {
+func (j *Jsonata) fDateAdd(d, duration string) (string, error) {
 
-		m, ok := v.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		if m[field] == nil {
-			continue
-		}
-		s, ok := m[field].(string)
-		if !ok {
-			continue
-		}
-		match, _ := regexp.MatchString(fmt.Sprintf("^%s", s), value)
-		if match {
-			r = append(r, k)
-		}
+	t, err := DateParse(d)
+	if err != nil {
+		return "", err
 	}
-	return r
+	dur, err := time.ParseDuration(duration)
+	if err != nil {
+		return "", err
+	}
+	return t.Add(dur).Format(time.RFC3339Nano), nil
 }
 
-func (j *Jsonata) fRegexMatchObjectByField(obj map[string]interface{}, field, value string) interface{} {
+// fDateDiff returns milliseconds between dates like $toMillis does
+func (j *Jsonata) fDateDiff(from, to string) (float64, error) {
 
-	keys := j.fRegexMatchObjectByFields(obj, field, value)
-	if len(keys) == 0 {
-		return value
+	t1, err := DateParse(from)
+	if err != nil {
+		return 0, err
 	}
-	return keys[0]
-}*/
+	t2, err := DateParse(to)
+	if err != nil {
+		return 0, err
+	}
+	return float64(t2.Sub(t1).Milliseconds()), nil
+}
 
-func (j *Jsonata) Eval(data interface{}, query string) (interface{}, error) {
+func (j *Jsonata) fUUID() string {
+	return uuid.New().String()
+}
+
+func (j *Jsonata) extensions() map[string]jsonata.Extension {
 
 	exts := make(map[string]jsonata.Extension)
 	exts["env"] = jsonata.Extension{
@@ -90,19 +230,120 @@ func (j *Jsonata) Eval(data interface{}, query string) (interface{}, error) {
 		UndefinedHandler:   jtypes.ArgUndefined(0),
 		EvalContextHandler: jtypes.ArgCountEquals(0),
 	}
-	/*exts["regexMatchObjectByField"] = jsonata.Extension{
-		Func:               tpl,
+	exts["regexMatch"] = jsonata.Extension{Func: RegexMatch}
+	exts["regexReplaceAll"] = jsonata.Extension{Func: RegexReplaceAll}
+	exts["regexFindSubmatch"] = jsonata.Extension{Func: RegexFindSubmatch}
+	exts["regexMatchFindKeys"] = jsonata.Extension{Func: RegexMatchFindKeys}
+	exts["regexMatchFindKey"] = jsonata.Extension{Func: RegexMatchFindKey}
+	exts["regexMatchObjectByField"] = jsonata.Extension{Func: RegexMatchObjectByField}
+	exts["dateParse"] = jsonata.Extension{
+		Func:               j.fDateParse,
 		UndefinedHandler:   jtypes.ArgUndefined(0),
 		EvalContextHandler: jtypes.ArgCountEquals(0),
-	}*/
-	jsonata.RegisterExts(exts)
+	}
+	exts["dateAdd"] = jsonata.Extension{Func: j.fDateAdd}
+	exts["dateDiff"] = jsonata.Extension{Func: j.fDateDiff}
+	exts["countryShort"] = jsonata.Extension{
+		Func:               CountryShort,
+		UndefinedHandler:   jtypes.ArgUndefined(0),
+		EvalContextHandler: jtypes.ArgCountEquals(0),
+	}
+	exts["ifIP"] = jsonata.Extension{
+		Func:               IsIP,
+		EvalContextHandler: jtypes.ArgCountEquals(0),
+	}
+	exts["ifIPAndPort"] = jsonata.Extension{
+		Func:               IsIPAndPort,
+		EvalContextHandler: jtypes.ArgCountEquals(0),
+	}
+	exts["ifIPInCIDR"] = jsonata.Extension{Func: IsIPInCIDR}
+	exts["uuid"] = jsonata.Extension{Func: j.fUUID}
+	exts["base64"] = jsonata.Extension{
+		Func:               Base64Encode,
+		UndefinedHandler:   jtypes.ArgUndefined(0),
+		EvalContextHandler: jtypes.ArgCountEquals(0),
+	}
+	exts["base64Decode"] = jsonata.Extension{
+		Func:               Base64Decode,
+		UndefinedHandler:   jtypes.ArgUndefined(0),
+		EvalContextHandler: jtypes.ArgCountEquals(0),
+	}
+	return exts
+}
+
+// query prepends function libraries to query, library is a block of definitions like "$double := function($x) { $x * 2 };"
+func (j *Jsonata) query(query string) (string, error) {
+
+	var libs []string
+	for _, lib := range RemoveEmptyStrings(j.options.Libs) {
+
+		b, err := utils.Content(strings.TrimSpace(lib))
+		if err != nil {
+			return "", err
+		}
+		s := strings.TrimSpace(string(b))
+		if s == "" {
+			continue
+		}
+		if !strings.HasSuffix(s, ";") {
+			s = s + ";"
+		}
+		libs = append(libs, s)
+	}
+
+	if len(libs) == 0 {
+		return query, nil
+	}
+	return fmt.Sprintf("(\n%s\n%s\n)", strings.Join(libs, "\n"), query), nil
+}
+
+func (j *Jsonata) compile(query string) (*jsonata.Expr, error) {
 
-	expr := jsonata.MustCompile(query)
-	v, err := expr.Eval(data)
+	expr, err := jsonata.Compile(query)
 	if err != nil {
 		return nil, err
 	}
-	return v, nil
+	if err := expr.RegisterExts(j.extensions()); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+func (j *Jsonata) Eval(data interface{}, query string) (interface{}, error) {
+
+	query, err := j.query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+
+	entry := jsonataCompiled.entry(query)
+	if len(j.vars) == 0 {
+		expr, err := entry.sharedExpr(j.compile)
+		if err != nil {
+			return nil, err
+		}
+		return expr.Eval(data)
+	}
+
+	// variables are bound to expression taken by this evaluation only, so they don't leak to others
+	names := make([]string, 0, len(j.vars))
+	for k := range j.vars {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	x, err := entry.acquire(strings.Join(names, ","), j.compile)
+	if err != nil {
+		return nil, err
+	}
+	if err := x.expr.RegisterVars(j.vars); err != nil {
+		return nil, err
+	}
+	defer entry.release(x)
+	return x.expr.Eval(data)
 }
 
 func NewJsonata(options JsonataOptions) *Jsonata {
@@ -0,0 +1,314 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/blues/jsonata-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJsonataExtensions(t *testing.T) {
+
+	data := map[string]interface{}{
+		"hosts": []interface{}{
+			map[string]interface{}{"name": "web", "ip": "10.0.0.5:8080"},
+			map[string]interface{}{"name": "db", "ip": "192.168.1.10"},
+		},
+		"date": "2024-01-02 03:04:05",
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  interface{}
+	}{
+		{name: "regexMatch", query: `$regexMatch("^w", hosts[0].name)`, want: true},
+		{name: "regexFindSubmatch", query: `$regexFindSubmatch("(\\d+)\\.(\\d+)", hosts[1].ip)[2]`, want: "168"},
+		{name: "regexMatchObjectByField", query: `$regexMatchObjectByField(hosts, "name", "db-01").ip`, want: "192.168.1.10"},
+		{name: "dateParse", query: `$dateParse(date)`, want: "2024-01-02T03:04:05Z"},
+		{name: "dateAdd", query: `$dateAdd(date, "1h")`, want: "2024-01-02T04:04:05Z"},
+		{name: "dateDiff", query: `$dateDiff(date, "2024-01-02 03:04:06")`, want: float64(1000)},
+		{name: "ifIP", query: `hosts[$ifIP(ip)].name`, want: "db"},
+		{name: "ifIPAndPort", query: `$ifIPAndPort(hosts[0].ip)`, want: true},
+		{name: "ifIPInCIDR", query: `hosts[$ifIPInCIDR(ip, "10.0.0.0/8")].name`, want: "web"},
+		{name: "base64", query: `$base64Decode($base64("tools"))`, want: "tools"},
+	}
+
+	j := NewJsonata(JsonataOptions{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := j.Eval(data, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	v, err := j.Eval(data, `$uuid()`)
+	require.NoError(t, err)
+	assert.Len(t, v, 36)
+}
+
+func TestRegexMatchObjectByField(t *testing.T) {
+
+	hosts := []interface{}{
+		map[string]interface{}{"name": "web", "ip": "10.0.0.5"},
+		map[string]interface{}{"name": "db", "ip": "192.168.1.10"},
+	}
+
+	tests := []struct {
+		name  string
+		obj   interface{}
+		value string
+		want  interface{}
+	}{
+		{name: "Match", obj: hosts, value: "db-01", want: hosts[1]},
+		{name: "No match", obj: hosts, value: "cache-01", want: nil},
+		{name: "Empty array", obj: []interface{}{}, value: "db-01", want: nil},
+		{name: "Map", obj: map[string]interface{}{"primary": hosts[0]}, value: "web-01", want: hosts[0]},
+		{name: "Nil", obj: nil, value: "db-01", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RegexMatchObjectByField(tt.obj, "name", tt.value))
+		})
+	}
+
+	// extension returns undefined instead of panic
+	j := NewJsonata(JsonataOptions{})
+	v, err := j.Eval(map[string]interface{}{"hosts": hosts}, `$regexMatchObjectByField([], "name", "db-01")`)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	_, err = j.Eval(map[string]interface{}{"hosts": hosts}, `$regexMatchObjectByField(hosts, "name", "cache-01").ip`)
+	assert.ErrorIs(t, err, jsonata.ErrUndefined)
+}
+
+func TestJsonataLibs(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "lib.jsonata")
+	require.NoError(t, os.WriteFile(file, []byte(`$double := function($x) { $x * 2 }`), 0600))
+
+	j := NewJsonata(JsonataOptions{Libs: []string{file, `$inc := function($x) { $x + 1 };`}})
+	v, err := j.Eval(map[string]interface{}{"n": 2}, `$inc($double(n))`)
+	require.NoError(t, err)
+	assert.Equal(t, float64(5), v)
+}
+
+func TestJsonataConcurrentVars(t *testing.T) {
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			j := NewJsonata(JsonataOptions{})
+			j.RegisterVars(map[string]interface{}{"n": i})
+
+			v, err := j.Eval(map[string]interface{}{"k": 1}, `$n + k`)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if v != float64(i+1) {
+				errs <- fmt.Errorf("expected %d, got %v", i+1, v)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+func TestJsonataCacheVars(t *testing.T) {
+
+	query := `$n & "-" & k & ($exists($m) ? "-m" : "")`
+	eval := func(vars map[string]interface{}) (interface{}, error) {
+		j := NewJsonata(JsonataOptions{})
+		j.RegisterVars(vars)
+		return j.Eval(map[string]interface{}{"k": "v"}, query)
+	}
+
+	v, err := eval(map[string]interface{}{"n": "a", "m": 1})
+	require.NoError(t, err)
+	assert.Equal(t, "a-v-m", v)
+
+	// expression of the first evaluation is reused with new values
+	v, err = eval(map[string]interface{}{"n": "b", "m": 2})
+	require.NoError(t, err)
+	assert.Equal(t, "b-v-m", v)
+
+	entry := jsonataCompiled.entry(query)
+	require.Len(t, entry.free, 1)
+	assert.Equal(t, "m,n", entry.free[0].vars)
+
+	// variables of other evaluations are not visible
+	v, err = eval(map[string]interface{}{"n": "c"})
+	require.NoError(t, err)
+	assert.Equal(t, "c-v", v)
+	assert.Len(t, entry.free, 2)
+}
+
+func TestJsonataCacheLRU(t *testing.T) {
+
+	c := newJsonataCache(2)
+	a := c.entry("a")
+	c.entry("b")
+	assert.Same(t, a, c.entry("a"))
+
+	c.entry("c")
+	assert.Equal(t, 2, c.len())
+	assert.Contains(t, c.items, "a")
+	assert.Contains(t, c.items, "c")
+	assert.NotContains(t, c.items, "b")
+}
+
+func jsonataZabbixPayload(n int) interface{} {
+
+	var hosts []interface{}
+	for i := 0; i < n; i++ {
+		hosts = append(hosts, map[string]interface{}{
+			"hostid": strconv.Itoa(10000 + i),
+			"host":   fmt.Sprintf("host-%d.example.com", i),
+			"status": strconv.Itoa(i % 2),
+			"groups": []interface{}{
+				map[string]interface{}{"groupid": strconv.Itoa(i % 20), "name": fmt.Sprintf("group-%d", i%20)},
+			},
+			"interfaces": []interface{}{
+				map[string]interface{}{"ip": fmt.Sprintf("10.%d.%d.%d", i/65536%256, i/256%256, i%256), "port": "10050"},
+			},
+		})
+	}
+	return map[string]interface{}{"jsonrpc": "2.0", "result": hosts}
+}
+
+func jsonataNetboxPayload(n int) interface{} {
+
+	var devices []interface{}
+	for i := 0; i < n; i++ {
+		devices = append(devices, map[string]interface{}{
+			"id":          float64(i),
+			"name":        fmt.Sprintf("device-%d", i),
+			"status":      map[string]interface{}{"value": "active", "label": "Active"},
+			"site":        map[string]interface{}{"slug": fmt.Sprintf("site-%d", i%10)},
+			"role":        map[string]interface{}{"slug": "server"},
+			"primary_ip4": map[string]interface{}{"address": fmt.Sprintf("192.168.%d.%d/24", i/256%256, i%256)},
+		})
+	}
+	return map[string]interface{}{"count": float64(n), "results": devices}
+}
+
+func benchmarkJsonata(b *testing.B, data interface{}, query string) {
+
+	b.Run("cached", func(b *testing.B) {
+		j := NewJsonata(JsonataOptions{})
+		for i := 0; i < b.N; i++ {
+			if _, err := j.Eval(data, query); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("compile", func(b *testing.B) {
+		j := NewJsonata(JsonataOptions{})
+		for i := 0; i < b.N; i++ {
+			expr, err := jsonata.Compile(query)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := expr.RegisterExts(j.extensions()); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := expr.Eval(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached-vars", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			j := NewJsonata(JsonataOptions{})
+			j.RegisterVars(map[string]interface{}{"jiraOptions": map[string]interface{}{"url": "https://jira", "timeout": i}})
+			if _, err := j.Eval(data, query); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("compile-vars", func(b *testing.B) {
+		j := NewJsonata(JsonataOptions{})
+		for i := 0; i < b.N; i++ {
+			expr, err := jsonata.Compile(query)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := expr.RegisterExts(j.extensions()); err != nil {
+				b.Fatal(err)
+			}
+			if err := expr.RegisterVars(map[string]interface{}{"jiraOptions": map[string]interface{}{"url": "https://jira", "timeout": i}}); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := expr.Eval(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached-vars-parallel", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				i++
+				j := NewJsonata(JsonataOptions{})
+				j.RegisterVars(map[string]interface{}{"jiraOptions": map[string]interface{}{"url": "https://jira", "timeout": i}})
+				if _, err := j.Eval(data, query); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+
+	b.Run("cached-parallel", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			j := NewJsonata(JsonataOptions{})
+			for pb.Next() {
+				if _, err := j.Eval(data, query); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+}
+
+func BenchmarkJsonataZabbix(b *testing.B) {
+
+	data := jsonataZabbixPayload(5000)
+	query := `result[status = "0" and $ifIPInCIDR(interfaces[0].ip, "10.0.0.0/16")].{ "name": host, "group": groups[0].name, "ip": interfaces[0].ip }`
+	benchmarkJsonata(b, data, query)
+}
+
+func BenchmarkJsonataNetbox(b *testing.B) {
+
+	data := jsonataNetboxPayload(5000)
+	query := `results[status.value = "active" and $regexMatch("^site-[0-4]$", site.slug)].{ "name": name, "ip": $substringBefore(primary_ip4.address, "/") }`
+	benchmarkJsonata(b, data, query)
+}
+
+func BenchmarkJsonataSmallQueries(b *testing.B) {
+
+	data := map[string]interface{}{"name": "host-1", "ip": "10.0.0.1"}
+	query := `$uppercase(name) & " (" & ip & ")" & ($ifIP(ip) ? " ip" : "")`
+	benchmarkJsonata(b, data, query)
+}
@@ -1,5 +1,12 @@
 package common
 
+import "github.com/google/uuid"
+
+const (
+	CorrelationIDField  = "correlation_id"
+	CorrelationIDHeader = "X-Request-ID"
+)
+
 type Logger interface {
 	Info(obj interface{}, args ...interface{})
 	Warn(obj interface{}, args ...interface{})
@@ -7,3 +14,22 @@ type Logger interface {
 	Error(obj interface{}, args ...interface{})
 	Panic(obj interface{}, args ...interface{})
 }
+
+// FieldLogger is logger which keeps key/value fields, e.g. correlation id, for all messages
+type FieldLogger interface {
+	Logger
+	With(keyvals ...interface{}) Logger
+}
+
+// LoggerWith returns logger with fields if it supports them, otherwise logger as is
+func LoggerWith(logger Logger, keyvals ...interface{}) Logger {
+
+	if fl, ok := logger.(FieldLogger); ok {
+		return fl.With(keyvals...)
+	}
+	return logger
+}
+
+func NewCorrelationID() string {
+	return uuid.New().String()
+}
@@ -0,0 +1,727 @@
+package common
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	MarkdownFormat = "markdown"
+
+	MarkdownSlack          = "slack"
+	MarkdownTelegram       = "telegram"
+	MarkdownTelegramHTML   = "telegram-html"
+	MarkdownTelegramLegacy = "telegram-markdown"
+	MarkdownJira           = "jira"
+	MarkdownADF            = "adf"
+)
+
+// markdownDialect describes how CommonMark nodes are written in a target markup
+type markdownDialect interface {
+	text(s string) string
+	code(s string) string
+	codeBlock(language, s string) string
+	emphasis(level int, s string) string
+	strike(s string) string
+	link(url, s string) string
+	heading(level int, s string) string
+	quote(s string) string
+	listPrefix(ordered []bool, index int) string
+	listIndent(depth int) string
+	rule() string
+}
+
+type markdownConverter struct {
+	source  []byte
+	dialect markdownDialect
+	ordered []bool
+}
+
+var markdownParser = goldmark.New(goldmark.WithExtensions(extension.Strikethrough))
+
+func markdownParse(s string) (ast.Node, []byte) {
+
+	source := []byte(s)
+	return markdownParser.Parser().Parse(text.NewReader(source)), source
+}
+
+// markdownText resolves backslash escapes and entities kept by parser in text segments
+func markdownText(t *ast.Text, source []byte) string {
+
+	v := util.UnescapePunctuations(t.Segment.Value(source))
+	v = util.ResolveNumericReferences(v)
+	return string(util.ResolveEntityNames(v))
+}
+
+func markdownLines(n ast.Node, source []byte) string {
+
+	var b strings.Builder
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		b.Write(segment.Value(source))
+	}
+	return b.String()
+}
+
+func markdownPlain(n ast.Node, source []byte) string {
+
+	var b strings.Builder
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := c.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(source))
+		case *ast.String:
+			b.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return b.String()
+}
+
+func (mc *markdownConverter) inlines(n ast.Node) string {
+
+	var b strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+
+		switch t := c.(type) {
+		case *ast.Text:
+			b.WriteString(mc.dialect.text(markdownText(t, mc.source)))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b.WriteString("\n")
+			}
+		case *ast.String:
+			b.WriteString(mc.dialect.text(string(t.Value)))
+		case *ast.CodeSpan:
+			b.WriteString(mc.dialect.code(markdownPlain(t, mc.source)))
+		case *ast.Emphasis:
+			b.WriteString(mc.dialect.emphasis(t.Level, mc.inlines(t)))
+		case *east.Strikethrough:
+			b.WriteString(mc.dialect.strike(mc.inlines(t)))
+		case *ast.Link:
+			b.WriteString(mc.dialect.link(string(t.Destination), mc.inlines(t)))
+		case *ast.Image:
+			b.WriteString(mc.dialect.link(string(t.Destination), mc.inlines(t)))
+		case *ast.AutoLink:
+			url := string(t.URL(mc.source))
+			b.WriteString(mc.dialect.link(url, mc.dialect.text(string(t.Label(mc.source)))))
+		case *ast.RawHTML:
+			var raw strings.Builder
+			for i := 0; i < t.Segments.Len(); i++ {
+				segment := t.Segments.At(i)
+				raw.Write(segment.Value(mc.source))
+			}
+			b.WriteString(mc.dialect.text(raw.String()))
+		default:
+			b.WriteString(mc.inlines(c))
+		}
+	}
+	return b.String()
+}
+
+func (mc *markdownConverter) list(n *ast.List, depth int) string {
+
+	mc.ordered = append(mc.ordered, n.IsOrdered())
+	defer func() {
+		mc.ordered = mc.ordered[:len(mc.ordered)-1]
+	}()
+
+	var lines []string
+	index := n.Start
+	if index == 0 {
+		index = 1
+	}
+
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+
+		first := true
+		for c := item.FirstChild(); c != nil; c = c.NextSibling() {
+
+			if l, ok := c.(*ast.List); ok {
+				lines = append(lines, mc.list(l, depth+1))
+				continue
+			}
+			s := mc.block(c, depth+1)
+			if first {
+				lines = append(lines, mc.dialect.listIndent(depth)+mc.dialect.listPrefix(mc.ordered, index)+s)
+				first = false
+			} else {
+				lines = append(lines, mc.dialect.listIndent(depth+1)+s)
+			}
+		}
+		if first {
+			lines = append(lines, mc.dialect.listIndent(depth)+mc.dialect.listPrefix(mc.ordered, index))
+		}
+		index++
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (mc *markdownConverter) block(n ast.Node, depth int) string {
+
+	switch t := n.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		return mc.inlines(t)
+	case *ast.Heading:
+		return mc.dialect.heading(t.Level, mc.inlines(t))
+	case *ast.ThematicBreak:
+		return mc.dialect.rule()
+	case *ast.FencedCodeBlock:
+		return mc.dialect.codeBlock(string(t.Language(mc.source)), strings.TrimRight(markdownLines(t, mc.source), "\n"))
+	case *ast.CodeBlock:
+		return mc.dialect.codeBlock("", strings.TrimRight(markdownLines(t, mc.source), "\n"))
+	case *ast.Blockquote:
+		return mc.dialect.quote(mc.blocks(t, depth))
+	case *ast.List:
+		return mc.list(t, depth)
+	case *ast.HTMLBlock:
+		return mc.dialect.text(strings.TrimRight(markdownLines(t, mc.source), "\n"))
+	default:
+		return mc.blocks(n, depth)
+	}
+}
+
+func (mc *markdownConverter) blocks(n ast.Node, depth int) string {
+
+	var parts []string
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		s := mc.block(c, depth)
+		if s == "" {
+			continue
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func markdownConvert(s string, dialect markdownDialect) string {
+
+	doc, source := markdownParse(s)
+	mc := &markdownConverter{
+		source:  source,
+		dialect: dialect,
+	}
+	return mc.blocks(doc, 0)
+}
+
+func markdownPrefixLines(s, prefix string) string {
+
+	lines := strings.Split(s, "\n")
+	for k, l := range lines {
+		lines[k] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+func markdownBullet(ordered []bool, index int, format string) string {
+
+	if len(ordered) > 0 && ordered[len(ordered)-1] {
+		return fmt.Sprintf(format, strconv.Itoa(index))
+	}
+	return "• "
+}
+
+// Slack mrkdwn
+
+type slackDialect struct{}
+
+func (d slackDialect) text(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	return r.Replace(s)
+}
+
+func (d slackDialect) code(s string) string {
+	return "`" + d.text(s) + "`"
+}
+
+func (d slackDialect) codeBlock(language, s string) string {
+	return "```\n" + d.text(s) + "\n```"
+}
+
+func (d slackDialect) emphasis(level int, s string) string {
+	if level >= 2 {
+		return "*" + s + "*"
+	}
+	return "_" + s + "_"
+}
+
+func (d slackDialect) strike(s string) string {
+	return "~" + s + "~"
+}
+
+func (d slackDialect) link(url, s string) string {
+	if s == "" || s == d.text(url) {
+		return "<" + url + ">"
+	}
+	return "<" + url + "|" + s + ">"
+}
+
+func (d slackDialect) heading(level int, s string) string {
+	return "*" + s + "*"
+}
+
+func (d slackDialect) quote(s string) string {
+	return markdownPrefixLines(s, "> ")
+}
+
+func (d slackDialect) listPrefix(ordered []bool, index int) string {
+	return markdownBullet(ordered, index, "%s. ")
+}
+
+func (d slackDialect) listIndent(depth int) string {
+	return strings.Repeat("    ", depth)
+}
+
+func (d slackDialect) rule() string {
+	return "──────────"
+}
+
+// Telegram MarkdownV2
+
+type telegramDialect struct{}
+
+const telegramSpecialChars = "_*[]()~`>#+-=|{}.!\\"
+
+func telegramEscape(s, chars string) string {
+
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(chars, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (d telegramDialect) text(s string) string {
+	return telegramEscape(s, telegramSpecialChars)
+}
+
+func (d telegramDialect) code(s string) string {
+	return "`" + telegramEscape(s, "`\\") + "`"
+}
+
+func (d telegramDialect) codeBlock(language, s string) string {
+	return "```" + language + "\n" + telegramEscape(s, "`\\") + "\n```"
+}
+
+func (d telegramDialect) emphasis(level int, s string) string {
+	if level >= 2 {
+		return "*" + s + "*"
+	}
+	return "_" + s + "_"
+}
+
+func (d telegramDialect) strike(s string) string {
+	return "~" + s + "~"
+}
+
+func (d telegramDialect) link(url, s string) string {
+	if s == "" {
+		s = d.text(url)
+	}
+	return "[" + s + "](" + telegramEscape(url, ")\\") + ")"
+}
+
+func (d telegramDialect) heading(level int, s string) string {
+	return "*" + s + "*"
+}
+
+func (d telegramDialect) quote(s string) string {
+	return markdownPrefixLines(s, ">")
+}
+
+func (d telegramDialect) listPrefix(ordered []bool, index int) string {
+	return markdownBullet(ordered, index, "%s\\. ")
+}
+
+func (d telegramDialect) listIndent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
+
+func (d telegramDialect) rule() string {
+	return "──────────"
+}
+
+// Telegram legacy Markdown, supports only bold, italic, code, pre and links
+
+type telegramLegacyDialect struct{}
+
+func (d telegramLegacyDialect) text(s string) string {
+	return telegramEscape(s, "_*`[")
+}
+
+// legacy mode has no escaping inside entities, so closing markers are replaced
+func (d telegramLegacyDialect) code(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "'") + "`"
+}
+
+func (d telegramLegacyDialect) codeBlock(language, s string) string {
+	return "```" + language + "\n" + strings.ReplaceAll(s, "```", "'''") + "\n```"
+}
+
+func (d telegramLegacyDialect) emphasis(level int, s string) string {
+	if level >= 2 {
+		return "*" + s + "*"
+	}
+	return "_" + s + "_"
+}
+
+func (d telegramLegacyDialect) strike(s string) string {
+	return s
+}
+
+func (d telegramLegacyDialect) link(url, s string) string {
+	if s == "" {
+		s = d.text(url)
+	}
+	return "[" + s + "](" + url + ")"
+}
+
+func (d telegramLegacyDialect) heading(level int, s string) string {
+	return "*" + s + "*"
+}
+
+func (d telegramLegacyDialect) quote(s string) string {
+	return markdownPrefixLines(s, "> ")
+}
+
+func (d telegramLegacyDialect) listPrefix(ordered []bool, index int) string {
+	return markdownBullet(ordered, index, "%s. ")
+}
+
+func (d telegramLegacyDialect) listIndent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
+
+func (d telegramLegacyDialect) rule() string {
+	return "──────────"
+}
+
+// Telegram HTML
+
+type telegramHTMLDialect struct{}
+
+func (d telegramHTMLDialect) text(s string) string {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	return r.Replace(s)
+}
+
+func (d telegramHTMLDialect) code(s string) string {
+	return "<code>" + d.text(s) + "</code>"
+}
+
+func (d telegramHTMLDialect) codeBlock(language, s string) string {
+	if language != "" {
+		return fmt.Sprintf("<pre><code class=\"language-%s\">%s</code></pre>", html.EscapeString(language), d.text(s))
+	}
+	return "<pre>" + d.text(s) + "</pre>"
+}
+
+func (d telegramHTMLDialect) emphasis(level int, s string) string {
+	if level >= 2 {
+		return "<b>" + s + "</b>"
+	}
+	return "<i>" + s + "</i>"
+}
+
+func (d telegramHTMLDialect) strike(s string) string {
+	return "<s>" + s + "</s>"
+}
+
+func (d telegramHTMLDialect) link(url, s string) string {
+	if s == "" {
+		s = d.text(url)
+	}
+	return "<a href=\"" + html.EscapeString(url) + "\">" + s + "</a>"
+}
+
+func (d telegramHTMLDialect) heading(level int, s string) string {
+	return "<b>" + s + "</b>"
+}
+
+func (d telegramHTMLDialect) quote(s string) string {
+	return "<blockquote>" + s + "</blockquote>"
+}
+
+func (d telegramHTMLDialect) listPrefix(ordered []bool, index int) string {
+	return markdownBullet(ordered, index, "%s. ")
+}
+
+func (d telegramHTMLDialect) listIndent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
+
+func (d telegramHTMLDialect) rule() string {
+	return "──────────"
+}
+
+// Jira wiki markup
+
+type jiraDialect struct{}
+
+func (d jiraDialect) text(s string) string {
+	return telegramEscape(s, "*_{}[]|^~-+#\\")
+}
+
+func (d jiraDialect) code(s string) string {
+	return "{{" + telegramEscape(s, "{}\\") + "}}"
+}
+
+func (d jiraDialect) codeBlock(language, s string) string {
+	if language != "" {
+		return "{code:" + language + "}\n" + s + "\n{code}"
+	}
+	return "{noformat}\n" + s + "\n{noformat}"
+}
+
+func (d jiraDialect) emphasis(level int, s string) string {
+	if level >= 2 {
+		return "*" + s + "*"
+	}
+	return "_" + s + "_"
+}
+
+func (d jiraDialect) strike(s string) string {
+	return "-" + s + "-"
+}
+
+func (d jiraDialect) link(url, s string) string {
+	if s == "" || s == d.text(url) {
+		return "[" + url + "]"
+	}
+	return "[" + s + "|" + url + "]"
+}
+
+func (d jiraDialect) heading(level int, s string) string {
+	return fmt.Sprintf("h%d. %s", level, s)
+}
+
+func (d jiraDialect) quote(s string) string {
+	return "{quote}\n" + s + "\n{quote}"
+}
+
+func (d jiraDialect) listPrefix(ordered []bool, index int) string {
+
+	var b strings.Builder
+	for _, o := range ordered {
+		if o {
+			b.WriteString("#")
+		} else {
+			b.WriteString("*")
+		}
+	}
+	return b.String() + " "
+}
+
+func (d jiraDialect) listIndent(depth int) string {
+	return ""
+}
+
+func (d jiraDialect) rule() string {
+	return "----"
+}
+
+// MarkdownToSlack converts CommonMark into Slack mrkdwn
+func MarkdownToSlack(s string) string {
+	return markdownConvert(s, slackDialect{})
+}
+
+// MarkdownToTelegram converts CommonMark into Telegram MarkdownV2 with escaping of reserved characters
+func MarkdownToTelegram(s string) string {
+	return markdownConvert(s, telegramDialect{})
+}
+
+// MarkdownToTelegramLegacy converts CommonMark into Telegram legacy Markdown parse mode
+func MarkdownToTelegramLegacy(s string) string {
+	return markdownConvert(s, telegramLegacyDialect{})
+}
+
+// MarkdownToTelegramHTML converts CommonMark into the HTML subset supported by Telegram
+func MarkdownToTelegramHTML(s string) string {
+	return markdownConvert(s, telegramHTMLDialect{})
+}
+
+// MarkdownToJira converts CommonMark into Jira wiki markup
+func MarkdownToJira(s string) string {
+	return markdownConvert(s, jiraDialect{})
+}
+
+// Atlassian Document Format
+
+type adfConverter struct {
+	source []byte
+}
+
+func (ac *adfConverter) textNode(s string, marks []interface{}) map[string]interface{} {
+
+	node := map[string]interface{}{
+		"type": "text",
+		"text": s,
+	}
+	if len(marks) > 0 {
+		node["marks"] = marks
+	}
+	return node
+}
+
+func (ac *adfConverter) withMark(marks []interface{}, mark map[string]interface{}) []interface{} {
+
+	r := make([]interface{}, len(marks), len(marks)+1)
+	copy(r, marks)
+	return append(r, mark)
+}
+
+func (ac *adfConverter) inlines(n ast.Node, marks []interface{}) []interface{} {
+
+	r := []interface{}{}
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+
+		switch t := c.(type) {
+		case *ast.Text:
+			if s := markdownText(t, ac.source); s != "" {
+				r = append(r, ac.textNode(s, marks))
+			}
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				r = append(r, map[string]interface{}{"type": "hardBreak"})
+			}
+		case *ast.String:
+			r = append(r, ac.textNode(string(t.Value), marks))
+		case *ast.CodeSpan:
+			r = append(r, ac.textNode(markdownPlain(t, ac.source), ac.withMark(marks, map[string]interface{}{"type": "code"})))
+		case *ast.Emphasis:
+			mark := "em"
+			if t.Level >= 2 {
+				mark = "strong"
+			}
+			r = append(r, ac.inlines(t, ac.withMark(marks, map[string]interface{}{"type": mark}))...)
+		case *east.Strikethrough:
+			r = append(r, ac.inlines(t, ac.withMark(marks, map[string]interface{}{"type": "strike"}))...)
+		case *ast.Link:
+			link := map[string]interface{}{"type": "link", "attrs": map[string]interface{}{"href": string(t.Destination)}}
+			r = append(r, ac.inlines(t, ac.withMark(marks, link))...)
+		case *ast.Image:
+			link := map[string]interface{}{"type": "link", "attrs": map[string]interface{}{"href": string(t.Destination)}}
+			r = append(r, ac.textNode(markdownPlain(t, ac.source), ac.withMark(marks, link)))
+		case *ast.AutoLink:
+			url := string(t.URL(ac.source))
+			link := map[string]interface{}{"type": "link", "attrs": map[string]interface{}{"href": url}}
+			r = append(r, ac.textNode(string(t.Label(ac.source)), ac.withMark(marks, link)))
+		default:
+			r = append(r, ac.inlines(c, marks)...)
+		}
+	}
+	return r
+}
+
+func (ac *adfConverter) block(n ast.Node) map[string]interface{} {
+
+	switch t := n.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		return map[string]interface{}{"type": "paragraph", "content": ac.inlines(t, nil)}
+	case *ast.Heading:
+		return map[string]interface{}{
+			"type":    "heading",
+			"attrs":   map[string]interface{}{"level": t.Level},
+			"content": ac.inlines(t, nil),
+		}
+	case *ast.ThematicBreak:
+		return map[string]interface{}{"type": "rule"}
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		node := map[string]interface{}{"type": "codeBlock", "content": []interface{}{}}
+		code := strings.TrimRight(markdownLines(t, ac.source), "\n")
+		if code != "" {
+			node["content"] = []interface{}{ac.textNode(code, nil)}
+		}
+		if f, ok := t.(*ast.FencedCodeBlock); ok {
+			if language := string(f.Language(ac.source)); language != "" {
+				node["attrs"] = map[string]interface{}{"language": language}
+			}
+		}
+		return node
+	case *ast.Blockquote:
+		return map[string]interface{}{"type": "blockquote", "content": ac.blocks(t)}
+	case *ast.List:
+		node := map[string]interface{}{"type": "bulletList"}
+		if t.IsOrdered() {
+			node["type"] = "orderedList"
+			start := t.Start
+			if start == 0 {
+				start = 1
+			}
+			node["attrs"] = map[string]interface{}{"order": start}
+		}
+		items := []interface{}{}
+		for item := t.FirstChild(); item != nil; item = item.NextSibling() {
+			items = append(items, map[string]interface{}{"type": "listItem", "content": ac.blocks(item)})
+		}
+		node["content"] = items
+		return node
+	case *ast.HTMLBlock:
+		return map[string]interface{}{
+			"type":    "paragraph",
+			"content": []interface{}{ac.textNode(strings.TrimRight(markdownLines(t, ac.source), "\n"), nil)},
+		}
+	}
+	return nil
+}
+
+func (ac *adfConverter) blocks(n ast.Node) []interface{} {
+
+	r := []interface{}{}
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if b := ac.block(c); b != nil {
+			r = append(r, b)
+		}
+	}
+	return r
+}
+
+// MarkdownToADF converts CommonMark into Atlassian Document Format object
+func MarkdownToADF(s string) map[string]interface{} {
+
+	doc, source := markdownParse(s)
+	ac := &adfConverter{source: source}
+	return map[string]interface{}{
+		"version": 1,
+		"type":    "doc",
+		"content": ac.blocks(doc),
+	}
+}
+
+// MarkdownConvert converts CommonMark into one of the supported targets: slack, telegram, telegram-html, telegram-markdown, jira, adf
+func MarkdownConvert(s, target string) (string, error) {
+
+	switch strings.ToLower(target) {
+	case MarkdownSlack:
+		return MarkdownToSlack(s), nil
+	case MarkdownTelegram, "markdownv2":
+		return MarkdownToTelegram(s), nil
+	case MarkdownTelegramHTML, "html":
+		return MarkdownToTelegramHTML(s), nil
+	case MarkdownTelegramLegacy, "markdown":
+		return MarkdownToTelegramLegacy(s), nil
+	case MarkdownJira:
+		return MarkdownToJira(s), nil
+	case MarkdownADF:
+		b, err := JsonMarshal(MarkdownToADF(s))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	return "", fmt.Errorf("markdown target %s is not supported", target)
+}
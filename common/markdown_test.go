@@ -0,0 +1,79 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownConvert(t *testing.T) {
+
+	src := "# Alert\n\nHost `web_1` is **down**, see [dash](https://grafana/d/1).\n\n- one\n  - two"
+
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{
+			target:   MarkdownSlack,
+			expected: "*Alert*\n\nHost `web_1` is *down*, see <https://grafana/d/1|dash>.\n\n• one\n    • two",
+		},
+		{
+			target:   MarkdownTelegram,
+			expected: "*Alert*\n\nHost `web_1` is *down*, see [dash](https://grafana/d/1)\\.\n\n• one\n  • two",
+		},
+		{
+			target:   MarkdownTelegramHTML,
+			expected: "<b>Alert</b>\n\nHost <code>web_1</code> is <b>down</b>, see <a href=\"https://grafana/d/1\">dash</a>.\n\n• one\n  • two",
+		},
+		{
+			target:   MarkdownTelegramLegacy,
+			expected: "*Alert*\n\nHost `web_1` is *down*, see [dash](https://grafana/d/1).\n\n• one\n  • two",
+		},
+		{
+			target:   MarkdownJira,
+			expected: "h1. Alert\n\nHost {{web_1}} is *down*, see [dash|https://grafana/d/1].\n\n* one\n** two",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			r, err := MarkdownConvert(src, tt.target)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, r)
+		})
+	}
+
+	_, err := MarkdownConvert(src, "unknown")
+	assert.Error(t, err)
+}
+
+func TestMarkdownEscaping(t *testing.T) {
+
+	assert.Equal(t, "1\\+1\\=2\\! a\\_b", MarkdownToTelegram("1+1=2! a\\_b"))
+	assert.Equal(t, "a &lt; b &amp; c", MarkdownToSlack("a < b & c"))
+	assert.Equal(t, "\\{x\\} a\\|b", MarkdownToJira("{x} a|b"))
+	assert.Equal(t, "a\\-b\\- 1\\+1 \\#2", MarkdownToJira("a-b- 1+1 #2"))
+	assert.Equal(t, "a\\_b \\*c\\* 1+1=2!", MarkdownToTelegramLegacy("a\\_b \\*c\\* 1+1=2!"))
+	assert.Equal(t, "~old~ `a'b`", MarkdownToTelegramLegacy("\\~old\\~ ``a`b``"))
+}
+
+func TestMarkdownToADF(t *testing.T) {
+
+	r := MarkdownToADF("**bold** text\n\n```go\nfmt.Println()\n```")
+	assert.Equal(t, 1, r["version"])
+	assert.Equal(t, "doc", r["type"])
+
+	content := r["content"].([]interface{})
+	require.Len(t, content, 2)
+
+	paragraph := content[0].(map[string]interface{})
+	assert.Equal(t, "paragraph", paragraph["type"])
+	texts := paragraph["content"].([]interface{})
+	assert.Equal(t, []interface{}{map[string]interface{}{"type": "strong"}}, texts[0].(map[string]interface{})["marks"])
+
+	code := content[1].(map[string]interface{})
+	assert.Equal(t, "codeBlock", code["type"])
+	assert.Equal(t, map[string]interface{}{"language": "go"}, code["attrs"])
+}
@@ -0,0 +1,122 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/devopsext/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputFormatJson       = "json"
+	OutputFormatYaml       = "yaml"
+	OutputFormatCsv        = "csv"
+	OutputFormatTsv        = "tsv"
+	OutputFormatTable      = "table"
+	OutputFormatNdjson     = "ndjson"
+	OutputFormatGoTemplate = "go-template"
+)
+
+// OutputFormats lists supported values of --*-output-format
+var OutputFormats = []string{
+	OutputFormatJson,
+	OutputFormatYaml,
+	OutputFormatCsv,
+	OutputFormatTsv,
+	OutputFormatTable,
+	OutputFormatNdjson,
+	OutputFormatGoTemplate + "=<template or file>",
+}
+
+func outputItems(v interface{}) []interface{} {
+
+	switch vt := v.(type) {
+	case nil:
+		return []interface{}{}
+	case []interface{}:
+		return vt
+	}
+	return []interface{}{v}
+}
+
+func outputGoTemplate(v interface{}, text string) (string, error) {
+
+	content, err := utils.Content(text)
+	if err != nil {
+		return "", err
+	}
+
+	t, err := template.New(OutputFormatGoTemplate).Funcs(sprig.TxtFuncMap()).Parse(string(content))
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, v); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// FormatOutput renders json value (result of query) in format: json, yaml, csv, tsv, table, ndjson, go-template=...
+func FormatOutput(i interface{}, format string, columns []string) (string, error) {
+
+	v, err := NormalizeJson(i)
+	if err != nil {
+		return "", err
+	}
+
+	name, arg, _ := strings.Cut(strings.TrimSpace(format), "=")
+	columns = RemoveEmptyStrings(columns)
+
+	switch strings.ToLower(name) {
+	case "", OutputFormatJson:
+		b, err := JsonMarshal(v)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	case OutputFormatYaml, "yml":
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\n"), nil
+	case OutputFormatCsv, OutputFormatTsv:
+		options := CsvOptions{Header: true, Columns: columns}
+		if strings.EqualFold(name, OutputFormatTsv) {
+			options.Delimiter = "tab"
+		}
+		b, err := ToCsv(v, options)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\n"), nil
+	case OutputFormatTable:
+		t, err := NewTable(v, TableOptions{Columns: columns})
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(t.Plain(), "\n"), nil
+	case OutputFormatNdjson:
+		var lines []string
+		for _, item := range outputItems(v) {
+			b, err := JsonMarshal(item)
+			if err != nil {
+				return "", err
+			}
+			lines = append(lines, strings.TrimSpace(string(b)))
+		}
+		return strings.Join(lines, "\n"), nil
+	case OutputFormatGoTemplate:
+		if utils.IsEmpty(arg) {
+			return "", fmt.Errorf("output format %s requires template, e.g. %s={{.name}}", name, name)
+		}
+		return outputGoTemplate(v, arg)
+	}
+	return "", fmt.Errorf("output format %s is not supported, use one of: %s", name, strings.Join(OutputFormats, ", "))
+}
@@ -0,0 +1,41 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatOutput(t *testing.T) {
+
+	data := []byte(`[{"name":"a","count":1},{"name":"b","count":2}]`)
+
+	tests := []struct {
+		name    string
+		format  string
+		columns []string
+		want    string
+		wantErr bool
+	}{
+		{name: "json", format: "json", want: `[{"count":1,"name":"a"},{"count":2,"name":"b"}]`},
+		{name: "yaml", format: "yaml", want: "- count: 1\n  name: a\n- count: 2\n  name: b"},
+		{name: "csv", format: "csv", columns: []string{"name", "count"}, want: "name,count\na,1\nb,2"},
+		{name: "tsv", format: "tsv", columns: []string{"name"}, want: "name\na\nb"},
+		{name: "ndjson", format: "ndjson", want: "{\"count\":1,\"name\":\"a\"}\n{\"count\":2,\"name\":\"b\"}"},
+		{name: "go-template", format: "go-template={{range .}}{{.name}};{{end}}", want: "a;b;"},
+		{name: "go-template without template", format: "go-template", wantErr: true},
+		{name: "unknown", format: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatOutput(data, tt.format, tt.columns)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
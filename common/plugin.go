@@ -0,0 +1,250 @@
+package common
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devopsext/utils"
+)
+
+// PluginPrefix is prefix of plugin executables, tools-<name> on PATH becomes tools <name>
+const PluginPrefix = "tools-"
+
+// PluginModeEnv tells plugin that it's started to serve template functions instead of command
+const (
+	PluginModeEnv   = "TOOLS_PLUGIN_MODE"
+	PluginModeFuncs = "funcs"
+)
+
+const (
+	pluginMethodFuncs = "funcs"
+	pluginMethodCall  = "call"
+)
+
+// PluginFuncs is plugin process serving template functions, requests and responses are JSON lines on its stdin and stdout:
+// {"id":1,"method":"funcs"} -> {"id":1,"funcs":["upper"]}, {"id":2,"method":"call","func":"upper","args":["a"]} -> {"id":2,"result":"A"}
+type PluginFuncs struct {
+	name    string
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	decoder *json.Decoder
+	names   []string
+	id      int
+	mutex   sync.Mutex
+}
+
+type pluginRequest struct {
+	ID     int           `json:"id"`
+	Method string        `json:"method"`
+	Func   string        `json:"func,omitempty"`
+	Args   []interface{} `json:"args,omitempty"`
+}
+
+type pluginResponse struct {
+	ID     int         `json:"id"`
+	Funcs  []string    `json:"funcs,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+func pluginName(file string) string {
+
+	name := strings.TrimPrefix(file, PluginPrefix)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+func pluginExecutable(path string) bool {
+
+	fi, err := os.Stat(path)
+	if err != nil || !fi.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(path), ".exe")
+	}
+	return fi.Mode()&0111 != 0
+}
+
+// FindPlugins returns paths of plugins on PATH by name, the first one on PATH wins like in shell
+func FindPlugins() map[string]string {
+
+	r := make(map[string]string)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+
+		if utils.IsEmpty(dir) {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+
+			if !strings.HasPrefix(e.Name(), PluginPrefix) {
+				continue
+			}
+			name := pluginName(e.Name())
+			if utils.IsEmpty(name) {
+				continue
+			}
+			if _, ok := r[name]; ok {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if pluginExecutable(path) {
+				r[name] = path
+			}
+		}
+	}
+	return r
+}
+
+// FindPlugin returns path of plugin by name
+func FindPlugin(name string) (string, error) {
+
+	path, ok := FindPlugins()[name]
+	if !ok {
+		return "", fmt.Errorf("plugin %s%s is not found on PATH", PluginPrefix, name)
+	}
+	return path, nil
+}
+
+func (p *PluginFuncs) request(req pluginRequest) (*pluginResponse, error) {
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.id++
+	req.ID = p.id
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.stdin.Write(append(b, '\n')); err != nil {
+		return nil, fmt.Errorf("plugin %s: %v", p.name, err)
+	}
+
+	var resp pluginResponse
+	if err := p.decoder.Decode(&resp); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("plugin %s: %v", p.name, err)
+	}
+	if resp.ID != req.ID {
+		return nil, fmt.Errorf("plugin %s: response id %d, expected %d", p.name, resp.ID, req.ID)
+	}
+	if !utils.IsEmpty(resp.Error) {
+		return nil, fmt.Errorf("plugin %s: %s", p.name, resp.Error)
+	}
+	return &resp, nil
+}
+
+// Names returns functions of plugin
+func (p *PluginFuncs) Names() []string {
+	return p.names
+}
+
+// Call calls plugin function, arguments and result are passed as JSON
+func (p *PluginFuncs) Call(name string, args ...interface{}) (interface{}, error) {
+
+	resp, err := p.request(pluginRequest{Method: pluginMethodCall, Func: name, Args: args})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+// Funcs returns template functions of plugin
+func (p *PluginFuncs) Funcs() map[string]any {
+
+	r := make(map[string]any)
+	for _, name := range p.names {
+		n := name
+		r[n] = func(args ...interface{}) (interface{}, error) {
+			return p.Call(n, args...)
+		}
+	}
+	return r
+}
+
+// Close closes stdin of plugin, so it exits, and waits for it
+func (p *PluginFuncs) Close() error {
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.stdin.Close()
+	return p.cmd.Wait()
+}
+
+// PluginStartTimeout is time of plugin to answer function names, plugin which ignores PluginModeEnv is killed after it
+var PluginStartTimeout = 10 * time.Second
+
+// StartPluginFuncs starts plugin with PluginModeEnv and asks it for function names
+func StartPluginFuncs(name, path string, env []string) (*PluginFuncs, error) {
+
+	cmd := exec.Command(path)
+	cmd.Env = append(env, fmt.Sprintf("%s=%s", PluginModeEnv, PluginModeFuncs))
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("plugin %s: %v", name, err)
+	}
+
+	p := &PluginFuncs{
+		name:    name,
+		cmd:     cmd,
+		stdin:   stdin,
+		decoder: json.NewDecoder(bufio.NewReader(stdout)),
+	}
+	var resp *pluginResponse
+	done := make(chan struct{})
+	go func() {
+		resp, err = p.request(pluginRequest{Method: pluginMethodFuncs})
+		close(done)
+	}()
+
+	timer := time.NewTimer(PluginStartTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		// killed plugin closes stdout, so request returns
+		cmd.Process.Kill()
+		<-done
+		err = fmt.Errorf("plugin %s doesn't answer %s in %s", name, pluginMethodFuncs, PluginStartTimeout)
+	}
+	if err != nil {
+		// plugin which doesn't serve functions may not exit on closed stdin
+		cmd.Process.Kill()
+		p.Close()
+		return nil, err
+	}
+	p.names = resp.Funcs
+	sort.Strings(p.names)
+	return p, nil
+}
@@ -0,0 +1,102 @@
+package common
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pluginTestEnv = "TOOLS_PLUGIN_TEST"
+
+// TestMain lets test binary be a plugin serving template functions
+func TestMain(m *testing.M) {
+
+	if os.Getenv(pluginTestEnv) == "true" && os.Getenv(PluginModeEnv) == PluginModeFuncs {
+		pluginTestServe()
+		os.Exit(0)
+	}
+	// plugin which ignores funcs mode waits for its own input
+	if os.Getenv(pluginTestEnv) == "hang" {
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func pluginTestServe() {
+
+	scanner := bufio.NewScanner(os.Stdin)
+	encoder := json.NewEncoder(os.Stdout)
+	for scanner.Scan() {
+
+		var req pluginRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return
+		}
+		resp := pluginResponse{ID: req.ID}
+		switch {
+		case req.Method == pluginMethodFuncs:
+			resp.Funcs = []string{"upper", "fail"}
+		case req.Func == "upper":
+			resp.Result = strings.ToUpper(fmt.Sprint(req.Args...))
+		default:
+			resp.Error = fmt.Sprintf("%s failed", req.Func)
+		}
+		encoder.Encode(resp)
+	}
+}
+
+func TestPluginFuncs(t *testing.T) {
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.Symlink(exe, filepath.Join(dir, PluginPrefix+"test")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PluginPrefix+"data"), []byte("not executable"), 0644))
+	t.Setenv("PATH", dir)
+	t.Setenv(pluginTestEnv, "true")
+
+	plugins := FindPlugins()
+	assert.Equal(t, map[string]string{"test": filepath.Join(dir, PluginPrefix+"test")}, plugins)
+	_, err = FindPlugin("data")
+	assert.Error(t, err)
+
+	p, err := StartPluginFuncs("test", plugins["test"], os.Environ())
+	require.NoError(t, err)
+	defer p.Close()
+
+	assert.Equal(t, []string{"fail", "upper"}, p.Names())
+	r, err := p.Call("upper", "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "ABC", r)
+
+	_, err = p.Funcs()["fail"].(func(...interface{}) (interface{}, error))()
+	assert.EqualError(t, err, "plugin test: fail failed")
+}
+
+func TestPluginFuncsTimeout(t *testing.T) {
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	timeout := PluginStartTimeout
+	PluginStartTimeout = 100 * time.Millisecond
+	defer func() {
+		PluginStartTimeout = timeout
+	}()
+	t.Setenv(pluginTestEnv, "hang")
+
+	start := time.Now()
+	_, err = StartPluginFuncs("hang", exe, os.Environ())
+	assert.ErrorContains(t, err, "doesn't answer")
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
@@ -0,0 +1,214 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RetryDefaultAttempts = 3
+	RetryDefaultDelay    = time.Second
+	RetryDefaultMaxDelay = 30 * time.Second
+	RetryDefaultFactor   = 2.0
+	RetryAfterHeader     = "Retry-After"
+)
+
+type RetryOptions struct {
+	Attempts    int
+	Delay       time.Duration
+	MaxDelay    time.Duration
+	Factor      float64
+	Jitter      float64 // fraction of delay, 0..1
+	Errors      []string
+	StatusCodes []int
+}
+
+// RetryState is what attempt reports back to decide whether and when to retry
+type RetryState struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Err        error
+}
+
+// retryTimer is replaced in tests
+var retryTimer = time.NewTimer
+
+func (o RetryOptions) attempts() int {
+	if o.Attempts <= 0 {
+		return RetryDefaultAttempts
+	}
+	return o.Attempts
+}
+
+// Backoff returns delay before next attempt, attempt starts from 0
+func (o RetryOptions) Backoff(attempt int) time.Duration {
+
+	delay := o.Delay
+	if delay <= 0 {
+		delay = RetryDefaultDelay
+	}
+	maxDelay := o.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = RetryDefaultMaxDelay
+	}
+	factor := o.Factor
+	if factor < 1 {
+		factor = RetryDefaultFactor
+	}
+
+	d := float64(delay) * math.Pow(factor, float64(attempt))
+	if d > float64(maxDelay) {
+		d = float64(maxDelay)
+	}
+
+	if o.Jitter > 0 {
+		jitter := math.Min(o.Jitter, 1)
+		d = d - d*jitter + rand.Float64()*2*d*jitter
+	}
+	return time.Duration(d)
+}
+
+// retryPermanent checks whether error is vendor error which can't succeed on retry
+func retryPermanent(err error) bool {
+
+	var ve *VendorError
+	if !errors.As(err, &ve) {
+		return false
+	}
+	switch ve.Kind() {
+	case VendorErrorInvalid, VendorErrorAuth, VendorErrorNotFound, VendorErrorCanceled:
+		return true
+	}
+	return false
+}
+
+// ShouldRetry checks state against error patterns and status codes, by default 408, 429, 5xx and
+// errors other than invalid, auth and not found vendor errors are retried
+func (o RetryOptions) ShouldRetry(state RetryState) bool {
+
+	if len(o.Errors) == 0 && len(o.StatusCodes) == 0 {
+		switch {
+		case state.StatusCode == http.StatusRequestTimeout || state.StatusCode == http.StatusTooManyRequests:
+			return true
+		case state.StatusCode >= http.StatusInternalServerError:
+			return true
+		case state.StatusCode >= http.StatusBadRequest:
+			return false
+		}
+		return state.Err != nil && !retryPermanent(state.Err)
+	}
+
+	for _, code := range o.StatusCodes {
+		if code == state.StatusCode {
+			return true
+		}
+	}
+
+	if state.Err != nil {
+		text := state.Err.Error()
+		for _, e := range o.Errors {
+			re, err := regexp.Compile(e)
+			if err != nil {
+				if strings.Contains(text, e) {
+					return true
+				}
+				continue
+			}
+			if re.MatchString(text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseRetryAfter parses Retry-After value as seconds, HTTP date or Go duration
+func ParseRetryAfter(value string) (time.Duration, bool) {
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return d, true
+	}
+	return 0, false
+}
+
+// SleepContext waits for d or until ctx is done
+func SleepContext(ctx context.Context, d time.Duration) error {
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	t := retryTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Retry calls fn until it succeeds, attempts are exhausted or state is not retryable
+func Retry(options RetryOptions, fn func(attempt int) RetryState) error {
+	return RetryContext(context.Background(), options, fn)
+}
+
+// RetryContext is Retry which stops waiting for next attempt when ctx is done and returns its error
+func RetryContext(ctx context.Context, options RetryOptions, fn func(attempt int) RetryState) error {
+
+	attempts := options.attempts()
+
+	var state RetryState
+	for attempt := 0; attempt < attempts; attempt++ {
+
+		state = fn(attempt)
+		if !options.ShouldRetry(state) {
+			return state.Err
+		}
+
+		if attempt == attempts-1 {
+			break
+		}
+
+		delay := options.Backoff(attempt)
+		if state.RetryAfter > 0 {
+			delay = state.RetryAfter
+			if options.MaxDelay > 0 && delay > options.MaxDelay {
+				delay = options.MaxDelay
+			}
+		}
+		if err := SleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+
+	if state.Err != nil {
+		return fmt.Errorf("retry attempts %d exceeded: %v", attempts, state.Err)
+	}
+	return fmt.Errorf("retry attempts %d exceeded: status code %d", attempts, state.StatusCode)
+}
@@ -0,0 +1,167 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+
+	var delays []time.Duration
+	retryTimer = func(d time.Duration) *time.Timer {
+		delays = append(delays, d)
+		return time.NewTimer(0)
+	}
+	defer func() {
+		retryTimer = time.NewTimer
+	}()
+
+	tests := []struct {
+		name     string
+		options  RetryOptions
+		states   []RetryState
+		calls    int
+		delays   []time.Duration
+		hasError bool
+	}{
+		{
+			name:    "Success after transient errors",
+			options: RetryOptions{Attempts: 3, Delay: time.Second},
+			states:  []RetryState{{Err: errors.New("timeout")}, {StatusCode: http.StatusServiceUnavailable}, {StatusCode: http.StatusOK}},
+			calls:   3,
+			delays:  []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:     "Attempts exceeded",
+			options:  RetryOptions{Attempts: 2, Delay: time.Second},
+			states:   []RetryState{{Err: errors.New("timeout")}, {Err: errors.New("timeout")}},
+			calls:    2,
+			delays:   []time.Duration{time.Second},
+			hasError: true,
+		},
+		{
+			name:    "Retry-After wins over backoff",
+			options: RetryOptions{Attempts: 2, Delay: time.Second},
+			states:  []RetryState{{StatusCode: http.StatusTooManyRequests, RetryAfter: 5 * time.Second}, {StatusCode: http.StatusOK}},
+			calls:   2,
+			delays:  []time.Duration{5 * time.Second},
+		},
+		{
+			name:     "Error not matched by predicate",
+			options:  RetryOptions{Attempts: 3, Errors: []string{"connection reset"}, StatusCodes: []int{http.StatusBadGateway}},
+			states:   []RetryState{{Err: errors.New("404 Not Found"), StatusCode: http.StatusNotFound}},
+			calls:    1,
+			hasError: true,
+		},
+		{
+			name:     "Client error status is not retried",
+			options:  RetryOptions{Attempts: 3},
+			states:   []RetryState{{Err: errors.New("400 Bad Request"), StatusCode: http.StatusBadRequest}},
+			calls:    1,
+			hasError: true,
+		},
+		{
+			name:     "Invalid vendor error is not retried",
+			options:  RetryOptions{Attempts: 3},
+			states:   []RetryState{{Err: &VendorError{Vendor: "jira", Status: http.StatusBadRequest}}},
+			calls:    1,
+			hasError: true,
+		},
+		{
+			name:     "Auth vendor error is not retried",
+			options:  RetryOptions{Attempts: 3},
+			states:   []RetryState{{Err: &VendorError{Vendor: "jira", Status: http.StatusUnauthorized}}},
+			calls:    1,
+			hasError: true,
+		},
+		{
+			name:    "Unavailable vendor error is retried",
+			options: RetryOptions{Attempts: 2, Delay: time.Second},
+			states:  []RetryState{{Err: &VendorError{Vendor: "jira", Status: http.StatusBadGateway}}, {}},
+			calls:   2,
+			delays:  []time.Duration{time.Second},
+		},
+		{
+			name:    "Status matched by predicate",
+			options: RetryOptions{Attempts: 3, Delay: time.Second, MaxDelay: time.Second, StatusCodes: []int{http.StatusNotFound}},
+			states:  []RetryState{{StatusCode: http.StatusNotFound}, {StatusCode: http.StatusNotFound}, {StatusCode: http.StatusOK}},
+			calls:   3,
+			delays:  []time.Duration{time.Second, time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			delays = nil
+			calls := 0
+			err := Retry(tt.options, func(attempt int) RetryState {
+				calls++
+				return tt.states[attempt]
+			})
+			assert.Equal(t, tt.calls, calls)
+			assert.Equal(t, tt.delays, delays)
+			assert.Equal(t, tt.hasError, err != nil)
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+
+	d, ok := ParseRetryAfter("120")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, d)
+
+	d, ok = ParseRetryAfter("1500ms")
+	assert.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, d)
+
+	_, ok = ParseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+
+	_, ok = ParseRetryAfter("soon")
+	assert.False(t, ok)
+}
+
+func TestSleepContext(t *testing.T) {
+
+	var timer *time.Timer
+	retryTimer = func(d time.Duration) *time.Timer {
+		timer = time.NewTimer(d)
+		return timer
+	}
+	defer func() {
+		retryTimer = time.NewTimer
+	}()
+
+	assert.NoError(t, SleepContext(context.Background(), time.Millisecond))
+
+	// canceled context returns at once and stops timer
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	assert.ErrorIs(t, SleepContext(ctx, time.Hour), context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, timer.Stop())
+}
+
+func TestRetryContext(t *testing.T) {
+
+	// canceled context stops retries instead of sleeping for remaining attempts
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	start := time.Now()
+	err := RetryContext(ctx, RetryOptions{Attempts: 5, Delay: time.Hour}, func(attempt int) RetryState {
+		calls++
+		cancel()
+		return RetryState{Err: errors.New("timeout")}
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,171 @@
+package common
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/devopsext/utils"
+)
+
+const (
+	RotateFileKeep = 5
+	rotateFileMode = 0600
+)
+
+// RotateFile is append only writer which rotates file to file.1, file.2 and so on when size exceeds max size
+type RotateFile struct {
+	mutex   sync.Mutex
+	path    string
+	maxSize int64
+	keep    int
+	file    *os.File
+	size    int64
+}
+
+// parseSize parses sizes like 1024, 512KB, 10MB or 1GB
+func parseSize(s string) (int64, error) {
+
+	s = strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, u := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, u.suffix) {
+			multiplier = u.size
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %s", s)
+	}
+	return n * multiplier, nil
+}
+
+func urlFilePath(u *url.URL) string {
+
+	// file://out.json keeps relative path in host
+	p := u.Host + u.Path
+	if u.Opaque != "" {
+		p = u.Opaque
+	}
+	return filepath.FromSlash(p)
+}
+
+// rotateFiles shifts file to file.1, file.1 to file.2 and so on, keeping up to keep files
+func rotateFiles(path string, keep int) error {
+
+	for i := keep - 1; i > 0; i-- {
+		from := fmt.Sprintf("%s.%d", path, i)
+		if _, err := os.Stat(from); err != nil {
+			continue
+		}
+		if err := os.Rename(from, fmt.Sprintf("%s.%d", path, i+1)); err != nil {
+			return err
+		}
+	}
+	if keep <= 0 {
+		return os.Remove(path)
+	}
+	return os.Rename(path, path+".1")
+}
+
+func (r *RotateFile) open() error {
+
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, rotateFileMode)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+func (r *RotateFile) Write(p []byte) (int, error) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		r.file.Close()
+		r.file = nil
+		if err := rotateFiles(r.path, r.keep); err != nil {
+			return 0, err
+		}
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotateFile) Close() error {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// NewRotateFile creates writer, max size 0 disables rotation
+func NewRotateFile(path string, maxSize int64, keep int) *RotateFile {
+
+	return &RotateFile{
+		path:    path,
+		maxSize: maxSize,
+		keep:    keep,
+	}
+}
+
+// NewRotateFileFromURL creates writer for file:///path?rotate=10MB&keep=5
+func NewRotateFileFromURL(u *url.URL) (*RotateFile, error) {
+
+	path := urlFilePath(u)
+	if utils.IsEmpty(path) {
+		return nil, fmt.Errorf("file path is empty")
+	}
+
+	query := u.Query()
+	var size int64
+	var err error
+	if rotate := query.Get("rotate"); !utils.IsEmpty(rotate) {
+		if size, err = parseSize(rotate); err != nil {
+			return nil, err
+		}
+	}
+
+	keep := RotateFileKeep
+	if s := query.Get("keep"); !utils.IsEmpty(s) {
+		if keep, err = strconv.Atoi(s); err != nil {
+			return nil, fmt.Errorf("invalid keep %s", s)
+		}
+	}
+	return NewRotateFile(path, size, keep), nil
+}
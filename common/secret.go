@@ -0,0 +1,172 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	SecretPrefix = "secret:"
+	SecretMask   = "********"
+
+	// values shorter than this are not redacted to avoid masking common words
+	secretMinSensitiveLength = 4
+)
+
+// SecretBackend resolves reference without scheme, e.g. "/run/secrets/jira" for "file:/run/secrets/jira"
+type SecretBackend interface {
+	Resolve(ref string) (string, error)
+}
+
+type SecretBackendFunc func(ref string) (string, error)
+
+func (f SecretBackendFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+type secretRegistry struct {
+	mutex     sync.RWMutex
+	backends  map[string]SecretBackend
+	sensitive map[string]bool
+	replacer  *strings.Replacer
+}
+
+var secrets = &secretRegistry{
+	backends: map[string]SecretBackend{
+		"file": SecretBackendFunc(secretFile),
+		"env":  SecretBackendFunc(secretEnv),
+	},
+	sensitive: make(map[string]bool),
+}
+
+func secretFile(ref string) (string, error) {
+
+	b, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+func secretEnv(ref string) (string, error) {
+
+	v, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("secret env %s is not set", ref)
+	}
+	return v, nil
+}
+
+// RegisterSecretBackend adds or replaces backend for scheme
+func RegisterSecretBackend(scheme string, backend SecretBackend) {
+
+	secrets.mutex.Lock()
+	defer secrets.mutex.Unlock()
+	secrets.backends[strings.ToLower(scheme)] = backend
+}
+
+// SecretSchemes returns sorted list of registered schemes
+func SecretSchemes() []string {
+
+	secrets.mutex.RLock()
+	defer secrets.mutex.RUnlock()
+
+	var r []string
+	for k := range secrets.backends {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r
+}
+
+// AddSensitive marks value to be redacted by logger and server
+func AddSensitive(values ...string) {
+
+	secrets.mutex.Lock()
+	defer secrets.mutex.Unlock()
+
+	for _, v := range values {
+		if len(v) < secretMinSensitiveLength || secrets.sensitive[v] {
+			continue
+		}
+		secrets.sensitive[v] = true
+		secrets.replacer = nil
+	}
+}
+
+// RedactSensitive replaces all values marked as sensitive with mask
+func RedactSensitive(s string) string {
+
+	secrets.mutex.RLock()
+	replacer := secrets.replacer
+	count := len(secrets.sensitive)
+	secrets.mutex.RUnlock()
+
+	if count == 0 || s == "" {
+		return s
+	}
+
+	if replacer == nil {
+		secrets.mutex.Lock()
+		if secrets.replacer == nil {
+			var values []string
+			for k := range secrets.sensitive {
+				values = append(values, k)
+			}
+			// longer values first, so a secret containing another one is fully masked
+			sort.Slice(values, func(i, j int) bool {
+				return len(values[i]) > len(values[j])
+			})
+			var pairs []string
+			for _, v := range values {
+				pairs = append(pairs, v, SecretMask)
+			}
+			secrets.replacer = strings.NewReplacer(pairs...)
+		}
+		replacer = secrets.replacer
+		secrets.mutex.Unlock()
+	}
+	return replacer.Replace(s)
+}
+
+// ResolveSecret resolves reference like "file:/run/secrets/jira", "env:JIRA_TOKEN", "rsa:<ciphertext>", "vault:<path>#<key>"
+func ResolveSecret(ref string) (string, error) {
+
+	ref = strings.TrimPrefix(strings.TrimSpace(ref), SecretPrefix)
+	scheme, value, ok := strings.Cut(ref, ":")
+	if !ok {
+		return "", fmt.Errorf("secret reference %s has no scheme", ref)
+	}
+
+	secrets.mutex.RLock()
+	backend, ok := secrets.backends[strings.ToLower(scheme)]
+	secrets.mutex.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("secret scheme %s is not supported", scheme)
+	}
+
+	r, err := backend.Resolve(value)
+	if err != nil {
+		return "", fmt.Errorf("secret %s: %v", scheme, err)
+	}
+	AddSensitive(r)
+	return r, nil
+}
+
+// IsSecretReference checks that value has to be resolved, e.g. "secret:env:JIRA_TOKEN"
+func IsSecretReference(s string) bool {
+	return strings.HasPrefix(s, SecretPrefix)
+}
+
+// ResolveSecretValue resolves value if it is secret reference, otherwise returns it as is
+func ResolveSecretValue(s string) (string, error) {
+
+	if !IsSecretReference(s) {
+		return s, nil
+	}
+	return ResolveSecret(s)
+}
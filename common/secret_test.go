@@ -0,0 +1,53 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecret(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "jira")
+	require.NoError(t, os.WriteFile(file, []byte("file-secret\n"), 0600))
+	t.Setenv("TOOLS_TEST_SECRET", "env-secret")
+
+	RegisterSecretBackend("test", SecretBackendFunc(func(ref string) (string, error) {
+		return "test-" + ref, nil
+	}))
+
+	tests := []struct {
+		ref      string
+		expected string
+		err      bool
+	}{
+		{ref: "file:" + file, expected: "file-secret"},
+		{ref: "env:TOOLS_TEST_SECRET", expected: "env-secret"},
+		{ref: "secret:test:value", expected: "test-value"},
+		{ref: "env:TOOLS_TEST_SECRET_MISSING", err: true},
+		{ref: "unknown:value", err: true},
+		{ref: "value", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			r, err := ResolveSecret(tt.ref)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, r)
+		})
+	}
+
+	assert.Equal(t, "token: "+SecretMask, RedactSensitive("token: env-secret"))
+	assert.Equal(t, "plain value", RedactSensitive("plain value"))
+
+	v, err := ResolveSecretValue("plain")
+	require.NoError(t, err)
+	assert.Equal(t, "plain", v)
+}
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/devopsext/utils"
+)
+
+const (
+	OutputSinkTimeout  = 30
+	outputSinkFileMode = 0600
+)
+
+// OutputSink writes command output to target like "s3://bucket/key" or "slack://channel"
+type OutputSink interface {
+	Write(u *url.URL, data []byte) error
+}
+
+type OutputSinkFunc func(u *url.URL, data []byte) error
+
+func (f OutputSinkFunc) Write(u *url.URL, data []byte) error {
+	return f(u, data)
+}
+
+type outputSinkRegistry struct {
+	mutex sync.RWMutex
+	sinks map[string]OutputSink
+}
+
+var outputSinks = &outputSinkRegistry{
+	sinks: map[string]OutputSink{
+		"file":  OutputSinkFunc(outputSinkFile),
+		"http":  OutputSinkFunc(outputSinkHttp),
+		"https": OutputSinkFunc(outputSinkHttp),
+	},
+}
+
+// RegisterOutputSink adds or replaces sink for scheme
+func RegisterOutputSink(scheme string, sink OutputSink) {
+
+	outputSinks.mutex.Lock()
+	defer outputSinks.mutex.Unlock()
+	outputSinks.sinks[strings.ToLower(scheme)] = sink
+}
+
+// OutputSchemes returns sorted list of registered schemes
+func OutputSchemes() []string {
+
+	outputSinks.mutex.RLock()
+	defer outputSinks.mutex.RUnlock()
+
+	var r []string
+	for k := range outputSinks.sinks {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r
+}
+
+// outputSinkFile writes to file:///path?append=true&rotate=10MB&keep=5
+func outputSinkFile(u *url.URL, data []byte) error {
+
+	appendMode, _ := strconv.ParseBool(u.Query().Get("append"))
+	if !appendMode {
+		path := urlFilePath(u)
+		if utils.IsEmpty(path) {
+			return fmt.Errorf("output file path is empty")
+		}
+		return os.WriteFile(path, data, outputSinkFileMode)
+	}
+
+	f, err := NewRotateFileFromURL(u)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+// outputSinkHttp posts output to URL as is
+func outputSinkHttp(u *url.URL, data []byte) error {
+
+	contentType := "text/plain"
+	if json.Valid(data) {
+		contentType = "application/json"
+	}
+
+	client := NewHttpClient(OutputSinkTimeout, false)
+	_, err := utils.HttpPostRaw(client, u.String(), contentType, "", data)
+	return err
+}
+
+// WriteOutput writes data to plain file path or URI target like "s3://bucket/key", "https://host/path", "slack://channel", "file:///path?append=true"
+func WriteOutput(to string, data []byte) error {
+
+	scheme, _, ok := strings.Cut(to, "://")
+	if !ok || strings.ContainsAny(scheme, `/\`) {
+		return os.WriteFile(to, data, outputSinkFileMode)
+	}
+
+	u, err := url.Parse(to)
+	if err != nil {
+		return err
+	}
+
+	outputSinks.mutex.RLock()
+	sink, ok := outputSinks.sinks[strings.ToLower(u.Scheme)]
+	outputSinks.mutex.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("output scheme %s is not supported, use one of: %s", u.Scheme, strings.Join(OutputSchemes(), ", "))
+	}
+
+	if err := sink.Write(u, data); err != nil {
+		return fmt.Errorf("output %s: %v", u.Scheme, err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package common
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteOutputFile(t *testing.T) {
+
+	dir := t.TempDir()
+
+	plain := filepath.Join(dir, "plain.json")
+	require.NoError(t, WriteOutput(plain, []byte(`{"a":1}`)))
+	require.NoError(t, WriteOutput(plain, []byte(`{"a":2}`)))
+	b, err := os.ReadFile(plain)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":2}`, string(b))
+
+	appended := filepath.Join(dir, "appended.log")
+	for _, s := range []string{"one", "two"} {
+		require.NoError(t, WriteOutput("file://"+appended+"?append=true", []byte(s)))
+	}
+	b, err = os.ReadFile(appended)
+	require.NoError(t, err)
+	assert.Equal(t, "one\ntwo\n", string(b))
+
+	rotated := filepath.Join(dir, "rotated.log")
+	for _, s := range []string{"first", "second", "third"} {
+		require.NoError(t, WriteOutput("file://"+rotated+"?append=true&rotate=8B&keep=1", []byte(s)))
+	}
+	b, err = os.ReadFile(rotated)
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(b))
+	b, err = os.ReadFile(rotated + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", string(b))
+	assert.NoFileExists(t, rotated+".2")
+}
+
+func TestWriteOutputHttp(t *testing.T) {
+
+	var body, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body, contentType = string(b), r.Header.Get("Content-Type")
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	require.NoError(t, WriteOutput(server.URL+"/ok", []byte(`{"a":1}`)))
+	assert.Equal(t, `{"a":1}`, body)
+	assert.Equal(t, "application/json", contentType)
+
+	assert.Error(t, WriteOutput(server.URL+"/fail", []byte("text")))
+	assert.Equal(t, "text/plain", contentType)
+}
+
+func TestWriteOutputScheme(t *testing.T) {
+
+	var got string
+	RegisterOutputSink("test", OutputSinkFunc(func(u *url.URL, data []byte) error {
+		got = u.Host + u.Path + "=" + string(data)
+		return nil
+	}))
+
+	require.NoError(t, WriteOutput("test://channel/x", []byte("data")))
+	assert.Equal(t, "channel/x=data", got)
+	assert.Error(t, WriteOutput("unknown://target", []byte("data")))
+}
@@ -3,7 +3,11 @@ package common
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"sort"
+	"strings"
 	"text/template"
 
 	"github.com/devopsext/utils"
@@ -16,14 +20,29 @@ type StdoutOptions struct {
 	Template        string
 	TimestampFormat string
 	TextColors      bool
+	Output          string // stdout, stderr, file path, file:///path?rotate=10MB&keep=5, syslog://host:514?tag=tools, journald
 }
 
 type Stdout struct {
 	log          *logrus.Logger
 	options      StdoutOptions
 	callerOffset int
+	fields       logrus.Fields
 }
 
+const (
+	StdoutOutputStdout   = "stdout"
+	StdoutOutputStderr   = "stderr"
+	StdoutOutputSyslog   = "syslog"
+	StdoutOutputJournald = "journald"
+	StdoutOutputFile     = "file"
+
+	stdoutSyslogTag = "tools"
+)
+
+// stdoutCallerFields are added to every message and are not listed in template .fields
+var stdoutCallerFields = map[string]bool{"file": true, "func": true}
+
 type templateFormatter struct {
 	template        *template.Template
 	timestampFormat string
@@ -46,6 +65,7 @@ func (f *templateFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	m["msg"] = entry.Message
 	m["time"] = entry.Time.Format(f.timestampFormat)
 	m["level"] = entry.Level.String()
+	m["fields"] = templateFields(entry.Data)
 
 	var err error
 
@@ -62,13 +82,38 @@ func (f *templateFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	return []byte(r), err
 }
 
-func (so *Stdout) addCallerFields(offset int) logrus.Fields {
+// templateFields formats fields as " key=value" pairs sorted by key
+func templateFields(data logrus.Fields) string {
+
+	var keys []string
+	for k := range data {
+		if !stdoutCallerFields[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, data[k])
+	}
+	return b.String()
+}
+
+func (so *Stdout) addCallerFields(offset int, fields logrus.Fields) logrus.Fields {
 
 	function, file, line := utils.CallerGetInfo(so.callerOffset + offset)
-	return logrus.Fields{
+	r := logrus.Fields{
 		"file": fmt.Sprintf("%s:%d", file, line),
 		"func": function,
 	}
+	for k, v := range so.fields {
+		r[k] = v
+	}
+	for k, v := range fields {
+		r[k] = v
+	}
+	return r
 }
 
 func prepare(message string, args ...interface{}) string {
@@ -80,10 +125,59 @@ func prepare(message string, args ...interface{}) string {
 	}
 }
 
-func (so *Stdout) exists(level logrus.Level, obj interface{}, args ...interface{}) (bool, string) {
+// formatVerbs counts verbs in format, so the rest of args are treated as key/value fields
+func formatVerbs(format string) int {
+
+	n := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '%' {
+			i++
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+func logFields(keyvals ...interface{}) logrus.Fields {
+
+	fields := logrus.Fields{}
+	for i := 0; i < len(keyvals); i += 2 {
+
+		key := fmt.Sprintf("%v", keyvals[i])
+		var value interface{}
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+
+		switch v := value.(type) {
+		case string:
+			value = RedactSensitive(v)
+		case error:
+			value = RedactSensitive(v.Error())
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// logArgs splits args into format arguments and key/value fields, e.g. Debug("HTTP request completed", "url", url)
+func logArgs(message string, args []interface{}) ([]interface{}, logrus.Fields) {
+
+	n := formatVerbs(message)
+	if n >= len(args) {
+		return args, nil
+	}
+	return args[:n], logFields(args[n:]...)
+}
+
+func (so *Stdout) exists(level logrus.Level, obj interface{}, args ...interface{}) (bool, string, logrus.Fields) {
 
 	if so == nil || so.log == nil || obj == nil {
-		return false, ""
+		return false, "", nil
 	}
 
 	message := ""
@@ -97,48 +191,108 @@ func (so *Stdout) exists(level logrus.Level, obj interface{}, args ...interface{
 		message = "not implemented"
 	}
 
+	var fields logrus.Fields
 	flag := message != "" && so.log.IsLevelEnabled(level)
 	if flag {
-		message = prepare(message, args...)
+		args, fields = logArgs(message, args)
+		message = RedactSensitive(prepare(message, args...))
 	}
-	return flag, message
+	return flag, message, fields
 }
 
 func (so *Stdout) Info(obj interface{}, args ...interface{}) {
 
-	if exists, message := so.exists(logrus.InfoLevel, obj, args...); exists {
-		so.log.WithFields(so.addCallerFields(3)).Infoln(message)
+	if exists, message, fields := so.exists(logrus.InfoLevel, obj, args...); exists {
+		so.log.WithFields(so.addCallerFields(3, fields)).Infoln(message)
 	}
 }
 
 func (so *Stdout) Warn(obj interface{}, args ...interface{}) {
 
-	if exists, message := so.exists(logrus.WarnLevel, obj, args...); exists {
-		so.log.WithFields(so.addCallerFields(3)).Warnln(message)
+	if exists, message, fields := so.exists(logrus.WarnLevel, obj, args...); exists {
+		so.log.WithFields(so.addCallerFields(3, fields)).Warnln(message)
 	}
 }
 
 func (so *Stdout) Error(obj interface{}, args ...interface{}) {
 
-	if exists, message := so.exists(logrus.ErrorLevel, obj, args...); exists {
-		so.log.WithFields(so.addCallerFields(3)).Errorln(message)
+	if exists, message, fields := so.exists(logrus.ErrorLevel, obj, args...); exists {
+		so.log.WithFields(so.addCallerFields(3, fields)).Errorln(message)
 	}
 }
 
 func (so *Stdout) Debug(obj interface{}, args ...interface{}) {
 
-	if exists, message := so.exists(logrus.DebugLevel, obj, args...); exists {
-		so.log.WithFields(so.addCallerFields(3)).Debugln(message)
+	if exists, message, fields := so.exists(logrus.DebugLevel, obj, args...); exists {
+		so.log.WithFields(so.addCallerFields(3, fields)).Debugln(message)
 	}
 }
 
 func (so *Stdout) Panic(obj interface{}, args ...interface{}) {
 
-	if exists, message := so.exists(logrus.PanicLevel, obj, args...); exists {
-		so.log.WithFields(so.addCallerFields(3)).Panicln(message)
+	if exists, message, fields := so.exists(logrus.PanicLevel, obj, args...); exists {
+		so.log.WithFields(so.addCallerFields(3, fields)).Panicln(message)
+	}
+}
+
+// With returns logger which adds key/value fields to every message
+func (so *Stdout) With(keyvals ...interface{}) Logger {
+
+	if so == nil {
+		return so
+	}
+
+	fields := logrus.Fields{}
+	for k, v := range so.fields {
+		fields[k] = v
+	}
+	for k, v := range logFields(keyvals...) {
+		fields[k] = v
+	}
+
+	return &Stdout{
+		log:          so.log,
+		options:      so.options,
+		callerOffset: so.callerOffset,
+		fields:       fields,
 	}
 }
 
+func newLogOutput(output string) (io.Writer, logrus.Hook, error) {
+
+	output = strings.TrimSpace(output)
+	switch strings.ToLower(output) {
+	case "", StdoutOutputStdout:
+		return os.Stdout, nil, nil
+	case StdoutOutputStderr:
+		return os.Stderr, nil, nil
+	case StdoutOutputSyslog, StdoutOutputJournald:
+		// journald collects local syslog messages
+		hook, err := newSyslogHook(&url.URL{Scheme: StdoutOutputSyslog})
+		return io.Discard, hook, err
+	}
+
+	scheme, _, ok := strings.Cut(output, "://")
+	if !ok || strings.ContainsAny(scheme, `/\`) {
+		return NewRotateFile(output, 0, RotateFileKeep), nil, nil
+	}
+
+	u, err := url.Parse(output)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case StdoutOutputFile:
+		f, err := NewRotateFileFromURL(u)
+		return f, nil, err
+	case StdoutOutputSyslog:
+		hook, err := newSyslogHook(u)
+		return io.Discard, hook, err
+	}
+	return nil, nil, fmt.Errorf("stdout output %s is not supported", u.Scheme)
+}
+
 func newLog(options StdoutOptions) *logrus.Logger {
 
 	log := logrus.New()
@@ -183,7 +337,16 @@ func newLog(options StdoutOptions) *logrus.Logger {
 		log.SetLevel(logrus.InfoLevel)
 	}
 
-	log.SetOutput(os.Stdout)
+	out, hook, err := newLogOutput(options.Output)
+	if err != nil {
+		log.SetOutput(os.Stderr)
+		log.Error(err)
+		return log
+	}
+	log.SetOutput(out)
+	if hook != nil {
+		log.AddHook(hook)
+	}
 	return log
 }
 
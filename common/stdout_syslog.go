@@ -0,0 +1,27 @@
+//go:build !windows
+
+package common
+
+import (
+	"log/syslog"
+	"net/url"
+
+	"github.com/devopsext/utils"
+	"github.com/sirupsen/logrus"
+	lsyslog "github.com/sirupsen/logrus/hooks/syslog"
+)
+
+// newSyslogHook creates hook for syslog://host:514?network=udp&tag=tools, local syslog is used without host
+func newSyslogHook(u *url.URL) (logrus.Hook, error) {
+
+	query := u.Query()
+	network := query.Get("network")
+	if utils.IsEmpty(network) && !utils.IsEmpty(u.Host) {
+		network = "udp"
+	}
+	tag := query.Get("tag")
+	if utils.IsEmpty(tag) {
+		tag = stdoutSyslogTag
+	}
+	return lsyslog.NewSyslogHook(network, u.Host, syslog.LOG_INFO|syslog.LOG_USER, tag)
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package common
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newSyslogHook(u *url.URL) (logrus.Hook, error) {
+	return nil, errors.New("syslog is not supported on windows")
+}
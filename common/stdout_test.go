@@ -0,0 +1,66 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdoutFields(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "tools.log")
+	stdout := NewStdout(StdoutOptions{Format: "json", Level: "debug", Output: file})
+
+	stdout.Debug("HTTP request completed", "url", "http://host", "status", 200)
+	stdout.With(CorrelationIDField, "id-1").Info("request %s", "done", "duration", "1s")
+
+	b, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, lines, 2)
+
+	var first, second map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+
+	assert.Equal(t, "HTTP request completed", first["msg"])
+	assert.Equal(t, "http://host", first["url"])
+	assert.Equal(t, float64(200), first["status"])
+
+	assert.Equal(t, "request done", second["msg"])
+	assert.Equal(t, "id-1", second[CorrelationIDField])
+	assert.Equal(t, "1s", second["duration"])
+}
+
+func TestRotateFile(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "tools.log")
+	w := NewRotateFile(file, 10, 2)
+	defer w.Close()
+
+	for i := 0; i < 4; i++ {
+		_, err := fmt.Fprintf(w, "line-%d\n", i)
+		require.NoError(t, err)
+	}
+
+	for name, want := range map[string]string{file: "line-3\n", file + ".1": "line-2\n", file + ".2": "line-1\n"} {
+		b, err := os.ReadFile(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(b))
+	}
+	assert.NoFileExists(t, file+".3")
+}
+
+func TestExitCode(t *testing.T) {
+
+	assert.Equal(t, ExitCodeOK, ExitCode(nil))
+	assert.Equal(t, ExitCodeError, ExitCode(errors.New("failed")))
+	assert.Equal(t, 3, ExitCode(fmt.Errorf("wrapped: %w", NewExitError(3, errors.New("failed")))))
+}
@@ -0,0 +1,338 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/devopsext/utils"
+)
+
+const (
+	TableSlackMaxFields = 10
+	TableSlackMaxText   = 2000
+	TableSlackMaxBlocks = 50
+)
+
+type TableOptions struct {
+	Columns  []string
+	Mapping  map[string]string
+	Width    int
+	Sort     string
+	NoHeader bool
+}
+
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+func tableCompare(v1, v2 interface{}) int {
+
+	f1, ok1 := v1.(float64)
+	f2, ok2 := v2.(float64)
+	if ok1 && ok2 {
+		switch {
+		case f1 < f2:
+			return -1
+		case f1 > f2:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(ValueToString(v1), ValueToString(v2))
+}
+
+func tableCell(v interface{}, width int) string {
+
+	s := ValueToString(v)
+	s = strings.ReplaceAll(s, "\r", "")
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "\t", " ")
+	if width > 0 && utf8.RuneCountInString(s) > width {
+		s = TruncateString(s, width)
+	}
+	return s
+}
+
+// NewTable converts list of objects into rows according to column selection, sort and width
+func NewTable(i interface{}, options TableOptions) (*Table, error) {
+
+	v, err := NormalizeJson(i)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []interface{}
+	switch vt := v.(type) {
+	case nil:
+		items = []interface{}{}
+	case []interface{}:
+		items = vt
+	default:
+		items = []interface{}{vt}
+	}
+
+	columns := options.Columns
+	if len(columns) == 0 {
+		columns = MapKeys(items)
+	}
+
+	if !utils.IsEmpty(options.Sort) {
+
+		field := strings.TrimPrefix(options.Sort, "-")
+		desc := strings.HasPrefix(options.Sort, "-")
+
+		sorted := make([]interface{}, len(items))
+		copy(sorted, items)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			m1, _ := sorted[i].(map[string]interface{})
+			m2, _ := sorted[j].(map[string]interface{})
+			c := tableCompare(m1[field], m2[field])
+			if desc {
+				return c > 0
+			}
+			return c < 0
+		})
+		items = sorted
+	}
+
+	t := &Table{}
+	for _, c := range columns {
+		title := c
+		if v, ok := options.Mapping[c]; ok && !utils.IsEmpty(v) {
+			title = v
+		}
+		t.Headers = append(t.Headers, tableCell(title, options.Width))
+	}
+
+	for _, item := range items {
+
+		var row []string
+		switch it := item.(type) {
+		case map[string]interface{}:
+			for _, c := range columns {
+				row = append(row, tableCell(it[c], options.Width))
+			}
+		case []interface{}:
+			for _, f := range it {
+				row = append(row, tableCell(f, options.Width))
+			}
+		default:
+			row = append(row, tableCell(it, options.Width))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	if options.NoHeader {
+		t.Headers = nil
+	}
+	return t, nil
+}
+
+func (t *Table) widths() []int {
+
+	var widths []int
+	all := append([][]string{t.Headers}, t.Rows...)
+	for _, row := range all {
+		for k, cell := range row {
+			if k >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if l := utf8.RuneCountInString(cell); l > widths[k] {
+				widths[k] = l
+			}
+		}
+	}
+	return widths
+}
+
+func tablePad(s string, width int) string {
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+func (t *Table) asciiRow(b *bytes.Buffer, row []string, widths []int, border bool) {
+
+	cells := make([]string, len(widths))
+	for k, w := range widths {
+		cell := ""
+		if k < len(row) {
+			cell = row[k]
+		}
+		cells[k] = tablePad(cell, w)
+	}
+	if border {
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		return
+	}
+	b.WriteString(strings.TrimRight(strings.Join(cells, "  "), " ") + "\n")
+}
+
+func (t *Table) asciiLine(b *bytes.Buffer, widths []int) {
+
+	parts := make([]string, len(widths))
+	for k, w := range widths {
+		parts[k] = strings.Repeat("-", w+2)
+	}
+	b.WriteString("+" + strings.Join(parts, "+") + "+\n")
+}
+
+// Ascii renders table with borders
+func (t *Table) Ascii() string {
+
+	widths := t.widths()
+	if len(widths) == 0 {
+		return ""
+	}
+
+	var b bytes.Buffer
+	t.asciiLine(&b, widths)
+	if len(t.Headers) > 0 {
+		t.asciiRow(&b, t.Headers, widths, true)
+		t.asciiLine(&b, widths)
+	}
+	for _, row := range t.Rows {
+		t.asciiRow(&b, row, widths, true)
+	}
+	t.asciiLine(&b, widths)
+	return b.String()
+}
+
+// Plain renders aligned columns without borders
+func (t *Table) Plain() string {
+
+	widths := t.widths()
+	if len(widths) == 0 {
+		return ""
+	}
+
+	var b bytes.Buffer
+	if len(t.Headers) > 0 {
+		t.asciiRow(&b, t.Headers, widths, false)
+	}
+	for _, row := range t.Rows {
+		t.asciiRow(&b, row, widths, false)
+	}
+	return b.String()
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+func (t *Table) Markdown() string {
+
+	widths := t.widths()
+	if len(widths) == 0 {
+		return ""
+	}
+
+	headers := t.Headers
+	if len(headers) == 0 {
+		// markdown tables require header
+		headers = make([]string, len(widths))
+	}
+
+	var b bytes.Buffer
+	line := func(row []string) {
+		cells := make([]string, len(widths))
+		for k := range widths {
+			if k < len(row) {
+				cells[k] = markdownCell(row[k])
+			}
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	line(headers)
+	seps := make([]string, len(widths))
+	for k := range seps {
+		seps[k] = "---"
+	}
+	b.WriteString("| " + strings.Join(seps, " | ") + " |\n")
+	for _, row := range t.Rows {
+		line(row)
+	}
+	return b.String()
+}
+
+func (t *Table) slackRowBlocks(row []string) []interface{} {
+
+	blocks := []interface{}{}
+	fields := []interface{}{}
+	for i, cell := range row {
+
+		text := cell
+		if i < len(t.Headers) {
+			text = fmt.Sprintf("*%s*\n%s", t.Headers[i], cell)
+		}
+		fields = append(fields, map[string]interface{}{
+			"type": "mrkdwn",
+			"text": TruncateString(text, TableSlackMaxText),
+		})
+
+		if len(fields) == TableSlackMaxFields {
+			blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+			fields = []interface{}{}
+		}
+	}
+	if len(fields) > 0 {
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	}
+	return blocks
+}
+
+// SlackBlocks renders every row as section with fields, suitable for chat.postMessage blocks.
+// Rows which don't fit into Slack limit of 50 blocks are replaced by context block with their count
+func (t *Table) SlackBlocks() []interface{} {
+
+	blocks := []interface{}{}
+	for k, row := range t.Rows {
+
+		rowBlocks := t.slackRowBlocks(row)
+		if k > 0 {
+			rowBlocks = append([]interface{}{map[string]interface{}{"type": "divider"}}, rowBlocks...)
+		}
+
+		// keep one block for the note if more rows follow
+		limit := TableSlackMaxBlocks
+		if k < len(t.Rows)-1 {
+			limit--
+		}
+		if len(blocks)+len(rowBlocks) > limit {
+			blocks = append(blocks, map[string]interface{}{
+				"type": "context",
+				"elements": []interface{}{
+					map[string]interface{}{
+						"type": "mrkdwn",
+						"text": fmt.Sprintf("_%d more rows not shown_", len(t.Rows)-k),
+					},
+				},
+			})
+			break
+		}
+		blocks = append(blocks, rowBlocks...)
+	}
+	return blocks
+}
+
+// Telegram renders monospace table for HTML (default), MarkdownV2 or legacy Markdown parse modes, other modes get plain table
+func (t *Table) Telegram(parseMode string) string {
+
+	plain := strings.TrimRight(t.Plain(), "\n")
+	switch {
+	case strings.EqualFold(parseMode, "MarkdownV2"):
+		plain = strings.ReplaceAll(plain, "\\", "\\\\")
+		plain = strings.ReplaceAll(plain, "`", "\\`")
+		return "```\n" + plain + "\n```"
+	case strings.EqualFold(parseMode, "Markdown"):
+		// legacy markdown has no escaping inside pre, so backticks can't be kept
+		return "```\n" + strings.ReplaceAll(plain, "`", "'") + "\n```"
+	case utils.IsEmpty(parseMode) || strings.EqualFold(parseMode, "HTML"):
+		return "<pre>" + html.EscapeString(plain) + "</pre>"
+	}
+	return plain
+}
@@ -0,0 +1,162 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTable(t *testing.T) {
+
+	items := []interface{}{
+		map[string]interface{}{"name": "host-2", "cpu": 20.0},
+		map[string]interface{}{"name": "host-1", "cpu": 5.0},
+		map[string]interface{}{"name": "host-3|x", "cpu": 100.0},
+	}
+
+	tests := []struct {
+		name     string
+		options  TableOptions
+		expected *Table
+	}{
+		{
+			name:    "Columns and mapping",
+			options: TableOptions{Columns: []string{"name", "cpu"}, Mapping: map[string]string{"cpu": "CPU"}},
+			expected: &Table{
+				Headers: []string{"name", "CPU"},
+				Rows:    [][]string{{"host-2", "20"}, {"host-1", "5"}, {"host-3|x", "100"}},
+			},
+		},
+		{
+			name:    "Numeric sort descending",
+			options: TableOptions{Columns: []string{"name"}, Sort: "-cpu"},
+			expected: &Table{
+				Headers: []string{"name"},
+				Rows:    [][]string{{"host-3|x"}, {"host-2"}, {"host-1"}},
+			},
+		},
+		{
+			name:    "No header",
+			options: TableOptions{Columns: []string{"name"}, Sort: "name", NoHeader: true},
+			expected: &Table{
+				Rows: [][]string{{"host-1"}, {"host-2"}, {"host-3|x"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewTable(items, tt.options)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, r)
+		})
+	}
+}
+
+func TestTableRender(t *testing.T) {
+
+	table := &Table{
+		Headers: []string{"name", "status"},
+		Rows:    [][]string{{"host-1", "up"}, {"h|2", "down"}},
+	}
+
+	tests := []struct {
+		name     string
+		render   func() string
+		expected string
+	}{
+		{
+			name:   "Ascii",
+			render: table.Ascii,
+			expected: "+--------+--------+\n" +
+				"| name   | status |\n" +
+				"+--------+--------+\n" +
+				"| host-1 | up     |\n" +
+				"| h|2    | down   |\n" +
+				"+--------+--------+\n",
+		},
+		{
+			name:     "Plain",
+			render:   table.Plain,
+			expected: "name    status\nhost-1  up\nh|2     down\n",
+		},
+		{
+			name:     "Markdown",
+			render:   table.Markdown,
+			expected: "| name | status |\n| --- | --- |\n| host-1 | up |\n| h\\|2 | down |\n",
+		},
+		{
+			name:     "Telegram HTML",
+			render:   func() string { return (&Table{Rows: [][]string{{"a<b"}}}).Telegram("HTML") },
+			expected: "<pre>a&lt;b</pre>",
+		},
+		{
+			name:     "Telegram MarkdownV2",
+			render:   func() string { return (&Table{Rows: [][]string{{"a`b"}}}).Telegram("MarkdownV2") },
+			expected: "```\na\\`b\n```",
+		},
+		{
+			name:     "Telegram Markdown",
+			render:   func() string { return (&Table{Rows: [][]string{{"a_b`c"}}}).Telegram("Markdown") },
+			expected: "```\na_b'c\n```",
+		},
+		{
+			name:     "Telegram plain",
+			render:   func() string { return (&Table{Rows: [][]string{{"a<b"}}}).Telegram("None") },
+			expected: "a<b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.render())
+		})
+	}
+}
+
+func TestTableSlackBlocks(t *testing.T) {
+
+	rows := func(n int) [][]string {
+		r := [][]string{}
+		for i := 0; i < n; i++ {
+			r = append(r, []string{fmt.Sprintf("host-%d", i)})
+		}
+		return r
+	}
+
+	tests := []struct {
+		name   string
+		rows   [][]string
+		blocks int
+		note   string
+	}{
+		{name: "Empty", rows: rows(0), blocks: 0},
+		{name: "Rows with dividers", rows: rows(3), blocks: 5},
+		{name: "Exactly at limit", rows: rows(25), blocks: 49},
+		{name: "Truncated", rows: rows(40), blocks: 50, note: "_15 more rows not shown_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := (&Table{Headers: []string{"name"}, Rows: tt.rows}).SlackBlocks()
+			assert.Len(t, blocks, tt.blocks)
+			assert.LessOrEqual(t, len(blocks), TableSlackMaxBlocks)
+			if tt.note == "" {
+				return
+			}
+			last := blocks[len(blocks)-1].(map[string]interface{})
+			assert.Equal(t, "context", last["type"])
+			elements := last["elements"].([]interface{})
+			assert.Equal(t, tt.note, elements[0].(map[string]interface{})["text"])
+		})
+	}
+
+	t.Run("Fields split", func(t *testing.T) {
+		row := make([]string, 12)
+		blocks := (&Table{Rows: [][]string{row}}).SlackBlocks()
+		require.Len(t, blocks, 2)
+		assert.Len(t, blocks[0].(map[string]interface{})["fields"], TableSlackMaxFields)
+	})
+}
@@ -0,0 +1,240 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/devopsext/utils"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TracingExporterNone     = "none"
+	TracingExporterStdout   = "stdout"
+	TracingExporterStderr   = "stderr"
+	TracingExporterFile     = "file"
+	TracingExporterOtlpGrpc = "otlp-grpc"
+	TracingExporterOtlpHttp = "otlp-http"
+
+	tracingName = "github.com/devopsext/tools"
+)
+
+type TracingOptions struct {
+	Exporter    string // none, stdout, stderr, file:///path?rotate=10MB, otlp-grpc, otlp-http
+	Endpoint    string
+	Headers     []string // key=value
+	Insecure    bool
+	ServiceName string
+	SampleRatio *float64 // nil samples every trace, 0 disables sampling
+}
+
+var (
+	tracingEnabled atomic.Bool
+	tracingContext atomic.Pointer[context.Context]
+)
+
+// tracingSampler samples all traces if ratio is not set, explicit 0 samples none
+func tracingSampler(ratio *float64) sdktrace.Sampler {
+
+	switch {
+	case ratio == nil || *ratio >= 1:
+		return sdktrace.AlwaysSample()
+	case *ratio <= 0:
+		return sdktrace.NeverSample()
+	}
+	return sdktrace.TraceIDRatioBased(*ratio)
+}
+
+func tracingHeaders(headers []string) map[string]string {
+
+	r := make(map[string]string)
+	for _, h := range RemoveEmptyStrings(headers) {
+		k, v, ok := strings.Cut(h, "=")
+		if ok {
+			r[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return r
+}
+
+func newTracingExporter(ctx context.Context, options TracingOptions) (sdktrace.SpanExporter, error) {
+
+	exporter := strings.TrimSpace(options.Exporter)
+	switch strings.ToLower(exporter) {
+	case TracingExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case TracingExporterStderr:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+	case TracingExporterOtlpGrpc, "otlp":
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithHeaders(tracingHeaders(options.Headers))}
+		if !utils.IsEmpty(options.Endpoint) {
+			opts = append(opts, otlptracegrpc.WithEndpoint(options.Endpoint))
+		}
+		if options.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case TracingExporterOtlpHttp:
+		opts := []otlptracehttp.Option{otlptracehttp.WithHeaders(tracingHeaders(options.Headers))}
+		if !utils.IsEmpty(options.Endpoint) {
+			opts = append(opts, otlptracehttp.WithEndpoint(options.Endpoint))
+		}
+		if options.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	// file:///path or plain path, spans are written as json lines
+	var w io.Writer
+	scheme, _, ok := strings.Cut(exporter, "://")
+	if !ok || strings.ContainsAny(scheme, `/\`) {
+		w = NewRotateFile(exporter, 0, RotateFileKeep)
+	} else {
+		u, err := url.Parse(exporter)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(u.Scheme, TracingExporterFile) {
+			return nil, fmt.Errorf("tracing exporter %s is not supported", u.Scheme)
+		}
+		if w, err = NewRotateFileFromURL(u); err != nil {
+			return nil, err
+		}
+	}
+	return stdouttrace.New(stdouttrace.WithWriter(w))
+}
+
+// InitTracing sets global tracer provider, returns shutdown which flushes spans
+func InitTracing(options TracingOptions) (func(context.Context) error, error) {
+
+	nop := func(context.Context) error { return nil }
+	if utils.IsEmpty(options.Exporter) || strings.EqualFold(options.Exporter, TracingExporterNone) {
+		return nop, nil
+	}
+
+	ctx := context.Background()
+	exporter, err := newTracingExporter(ctx, options)
+	if err != nil {
+		return nop, err
+	}
+
+	name := options.ServiceName
+	if utils.IsEmpty(name) {
+		name = "tools"
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(name)))
+	if err != nil {
+		return nop, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(tracingSampler(options.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	tracingEnabled.Store(true)
+
+	return provider.Shutdown, nil
+}
+
+func TracingEnabled() bool {
+	return tracingEnabled.Load()
+}
+
+// SetTracingContext sets context which is used as parent for spans started without one, e.g. CLI command span
+func SetTracingContext(ctx context.Context) {
+	tracingContext.Store(&ctx)
+}
+
+func TracingContext() context.Context {
+
+	if ctx := tracingContext.Load(); ctx != nil && *ctx != nil {
+		return *ctx
+	}
+	return context.Background()
+}
+
+// StartSpan starts span as child of ctx or tracing context if ctx is nil
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+
+	if ctx == nil {
+		ctx = TracingContext()
+	}
+	return otel.Tracer(tracingName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records error if any and ends span
+func EndSpan(span trace.Span, err error) {
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, RedactSensitive(err.Error()))
+	}
+	span.End()
+}
+
+// ExtractTracingContext returns context with remote parent from traceparent header if any
+func ExtractTracingContext(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// tracingTransport makes requests without span in context children of parent context
+type tracingTransport struct {
+	transport http.RoundTripper
+	parent    func() context.Context
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if !trace.SpanContextFromContext(req.Context()).IsValid() {
+		span := trace.SpanFromContext(t.parent())
+		req = req.WithContext(trace.ContextWithSpan(req.Context(), span))
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// TracingTransportWithContext wraps transport with span per request having method, host and status, if tracing is enabled
+func TracingTransportWithContext(rt http.RoundTripper, parent func() context.Context) http.RoundTripper {
+
+	if !TracingEnabled() {
+		return rt
+	}
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if parent == nil {
+		parent = TracingContext
+	}
+	return &tracingTransport{
+		transport: otelhttp.NewTransport(rt,
+			otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+				return fmt.Sprintf("HTTP %s %s", r.Method, r.URL.Host)
+			}),
+		),
+		parent: parent,
+	}
+}
+
+func TracingTransport(rt http.RoundTripper) http.RoundTripper {
+	return TracingTransportWithContext(rt, TracingContext)
+}
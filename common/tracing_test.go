@@ -0,0 +1,83 @@
+package common
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTracingFileExporter(t *testing.T) {
+
+	t.Cleanup(func() {
+		tracingEnabled.Store(false)
+		SetTracingContext(context.Background())
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte("OK"))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "spans.json")
+	shutdown, err := InitTracing(TracingOptions{Exporter: "file://" + path, ServiceName: "test"})
+	require.NoError(t, err)
+	require.True(t, TracingEnabled())
+
+	ctx, span := StartSpan(context.Background(), "tools test")
+	SetTracingContext(ctx)
+
+	resp, err := NewHttpClient(5, false).Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	EndSpan(span, nil)
+	require.NoError(t, shutdown(context.Background()))
+
+	// request has no context, so its span is child of tracing context
+	assert.True(t, strings.HasPrefix(traceparent, "00-"+span.SpanContext().TraceID().String()))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"Name":"tools test"`)
+	assert.Contains(t, string(b), `"Name":"HTTP GET `+strings.TrimPrefix(srv.URL, "http://")+`"`)
+}
+
+func TestTracingExporterInvalid(t *testing.T) {
+
+	_, err := InitTracing(TracingOptions{Exporter: "kafka://localhost"})
+	assert.Error(t, err)
+	assert.False(t, TracingEnabled())
+}
+
+func TestTracingSampler(t *testing.T) {
+
+	ratio := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name     string
+		ratio    *float64
+		expected string
+	}{
+		{name: "Not set", ratio: nil, expected: "AlwaysOnSampler"},
+		{name: "Zero", ratio: ratio(0), expected: "AlwaysOffSampler"},
+		{name: "Ratio", ratio: ratio(0.25), expected: "TraceIDRatioBased{0.25}"},
+		{name: "One", ratio: ratio(1), expected: "AlwaysOnSampler"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tracingSampler(tt.ratio).Description())
+		})
+	}
+}
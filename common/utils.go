@@ -15,8 +15,11 @@ import (
 )
 
 type OutputOptions struct {
-	Output string
-	Query  string
+	Output   string
+	Query    string
+	QueryLib []string
+	Format   string
+	Columns  []string
 }
 
 func FormatBasicAuth(user, pass string) string {
@@ -52,19 +55,24 @@ func InterfaceToMap(prefix string, i interface{}) (map[string]interface{}, error
 }
 
 func Output(query, to string, prefix string, opts []interface{}, bytes []byte, stdout *Stdout) {
+	OutputJson(OutputOptions{Output: to, Query: query}, prefix, opts, bytes, stdout)
+}
+
+func OutputJson(outputOpts OutputOptions, prefix string, opts []interface{}, bytes []byte, stdout *Stdout) {
 
 	stdout.Debug("Raw output => %s", string(bytes))
 
-	b, err := utils.Content(query)
+	b, err := utils.Content(outputOpts.Query)
 	if err != nil {
 		stdout.Panic(err)
 	}
-	query = string(b)
+	query := string(b)
 
 	output := string(bytes)
+	var result interface{} = bytes
 	if !utils.IsEmpty(query) {
 
-		jnata := NewJsonata(JsonataOptions{})
+		jnata := NewJsonata(JsonataOptions{Libs: outputOpts.QueryLib})
 
 		for _, v := range opts {
 			vars, err := InterfaceToMap(prefix, v)
@@ -83,6 +91,7 @@ func Output(query, to string, prefix string, opts []interface{}, bytes []byte, s
 		if err != nil {
 			stdout.Panic(err)
 		}
+		result = v1
 
 		// v1 is json object
 		_, ok := v1.(map[string]interface{})
@@ -110,21 +119,25 @@ func Output(query, to string, prefix string, opts []interface{}, bytes []byte, s
 		}
 	}
 
+	if !utils.IsEmpty(outputOpts.Format) {
+		output, err = FormatOutput(result, outputOpts.Format, outputOpts.Columns)
+		if err != nil {
+			stdout.Panic(err)
+		}
+	}
+
+	to := outputOpts.Output
 	if utils.IsEmpty(to) {
 		stdout.Info(output)
 	} else {
 		stdout.Debug("Writing output to %s...", to)
-		err := os.WriteFile(to, []byte(output), 0600)
+		err := WriteOutput(to, []byte(output))
 		if err != nil {
-			stdout.Error(err)
+			stdout.Panic(err)
 		}
 	}
 }
 
-func OutputJson(outputOpts OutputOptions, prefix string, opts []interface{}, bytes []byte, stdout *Stdout) {
-	Output(outputOpts.Query, outputOpts.Output, prefix, opts, bytes, stdout)
-}
-
 func OutputRaw(output string, bytes []byte, stdout *Stdout) {
 
 	stdout.Debug("Raw output => %s", string(bytes))
@@ -134,9 +147,9 @@ func OutputRaw(output string, bytes []byte, stdout *Stdout) {
 		stdout.Info(out)
 	} else {
 		stdout.Debug("Writing output to %s...", output)
-		err := os.WriteFile(output, bytes, 0600)
+		err := WriteOutput(output, bytes)
 		if err != nil {
-			stdout.Error(err)
+			stdout.Panic(err)
 		}
 	}
 }
@@ -212,14 +225,28 @@ func RemoveEmptyStrings(items []string) []string {
 
 func Invoke(any interface{}, name string, args ...interface{}) ([]interface{}, error) {
 
-	var rt []interface{}
 	method := reflect.ValueOf(any).MethodByName(name)
 
 	vnil := reflect.ValueOf(nil)
 	if method == vnil {
-		return rt, fmt.Errorf("method %s not found", name)
+		return nil, fmt.Errorf("method %s not found", name)
+	}
+	return invokeValue(method, name, args...)
+}
+
+// InvokeFunc calls function value with args converted the same way as Invoke does
+func InvokeFunc(fn interface{}, name string, args ...interface{}) ([]interface{}, error) {
+
+	method := reflect.ValueOf(fn)
+	if method.Kind() != reflect.Func {
+		return nil, fmt.Errorf("function %s not found", name)
 	}
+	return invokeValue(method, name, args...)
+}
 
+func invokeValue(method reflect.Value, name string, args ...interface{}) ([]interface{}, error) {
+
+	var rt []interface{}
 	methodType := method.Type()
 	numIn := methodType.NumIn()
 
@@ -0,0 +1,311 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/devopsext/utils"
+)
+
+const (
+	VendorErrorAuth        = "auth"
+	VendorErrorNotFound    = "not_found"
+	VendorErrorRateLimit   = "rate_limit"
+	VendorErrorInvalid     = "invalid"
+	VendorErrorUnavailable = "unavailable"
+	VendorErrorTimeout     = "timeout"
+	VendorErrorCanceled    = "canceled"
+	VendorErrorUnknown     = "unknown"
+)
+
+// request ID headers of vendors, the first non empty is used
+var vendorRequestIDHeaders = []string{
+	"X-Request-Id",
+	"X-Arequestid",
+	"X-Amzn-Requestid",
+	"X-Amz-Request-Id",
+	"X-Correlation-Id",
+	"X-Trace-Id",
+	"X-Slack-Req-Id",
+	"Request-Id",
+}
+
+const vendorErrorMessageMax = 512
+
+// VendorError is returned by vendors, so callers can tell auth failures from not found or rate limits
+type VendorError struct {
+	Vendor    string `json:"vendor"`
+	Operation string `json:"operation,omitempty"`
+	Status    int    `json:"status,omitempty"` // HTTP status, 0 for transport errors
+	Code      string `json:"code,omitempty"`   // upstream error code
+	Message   string `json:"message,omitempty"`
+	Retryable bool   `json:"retryable"`
+	RequestID string `json:"requestId,omitempty"`
+	ErrorKind string `json:"-"` // kind of error which is not told by status, e.g. slack responds 200 with ok false
+	Err       error  `json:"-"`
+}
+
+func (e *VendorError) Error() string {
+
+	var sb strings.Builder
+	sb.WriteString(e.Vendor)
+	if !utils.IsEmpty(e.Operation) {
+		sb.WriteString(" ")
+		sb.WriteString(e.Operation)
+	}
+	sb.WriteString(":")
+	if e.Status > 0 {
+		sb.WriteString(fmt.Sprintf(" %d %s", e.Status, http.StatusText(e.Status)))
+	}
+	switch {
+	case !utils.IsEmpty(e.Message):
+		sb.WriteString(" " + e.Message)
+	case e.Err != nil && e.Status == 0:
+		sb.WriteString(" " + e.Err.Error())
+	}
+	if !utils.IsEmpty(e.Code) {
+		sb.WriteString(fmt.Sprintf(" [%s]", e.Code))
+	}
+	if !utils.IsEmpty(e.RequestID) {
+		sb.WriteString(fmt.Sprintf(" (request id: %s)", e.RequestID))
+	}
+	return sb.String()
+}
+
+func (e *VendorError) Unwrap() error {
+	return e.Err
+}
+
+// Kind classifies error by HTTP status or transport error, kind set by vendor is used as is
+func (e *VendorError) Kind() string {
+
+	switch {
+	case !utils.IsEmpty(e.ErrorKind):
+		return e.ErrorKind
+	case e.Status == http.StatusUnauthorized || e.Status == http.StatusForbidden:
+		return VendorErrorAuth
+	case e.Status == http.StatusNotFound || e.Status == http.StatusGone:
+		return VendorErrorNotFound
+	case e.Status == http.StatusTooManyRequests:
+		return VendorErrorRateLimit
+	case e.Status == http.StatusRequestTimeout || e.Status == http.StatusGatewayTimeout:
+		return VendorErrorTimeout
+	case e.Status >= 500:
+		return VendorErrorUnavailable
+	case e.Status >= 400:
+		return VendorErrorInvalid
+	case errors.Is(e.Err, context.Canceled):
+		return VendorErrorCanceled
+	case errors.Is(e.Err, context.DeadlineExceeded):
+		return VendorErrorTimeout
+	}
+
+	var ne net.Error
+	if errors.As(e.Err, &ne) {
+		if ne.Timeout() {
+			return VendorErrorTimeout
+		}
+		return VendorErrorUnavailable
+	}
+	return VendorErrorUnknown
+}
+
+// MarshalJSON adds kind, so server clients don't classify status themselves
+func (e *VendorError) MarshalJSON() ([]byte, error) {
+
+	type vendorError VendorError
+	return json.Marshal(struct {
+		*vendorError
+		Kind string `json:"kind"`
+	}{(*vendorError)(e), e.Kind()})
+}
+
+func vendorErrorRetryable(e *VendorError) bool {
+
+	switch e.Kind() {
+	case VendorErrorRateLimit, VendorErrorUnavailable, VendorErrorTimeout:
+		return true
+	}
+	return false
+}
+
+// vendorErrorValue returns first non empty string of keys, numbers are formatted as codes
+func vendorErrorValue(m map[string]interface{}, keys ...string) string {
+
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case string:
+			if !utils.IsEmpty(v) {
+				return v
+			}
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case []interface{}:
+			var s []string
+			for _, i := range v {
+				if str, ok := i.(string); ok && !utils.IsEmpty(str) {
+					s = append(s, str)
+				} else if im, ok := i.(map[string]interface{}); ok {
+					if str := vendorErrorValue(im, "message", "title", "detail"); !utils.IsEmpty(str) {
+						s = append(s, str)
+					}
+				}
+			}
+			if len(s) > 0 {
+				return strings.Join(s, "; ")
+			}
+		case map[string]interface{}:
+			if str := vendorErrorValue(v, "message", "description"); !utils.IsEmpty(str) {
+				return str
+			}
+		}
+	}
+	return ""
+}
+
+// parseVendorErrorBody finds upstream code, message and request ID in common JSON error shapes, other bodies are messages
+func parseVendorErrorBody(e *VendorError, body []byte) {
+
+	s := strings.TrimSpace(string(body))
+	if utils.IsEmpty(s) {
+		return
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		if !strings.HasPrefix(s, "<") {
+			if len(s) > vendorErrorMessageMax {
+				s = s[:vendorErrorMessageMax] + "..."
+			}
+			e.Message = s
+		}
+		return
+	}
+
+	// {"error":{"code":..,"message":..}} is used by google, pagerduty and zabbix
+	if em, ok := m["error"].(map[string]interface{}); ok {
+		e.Code = vendorErrorValue(em, "code", "status", "reason")
+		e.Message = vendorErrorValue(em, "message", "data", "errors")
+	} else if es, ok := m["error"].(string); ok {
+		e.Code = es
+	}
+	if utils.IsEmpty(e.Code) {
+		e.Code = vendorErrorValue(m, "error_code", "errorCode", "code", "status_code")
+	}
+	if utils.IsEmpty(e.Message) {
+		e.Message = vendorErrorValue(m, "message", "Message", "error_description", "description", "detail", "errorMessages", "errors", "messages", "error_message", "title")
+	}
+	if e.Message == e.Code {
+		e.Message = ""
+	}
+	if utils.IsEmpty(e.RequestID) {
+		e.RequestID = vendorErrorValue(m, "requestId", "request_id", "traceId", "correlationId")
+	}
+}
+
+func vendorRequestID(header http.Header) string {
+
+	for _, h := range vendorRequestIDHeaders {
+		if v := header.Get(h); !utils.IsEmpty(v) {
+			return v
+		}
+	}
+	return ""
+}
+
+// NewVendorError creates error from status, headers and body of failed response
+func NewVendorError(vendor, operation string, status int, header http.Header, body []byte) *VendorError {
+
+	e := &VendorError{Vendor: vendor, Operation: operation, Status: status, RequestID: vendorRequestID(header)}
+	parseVendorErrorBody(e, body)
+	e.Err = fmt.Errorf("%d %s", status, http.StatusText(status))
+	e.Retryable = vendorErrorRetryable(e)
+	return e
+}
+
+// NewVendorErrorKind creates error whose kind is not told by status, e.g. error code of 200 response
+func NewVendorErrorKind(vendor, operation, kind, code, message string) *VendorError {
+
+	e := &VendorError{Vendor: vendor, Operation: operation, Code: code, Message: message, ErrorKind: kind}
+	e.Retryable = vendorErrorRetryable(e)
+	return e
+}
+
+// VendorErrorFrom converts error of vendor call to VendorError, status and request ID are taken from
+// HttpStatusError of HTTP helpers and body of failed response, existing VendorError is returned as is
+func VendorErrorFrom(vendor, operation string, body []byte, err error) error {
+
+	if err == nil {
+		return nil
+	}
+	var ve *VendorError
+	if errors.As(err, &ve) {
+		if utils.IsEmpty(ve.Operation) {
+			ve.Operation = operation
+		}
+		return err
+	}
+
+	e := &VendorError{Vendor: vendor, Operation: operation, Err: err}
+	var se *HttpStatusError
+	if errors.As(err, &se) {
+		e.Status = se.StatusCode
+		e.RequestID = vendorRequestID(se.Header)
+		parseVendorErrorBody(e, body)
+	}
+	e.Retryable = vendorErrorRetryable(e)
+	return e
+}
+
+// VendorErrorExitCode returns exit code of vendor error kind
+func VendorErrorExitCode(e *VendorError) int {
+
+	switch e.Kind() {
+	case VendorErrorAuth:
+		return ExitCodeAuth
+	case VendorErrorNotFound:
+		return ExitCodeNotFound
+	case VendorErrorRateLimit:
+		return ExitCodeRateLimit
+	case VendorErrorInvalid:
+		return ExitCodeInvalid
+	case VendorErrorUnavailable, VendorErrorTimeout:
+		return ExitCodeUnavailable
+	}
+	return ExitCodeVendor
+}
+
+// status of kinds which are set by vendor, e.g. for errors of 200 responses
+var vendorErrorKindStatus = map[string]int{
+	VendorErrorAuth:      http.StatusUnauthorized,
+	VendorErrorNotFound:  http.StatusNotFound,
+	VendorErrorRateLimit: http.StatusTooManyRequests,
+	VendorErrorInvalid:   http.StatusBadRequest,
+}
+
+// VendorErrorHttpStatus returns status of server response for vendor error, 0 if err is not vendor error
+func VendorErrorHttpStatus(err error) int {
+
+	var e *VendorError
+	if !errors.As(err, &e) {
+		return 0
+	}
+	switch e.Kind() {
+	case VendorErrorAuth, VendorErrorNotFound, VendorErrorRateLimit, VendorErrorInvalid:
+		if e.Status >= 400 {
+			return e.Status
+		}
+		return vendorErrorKindStatus[e.Kind()]
+	case VendorErrorTimeout:
+		return http.StatusGatewayTimeout
+	case VendorErrorCanceled:
+		return 499
+	}
+	return http.StatusBadGateway
+}
@@ -0,0 +1,98 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func vendorTestStatus(status int, requestID string) error {
+
+	e := &HttpStatusError{StatusCode: status, Status: fmt.Sprintf("%d %s", status, http.StatusText(status)), Header: http.Header{}}
+	if requestID != "" {
+		e.Header.Set("X-Request-Id", requestID)
+	}
+	return e
+}
+
+func TestVendorErrorFrom(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		body      string
+		err       error
+		kind      string
+		status    int
+		code      string
+		message   string
+		requestID string
+		retryable bool
+		exitCode  int
+	}{
+		{"jira", `{"errorMessages":["Issue does not exist"],"errors":{}}`, vendorTestStatus(404, "r1"), VendorErrorNotFound, 404, "", "Issue does not exist", "r1", false, ExitCodeNotFound},
+		{"pagerduty", `{"error":{"code":2006,"message":"Invalid token"}}`, vendorTestStatus(401, ""), VendorErrorAuth, 401, "2006", "Invalid token", "", false, ExitCodeAuth},
+		{"slack", `{"ok":false,"error":"ratelimited"}`, vendorTestStatus(429, ""), VendorErrorRateLimit, 429, "ratelimited", "", "", true, ExitCodeRateLimit},
+		{"grafana", `<html>bad gateway</html>`, vendorTestStatus(502, ""), VendorErrorUnavailable, 502, "", "", "", true, ExitCodeUnavailable},
+		{"netbox", `{"detail":"Invalid filter"}`, vendorTestStatus(400, ""), VendorErrorInvalid, 400, "", "Invalid filter", "", false, ExitCodeInvalid},
+		{"vault", "", context.DeadlineExceeded, VendorErrorTimeout, 0, "", "", "", true, ExitCodeUnavailable},
+		{"zabbix", "", errors.New("zabbix url is empty"), VendorErrorUnknown, 0, "", "", "", false, ExitCodeVendor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := VendorErrorFrom(tt.name, "call", []byte(tt.body), tt.err)
+			var e *VendorError
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, tt.kind, e.Kind())
+			assert.Equal(t, tt.status, e.Status)
+			assert.Equal(t, tt.code, e.Code)
+			assert.Equal(t, tt.message, e.Message)
+			assert.Equal(t, tt.requestID, e.RequestID)
+			assert.Equal(t, tt.retryable, e.Retryable)
+			assert.Equal(t, tt.exitCode, ExitCode(fmt.Errorf("wrapped: %w", err)))
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	assert.Nil(t, VendorErrorFrom("jira", "call", nil, nil))
+	assert.Equal(t, 0, VendorErrorHttpStatus(errors.New("plain")))
+	assert.Equal(t, http.StatusNotFound, VendorErrorHttpStatus(VendorErrorFrom("jira", "call", nil, vendorTestStatus(404, ""))))
+	assert.Equal(t, http.StatusBadGateway, VendorErrorHttpStatus(VendorErrorFrom("jira", "call", nil, vendorTestStatus(503, ""))))
+
+	// status text of other errors is not parsed
+	assert.Equal(t, http.StatusBadGateway, VendorErrorHttpStatus(VendorErrorFrom("jira", "call", nil, errors.New("404 Not Found"))))
+
+	// kind set by vendor is used for errors of 200 responses
+	e := NewVendorErrorKind("slack", "send-message", VendorErrorAuth, "invalid_auth", "")
+	assert.Equal(t, ExitCodeAuth, ExitCode(e))
+	assert.Equal(t, http.StatusUnauthorized, VendorErrorHttpStatus(e))
+	assert.False(t, e.Retryable)
+	assert.Equal(t, "slack send-message: [invalid_auth]", e.Error())
+	assert.True(t, NewVendorErrorKind("slack", "send-message", VendorErrorRateLimit, "ratelimited", "").Retryable)
+}
+
+func TestVendorStatusRequestID(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	b, err := HttpGetRaw(NewHttpClient(5, false), srv.URL, "", "")
+	var se *HttpStatusError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, http.StatusForbidden, se.StatusCode)
+
+	var e *VendorError
+	require.True(t, errors.As(VendorErrorFrom("jira", "call", b, err), &e))
+	assert.Equal(t, "abc", e.RequestID)
+	assert.Equal(t, "jira call: 403 Forbidden (request id: abc)", e.Error())
+}
@@ -0,0 +1,347 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
+)
+
+// Repl evaluates template expressions and pipelines line by line, variables are kept between lines
+type Repl struct {
+	options TemplateOptions
+	logger  common.Logger
+	vars    map[string]interface{}
+	funcs   []string
+	result  interface{}
+}
+
+const (
+	ReplLastVar      = "_"
+	replResultFunc   = "replResult"
+	replTemplateName = "repl"
+)
+
+var (
+	replAssignRegex = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)\s*:?=\s*(.+)$`)
+	replVarRegex    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	replNameRegex   = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+	replWordRegex   = regexp.MustCompile(`[$:]?[A-Za-z0-9_]*$`)
+)
+
+// ReplCommands are commands of REPL
+var ReplCommands = []string{":load", ":set", ":unset", ":vars", ":funcs", ":help", ":quit"}
+
+const replHelp = `Expressions and pipelines are evaluated as {{ ... }}, lines with {{ are rendered as text:
+  toUpper "abc"
+  $obj := jsonata $data "items[0]"
+  $obj | toJson
+  {{ range $k, $v := $obj }}{{ $k }}={{ $v }} {{ end }}
+Variables are $name or .name, $_ is the last result.
+Commands:
+  :load [name] <file>   bind JSON of file, name is file name without extension by default
+  :set <name> <json>    bind JSON value
+  :unset <name>         remove variable
+  :vars                 list variables
+  :funcs [prefix]       list functions
+  :help                 show help
+  :quit                 exit`
+
+// replFormat returns pretty JSON of result, JSON strings and bytes are indented as well
+func replFormat(v interface{}) string {
+
+	var raw []byte
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		raw = t
+	case string:
+		raw = []byte(t)
+	case error:
+		return t.Error()
+	default:
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(b)
+	}
+
+	s := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+		var i interface{}
+		if err := json.Unmarshal(raw, &i); err == nil {
+			b, err := json.MarshalIndent(i, "", "  ")
+			if err == nil {
+				return string(b)
+			}
+		}
+	}
+	return string(raw)
+}
+
+// replDecode returns JSON value of data or data as string
+func replDecode(data []byte) interface{} {
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return string(data)
+	}
+	return v
+}
+
+func (r *Repl) capture(v interface{}) string {
+	r.result = v
+	return ""
+}
+
+// content declares variables, so they are used as $name in line
+func (r *Repl) content(body string) string {
+
+	var sb strings.Builder
+	for _, name := range r.Vars() {
+		sb.WriteString(fmt.Sprintf("{{- $%s := index . %q -}}", name, name))
+	}
+	sb.WriteString(body)
+	return sb.String()
+}
+
+func (r *Repl) render(body string) ([]byte, error) {
+
+	options := r.options
+	options.Name = replTemplateName
+	options.Content = r.content(body)
+	options.Files = nil
+	options.Pattern = ""
+	options.Funcs = make(map[string]any)
+	for k, v := range r.options.Funcs {
+		options.Funcs[k] = v
+	}
+	options.Funcs[replResultFunc] = r.capture
+
+	tpl, err := NewTextTemplate(options, r.logger)
+	if err != nil {
+		return nil, err
+	}
+	return tpl.RenderObject(r.vars)
+}
+
+// expression evaluates pipeline and returns its value
+func (r *Repl) expression(expr string) (interface{}, error) {
+
+	r.result = nil
+	if _, err := r.render(fmt.Sprintf("{{ $__r := %s }}{{ %s $__r }}", expr, replResultFunc)); err != nil {
+		return nil, err
+	}
+	return r.result, nil
+}
+
+func (r *Repl) load(args []string) (string, error) {
+
+	var name, file string
+	switch len(args) {
+	case 1:
+		file = args[0]
+		name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		name = replNameRegex.ReplaceAllString(name, "_")
+	case 2:
+		name, file = args[0], args[1]
+	default:
+		return "", fmt.Errorf(":load [name] <file>")
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	if err := r.Set(name, replDecode(data)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$%s is loaded from %s", name, file), nil
+}
+
+func (r *Repl) command(line string) (string, error) {
+
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case ":load":
+		return r.load(fields[1:])
+	case ":set":
+		if len(fields) < 3 {
+			return "", fmt.Errorf(":set <name> <json>")
+		}
+		value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(line, ":set")), fields[1]))
+		return "", r.Set(fields[1], replDecode([]byte(value)))
+	case ":unset":
+		for _, name := range fields[1:] {
+			delete(r.vars, strings.TrimPrefix(name, "$"))
+		}
+		return "", nil
+	case ":vars":
+		var lines []string
+		for _, name := range r.Vars() {
+			lines = append(lines, fmt.Sprintf("$%s = %s", name, common.TruncateString(strings.Join(strings.Fields(replFormat(r.vars[name])), " "), 80)))
+		}
+		return strings.Join(lines, "\n"), nil
+	case ":funcs":
+		prefix := ""
+		if len(fields) > 1 {
+			prefix = fields[1]
+		}
+		var names []string
+		for _, name := range r.funcs {
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, " "), nil
+	case ":help":
+		return replHelp, nil
+	}
+	return "", fmt.Errorf("unknown command %s, use one of: %s", fields[0], strings.Join(ReplCommands, ", "))
+}
+
+// Set binds value to variable name
+func (r *Repl) Set(name string, value interface{}) error {
+
+	name = strings.TrimPrefix(name, "$")
+	if !replVarRegex.MatchString(name) {
+		return fmt.Errorf("invalid variable name %s", name)
+	}
+	r.vars[name] = value
+	return nil
+}
+
+// Vars returns variable names
+func (r *Repl) Vars() []string {
+
+	var names []string
+	for name := range r.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Eval evaluates line and returns formatted result, ":quit" is handled by caller
+func (r *Repl) Eval(line string) (string, error) {
+
+	line = strings.TrimSpace(line)
+	switch {
+	case utils.IsEmpty(line):
+		return "", nil
+	case strings.HasPrefix(line, ":"):
+		return r.command(line)
+	case strings.Contains(line, "{{"):
+		b, err := r.render(line)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	if m := replAssignRegex.FindStringSubmatch(line); m != nil {
+		v, err := r.expression(m[2])
+		if err != nil {
+			return "", err
+		}
+		r.vars[m[1]] = v
+		r.vars[ReplLastVar] = v
+		return "", nil
+	}
+
+	v, err := r.expression(line)
+	if err != nil {
+		return "", err
+	}
+	r.vars[ReplLastVar] = v
+	return replFormat(v), nil
+}
+
+// Complete completes function, variable or command name before pos, common prefix is used for several matches
+func (r *Repl) Complete(line string, pos int) (string, int, bool) {
+
+	word := replWordRegex.FindString(line[:pos])
+	if utils.IsEmpty(word) || word == "$" {
+		return "", 0, false
+	}
+
+	var candidates []string
+	switch {
+	case strings.HasPrefix(word, ":"):
+		candidates = ReplCommands
+	case strings.HasPrefix(word, "$"):
+		for _, name := range r.Vars() {
+			candidates = append(candidates, "$"+name)
+		}
+	default:
+		candidates = r.funcs
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+
+	prefix := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(matches) == 1 {
+		prefix += " "
+	}
+	if prefix == word {
+		return "", 0, false
+	}
+
+	start := pos - len(word)
+	newLine := line[:start] + prefix + line[pos:]
+	return newLine, start + len(prefix), true
+}
+
+// Funcs returns names of functions of the function map, sprig functions are included
+func (r *Repl) Funcs() []string {
+	return r.funcs
+}
+
+func NewRepl(options TemplateOptions, logger common.Logger) (*Repl, error) {
+
+	options.Content = "{{ $d := 0 }}"
+	tpl, err := NewTextTemplate(options, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var funcs []string
+	for name := range tpl.funcs {
+		funcs = append(funcs, name)
+	}
+	sort.Strings(funcs)
+
+	r := &Repl{
+		options: options,
+		logger:  logger,
+		vars:    make(map[string]interface{}),
+		funcs:   funcs,
+	}
+	if !utils.IsEmpty(options.Object) {
+		if err := r.Set("object", replDecode([]byte(options.Object))); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,51 @@
+package render
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/devopsext/tools/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepl(t *testing.T) {
+
+	r, err := NewRepl(TemplateOptions{Object: `{"name":"a"}`}, common.NewStdout(common.StdoutOptions{}))
+	require.NoError(t, err)
+
+	tests := []struct {
+		line string
+		out  string
+	}{
+		{`$object.name`, "a"},
+		{`:set data {"items":[1,2]}`, ""},
+		{`$n := len $data.items`, ""},
+		{`$n`, "2"},
+		{`toUpper $object.name`, "A"},
+		{`{{ $n }}-{{ $_ }}`, "2-A"},
+	}
+	for _, tt := range tests {
+		out, err := r.Eval(tt.line)
+		require.NoError(t, err, tt.line)
+		assert.Equal(t, tt.out, out, tt.line)
+	}
+
+	_, err = r.Eval(":nope")
+	assert.Error(t, err)
+
+	// missing file is error, not content
+	_, err = r.Eval(":load missing " + filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+	_, ok := r.vars["missing"]
+	assert.False(t, ok)
+
+	line, pos, ok := r.Complete("toUpp", 5)
+	assert.True(t, ok)
+	assert.Equal(t, "toUpper ", line)
+	assert.Equal(t, 8, pos)
+
+	line, _, ok = r.Complete("$da", 3)
+	assert.True(t, ok)
+	assert.Equal(t, "$data ", line)
+}
@@ -16,6 +16,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
@@ -25,7 +26,6 @@ import (
 	"time"
 
 	"github.com/Masterminds/sprig/v3"
-	"github.com/araddon/dateparse"
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/tools/vendors"
 	utils "github.com/devopsext/utils"
@@ -58,6 +58,7 @@ type Template struct {
 	logger  common.Logger
 	funcs   template.FuncMap
 	tpl     interface{}
+	ctx     context.Context
 }
 
 type TextTemplate struct {
@@ -74,6 +75,24 @@ type HTTPResult struct {
 	Body       []byte
 	Error      string
 	StatusCode int
+	Header     http.Header
+}
+
+// httpResultTransport keeps status and headers of the last response for HTTPResult
+type httpResultTransport struct {
+	transport  http.RoundTripper
+	statusCode int
+	header     http.Header
+}
+
+func (t *httpResultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	resp, err := t.transport.RoundTrip(req)
+	if resp != nil {
+		t.statusCode = resp.StatusCode
+		t.header = resp.Header
+	}
+	return resp, err
 }
 
 func (tpl *Template) ParserLine() (int, error) {
@@ -146,21 +165,13 @@ func (tpl *Template) ReplaceAll(f, t, s string) (string, error) {
 // regexReplaceAll replaces all occurrences of a regular expression with
 // the given replacement value.
 func (tpl *Template) RegexReplaceAll(re, pl, s string) (string, error) {
-	compiled, err := regexp.Compile(re)
-	if err != nil {
-		return "", err
-	}
-	return compiled.ReplaceAllString(s, pl), nil
+	return common.RegexReplaceAll(re, pl, s)
 }
 
 // regexMatch returns true or alse if the string matches
 // the given regular expression
 func (tpl *Template) RegexMatch(re, s string) (bool, error) {
-	compiled, err := regexp.Compile(re)
-	if err != nil {
-		return false, err
-	}
-	return compiled.MatchString(s), nil
+	return common.RegexMatch(re, s)
 }
 
 func (tpl *Template) RegexFindSubmatch(regex string, s string) []string {
@@ -168,83 +179,16 @@ func (tpl *Template) RegexFindSubmatch(regex string, s string) []string {
 	return r.FindStringSubmatch(s)
 }
 
-func (tpl *Template) regexMatchFindKey(v interface{}, field, value string) bool {
-
-	m, ok := v.(map[string]interface{})
-	if !ok {
-		return false
-	}
-	if m[field] == nil {
-		return false
-	}
-	s := fmt.Sprintf("%v", m[field])
-	match, _ := regexp.MatchString(fmt.Sprintf("^%s", s), value)
-	return match
-}
-
 func (tpl *Template) RegexMatchFindKeys(obj interface{}, field, value string) []interface{} {
-
-	var r []interface{}
-	if obj == nil || utils.IsEmpty(field) || utils.IsEmpty(value) {
-		return r
-	}
-
-	a, ok := obj.([]interface{})
-	if ok {
-		for k, v := range a {
-			if !tpl.regexMatchFindKey(v, field, value) {
-				continue
-			}
-			r = append(r, k)
-		}
-		return r
-	}
-
-	m, ok := obj.(map[string]interface{})
-	if ok {
-		for k, v := range m {
-			if !tpl.regexMatchFindKey(v, field, value) {
-				continue
-			}
-			r = append(r, k)
-		}
-	}
-
-	return r
+	return common.RegexMatchFindKeys(obj, field, value)
 }
 
 func (tpl *Template) RegexMatchFindKey(obj interface{}, field, value string) interface{} {
-
-	keys := tpl.RegexMatchFindKeys(obj, field, value)
-	if len(keys) == 0 {
-		return value
-	}
-	return keys[0]
+	return common.RegexMatchFindKey(obj, field, value)
 }
 
 func (tpl *Template) RegexMatchObjectByField(obj interface{}, field, value string) interface{} {
-
-	if obj == nil {
-		return nil
-	}
-	key := tpl.RegexMatchFindKey(obj, field, value)
-	if key == nil {
-		return nil
-	}
-
-	a, ok := obj.([]interface{})
-	ka, _ := key.(int)
-	if ok {
-		return a[ka]
-	}
-
-	m, ok := obj.(map[string]interface{})
-	km, _ := key.(string)
-	if ok {
-		return m[km]
-	}
-
-	return nil
+	return common.RegexMatchObjectByField(obj, field, value)
 }
 
 func (tpl *Template) Compare(v1, v2 interface{}) bool {
@@ -503,6 +447,265 @@ func (tpl *Template) FromYaml(i interface{}) (interface{}, error) {
 	return r, nil
 }
 
+func (tpl *Template) formatBytes(i interface{}) []byte {
+
+	ds, ok := i.([]byte)
+	if ok {
+		return ds
+	}
+	return []byte(fmt.Sprintf("%v", i))
+}
+
+func (tpl *Template) paramAsStrings(param interface{}) []string {
+
+	switch v := param.(type) {
+	case nil:
+		return nil
+	case []string:
+		return common.RemoveEmptyStrings(v)
+	case []interface{}:
+		r := []string{}
+		for _, item := range v {
+			r = append(r, fmt.Sprintf("%v", item))
+		}
+		return common.RemoveEmptyStrings(r)
+	default:
+		return common.RemoveEmptyStrings(strings.Split(fmt.Sprintf("%v", v), ","))
+	}
+}
+
+func (tpl *Template) csvOptionsFromParams(params map[string]interface{}) common.CsvOptions {
+
+	delimiter, _ := params["delimiter"].(string)
+	quote, _ := params["quote"].(string)
+	header, ok := params["header"].(bool)
+	if !ok {
+		header = true
+	}
+
+	mapping := make(map[string]string)
+	switch m := params["mapping"].(type) {
+	case map[string]string:
+		mapping = m
+	case map[string]interface{}:
+		for k, v := range m {
+			mapping[k] = fmt.Sprintf("%v", v)
+		}
+	}
+
+	return common.CsvOptions{
+		Delimiter: delimiter,
+		Header:    header,
+		Columns:   tpl.paramAsStrings(params["columns"]),
+		Mapping:   mapping,
+		Quote:     quote,
+	}
+}
+
+// fromCsv converts csv with header into list of objects
+func (tpl *Template) FromCsv(i interface{}) (interface{}, error) {
+	return tpl.FromCsvWith(nil, i)
+}
+
+// fromCsvWith converts csv into list of objects, params: delimiter, header, columns, mapping
+func (tpl *Template) FromCsvWith(params map[string]interface{}, i interface{}) (interface{}, error) {
+	return common.FromCsv(tpl.formatBytes(i), tpl.csvOptionsFromParams(params))
+}
+
+// toCsv converts list of objects or arrays into csv with header
+func (tpl *Template) ToCsv(i interface{}) (string, error) {
+	return tpl.ToCsvWith(nil, i)
+}
+
+// toCsvWith converts list into csv, params: delimiter, header, columns, mapping, quote
+func (tpl *Template) ToCsvWith(params map[string]interface{}, i interface{}) (string, error) {
+
+	result, err := common.ToCsv(i, tpl.csvOptionsFromParams(params))
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+func (tpl *Template) FromToml(i interface{}) (interface{}, error) {
+	return common.FromToml(tpl.formatBytes(i))
+}
+
+func (tpl *Template) ToToml(i interface{}) (string, error) {
+
+	result, err := common.ToToml(i)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(result)), nil
+}
+
+// fromXml converts xml into object, attributes are prefixed with "-", text is put into "#text"
+func (tpl *Template) FromXml(i interface{}) (interface{}, error) {
+	return common.FromXml(tpl.formatBytes(i))
+}
+
+func (tpl *Template) ToXml(i interface{}) (string, error) {
+
+	result, err := common.ToXml(i)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(result)), nil
+}
+
+func (tpl *Template) FromIni(i interface{}) (interface{}, error) {
+	return common.FromIni(tpl.formatBytes(i))
+}
+
+func (tpl *Template) ToIni(i interface{}) (string, error) {
+
+	result, err := common.ToIni(i)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(result)), nil
+}
+
+func (tpl *Template) FromHcl(i interface{}) (interface{}, error) {
+	return common.FromHcl(tpl.formatBytes(i))
+}
+
+func (tpl *Template) tableFromParams(params map[string]interface{}, i interface{}) (*common.Table, error) {
+
+	sortBy, _ := params["sort"].(string)
+	header, ok := params["header"].(bool)
+	if !ok {
+		header = true
+	}
+
+	mapping := make(map[string]string)
+	switch m := params["mapping"].(type) {
+	case map[string]string:
+		mapping = m
+	case map[string]interface{}:
+		for k, v := range m {
+			mapping[k] = fmt.Sprintf("%v", v)
+		}
+	}
+
+	options := common.TableOptions{
+		Columns:  tpl.paramAsStrings(params["columns"]),
+		Mapping:  mapping,
+		Width:    tpl.paramAsInt(params["width"], 0),
+		Sort:     sortBy,
+		NoHeader: !header,
+	}
+	return common.NewTable(tpl.formatObject(i), options)
+}
+
+// json bytes or string returned by other functions are treated as objects
+func (tpl *Template) formatObject(i interface{}) interface{} {
+
+	switch v := i.(type) {
+	case []byte:
+		return tpl.TryFromJson(v)
+	case string:
+		r := tpl.TryFromJson(v)
+		if r != nil {
+			return r
+		}
+	}
+	return i
+}
+
+// asciiTable renders list of objects as table with borders
+func (tpl *Template) AsciiTable(i interface{}) (string, error) {
+	return tpl.AsciiTableWith(nil, i)
+}
+
+// asciiTableWith renders table, params: columns, mapping, width, sort ("-field" for descending), header
+func (tpl *Template) AsciiTableWith(params map[string]interface{}, i interface{}) (string, error) {
+
+	t, err := tpl.tableFromParams(params, i)
+	if err != nil {
+		return "", err
+	}
+	return t.Ascii(), nil
+}
+
+func (tpl *Template) MarkdownTable(i interface{}) (string, error) {
+	return tpl.MarkdownTableWith(nil, i)
+}
+
+func (tpl *Template) MarkdownTableWith(params map[string]interface{}, i interface{}) (string, error) {
+
+	t, err := tpl.tableFromParams(params, i)
+	if err != nil {
+		return "", err
+	}
+	return t.Markdown(), nil
+}
+
+// slackTableBlocks renders rows as Slack Block Kit sections with fields, returns blocks json
+func (tpl *Template) SlackTableBlocks(i interface{}) (string, error) {
+	return tpl.SlackTableBlocksWith(nil, i)
+}
+
+func (tpl *Template) SlackTableBlocksWith(params map[string]interface{}, i interface{}) (string, error) {
+
+	t, err := tpl.tableFromParams(params, i)
+	if err != nil {
+		return "", err
+	}
+	return tpl.ToJson(t.SlackBlocks())
+}
+
+// telegramTable renders monospace table, params: parseMode (HTML, MarkdownV2 or Markdown) and table params
+func (tpl *Template) TelegramTable(i interface{}) (string, error) {
+	return tpl.TelegramTableWith(nil, i)
+}
+
+func (tpl *Template) TelegramTableWith(params map[string]interface{}, i interface{}) (string, error) {
+
+	t, err := tpl.tableFromParams(params, i)
+	if err != nil {
+		return "", err
+	}
+	parseMode, _ := params["parseMode"].(string)
+	return t.Telegram(parseMode), nil
+}
+
+// markdownToSlack converts markdown into Slack mrkdwn
+func (tpl *Template) MarkdownToSlack(s string) string {
+	return common.MarkdownToSlack(s)
+}
+
+// markdownToTelegram converts markdown into Telegram MarkdownV2
+func (tpl *Template) MarkdownToTelegram(s string) string {
+	return common.MarkdownToTelegram(s)
+}
+
+// markdownToTelegramHtml converts markdown into Telegram HTML
+func (tpl *Template) MarkdownToTelegramHtml(s string) string {
+	return common.MarkdownToTelegramHTML(s)
+}
+
+// markdownToJira converts markdown into Jira wiki markup
+func (tpl *Template) MarkdownToJira(s string) string {
+	return common.MarkdownToJira(s)
+}
+
+// markdownToAdf converts markdown into Atlassian Document Format json
+func (tpl *Template) MarkdownToAdf(s string) (string, error) {
+	return tpl.ToJson(common.MarkdownToADF(s))
+}
+
+// secret resolves reference like "file:/run/secrets/jira", "env:JIRA_TOKEN", "rsa:<base64>", "vault:<path>#<key>"
+func (tpl *Template) Secret(ref string) (string, error) {
+	return common.ResolveSecret(ref)
+}
+
+// markdownTo converts markdown into target: slack, telegram, telegram-html, telegram-markdown, jira, adf
+func (tpl *Template) MarkdownTo(target, s string) (string, error) {
+	return common.MarkdownConvert(s, target)
+}
+
 // split is a version of strings.Split that can be piped
 func (tpl *Template) Split(sep, s string) ([]string, error) {
 	s = strings.TrimSpace(s)
@@ -700,28 +903,16 @@ func (tpl *Template) IfElse(o interface{}, vars []interface{}) interface{} {
 }
 
 func (tpl *Template) IfIP(obj interface{}) bool {
-
-	if obj == nil {
-		return false
-	}
-
-	a := net.ParseIP(fmt.Sprintf("%v", obj))
-	return a != nil
+	return common.IsIP(obj)
 }
 
 func (tpl *Template) IfIPAndPort(obj interface{}) bool {
+	return common.IsIPAndPort(obj)
+}
 
-	if obj == nil {
-		return false
-	}
-	s := fmt.Sprintf("%v", obj)
-	arr := strings.Split(s, ":")
-	if len(arr) > 0 {
-		s = strings.TrimSpace(arr[0])
-	} else {
-		return false
-	}
-	return tpl.IfIP(s)
+// IfIPInCIDR checks that IP or IP:port belongs to one of comma separated networks
+func (tpl *Template) IfIPInCIDR(obj interface{}, cidrs string) (bool, error) {
+	return common.IsIPInCIDR(obj, cidrs)
 }
 
 func (tpl *Template) Error(format string, a ...any) error {
@@ -780,7 +971,7 @@ func (tpl *Template) URLWait(url string, timeout, retry int, size int64) []byte
 
 	client := http.Client{
 		Timeout:   time.Duration(timeout) * time.Second,
-		Transport: transport,
+		Transport: common.TracingTransportWithContext(common.CassetteTransport(transport, "template"), tpl.tracingContext),
 	}
 
 	for i := 0; i < retry; i++ {
@@ -825,11 +1016,11 @@ func (tpl *Template) GitlabPipelineVars(URL string, token string, projectID int,
 	}
 
 	pipelineOptions := vendors.GitlabPipelineOptions{
-		ProjectID: projectID,
-		Scope:     "finished",
-		OrderBy:   "updated_at",
-		Sort:      "desc",
-		Limit:     limit,
+		ProjectID:   projectID,
+		Scope:       "finished",
+		OrderBy:     "updated_at",
+		Sort:        "desc",
+		PageOptions: vendors.PageOptions{Limit: limit},
 	}
 
 	pipelineGetVariablesOptions := vendors.GitlabGetPipelineVariablesOptions{
@@ -887,7 +1078,7 @@ func (tpl *Template) TagValue(s, key string) (string, error) {
 }
 
 func (tpl *Template) DateParse(d string) (time.Time, error) {
-	t, err := dateparse.ParseAny(d)
+	t, err := common.DateParse(d)
 	if err != nil {
 		return time.Now(), err
 	}
@@ -935,46 +1126,184 @@ func (tpl *Template) Sleep(ms int) string {
 	return ""
 }
 
-func (tpl *Template) UUID() string {
-
-	uuid := uuid.New()
-	return uuid.String()
-}
+func (tpl *Template) paramAsDuration(param interface{}, def time.Duration) time.Duration {
 
-func (tpl *Template) StringList(items ...string) []string {
-	return items
+	switch v := param.(type) {
+	case nil:
+		return def
+	case time.Duration:
+		return v
+	case int:
+		return time.Duration(v) * time.Second
+	case int64:
+		return time.Duration(v) * time.Second
+	case float64:
+		return time.Duration(v * float64(time.Second))
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return time.Duration(f * float64(time.Second))
+		}
+	}
+	return def
 }
 
-func (tpl *Template) AppendString(arr []string, items ...string) []string {
-	return append(arr, items...)
-}
+func (tpl *Template) paramAsFloat(param interface{}, def float64) float64 {
 
-func (tpl *Template) IntList(items ...int64) []int64 {
-	return items
+	switch v := param.(type) {
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
 }
 
-func (tpl *Template) FloatList(items ...float64) []float64 {
-	return items
-}
+func (tpl *Template) retryOptionsFromParams(params map[string]interface{}) common.RetryOptions {
 
-// url, contentType, authorization string, timeout int
-func (tpl *Template) HttpGetHeader(params map[string]interface{}) ([]byte, error) {
-	if len(params) == 0 {
-		return nil, fmt.Errorf("HttpGetHeader err => %s", "no params allowed")
+	var codes []int
+	for _, c := range tpl.paramAsStrings(params["status"]) {
+		if code, err := strconv.Atoi(strings.TrimSpace(c)); err == nil {
+			codes = append(codes, code)
+		}
 	}
 
-	url, ok := params["url"].(string)
-	if !ok || url == "" {
-		return nil, fmt.Errorf("HttpGetHeader err => %s", "invalid or missing URL")
+	var errs []string
+	switch v := params["on"].(type) {
+	case string:
+		if !utils.IsEmpty(v) {
+			errs = []string{v}
+		}
+	default:
+		errs = tpl.paramAsStrings(v)
 	}
-	timeout, ok := params["timeout"].(int)
-	if !ok || timeout <= 0 {
-		timeout = 5
+
+	return common.RetryOptions{
+		Attempts:    tpl.paramAsInt(params["attempts"], common.RetryDefaultAttempts),
+		Delay:       tpl.paramAsDuration(params["delay"], common.RetryDefaultDelay),
+		MaxDelay:    tpl.paramAsDuration(params["maxDelay"], common.RetryDefaultMaxDelay),
+		Factor:      tpl.paramAsFloat(params["factor"], common.RetryDefaultFactor),
+		Jitter:      tpl.paramAsFloat(params["jitter"], 0),
+		Errors:      errs,
+		StatusCodes: codes,
 	}
+}
 
-	insecure, _ := params["insecure"].(bool)
+func (tpl *Template) retryState(rt []interface{}, err error) common.RetryState {
 
-	clientCrt, _ := params["clientCrt"].(string)
+	state := common.RetryState{Err: err}
+	for _, v := range rt {
+
+		var r *HTTPResult
+		switch vt := v.(type) {
+		case HTTPResult:
+			r = &vt
+		case *HTTPResult:
+			r = vt
+		}
+		if r == nil {
+			continue
+		}
+
+		state.StatusCode = r.StatusCode
+		if state.Err == nil && !utils.IsEmpty(r.Error) {
+			state.Err = errors.New(r.Error)
+		}
+		if d, ok := common.ParseRetryAfter(r.Header.Get(common.RetryAfterHeader)); ok {
+			state.RetryAfter = d
+		}
+	}
+	return state
+}
+
+// retry invokes template function by name with args according to policy params:
+// attempts, delay, maxDelay, factor, jitter, on (error regex or list), status (list of codes)
+func (tpl *Template) Retry(params map[string]interface{}, name string, args ...interface{}) (interface{}, error) {
+
+	fn, ok := tpl.funcs[name]
+	if !ok {
+		return nil, fmt.Errorf("Retry err => function %s not found", name)
+	}
+
+	var rt []interface{}
+	var rerr error
+
+	// context of request or tool call stops waiting for next attempt
+	err := common.RetryContext(tpl.tracingContext(), tpl.retryOptionsFromParams(params), func(attempt int) common.RetryState {
+
+		rt, rerr = common.InvokeFunc(fn, name, args...)
+		state := tpl.retryState(rt, rerr)
+		if state.Err != nil {
+			tpl.logger.Debug("Retry %s attempt %d failed: %v", name, attempt+1, state.Err)
+		}
+		return state
+	})
+
+	// canceled or timed out context ends retry with its error
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		rerr = err
+	}
+
+	// functions returning HTTPResult keep error inside result, so final failure is reported here
+	if rerr == nil && err != nil {
+		tpl.logger.Warn("Retry %s: %v", name, err)
+	}
+
+	switch len(rt) {
+	case 0:
+		return nil, rerr
+	case 1:
+		return rt[0], rerr
+	}
+	return rt, rerr
+}
+
+func (tpl *Template) UUID() string {
+
+	uuid := uuid.New()
+	return uuid.String()
+}
+
+func (tpl *Template) StringList(items ...string) []string {
+	return items
+}
+
+func (tpl *Template) AppendString(arr []string, items ...string) []string {
+	return append(arr, items...)
+}
+
+func (tpl *Template) IntList(items ...int64) []int64 {
+	return items
+}
+
+func (tpl *Template) FloatList(items ...float64) []float64 {
+	return items
+}
+
+// url, contentType, authorization string, timeout int
+func (tpl *Template) HttpGetHeader(params map[string]interface{}) ([]byte, error) {
+	if len(params) == 0 {
+		return nil, fmt.Errorf("HttpGetHeader err => %s", "no params allowed")
+	}
+
+	url, ok := params["url"].(string)
+	if !ok || url == "" {
+		return nil, fmt.Errorf("HttpGetHeader err => %s", "invalid or missing URL")
+	}
+	timeout, ok := params["timeout"].(int)
+	if !ok || timeout <= 0 {
+		timeout = 5
+	}
+
+	insecure, _ := params["insecure"].(bool)
+
+	clientCrt, _ := params["clientCrt"].(string)
 	clientKey, _ := params["clientKey"].(string)
 
 	var certs []tls.Certificate
@@ -1005,7 +1334,7 @@ func (tpl *Template) HttpGetHeader(params map[string]interface{}) ([]byte, error
 
 	client := http.Client{
 		Timeout:   time.Duration(timeout) * time.Second,
-		Transport: transport,
+		Transport: common.TracingTransportWithContext(common.CassetteTransport(transport, "template"), tpl.tracingContext),
 	}
 
 	// Call the GetHeaders function
@@ -1083,7 +1412,7 @@ func (tpl *Template) HttpGetSilent(params map[string]interface{}) ([]byte, error
 
 	client := http.Client{
 		Timeout:   time.Duration(timeout) * time.Second,
-		Transport: transport,
+		Transport: common.TracingTransportWithContext(common.CassetteTransport(transport, "template"), tpl.tracingContext),
 	}
 
 	body, code, err := utils.HttpRequestRawWithHeadersOutCodeSilent(&client, "GET", url, headers, nil)
@@ -1157,9 +1486,10 @@ func (tpl *Template) HttpGetExt(params map[string]interface{}) HTTPResult {
 		},
 	}
 
+	resultTransport := &httpResultTransport{transport: common.CassetteTransport(transport, "template")}
 	client := http.Client{
 		Timeout:   time.Duration(timeout) * time.Second,
-		Transport: transport,
+		Transport: common.TracingTransportWithContext(resultTransport, tpl.tracingContext),
 	}
 
 	start := time.Now()
@@ -1172,12 +1502,15 @@ func (tpl *Template) HttpGetExt(params map[string]interface{}) HTTPResult {
 
 	// Call the HttpGetRaw4 function
 	body, err := utils.HttpGetRaw(&client, url, contentType, authorization)
+	result.Header = resultTransport.header
 	if err != nil {
 		result.Error = fmt.Errorf("HTTP request failed: %w", err).Error()
 		result.StatusCode = ErrorCodeHTTP
 
 		// Try to get status code from error if possible
-		if respErr, ok := err.(interface{ StatusCode() int }); ok {
+		if resultTransport.statusCode > 0 {
+			result.StatusCode = resultTransport.statusCode
+		} else if respErr, ok := err.(interface{ StatusCode() int }); ok {
 			result.StatusCode = respErr.StatusCode()
 		} else {
 			e := err.Error()
@@ -1291,9 +1624,10 @@ func (tpl *Template) HttpPostExt(params map[string]interface{}) HTTPResult {
 		},
 	}
 
+	resultTransport := &httpResultTransport{transport: common.CassetteTransport(transport, "template")}
 	client := http.Client{
 		Timeout:   time.Duration(timeout) * time.Second,
-		Transport: transport,
+		Transport: common.TracingTransportWithContext(resultTransport, tpl.tracingContext),
 	}
 
 	start := time.Now()
@@ -1305,10 +1639,13 @@ func (tpl *Template) HttpPostExt(params map[string]interface{}) HTTPResult {
 	}()
 
 	respBody, err := utils.HttpPostRaw(&client, url, contentType, authorization, body)
+	result.Header = resultTransport.header
 	if err != nil {
 		result.Error = fmt.Errorf("HTTP request failed: %w", err).Error()
 		result.StatusCode = ErrorCodeHTTP
-		if respErr, ok := err.(interface{ StatusCode() int }); ok {
+		if resultTransport.statusCode > 0 {
+			result.StatusCode = resultTransport.statusCode
+		} else if respErr, ok := err.(interface{ StatusCode() int }); ok {
 			result.StatusCode = respErr.StatusCode()
 		} else {
 			e := err.Error()
@@ -1392,7 +1729,7 @@ func (tpl *Template) HttpPut(params map[string]interface{}) ([]byte, error) {
 
 	client := http.Client{
 		Timeout:   time.Duration(timeout) * time.Second,
-		Transport: transport,
+		Transport: common.TracingTransportWithContext(common.CassetteTransport(transport, "template"), tpl.tracingContext),
 	}
 	return utils.HttpPutRaw(&client, u, contentType, authorization, body)
 }
@@ -1455,7 +1792,7 @@ func (tpl *Template) HttpPatch(params map[string]interface{}) ([]byte, error) {
 
 	client := http.Client{
 		Timeout:   time.Duration(timeout) * time.Second,
-		Transport: transport,
+		Transport: common.TracingTransportWithContext(common.CassetteTransport(transport, "template"), tpl.tracingContext),
 	}
 	return utils.HttpPatchRaw(&client, u, contentType, authorization, body)
 }
@@ -2117,7 +2454,12 @@ func (tpl *Template) AWSS3ListObjects(params map[string]interface{}) ([]byte, er
 	if err != nil {
 		return nil, fmt.Errorf("AWSS3ListObjects err => %w", err)
 	}
-	return s3.ListObjects(region, bucket, prefix)
+	// objects are complete unless limit is set
+	paging := tpl.pageOptionsFromParams(params, 0)
+	if paging.Limit <= 0 {
+		paging.All = true
+	}
+	return s3.ListObjectsPagesContext(tpl.tracingContext(), region, bucket, prefix, paging)
 }
 
 func (tpl *Template) AWSS3GetObject(params map[string]interface{}) ([]byte, error) {
@@ -2340,175 +2682,940 @@ func (tpl *Template) GrafanaCopyDashboard(params map[string]interface{}) ([]byte
 	from, _ := params["from"].(string)
 	to, _ := params["to"].(string)
 
-	clonedUID, _ := params["cloneduid"].(string)
+	clonedUID, _ := params["cloneduid"].(string)
+
+	grafanaOptions := vendors.GrafanaOptions{
+		URL:      url,
+		Timeout:  timeout,
+		Insecure: insecure,
+		APIKey:   token,
+		OrgID:    orgID,
+	}
+
+	grafanaCopyDashboardOptions := vendors.GrafanaDashboardOptions{
+		Title:     title,
+		UID:       dUID,
+		Slug:      dSlug,
+		Timezone:  dTimeZone,
+		FolderUID: fUID,
+		Tags:      tags,
+		From:      from,
+		To:        to,
+		Cloned: vendors.GrafanaClonedDashboardOptions{
+			UID: clonedUID,
+		},
+	}
+
+	grafana := vendors.NewGrafana(grafanaOptions)
+
+	response, err := grafana.CopyDashboard(grafanaCopyDashboardOptions)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (tpl *Template) PagerDutyCreateIncident(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("PagerDutyEscalate err => %s", "no params passed")
+	}
+
+	url, _ := params["url"].(string)
+	timeout, _ := params["timeout"].(int)
+	if timeout == 0 {
+		timeout = 10
+	}
+	insecure, _ := params["insecure"].(bool)
+	token, _ := params["token"].(string)
+
+	pagerDutyOptions := vendors.PagerDutyOptions{
+		URL:      url,
+		Timeout:  timeout,
+		Insecure: insecure,
+		Token:    token,
+	}
+
+	pagerDuty := vendors.NewPagerDuty(pagerDutyOptions, tpl.logger)
+
+	title, _ := params["title"].(string)
+	body, _ := params["body"].(string)
+	urgency, _ := params["urgency"].(string)
+	serviceID, _ := params["serviceID"].(string)
+	priorityID, _ := params["priorityID"].(string)
+	incidentType, _ := params["incidentType"].(string)
+
+	incidentOptions := vendors.PagerDutyIncidentOptions{
+		Title:        title,
+		Body:         body,
+		Urgency:      urgency,
+		ServiceID:    serviceID,
+		PriorityID:   priorityID,
+		IncidentType: incidentType,
+	}
+
+	from, _ := params["from"].(string)
+
+	createOptions := vendors.PagerDutyCreateIncidentOptions{
+		From: from,
+	}
+
+	return pagerDuty.CreateIncident(incidentOptions, createOptions)
+}
+
+func (tpl *Template) PagerDutySendNoteToIncident(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("PagerDutyEscalate err => %s", "no params passed")
+	}
+
+	url, _ := params["url"].(string)
+	timeout, _ := params["timeout"].(int)
+	if timeout == 0 {
+		timeout = 10
+	}
+	insecure, _ := params["insecure"].(bool)
+	token, _ := params["token"].(string)
+
+	pagerDutyOptions := vendors.PagerDutyOptions{
+		URL:      url,
+		Timeout:  timeout,
+		Insecure: insecure,
+		Token:    token,
+	}
+
+	pagerDuty := vendors.NewPagerDuty(pagerDutyOptions, tpl.logger)
+
+	incidentId, _ := params["incidentid"].(string)
+	noteContent, _ := params["notecontent"].(string)
+
+	noteOptions := vendors.PagerDutyIncidentNoteOptions{
+		IncidentID:  incidentId,
+		NoteContent: noteContent,
+	}
+
+	from, _ := params["from"].(string)
+
+	createOptions := vendors.PagerDutyCreateIncidentOptions{
+		From: from,
+	}
+
+	return pagerDuty.CreateIncidentNote(noteOptions, createOptions)
+}
+
+func (tpl *Template) PrometheusGet(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("PrometheusGet err => %s", "no params allowed")
+	}
+
+	url, _ := params["url"].(string)
+	timeout, _ := params["timeout"].(int)
+	if timeout == 0 {
+		timeout = 5
+	}
+
+	insecure, _ := params["insecure"].(bool)
+	user, _ := params["user"].(string)
+	password, _ := params["password"].(string)
+
+	query, _ := params["query"].(string)
+	if utils.IsEmpty(query) {
+		return nil, fmt.Errorf("PrometheusGet err => %s", "query is empty")
+	}
+
+	from, _ := params["from"].(string)
+	to, _ := params["to"].(string)
+	step, _ := params["step"].(string)
+	prms, _ := params["params"].(string)
+
+	noerror, _ := params["noerror"].(bool)
+
+	prometheusOptions := vendors.PrometheusOptions{
+		URL:      url,
+		User:     user,
+		Password: password,
+		Timeout:  timeout,
+		Insecure: insecure,
+		Query:    query,
+		From:     from,
+		To:       to,
+		Step:     step,
+		Params:   prms,
+	}
+
+	prometheus := vendors.NewPrometheus(prometheusOptions)
+
+	d, err := prometheus.Get()
+	if noerror {
+		err = nil
+	}
+	return d, err
+}
+
+func (tpl *Template) slackOptionsFromParams(params map[string]interface{}) vendors.SlackOptions {
+
+	url, _ := params["url"].(string)
+	token, _ := params["token"].(string)
+	insecure, _ := params["insecure"].(bool)
+	return vendors.SlackOptions{
+		URL:      url,
+		Token:    token,
+		Timeout:  tpl.paramAsInt(params["timeout"], 10),
+		Insecure: insecure,
+	}
+}
+
+func (tpl *Template) SlackSendMessage(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackSendMessage err => no params provided")
+	}
+
+	channel, _ := params["channel"].(string)
+	thread, _ := params["thread"].(string)
+	title, _ := params["title"].(string)
+	text, _ := params["text"].(string)
+	attachments, _ := params["attachments"].(string)
+	blocks, _ := params["blocks"].(string)
+	format, _ := params["format"].(string)
+
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomSendMessage(options, vendors.SlackMessageOptions{
+		Channel:     channel,
+		Thread:      thread,
+		Title:       title,
+		Text:        text,
+		Attachments: attachments,
+		Blocks:      blocks,
+		Format:      format,
+	})
+}
+
+func (tpl *Template) SlackSendFile(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackSendFile err => no params provided")
+	}
+
+	channel, _ := params["channel"].(string)
+	thread, _ := params["thread"].(string)
+	title, _ := params["title"].(string)
+	text, _ := params["text"].(string)
+	name, _ := params["name"].(string)
+	content, _ := params["content"].(string)
+	fileType, _ := params["type"].(string)
+	if utils.IsEmpty(fileType) {
+		fileType = "auto"
+	}
+
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomSendFile(options, vendors.SlackFileOptions{
+		Channel: channel,
+		Thread:  thread,
+		Title:   title,
+		Text:    text,
+		Name:    name,
+		Content: content,
+		Type:    fileType,
+	})
+}
+
+func (tpl *Template) SlackAddReaction(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackAddReaction err => no params provided")
+	}
+
+	channel, _ := params["channel"].(string)
+	thread, _ := params["thread"].(string)
+	name, _ := params["name"].(string)
+
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomAddReaction(options, vendors.SlackReactionOptions{
+		Channel: channel,
+		Thread:  thread,
+		Name:    name,
+	})
+}
+
+func (tpl *Template) SlackGetUser(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackGetUser err => no params provided")
+	}
+
+	email, _ := params["email"].(string)
+	if utils.IsEmpty(email) {
+		return nil, fmt.Errorf("SlackGetUser err => email is empty")
+	}
+
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomGetUser(options, vendors.SlackUserEmail{
+		Email: email,
+	})
+}
+
+func (tpl *Template) SlackUpdateUsergroup(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackUpdateUsergroup err => no params provided")
+	}
+
+	usergroup, _ := params["usergroup"].(string)
+	if utils.IsEmpty(usergroup) {
+		return nil, fmt.Errorf("SlackUpdateUsergroup err => usergroup is empty")
+	}
+
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomUpdateUsergroup(options, vendors.SlackUsergroupUsers{
+		Usergroup: usergroup,
+		Users:     tpl.paramAsStrings(params["users"]),
+	})
+}
+
+func (tpl *Template) SlackGetConversationHistory(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackGetConversationHistory err => no params provided")
+	}
+
+	channel, _ := params["channel"].(string)
+	cursor, _ := params["cursor"].(string)
+	inclusive, _ := params["inclusive"].(bool)
+	latest, _ := params["latest"].(string)
+	oldest, _ := params["oldest"].(string)
+	includeAllMetadata, _ := params["includeAllMetadata"].(bool)
+
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomGetConversationHistory(options, vendors.GetConversationHistoryParameters{
+		ChannelID:          channel,
+		Cursor:             cursor,
+		Inclusive:          inclusive,
+		Latest:             latest,
+		Oldest:             oldest,
+		IncludeAllMetadata: includeAllMetadata,
+		PageOptions:        tpl.pageOptionsFromParams(params, 0),
+	})
+}
+
+// slackMessageRefFromParams returns channel and ts of params, "message" is response of previous slack function
+func (tpl *Template) slackMessageRefFromParams(params map[string]interface{}) (vendors.SlackMessageRef, string) {
+
+	var ref vendors.SlackMessageRef
+	var scheduled string
+
+	var b []byte
+	switch m := params["message"].(type) {
+	case string:
+		b = []byte(m)
+	case []byte:
+		b = m
+	case map[string]interface{}:
+		b, _ = json.Marshal(m)
+	}
+	if len(b) > 0 {
+		ref, _ = vendors.SlackMessageRefFrom(b)
+		var r struct {
+			ID string `json:"scheduled_message_id"`
+		}
+		if json.Unmarshal(b, &r) == nil {
+			scheduled = r.ID
+		}
+	}
+
+	if channel, _ := params["channel"].(string); !utils.IsEmpty(channel) {
+		ref.Channel = channel
+	}
+	if ts, _ := params["ts"].(string); !utils.IsEmpty(ts) {
+		ref.TS = ts
+	}
+	if id, _ := params["scheduledMessageID"].(string); !utils.IsEmpty(id) {
+		scheduled = id
+	}
+	return ref, scheduled
+}
+
+func (tpl *Template) slackMessageOptionsFromParams(params map[string]interface{}) vendors.SlackMessageOptions {
+
+	ref, _ := tpl.slackMessageRefFromParams(params)
+	thread, _ := params["thread"].(string)
+	if utils.IsEmpty(thread) {
+		thread = ref.TS
+	}
+	text, _ := params["text"].(string)
+	attachments, _ := params["attachments"].(string)
+	blocks, _ := params["blocks"].(string)
+	format, _ := params["format"].(string)
+
+	return vendors.SlackMessageOptions{
+		Channel:     ref.Channel,
+		Thread:      thread,
+		Text:        text,
+		Attachments: attachments,
+		Blocks:      blocks,
+		Format:      format,
+	}
+}
+
+func (tpl *Template) SlackUpdateMessage(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackUpdateMessage err => no params provided")
+	}
+
+	ref, _ := tpl.slackMessageRefFromParams(params)
+	text, _ := params["text"].(string)
+	attachments, _ := params["attachments"].(string)
+	blocks, _ := params["blocks"].(string)
+	format, _ := params["format"].(string)
+
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomUpdateMessage(options, vendors.SlackUpdateMessageOptions{
+		SlackMessageRef: ref,
+		Text:            text,
+		Attachments:     attachments,
+		Blocks:          blocks,
+		Format:          format,
+	})
+}
+
+func (tpl *Template) SlackDeleteMessage(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackDeleteMessage err => no params provided")
+	}
+
+	ref, _ := tpl.slackMessageRefFromParams(params)
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomDeleteMessage(options, ref)
+}
+
+func (tpl *Template) SlackGetPermalink(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackGetPermalink err => no params provided")
+	}
+
+	ref, _ := tpl.slackMessageRefFromParams(params)
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomGetPermalink(options, ref)
+}
+
+func (tpl *Template) SlackScheduleMessage(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackScheduleMessage err => no params provided")
+	}
+
+	postAt := fmt.Sprintf("%v", params["postAt"])
+	if params["postAt"] == nil {
+		postAt = ""
+	}
+
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomScheduleMessage(options, vendors.SlackScheduleMessageOptions{
+		SlackMessageOptions: tpl.slackMessageOptionsFromParams(params),
+		PostAt:              postAt,
+	})
+}
+
+func (tpl *Template) SlackDeleteScheduledMessage(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackDeleteScheduledMessage err => no params provided")
+	}
+
+	ref, id := tpl.slackMessageRefFromParams(params)
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomDeleteScheduledMessage(options, vendors.SlackScheduledMessageOptions{
+		Channel:            ref.Channel,
+		ScheduledMessageID: id,
+	})
+}
+
+func (tpl *Template) SlackSendEphemeral(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("SlackSendEphemeral err => no params provided")
+	}
+
+	user, _ := params["user"].(string)
+	if utils.IsEmpty(user) {
+		return nil, fmt.Errorf("SlackSendEphemeral err => user is empty")
+	}
+
+	options := tpl.slackOptionsFromParams(params)
+	slack := vendors.NewSlack(options)
+
+	return slack.CustomSendEphemeral(options, vendors.SlackEphemeralOptions{
+		SlackMessageOptions: tpl.slackMessageOptionsFromParams(params),
+		User:                user,
+	})
+}
+
+func (tpl *Template) telegramOptionsFromParams(params map[string]interface{}) vendors.TelegramOptions {
+
+	token, _ := params["token"].(string)
+	chatID := ""
+	if !utils.IsEmpty(params["chatID"]) {
+		chatID = fmt.Sprintf("%v", params["chatID"])
+	}
+	insecure, _ := params["insecure"].(bool)
+	parseMode, _ := params["parseMode"].(string)
+	disableNotification, _ := params["disableNotification"].(bool)
+	disableWebPagePreview, ok := params["disableWebPagePreview"].(bool)
+	if !ok {
+		disableWebPagePreview = true
+	}
+	return vendors.TelegramOptions{
+		IDToken:               token,
+		ChatID:                chatID,
+		Timeout:               tpl.paramAsInt(params["timeout"], 10),
+		Insecure:              insecure,
+		ParseMode:             parseMode,
+		DisableNotification:   disableNotification,
+		DisableWebPagePreview: disableWebPagePreview,
+	}
+}
+
+func (tpl *Template) TelegramSendMessage(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("TelegramSendMessage err => no params provided")
+	}
+
+	text, _ := params["text"].(string)
+	format, _ := params["format"].(string)
+
+	options := tpl.telegramOptionsFromParams(params)
+	telegram := vendors.NewTelegram(options)
+
+	return telegram.CustomSendMessage(options, vendors.TelegramMessageOptions{
+		Text:   text,
+		Format: format,
+	})
+}
+
+func (tpl *Template) TelegramSendPhoto(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("TelegramSendPhoto err => no params provided")
+	}
+
+	caption, _ := params["caption"].(string)
+	name, _ := params["name"].(string)
+	content, _ := params["content"].(string)
+
+	options := tpl.telegramOptionsFromParams(params)
+	telegram := vendors.NewTelegram(options)
+
+	return telegram.CustomSendPhoto(options, vendors.TelegramPhotoOptions{
+		Caption: caption,
+		Name:    name,
+		Content: content,
+	})
+}
+
+func (tpl *Template) TelegramSendDocument(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("TelegramSendDocument err => no params provided")
+	}
+
+	caption, _ := params["caption"].(string)
+	name, _ := params["name"].(string)
+	content, _ := params["content"].(string)
+
+	options := tpl.telegramOptionsFromParams(params)
+	telegram := vendors.NewTelegram(options)
+
+	return telegram.CustomSendDocument(options, vendors.TelegramDocumentOptions{
+		Caption: caption,
+		Name:    name,
+		Content: content,
+	})
+}
+
+func (tpl *Template) ZabbixGetHosts(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("ZabbixGetHosts err => no params provided")
+	}
+
+	url, _ := params["url"].(string)
+	insecure, _ := params["insecure"].(bool)
+	user, _ := params["user"].(string)
+	password, _ := params["password"].(string)
+	auth, _ := params["auth"].(string)
 
-	grafanaOptions := vendors.GrafanaOptions{
+	options := vendors.ZabbixOptions{
 		URL:      url,
-		Timeout:  timeout,
+		Timeout:  tpl.paramAsInt(params["timeout"], 10),
 		Insecure: insecure,
-		APIKey:   token,
-		OrgID:    orgID,
+		User:     user,
+		Password: password,
+		Auth:     auth,
 	}
+	zabbix := vendors.NewZabbix(options)
 
-	grafanaCopyDashboardOptions := vendors.GrafanaDashboardOptions{
-		Title:     title,
-		UID:       dUID,
-		Slug:      dSlug,
-		Timezone:  dTimeZone,
-		FolderUID: fUID,
-		Tags:      tags,
-		From:      from,
-		To:        to,
-		Cloned: vendors.GrafanaClonedDashboardOptions{
-			UID: clonedUID,
-		},
+	return zabbix.CustomGetHosts(options, vendors.ZabbixHostOptions{
+		Fields:     tpl.paramAsStrings(params["fields"]),
+		Inventory:  tpl.paramAsStrings(params["inventory"]),
+		Interfaces: tpl.paramAsStrings(params["interfaces"]),
+	})
+}
+
+func (tpl *Template) NetboxGetDevices(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("NetboxGetDevices err => no params provided")
 	}
 
-	grafana := vendors.NewGrafana(grafanaOptions)
+	url, _ := params["url"].(string)
+	insecure, _ := params["insecure"].(bool)
+	token, _ := params["token"].(string)
+	brief, _ := params["brief"].(bool)
+	deviceID := ""
+	if !utils.IsEmpty(params["deviceID"]) {
+		deviceID = fmt.Sprintf("%v", params["deviceID"])
+	}
+
+	filter := make(map[string]string)
+	switch f := params["filter"].(type) {
+	case map[string]string:
+		filter = f
+	case map[string]interface{}:
+		for k, v := range f {
+			filter[k] = fmt.Sprintf("%v", v)
+		}
+	case string:
+		filter = utils.MapGetKeyValues(f)
+	}
 
-	response, err := grafana.CopyDashboard(grafanaCopyDashboardOptions)
-	if err != nil {
-		return nil, err
+	options := vendors.NetboxOptions{
+		URL:      url,
+		Timeout:  tpl.paramAsInt(params["timeout"], 10),
+		Insecure: insecure,
+		Token:    token,
+		Limit:    strconv.Itoa(tpl.paramAsInt(params["limit"], 50)),
+		Brief:    brief,
+		Filter:   filter,
 	}
-	return response, nil
+	netbox := vendors.NewNetbox(options)
+
+	return netbox.CustomGetDevices(options, vendors.NetboxDeviceOptions{
+		DeviceID: deviceID,
+	})
 }
 
-func (tpl *Template) PagerDutyCreateIncident(params map[string]interface{}) ([]byte, error) {
+func (tpl *Template) ObserviumGetDevices(params map[string]interface{}) ([]byte, error) {
 
 	if len(params) == 0 {
-		return nil, fmt.Errorf("PagerDutyEscalate err => %s", "no params passed")
+		return nil, fmt.Errorf("ObserviumGetDevices err => no params provided")
 	}
 
 	url, _ := params["url"].(string)
-	timeout, _ := params["timeout"].(int)
-	if timeout == 0 {
-		timeout = 10
-	}
 	insecure, _ := params["insecure"].(bool)
+	user, _ := params["user"].(string)
+	password, _ := params["password"].(string)
 	token, _ := params["token"].(string)
 
-	pagerDutyOptions := vendors.PagerDutyOptions{
+	options := vendors.ObserviumOptions{
 		URL:      url,
-		Timeout:  timeout,
+		Timeout:  tpl.paramAsInt(params["timeout"], 10),
 		Insecure: insecure,
+		User:     user,
+		Password: password,
 		Token:    token,
 	}
+	observium := vendors.NewObservium(options)
 
-	pagerDuty := vendors.NewPagerDuty(pagerDutyOptions, tpl.logger)
+	return observium.CustomGetDevices(options)
+}
 
-	title, _ := params["title"].(string)
-	body, _ := params["body"].(string)
-	urgency, _ := params["urgency"].(string)
-	serviceID, _ := params["serviceID"].(string)
-	priorityID, _ := params["priorityID"].(string)
-	incidentType, _ := params["incidentType"].(string)
+func (tpl *Template) GraylogGetLogs(params map[string]interface{}) ([]byte, error) {
 
-	incidentOptions := vendors.PagerDutyIncidentOptions{
-		Title:        title,
-		Body:         body,
-		Urgency:      urgency,
-		ServiceID:    serviceID,
-		PriorityID:   priorityID,
-		IncidentType: incidentType,
+	if len(params) == 0 {
+		return nil, fmt.Errorf("GraylogGetLogs err => no params provided")
 	}
 
+	url, _ := params["url"].(string)
+	insecure, _ := params["insecure"].(bool)
+	user, _ := params["user"].(string)
+	password, _ := params["password"].(string)
+	streams, _ := params["streams"].(string)
+	query, _ := params["query"].(string)
+	rangeType, _ := params["rangeType"].(string)
+	if utils.IsEmpty(rangeType) {
+		rangeType = "relative"
+	}
+	sortBy, _ := params["sort"].(string)
 	from, _ := params["from"].(string)
+	to, _ := params["to"].(string)
+	ranges := ""
+	if !utils.IsEmpty(params["range"]) {
+		ranges = fmt.Sprintf("%v", params["range"])
+	}
+
+	graylog := vendors.NewGraylog(vendors.GraylogOptions{
+		URL:       url,
+		Timeout:   tpl.paramAsInt(params["timeout"], 10),
+		Insecure:  insecure,
+		User:      user,
+		Password:  password,
+		Streams:   streams,
+		Query:     query,
+		RangeType: rangeType,
+		Sort:      sortBy,
+		Limit:     tpl.paramAsInt(params["limit"], 100),
+		From:      from,
+		To:        to,
+		Range:     ranges,
+	})
 
-	createOptions := vendors.PagerDutyCreateIncidentOptions{
-		From: from,
+	return graylog.GetLogs()
+}
+
+func (tpl *Template) teleportOptionsFromParams(params map[string]interface{}) vendors.TeleportOptions {
+
+	address, _ := params["address"].(string)
+	identity, _ := params["identity"].(string)
+	insecure, _ := params["insecure"].(bool)
+	return vendors.TeleportOptions{
+		Address:  address,
+		Identity: identity,
+		Timeout:  tpl.paramAsInt(params["timeout"], 10),
+		Insecure: insecure,
 	}
+}
 
-	return pagerDuty.CreateIncident(incidentOptions, createOptions)
+func (tpl *Template) TeleportPing(params map[string]interface{}) ([]byte, error) {
+
+	options := tpl.teleportOptionsFromParams(params)
+	teleport := vendors.NewTeleport(options, tpl.logger)
+
+	return teleport.CustomPing(options)
 }
 
-func (tpl *Template) PagerDutySendNoteToIncident(params map[string]interface{}) ([]byte, error) {
+func (tpl *Template) TeleportResourceList(params map[string]interface{}) ([]byte, error) {
 
-	if len(params) == 0 {
-		return nil, fmt.Errorf("PagerDutyEscalate err => %s", "no params passed")
+	kind, _ := params["kind"].(string)
+	if utils.IsEmpty(kind) {
+		kind = vendors.TeleportResourceKubernetes
 	}
 
-	url, _ := params["url"].(string)
-	timeout, _ := params["timeout"].(int)
-	if timeout == 0 {
-		timeout = 10
+	options := tpl.teleportOptionsFromParams(params)
+	teleport := vendors.NewTeleport(options, tpl.logger)
+
+	return teleport.CustomResourceList(options, vendors.TeleportResourceListOptions{
+		TeleportResourceOptions: vendors.TeleportResourceOptions{
+			Kind: kind,
+		},
+	})
+}
+
+func (tpl *Template) VirusTotalDomainReport(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("VirusTotalDomainReport err => no params provided")
 	}
+
+	apiKey, _ := params["apiKey"].(string)
 	insecure, _ := params["insecure"].(bool)
-	token, _ := params["token"].(string)
+	domain, _ := params["domain"].(string)
+	if utils.IsEmpty(domain) {
+		return nil, fmt.Errorf("VirusTotalDomainReport err => domain is empty")
+	}
 
-	pagerDutyOptions := vendors.PagerDutyOptions{
-		URL:      url,
-		Timeout:  timeout,
+	options := vendors.VirusTotalOptions{
+		APIKey:   apiKey,
+		Timeout:  tpl.paramAsInt(params["timeout"], 10),
 		Insecure: insecure,
-		Token:    token,
 	}
+	virusTotal := vendors.NewVirusTotal(options, tpl.logger)
 
-	pagerDuty := vendors.NewPagerDuty(pagerDutyOptions, tpl.logger)
+	return virusTotal.CustomDomainReport(options, vendors.VirusTotalDomainReportOptions{
+		Domain: domain,
+	})
+}
 
-	incidentId, _ := params["incidentid"].(string)
-	noteContent, _ := params["notecontent"].(string)
+func (tpl *Template) site24x7OptionsFromParams(params map[string]interface{}) vendors.Site24x7Options {
 
-	noteOptions := vendors.PagerDutyIncidentNoteOptions{
-		IncidentID:  incidentId,
-		NoteContent: noteContent,
+	clientID, _ := params["clientID"].(string)
+	clientSecret, _ := params["clientSecret"].(string)
+	refreshToken, _ := params["refreshToken"].(string)
+	accessToken, _ := params["accessToken"].(string)
+	insecure, _ := params["insecure"].(bool)
+	return vendors.Site24x7Options{
+		Timeout:      tpl.paramAsInt(params["timeout"], 10),
+		Insecure:     insecure,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RefreshToken: refreshToken,
+		AccessToken:  accessToken,
 	}
+}
 
-	from, _ := params["from"].(string)
+func (tpl *Template) Site24x7RetrieveMonitorByName(params map[string]interface{}) ([]byte, error) {
 
-	createOptions := vendors.PagerDutyCreateIncidentOptions{
-		From: from,
+	name, _ := params["name"].(string)
+	if utils.IsEmpty(name) {
+		return nil, fmt.Errorf("Site24x7RetrieveMonitorByName err => name is empty")
 	}
 
-	return pagerDuty.CreateIncidentNote(noteOptions, createOptions)
+	options := tpl.site24x7OptionsFromParams(params)
+	site24x7 := vendors.NewSite24x7(options, tpl.logger)
+
+	return site24x7.CustomRetrieveMonitorByName(options, name)
 }
 
-func (tpl *Template) PrometheusGet(params map[string]interface{}) ([]byte, error) {
+func (tpl *Template) Site24x7PollMonitor(params map[string]interface{}) ([]byte, error) {
 
-	if len(params) == 0 {
-		return nil, fmt.Errorf("PrometheusGet err => %s", "no params allowed")
+	id, _ := params["id"].(string)
+	if utils.IsEmpty(id) {
+		return nil, fmt.Errorf("Site24x7PollMonitor err => id is empty")
 	}
 
-	url, _ := params["url"].(string)
-	timeout, _ := params["timeout"].(int)
-	if timeout == 0 {
-		timeout = 5
+	options := tpl.site24x7OptionsFromParams(params)
+	site24x7 := vendors.NewSite24x7(options, tpl.logger)
+
+	return site24x7.CustomPollMonitor(options, vendors.Site24x7MonitorOptions{ID: id})
+}
+
+func (tpl *Template) Site24x7GetPollingStatus(params map[string]interface{}) ([]byte, error) {
+
+	id, _ := params["id"].(string)
+	if utils.IsEmpty(id) {
+		return nil, fmt.Errorf("Site24x7GetPollingStatus err => id is empty")
 	}
 
-	insecure, _ := params["insecure"].(bool)
-	user, _ := params["user"].(string)
-	password, _ := params["password"].(string)
+	options := tpl.site24x7OptionsFromParams(params)
+	site24x7 := vendors.NewSite24x7(options, tpl.logger)
 
-	query, _ := params["query"].(string)
-	if utils.IsEmpty(query) {
-		return nil, fmt.Errorf("PrometheusGet err => %s", "query is empty")
+	return site24x7.CustomGetPollingStatus(options, vendors.Site24x7MonitorOptions{ID: id})
+}
+
+func (tpl *Template) Site24x7GetLogReport(params map[string]interface{}) ([]byte, error) {
+
+	id, _ := params["id"].(string)
+	if utils.IsEmpty(id) {
+		return nil, fmt.Errorf("Site24x7GetLogReport err => id is empty")
 	}
+	startDate, _ := params["startDate"].(string)
+	endDate, _ := params["endDate"].(string)
 
-	from, _ := params["from"].(string)
-	to, _ := params["to"].(string)
-	step, _ := params["step"].(string)
-	prms, _ := params["params"].(string)
+	options := tpl.site24x7OptionsFromParams(params)
+	site24x7 := vendors.NewSite24x7(options, tpl.logger)
 
-	noerror, _ := params["noerror"].(bool)
+	return site24x7.CustomGetLogReport(options, vendors.Site24x7LogReportOptions{
+		Site24x7MonitorOptions: vendors.Site24x7MonitorOptions{ID: id},
+		StartDate:              startDate,
+		EndDate:                endDate,
+	})
+}
 
-	prometheusOptions := vendors.PrometheusOptions{
+func (tpl *Template) Site24x7GetLocationProfiles(params map[string]interface{}) ([]byte, error) {
+
+	options := tpl.site24x7OptionsFromParams(params)
+	site24x7 := vendors.NewSite24x7(options, tpl.logger)
+
+	return site24x7.CustomGetLocationProfiles(options)
+}
+
+func (tpl *Template) vcenterFromParams(params map[string]interface{}) (*vendors.VCenter, vendors.VCenterOptions, error) {
+
+	url, _ := params["url"].(string)
+	user, _ := params["user"].(string)
+	password, _ := params["password"].(string)
+	insecure, _ := params["insecure"].(bool)
+
+	options, err := vendors.InitializeVCenterSession(vendors.VCenterOptions{
 		URL:      url,
 		User:     user,
 		Password: password,
-		Timeout:  timeout,
+		Timeout:  tpl.paramAsInt(params["timeout"], 20),
 		Insecure: insecure,
-		Query:    query,
-		From:     from,
-		To:       to,
-		Step:     step,
-		Params:   prms,
+	})
+	if err != nil {
+		return nil, options, err
 	}
+	return vendors.NewVCenter(options), options, nil
+}
 
-	prometheus := vendors.NewPrometheus(prometheusOptions)
+func (tpl *Template) VCenterGetClusters(params map[string]interface{}) ([]byte, error) {
 
-	d, err := prometheus.Get()
-	if noerror {
-		err = nil
+	vcenter, options, err := tpl.vcenterFromParams(params)
+	if err != nil {
+		return nil, err
 	}
-	return d, err
+	return vcenter.CustomGetClusters(options)
+}
+
+func (tpl *Template) VCenterGetHosts(params map[string]interface{}) ([]byte, error) {
+
+	cluster, _ := params["cluster"].(string)
+
+	vcenter, options, err := tpl.vcenterFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+	return vcenter.CustomGetHosts(options, vendors.VCenterHostOptions{
+		Cluster: cluster,
+	})
+}
+
+func (tpl *Template) VCenterGetVMs(params map[string]interface{}) ([]byte, error) {
+
+	cluster, _ := params["cluster"].(string)
+	host, _ := params["host"].(string)
+
+	vcenter, options, err := tpl.vcenterFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+	return vcenter.CustomGetVMs(options, vendors.VCenterVMOptions{
+		Cluster: cluster,
+		Host:    host,
+	})
+}
+
+func (tpl *Template) VCenterGetVMGuestIdentity(params map[string]interface{}) ([]byte, error) {
+
+	vm, _ := params["vm"].(string)
+	if utils.IsEmpty(vm) {
+		return nil, fmt.Errorf("VCenterGetVMGuestIdentity err => vm is empty")
+	}
+
+	vcenter, options, err := tpl.vcenterFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+	return vcenter.CustomGetVMGuestIdentity(options, vendors.VCenterVMGuestIdentityOptions{
+		VM: vm,
+	})
 }
 
 func (tpl *Template) TemplateRender(name string, obj interface{}) (string, error) {
@@ -3411,15 +4518,25 @@ func (tpl *Template) paramAsInt(param interface{}, def int) int {
 	}
 	value, ok := param.(int)
 	if !ok {
-		valueStr := fmt.Sprintf("%v", param)
-		value, _ = strconv.Atoi(valueStr)
-	}
-	if utils.IsEmpty(param) {
-		return def
+		v, err := strconv.Atoi(fmt.Sprintf("%v", param))
+		if err != nil {
+			return def
+		}
+		value = v
 	}
 	return value
 }
 
+// pageOptionsFromParams returns limit and all params of list functions
+func (tpl *Template) pageOptionsFromParams(params map[string]interface{}, limit int) vendors.PageOptions {
+
+	all, _ := params["all"].(bool)
+	return vendors.PageOptions{
+		Limit: tpl.paramAsInt(params["limit"], limit),
+		All:   all,
+	}
+}
+
 func (tpl *Template) K8sResourceRestart(params map[string]interface{}) ([]byte, error) {
 
 	config, _ := params["config"].(string)
@@ -3455,6 +4572,11 @@ func (tpl *Template) K8sResourceRestart(params map[string]interface{}) ([]byte,
 	return k8s.CustomResourceRestart(options, restartOptions)
 }
 
+// VendorCall calls operation of registered vendor, options and input are maps or JSON strings with case insensitive field names
+func (tpl *Template) VendorCall(name, operation string, options, input interface{}) ([]byte, error) {
+	return vendors.CallVendor(tpl.tracingContext(), name, operation, options, input, tpl.logger)
+}
+
 func (tpl *Template) DirCreate(path string, mode int) error {
 
 	m := mode
@@ -3559,6 +4681,33 @@ func (tpl *Template) setTemplateFuncs(funcs map[string]any) {
 	funcs["toYml"] = tpl.ToYaml
 	funcs["fromYaml"] = tpl.FromYaml
 	funcs["fromYml"] = tpl.FromYaml
+	funcs["fromCsv"] = tpl.FromCsv
+	funcs["fromCsvWith"] = tpl.FromCsvWith
+	funcs["toCsv"] = tpl.ToCsv
+	funcs["toCsvWith"] = tpl.ToCsvWith
+	funcs["fromToml"] = tpl.FromToml
+	funcs["toToml"] = tpl.ToToml
+	funcs["fromXml"] = tpl.FromXml
+	funcs["toXml"] = tpl.ToXml
+	funcs["fromIni"] = tpl.FromIni
+	funcs["toIni"] = tpl.ToIni
+	funcs["fromHcl"] = tpl.FromHcl
+
+	funcs["asciiTable"] = tpl.AsciiTable
+	funcs["asciiTableWith"] = tpl.AsciiTableWith
+	funcs["markdownTable"] = tpl.MarkdownTable
+	funcs["markdownTableWith"] = tpl.MarkdownTableWith
+	funcs["slackTableBlocks"] = tpl.SlackTableBlocks
+	funcs["slackTableBlocksWith"] = tpl.SlackTableBlocksWith
+	funcs["telegramTable"] = tpl.TelegramTable
+	funcs["telegramTableWith"] = tpl.TelegramTableWith
+	funcs["markdownToSlack"] = tpl.MarkdownToSlack
+	funcs["markdownToTelegram"] = tpl.MarkdownToTelegram
+	funcs["markdownToTelegramHtml"] = tpl.MarkdownToTelegramHtml
+	funcs["markdownToJira"] = tpl.MarkdownToJira
+	funcs["markdownToAdf"] = tpl.MarkdownToAdf
+	funcs["markdownTo"] = tpl.MarkdownTo
+	funcs["secret"] = tpl.Secret
 	funcs["split"] = tpl.Split
 	funcs["join"] = tpl.Join
 	funcs["isEmpty"] = tpl.IsEmpty
@@ -3578,6 +4727,7 @@ func (tpl *Template) setTemplateFuncs(funcs map[string]any) {
 	funcs["ifElse"] = tpl.IfElse
 	funcs["ifIP"] = tpl.IfIP
 	funcs["ifIPAndPort"] = tpl.IfIPAndPort
+	funcs["ifIPInCIDR"] = tpl.IfIPInCIDR
 
 	funcs["error"] = tpl.Error
 	funcs["ifError"] = tpl.IfError
@@ -3593,6 +4743,7 @@ func (tpl *Template) setTemplateFuncs(funcs map[string]any) {
 	funcs["durationString"] = tpl.DurationString
 	funcs["nowFmt"] = tpl.NowFmt
 	funcs["sleep"] = tpl.Sleep
+	funcs["retry"] = tpl.Retry
 	funcs["error"] = tpl.Error
 	funcs["uuid"] = tpl.UUID
 
@@ -3664,17 +4815,126 @@ func (tpl *Template) setTemplateFuncs(funcs map[string]any) {
 
 	funcs["prometheusGet"] = tpl.PrometheusGet
 
+	funcs["slackSendMessage"] = tpl.SlackSendMessage
+	funcs["slackSendFile"] = tpl.SlackSendFile
+	funcs["slackAddReaction"] = tpl.SlackAddReaction
+	funcs["slackGetUser"] = tpl.SlackGetUser
+	funcs["slackUpdateUsergroup"] = tpl.SlackUpdateUsergroup
+	funcs["slackGetConversationHistory"] = tpl.SlackGetConversationHistory
+	funcs["slackUpdateMessage"] = tpl.SlackUpdateMessage
+	funcs["slackDeleteMessage"] = tpl.SlackDeleteMessage
+	funcs["slackGetPermalink"] = tpl.SlackGetPermalink
+	funcs["slackScheduleMessage"] = tpl.SlackScheduleMessage
+	funcs["slackDeleteScheduledMessage"] = tpl.SlackDeleteScheduledMessage
+	funcs["slackSendEphemeral"] = tpl.SlackSendEphemeral
+
+	funcs["telegramSendMessage"] = tpl.TelegramSendMessage
+	funcs["telegramSendPhoto"] = tpl.TelegramSendPhoto
+	funcs["telegramSendDocument"] = tpl.TelegramSendDocument
+
+	funcs["zabbixGetHosts"] = tpl.ZabbixGetHosts
+	funcs["netboxGetDevices"] = tpl.NetboxGetDevices
+	funcs["observiumGetDevices"] = tpl.ObserviumGetDevices
+	funcs["graylogGetLogs"] = tpl.GraylogGetLogs
+
+	funcs["teleportPing"] = tpl.TeleportPing
+	funcs["teleportResourceList"] = tpl.TeleportResourceList
+
+	funcs["virusTotalDomainReport"] = tpl.VirusTotalDomainReport
+
+	funcs["site24x7RetrieveMonitorByName"] = tpl.Site24x7RetrieveMonitorByName
+	funcs["site24x7PollMonitor"] = tpl.Site24x7PollMonitor
+	funcs["site24x7GetPollingStatus"] = tpl.Site24x7GetPollingStatus
+	funcs["site24x7GetLogReport"] = tpl.Site24x7GetLogReport
+	funcs["site24x7GetLocationProfiles"] = tpl.Site24x7GetLocationProfiles
+
+	funcs["vcenterGetClusters"] = tpl.VCenterGetClusters
+	funcs["vcenterGetHosts"] = tpl.VCenterGetHosts
+	funcs["vcenterGetVMs"] = tpl.VCenterGetVMs
+	funcs["vcenterGetVMGuestIdentity"] = tpl.VCenterGetVMGuestIdentity
+
 	funcs["k8sResourceDescribe"] = tpl.K8sResourceDescribe
 	funcs["k8sResourceDelete"] = tpl.K8sResourceDelete
 	funcs["k8sResourceScale"] = tpl.K8sResourceScale
 	funcs["k8sResourceRestart"] = tpl.K8sResourceRestart
 
+	funcs["vendorCall"] = tpl.VendorCall
+
 	funcs["dirCreate"] = tpl.DirCreate
 	funcs["dirRemove"] = tpl.DirRemove
 	funcs["fileCreate"] = tpl.FileCreate
 	funcs["exec"] = tpl.Exec
 }
 
+// SetContext sets parent context for spans of template functions and their HTTP requests
+func (tpl *Template) SetContext(ctx context.Context) {
+	tpl.ctx = ctx
+}
+
+func (tpl *Template) tracingContext() context.Context {
+
+	if tpl.ctx != nil {
+		return tpl.ctx
+	}
+	return common.TracingContext()
+}
+
+// tracingFunc wraps template function into span "template.<name>", template is executed sequentially so nested calls keep parent in ctx
+func (tpl *Template) tracingFunc(name string, f any) any {
+
+	v := reflect.ValueOf(f)
+	t := v.Type()
+	if !common.TracingEnabled() || t.Kind() != reflect.Func || strings.HasPrefix(name, "log") {
+		return f
+	}
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+
+		parent := tpl.ctx
+		ctx, span := common.StartSpan(tpl.tracingContext(), fmt.Sprintf("template.%s", name))
+		tpl.ctx = ctx
+
+		var out []reflect.Value
+		var err error
+		defer func() {
+			tpl.ctx = parent
+			common.EndSpan(span, err)
+		}()
+
+		if t.IsVariadic() {
+			out = v.CallSlice(args)
+		} else {
+			out = v.Call(args)
+		}
+		if n := len(out); n > 0 && t.Out(n-1) == errorType && !out[n-1].IsNil() {
+			err = out[n-1].Interface().(error)
+		}
+		return out
+	}).Interface()
+}
+
+// FuncNames returns names of own template functions, sprig functions are not included
+func (tpl *Template) FuncNames() []string {
+
+	own := make(map[string]any)
+	tpl.setTemplateFuncs(own)
+
+	var r []string
+	for name := range own {
+		r = append(r, name)
+	}
+	sort.Strings(r)
+	return r
+}
+
+// Func returns template function by name, so it's called like in template
+func (tpl *Template) Func(name string) (any, bool) {
+
+	fn, ok := tpl.funcs[name]
+	return fn, ok
+}
+
 func (tpl *Template) filterFuncsByContent(funcs map[string]any, content string) map[string]any {
 
 	m := make(map[string]any)
@@ -3732,7 +4992,11 @@ func NewTextTemplate(options TemplateOptions, logger common.Logger) (*TextTempla
 	var t *txtTemplate.Template
 
 	funcs := sprig.TxtFuncMap()
-	tpl.setTemplateFuncs(funcs)
+	own := make(map[string]any)
+	tpl.setTemplateFuncs(own)
+	for k, v := range own {
+		funcs[k] = tpl.tracingFunc(k, v)
+	}
 	for k, v := range options.Funcs {
 		funcs[k] = v
 	}
@@ -3814,7 +5078,11 @@ func NewHtmlTemplate(options TemplateOptions, logger common.Logger) (*HtmlTempla
 	var t *htmlTemplate.Template
 
 	funcs := sprig.HtmlFuncMap()
-	tpl.setTemplateFuncs(funcs)
+	own := make(map[string]any)
+	tpl.setTemplateFuncs(own)
+	for k, v := range own {
+		funcs[k] = tpl.tracingFunc(k, v)
+	}
 	for k, v := range options.Funcs {
 		funcs[k] = v
 	}
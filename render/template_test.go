@@ -0,0 +1,278 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors/vendortest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func templateTestNew(t *testing.T) *TextTemplate {
+
+	tpl, err := NewTextTemplate(TemplateOptions{Content: "{{ $d := 0 }}"}, common.NewStdout(common.StdoutOptions{}))
+	require.NoError(t, err)
+	return tpl
+}
+
+// templateTestRequest returns last request of emulator to path
+func templateTestRequest(t *testing.T, s *vendortest.Server, path string) vendortest.Request {
+
+	requests := s.Requests()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Path == path {
+			return requests[i]
+		}
+	}
+	t.Fatalf("no request to %s", path)
+	return vendortest.Request{}
+}
+
+// templateTestForm returns fields of multipart form of request
+func templateTestForm(t *testing.T, r vendortest.Request) map[string]string {
+
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	require.NoError(t, err)
+
+	fields := make(map[string]string)
+	reader := multipart.NewReader(strings.NewReader(r.Body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		b, err := io.ReadAll(part)
+		require.NoError(t, err)
+		fields[part.FormName()] = string(b)
+	}
+	return fields
+}
+
+func TestTemplateSlackSendMessage(t *testing.T) {
+
+	s := vendortest.Start(t, "slack")
+	tpl := templateTestNew(t)
+
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		expected map[string]string
+		err      bool
+	}{
+		{
+			name:     "Message",
+			params:   map[string]interface{}{"channel": "C1", "text": "hello", "title": "alert"},
+			expected: map[string]string{"channel": "C1", "text": "hello", "title": "alert"},
+		},
+		{
+			name:     "Thread with markdown",
+			params:   map[string]interface{}{"channel": "C1", "thread": "1.000001", "text": "**bold**", "format": "markdown", "blocks": `[{"type":"divider"}]`},
+			expected: map[string]string{"channel": "C1", "thread_ts": "1.000001", "text": "*bold*", "blocks": `[{"type":"divider"}]`},
+		},
+		{
+			name:   "No channel",
+			params: map[string]interface{}{"text": "hello"},
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			params := map[string]interface{}{"url": s.URL, "token": "slack-token", "timeout": "5"}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+			b, err := tpl.SlackSendMessage(params)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, string(b), `"ok":true`)
+
+			r := templateTestRequest(t, s, "/api/chat.postMessage")
+			assert.Equal(t, "Bearer slack-token", r.Header.Get("Authorization"))
+			fields := templateTestForm(t, r)
+			for k, v := range tt.expected {
+				assert.Equal(t, v, fields[k], k)
+			}
+		})
+	}
+}
+
+func TestTemplateZabbixGetHosts(t *testing.T) {
+
+	s := vendortest.Start(t, "zabbix")
+	s.Put("hosts", "1", vendortest.Object{"hostid": "1", "host": "web-1"})
+	s.Put("sessions", "session-token", vendortest.Object{"user": "admin"})
+	tpl := templateTestNew(t)
+
+	tests := []struct {
+		name       string
+		params     map[string]interface{}
+		auth       string
+		output     []string
+		inventory  []string
+		interfaces []string
+	}{
+		{
+			name:       "Fields as list and string",
+			params:     map[string]interface{}{"user": "admin", "password": "secret", "fields": []interface{}{"hostid", "host"}, "interfaces": "ip,dns"},
+			output:     []string{"hostid", "host"},
+			inventory:  []string{},
+			interfaces: []string{"ip", "dns"},
+		},
+		{
+			name:       "Auth without login",
+			params:     map[string]interface{}{"auth": "session-token", "inventory": "os"},
+			auth:       "session-token",
+			output:     []string{},
+			inventory:  []string{"os"},
+			interfaces: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			count := len(s.Requests())
+			params := map[string]interface{}{"url": s.URL, "timeout": 5}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+			b, err := tpl.ZabbixGetHosts(params)
+			require.NoError(t, err)
+			assert.Contains(t, string(b), `"web-1"`)
+
+			var req struct {
+				Method string `json:"method"`
+				Auth   string `json:"auth"`
+				Params struct {
+					Output           []string `json:"output"`
+					SelectInventory  []string `json:"selectInventory"`
+					SelectInterfaces []string `json:"selectInterfaces"`
+				} `json:"params"`
+			}
+			r := s.Requests()[count:]
+			require.NoError(t, json.Unmarshal([]byte(r[len(r)-1].Body), &req))
+			assert.Equal(t, "host.get", req.Method)
+			if tt.auth != "" {
+				assert.Equal(t, tt.auth, req.Auth)
+				assert.Len(t, r, 1)
+			} else {
+				assert.NotEmpty(t, req.Auth)
+				assert.Len(t, r, 2)
+			}
+			assert.Equal(t, tt.output, req.Params.Output)
+			assert.Equal(t, tt.inventory, req.Params.SelectInventory)
+			assert.Equal(t, tt.interfaces, req.Params.SelectInterfaces)
+		})
+	}
+}
+
+func TestTemplateNetboxGetDevices(t *testing.T) {
+
+	s := vendortest.Start(t, "netbox")
+	s.Put("devices", "1", vendortest.Object{"id": 1, "name": "sw1", "status": vendortest.Object{"value": "active"}})
+	s.Put("devices", "2", vendortest.Object{"id": 2, "name": "sw2", "status": vendortest.Object{"value": "offline"}})
+	tpl := templateTestNew(t)
+
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		path     string
+		query    url.Values
+		expected []string
+	}{
+		{
+			name:     "Default limit",
+			params:   map[string]interface{}{},
+			path:     "/api/dcim/devices/",
+			query:    url.Values{"limit": {"50"}},
+			expected: []string{"sw1", "sw2"},
+		},
+		{
+			name:     "Invalid limit is default",
+			params:   map[string]interface{}{"limit": "many"},
+			path:     "/api/dcim/devices/",
+			query:    url.Values{"limit": {"50"}},
+			expected: []string{"sw1", "sw2"},
+		},
+		{
+			name:     "Filter as string and brief",
+			params:   map[string]interface{}{"filter": "status=active", "brief": true, "limit": "10"},
+			path:     "/api/dcim/devices/",
+			query:    url.Values{"limit": {"10"}, "brief": {"1"}, "status": {"active"}},
+			expected: []string{"sw1"},
+		},
+		{
+			name:     "Filter as map",
+			params:   map[string]interface{}{"filter": map[string]interface{}{"status": "offline"}, "limit": 5},
+			path:     "/api/dcim/devices/",
+			query:    url.Values{"limit": {"5"}, "status": {"offline"}},
+			expected: []string{"sw2"},
+		},
+		{
+			name:     "Device",
+			params:   map[string]interface{}{"deviceID": 2},
+			path:     "/api/dcim/devices/2/",
+			query:    url.Values{"limit": {"50"}},
+			expected: []string{"sw2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			params := map[string]interface{}{"url": s.URL, "token": "netbox-token"}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+			b, err := tpl.NetboxGetDevices(params)
+			require.NoError(t, err)
+			for _, name := range tt.expected {
+				assert.Contains(t, string(b), `"`+name+`"`)
+			}
+
+			r := templateTestRequest(t, s, tt.path)
+			assert.Equal(t, "Token netbox-token", r.Header.Get("Authorization"))
+			query, err := url.ParseQuery(r.Query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.query, query)
+		})
+	}
+}
+
+func TestTemplateRetryContext(t *testing.T) {
+
+	calls := 0
+	tpl, err := NewTextTemplate(TemplateOptions{Content: "{{ $d := 0 }}", Funcs: map[string]any{
+		"fail": func() (string, error) {
+			calls++
+			return "", errors.New("timeout")
+		},
+	}}, common.NewStdout(common.StdoutOptions{}))
+	require.NoError(t, err)
+
+	// canceled call of server stops retry at once
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tpl.SetContext(ctx)
+	start := time.Now()
+	_, err = tpl.Retry(map[string]interface{}{"attempts": 3, "delay": "1h"}, "fail")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/render"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+)
+
+// CallOptions are shared by http and mcp servers, so both expose the same functions with the same credentials
+type CallOptions struct {
+	Allow         []string               // template functions and vendor operations as <vendor>.<operation>, globs, empty allows all calls of http server, mcp server publishes nothing without it
+	CallerOptions bool                   // callers may pass vendor options, otherwise options of server profile are used
+	Token         string                 // bearer token of HTTP callers, empty disables auth
+	Vendors       map[string]interface{} // vendor options of server profile
+}
+
+var errCallNotAllowed = errors.New("not allowed")
+
+func callLowerFirst(name string) string {
+
+	if utils.IsEmpty(name) {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// callEmpty checks that caller passed no value, e.g. null, "" or {}
+func callEmpty(v interface{}) bool {
+
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return utils.IsEmpty(strings.TrimSpace(t))
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// allowed checks template function or <vendor>.<operation> against allowlist, names are case insensitive
+func (o CallOptions) allowed(name string) bool {
+
+	allow := common.RemoveEmptyStrings(o.Allow)
+	if len(allow) == 0 {
+		return true
+	}
+	name = strings.ToLower(name)
+	for _, p := range allow {
+		if ok, _ := path.Match(strings.ToLower(strings.TrimSpace(p)), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// authorized checks bearer token of HTTP request, every request is authorized if token is not set
+func (o CallOptions) authorized(r *http.Request) bool {
+
+	if utils.IsEmpty(o.Token) {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(o.Token)) == 1
+}
+
+// callUnauthorized responds 401 with bearer challenge
+func callUnauthorized(w http.ResponseWriter, server string) error {
+
+	err := fmt.Errorf("%s has invalid bearer token", server)
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	http.Error(w, err.Error(), http.StatusUnauthorized)
+	return err
+}
+
+// callTemplate calls template function by name, method error is returned as last result
+func callTemplate(ctx context.Context, options CallOptions, name string, params []interface{}, logger common.Logger) ([]interface{}, error) {
+
+	if utils.IsEmpty(name) {
+		return nil, fmt.Errorf("name is empty")
+	}
+	name = callLowerFirst(name)
+	if !options.allowed(name) {
+		return nil, fmt.Errorf("template function %s is %w", name, errCallNotAllowed)
+	}
+
+	tpl, err := render.NewTextTemplate(render.TemplateOptions{Content: "{{ $d := 0 }}"}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := common.StartSpan(ctx, fmt.Sprintf("template.%s", name))
+	tpl.SetContext(ctx)
+
+	// function names which differ from methods, e.g. findObjectByField, are called as template functions
+	method := strings.ToUpper(name[:1]) + name[1:]
+	var arr []interface{}
+	if fn, ok := tpl.Func(name); ok && !reflect.ValueOf(tpl).MethodByName(method).IsValid() {
+		arr, err = common.InvokeFunc(fn, name, params...)
+	} else {
+		arr, err = common.Invoke(tpl, method, params...)
+	}
+
+	serr := err
+	if serr == nil && len(arr) > 0 {
+		serr, _ = arr[len(arr)-1].(error)
+	}
+	common.EndSpan(span, serr)
+	return arr, err
+}
+
+// callVendor calls "<vendor>.<operation>" with options of server profile, caller options are used if they are allowed
+func callVendor(ctx context.Context, options CallOptions, name string, vendorOptions, input interface{}, logger common.Logger) ([]byte, *vendors.VendorOperation, error) {
+
+	vendor, operation, ok := strings.Cut(name, ".")
+	if !ok {
+		return nil, nil, fmt.Errorf("vendor call %s should be <vendor>.<operation>", name)
+	}
+	vendor = strings.ToLower(vendor)
+
+	if callEmpty(vendorOptions) {
+		vendorOptions = nil
+	}
+	if vendorOptions != nil && !options.CallerOptions {
+		return nil, nil, fmt.Errorf("vendor %s options of caller are %w, options of server profile are used", vendor, errCallNotAllowed)
+	}
+	if vendorOptions == nil {
+		vendorOptions = options.Vendors[vendor]
+	}
+
+	// allowlist is checked before vendor is created with options
+	o, err := vendors.FindVendorOperationName(vendor, operation)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !options.allowed(fmt.Sprintf("%s.%s", vendor, o.Name)) {
+		return nil, nil, fmt.Errorf("vendor operation %s.%s is %w", vendor, o.Name, errCallNotAllowed)
+	}
+
+	v, err := vendors.NewVendor(vendor, vendorOptions, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o, err = vendors.FindVendorOperation(v, o.Name); err != nil {
+		return nil, nil, err
+	}
+
+	ctx, span := common.StartSpan(ctx, fmt.Sprintf("vendor.%s.%s", vendor, o.Name))
+	b, err := o.Call(ctx, input)
+	common.EndSpan(span, err)
+	return b, &o, err
+}
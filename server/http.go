@@ -1,9 +1,11 @@
 package server
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -13,9 +15,9 @@ import (
 	"sync"
 
 	"github.com/devopsext/tools/common"
-	"github.com/devopsext/tools/render"
 	"github.com/devopsext/utils"
 	"github.com/go-playground/form/v4"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type HttpServerOptions struct {
@@ -29,6 +31,7 @@ type HttpServerOptions struct {
 	Timeout         int
 	Methods         []string
 	SensitiveFields []string
+	Calls           CallOptions
 }
 
 type HttpServer struct {
@@ -54,9 +57,10 @@ type HttpServerCallRequest struct {
 }
 
 type HttpServerCallResponse struct {
-	Request *HttpServerCallRequest `json:"request"`
-	Result  []interface{}          `json:"result,omitempty"`
-	Error   string                 `json:"error,omitempty"`
+	Request     *HttpServerCallRequest `json:"request"`
+	Result      []interface{}          `json:"result,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+	VendorError *common.VendorError    `json:"vendorError,omitempty"`
 }
 
 type HttpServerCallProcessor struct {
@@ -114,26 +118,35 @@ func (h *HttpServerCallProcessor) params2String(params []interface{}) string {
 	for _, v := range h.server.options.SensitiveFields {
 		s = h.replaceByRegex(s, v)
 	}
+	s = common.RedactSensitive(s)
 
 	return fmt.Sprintf("params: %s", s)
 }
 
-func (h *HttpServerCallProcessor) handleTemplate(name string, params []interface{}) ([]interface{}, error) {
+// handleVendor calls "<vendor>.<operation>" of registered vendor, first param is vendor options and second is operation input
+func (h *HttpServerCallProcessor) handleVendor(ctx context.Context, name string, params []interface{}, logger common.Logger) ([]interface{}, error) {
 
-	options := render.TemplateOptions{
-		Content:     "{{ $d := 0 }}",
-		FilterFuncs: false,
+	var options, input interface{}
+	if len(params) > 0 {
+		options = params[0]
 	}
-	tpl, err := render.NewTextTemplate(options, h.server.logger)
+	if len(params) > 1 {
+		input = params[1]
+	}
+
+	b, _, err := callVendor(ctx, h.server.options.Calls, name, options, input, logger)
 	if err != nil {
-		return nil, nil
+		return nil, err
 	}
-	return common.Invoke(tpl, name, params...)
+	return []interface{}{b}, nil
 }
 
 func (h *HttpServerCallProcessor) HandleRequest(w http.ResponseWriter, r *http.Request) error {
 
 	var err error
+	if !h.server.options.Calls.authorized(r) {
+		return callUnauthorized(w, "HTTP Server")
+	}
 	if !utils.Contains(h.server.options.Methods, r.Method) {
 		err := fmt.Errorf("HTTP Server has invalid method: %v", r.Method)
 		http.Error(w, err.Error(), http.StatusMethodNotAllowed)
@@ -155,7 +168,25 @@ func (h *HttpServerCallProcessor) HandleRequest(w http.ResponseWriter, r *http.R
 		return err
 	}
 
-	h.server.logger.Debug("HTTP Server reguest id: %s => %s", request.ID, h.request2String(&request))
+	// request id is used as correlation id for all messages of the request
+	if utils.IsEmpty(request.ID) {
+		request.ID = r.Header.Get(common.CorrelationIDHeader)
+	}
+	if utils.IsEmpty(request.ID) {
+		request.ID = common.NewCorrelationID()
+	}
+	w.Header().Set(common.CorrelationIDHeader, request.ID)
+	logger := common.LoggerWith(h.server.logger, common.CorrelationIDField, request.ID)
+
+	// span is child of caller span if traceparent header is set
+	ctx, span := common.StartSpan(common.ExtractTracingContext(r.Context(), r.Header), "server.call",
+		attribute.String(common.CorrelationIDField, request.ID),
+		attribute.String("call.name", request.Name),
+		attribute.String("call.package", request.Package),
+	)
+	defer func() { common.EndSpan(span, err) }()
+
+	logger.Debug("HTTP Server reguest id: %s => %s", request.ID, h.request2String(&request))
 
 	if utils.IsEmpty(request.Name) {
 		err := fmt.Errorf("name is empty")
@@ -200,20 +231,21 @@ func (h *HttpServerCallProcessor) HandleRequest(w http.ResponseWriter, r *http.R
 		}
 	}
 
-	h.server.logger.Debug("HTTP Server request id: %s => %s", request.ID, h.params2String(params))
-
-	name := strings.ToUpper(request.Name[:1]) + request.Name[1:]
+	logger.Debug("HTTP Server request id: %s => %s", request.ID, h.params2String(params))
 
 	switch request.Package {
 	case "template":
-		arr, err = h.handleTemplate(name, params)
+		arr, err = callTemplate(ctx, h.server.options.Calls, request.Name, params, logger)
+	case "vendors":
+		arr, err = h.handleVendor(ctx, request.Name, params, logger)
 	default:
-		arr, err = h.handleTemplate(name, params)
+		arr, err = callTemplate(ctx, h.server.options.Calls, request.Name, params, logger)
 	}
 
+	// results are written as is, only error messages can carry secrets of options
 	var rerr string
 	if err != nil {
-		rerr = err.Error()
+		rerr = common.RedactSensitive(err.Error())
 	}
 
 	var rarr []interface{}
@@ -242,6 +274,19 @@ func (h *HttpServerCallProcessor) HandleRequest(w http.ResponseWriter, r *http.R
 		Error:   rerr,
 	}
 
+	// vendor error of call or template method is mapped to status, other errors are reported with 200
+	cerr := err
+	if cerr == nil && len(arr) > 0 {
+		cerr, _ = arr[len(arr)-1].(error)
+	}
+	status := common.VendorErrorHttpStatus(cerr)
+	if status > 0 {
+		errors.As(cerr, &res.VendorError)
+	}
+	if errors.Is(err, errCallNotAllowed) {
+		status = http.StatusForbidden
+	}
+
 	serr := ""
 	if !utils.IsEmpty(rerr) {
 		serr = fmt.Sprintf(" error: %s", rerr)
@@ -249,10 +294,10 @@ func (h *HttpServerCallProcessor) HandleRequest(w http.ResponseWriter, r *http.R
 
 	sarr := "no result"
 	if !utils.IsEmpty(rarr) {
-		sarr = fmt.Sprintf("result: %v", rarr)
+		sarr = common.RedactSensitive(fmt.Sprintf("result: %v", rarr))
 	}
 
-	h.server.logger.Debug("HTTP Server request id: %s => %s%s", request.ID, sarr, serr)
+	logger.Debug("HTTP Server request id: %s => %s%s", request.ID, sarr, serr)
 
 	data, err := json.Marshal(res)
 	if err != nil {
@@ -260,6 +305,10 @@ func (h *HttpServerCallProcessor) HandleRequest(w http.ResponseWriter, r *http.R
 		return err
 	}
 
+	if status > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+	}
 	if _, err := w.Write(data); err != nil {
 		http.Error(w, fmt.Sprintf("HTTP Server could not write response: %v", err), http.StatusInternalServerError)
 		return err
@@ -292,6 +341,12 @@ func (h *HttpServer) Start(wg *sync.WaitGroup) {
 
 		defer wg.Done()
 		h.logger.Info("Start HTTP Server...")
+		if utils.IsEmpty(h.options.Calls.Token) {
+			h.logger.Warn("HTTP Server has no bearer token, calls are not authenticated")
+		}
+		if len(common.RemoveEmptyStrings(h.options.Calls.Allow)) == 0 {
+			h.logger.Warn("HTTP Server allows all template functions and vendor operations, restrict them by server allowlist")
+		}
 
 		var caPool *x509.CertPool
 		var certificates []tls.Certificate
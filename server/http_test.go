@@ -0,0 +1,47 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/devopsext/tools/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpServerCall(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		calls  CallOptions
+		header string
+		status int
+		body   string
+	}{
+		{name: "Empty allowlist allows all", status: http.StatusOK, body: `"result":["abc"`},
+		{name: "Not allowed", calls: CallOptions{Allow: []string{"toUpper"}}, status: http.StatusForbidden},
+		{name: "No token", calls: CallOptions{Token: "server-token"}, status: http.StatusUnauthorized},
+		{name: "Valid token", calls: CallOptions{Token: "server-token"}, header: "Bearer server-token", status: http.StatusOK, body: `"result":["abc"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			s := NewHttpServer(HttpServerOptions{Methods: []string{http.MethodPost}, Calls: tt.calls}, common.NewStdout(common.StdoutOptions{}))
+			p := &HttpServerCallProcessor{server: s}
+
+			form := url.Values{"name": {"toLower"}, "package": {"template"}, "params": {"ABC"}}
+			r := httptest.NewRequest(http.MethodPost, HttpServerCallProcessorPath, strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			p.HandleRequest(w, r)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.body)
+		})
+	}
+}
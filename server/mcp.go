@@ -0,0 +1,556 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/render"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type McpServerOptions struct {
+	Name    string
+	Version string
+	Listen  string // streamable HTTP listen, stdio is used if it's empty
+	Path    string
+	Timeout int
+	Calls   CallOptions
+}
+
+// McpServer speaks Model Context Protocol over stdio or streamable HTTP, allowed vendor operations and template
+// functions are published as tools
+type McpServer struct {
+	options McpServerOptions
+	logger  common.Logger
+	tools   map[string]*mcpTool
+}
+
+type mcpTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	call        func(ctx context.Context, args map[string]interface{}) ([]mcpContent, error)
+}
+
+type mcpRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type mcpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type mcpResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *mcpError       `json:"error,omitempty"`
+}
+
+type mcpContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+type mcpToolCallParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+type mcpToolCallResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+const (
+	McpServerDefaultPath = "/mcp"
+	McpSessionIDHeader   = "Mcp-Session-Id"
+
+	mcpErrorParse          = -32700
+	mcpErrorInvalidRequest = -32600
+	mcpErrorMethodNotFound = -32601
+	mcpErrorInvalidParams  = -32602
+
+	mcpMessageMax = 16 * 1024 * 1024
+)
+
+// protocol versions which are supported, the first is used if client asks for unknown one
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// mcpToolName is "<vendor>_<operation>", clients allow letters, digits, "_" and "-" only
+func mcpToolName(vendor, operation string) string {
+	return fmt.Sprintf("%s_%s", vendor, strings.ReplaceAll(operation, "-", "_"))
+}
+
+func mcpFieldSchema(f vendors.VendorField) map[string]interface{} {
+
+	if strings.HasPrefix(f.Type, "[]") {
+		item := f
+		item.Type = strings.TrimPrefix(f.Type, "[]")
+		return map[string]interface{}{"type": "array", "items": mcpFieldSchema(item)}
+	}
+	switch f.Type {
+	case "string", "bytes":
+		return map[string]interface{}{"type": "string"}
+	case "bool":
+		return map[string]interface{}{"type": "boolean"}
+	case "int":
+		return map[string]interface{}{"type": "integer"}
+	case "float":
+		return map[string]interface{}{"type": "number"}
+	case "object":
+		if len(f.Fields) > 0 {
+			return mcpFieldsSchema(f.Fields)
+		}
+	}
+	return map[string]interface{}{"type": "object"}
+}
+
+// mcpFieldsSchema returns JSON Schema of option struct fields
+func mcpFieldsSchema(fields []vendors.VendorField) map[string]interface{} {
+
+	properties := make(map[string]interface{})
+	for _, f := range fields {
+		properties[f.Name] = mcpFieldSchema(f)
+	}
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+func mcpTypeSchema(t reflect.Type) map[string]interface{} {
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": mcpTypeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object"}
+	case reflect.Struct:
+		return mcpFieldsSchema(vendors.VendorFields(reflect.New(t).Elem().Interface()))
+	case reflect.Ptr:
+		return mcpTypeSchema(t.Elem())
+	}
+	// interface{} is any JSON value
+	return map[string]interface{}{}
+}
+
+// mcpFuncSchema returns schema of template function arguments, they are positional, so named arg1, arg2...
+func mcpFuncSchema(t reflect.Type) (map[string]interface{}, []string) {
+
+	properties := make(map[string]interface{})
+	var required, names []string
+	for i := 0; i < t.NumIn(); i++ {
+
+		name := fmt.Sprintf("arg%d", i+1)
+		names = append(names, name)
+		properties[name] = mcpTypeSchema(t.In(i))
+		// variadic arguments are optional array
+		if !t.IsVariadic() || i < t.NumIn()-1 {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, names
+}
+
+// mcpText returns text content of value, JSON bytes are kept as is
+func mcpText(v interface{}) mcpContent {
+
+	var s string
+	switch t := v.(type) {
+	case []byte:
+		s = string(t)
+	case string:
+		s = t
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprintf("%v", v)
+		} else {
+			s = string(b)
+		}
+	}
+	return mcpContent{Type: "text", Text: s}
+}
+
+func (m *McpServer) vendorTool(vendor string, o vendors.VendorOperation) *mcpTool {
+
+	name := fmt.Sprintf("%s.%s", vendor, o.Name)
+	description := o.Description
+	if utils.IsEmpty(description) {
+		description = fmt.Sprintf("Call %s %s", vendor, o.Name)
+	}
+	return &mcpTool{
+		Name:        mcpToolName(vendor, o.Name),
+		Description: description,
+		InputSchema: mcpFieldsSchema(o.Input),
+		call: func(ctx context.Context, args map[string]interface{}) ([]mcpContent, error) {
+
+			b, _, err := callVendor(ctx, m.options.Calls, name, nil, args, m.logger)
+			if err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(o.Output, "image/") {
+				return []mcpContent{{Type: "image", Data: base64.StdEncoding.EncodeToString(b), MimeType: o.Output}}, nil
+			}
+			return []mcpContent{mcpText(b)}, nil
+		},
+	}
+}
+
+func (m *McpServer) templateTool(name string, t reflect.Type) *mcpTool {
+
+	schema, names := mcpFuncSchema(t)
+	var in []string
+	for i := 0; i < t.NumIn(); i++ {
+		in = append(in, t.In(i).String())
+	}
+	return &mcpTool{
+		Name:        name,
+		Description: fmt.Sprintf("Template function %s(%s), arguments are positional", name, strings.Join(in, ", ")),
+		InputSchema: schema,
+		call: func(ctx context.Context, args map[string]interface{}) ([]mcpContent, error) {
+
+			var params []interface{}
+			for i, n := range names {
+				v, ok := args[n]
+				if !ok {
+					break
+				}
+				if t.IsVariadic() && i == len(names)-1 {
+					if arr, ok := v.([]interface{}); ok {
+						params = append(params, arr...)
+						continue
+					}
+				}
+				params = append(params, v)
+			}
+
+			arr, err := callTemplate(ctx, m.options.Calls, name, params, m.logger)
+			if err != nil {
+				return nil, err
+			}
+			// method error is returned as last result
+			if len(arr) > 0 {
+				if e, ok := arr[len(arr)-1].(error); ok && e != nil {
+					return nil, e
+				}
+				if t.NumOut() > 1 && t.Out(t.NumOut()-1) == reflect.TypeOf((*error)(nil)).Elem() {
+					arr = arr[:len(arr)-1]
+				}
+			}
+			if len(arr) == 1 {
+				return []mcpContent{mcpText(arr[0])}, nil
+			}
+			return []mcpContent{mcpText(arr)}, nil
+		},
+	}
+}
+
+// loadTools publishes vendor operations and template functions selected by allowlist, nothing is published without it
+func (m *McpServer) loadTools() error {
+
+	m.tools = make(map[string]*mcpTool)
+	if len(common.RemoveEmptyStrings(m.options.Calls.Allow)) == 0 {
+		return nil
+	}
+	for _, vendor := range vendors.VendorNames() {
+
+		operations, err := vendors.VendorOperations(vendor)
+		if err != nil {
+			m.logger.Debug("MCP Server skips vendor %s: %v", vendor, err)
+			continue
+		}
+		for _, o := range operations {
+			if m.options.Calls.allowed(fmt.Sprintf("%s.%s", vendor, o.Name)) {
+				t := m.vendorTool(vendor, o)
+				m.tools[t.Name] = t
+			}
+		}
+	}
+
+	tpl, err := render.NewTextTemplate(render.TemplateOptions{Content: "{{ $d := 0 }}"}, m.logger)
+	if err != nil {
+		return err
+	}
+	for _, name := range tpl.FuncNames() {
+
+		if !m.options.Calls.allowed(name) {
+			continue
+		}
+		fn, ok := tpl.Func(name)
+		if !ok {
+			continue
+		}
+		m.tools[name] = m.templateTool(name, reflect.TypeOf(fn))
+	}
+	return nil
+}
+
+func (m *McpServer) toolList() []*mcpTool {
+
+	var names []string
+	for name := range m.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r := make([]*mcpTool, 0, len(names))
+	for _, name := range names {
+		r = append(r, m.tools[name])
+	}
+	return r
+}
+
+func (m *McpServer) callTool(ctx context.Context, params json.RawMessage) (interface{}, *mcpError) {
+
+	var p mcpToolCallParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &mcpError{Code: mcpErrorInvalidParams, Message: err.Error()}
+	}
+	tool, ok := m.tools[p.Name]
+	if !ok {
+		return nil, &mcpError{Code: mcpErrorInvalidParams, Message: fmt.Sprintf("tool %s is not found", p.Name)}
+	}
+
+	if m.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(m.options.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	id := common.NewCorrelationID()
+	logger := common.LoggerWith(m.logger, common.CorrelationIDField, id)
+	ctx, span := common.StartSpan(ctx, "server.mcp",
+		attribute.String(common.CorrelationIDField, id),
+		attribute.String("call.name", p.Name),
+	)
+	logger.Debug("MCP Server tool %s => %s", p.Name, common.RedactSensitive(fmt.Sprintf("%v", p.Arguments)))
+
+	content, err := tool.call(ctx, p.Arguments)
+	common.EndSpan(span, err)
+
+	// tool errors are results, so model sees them
+	if err != nil {
+		serr := common.RedactSensitive(err.Error())
+		logger.Debug("MCP Server tool %s error: %s", p.Name, serr)
+		return &mcpToolCallResult{Content: []mcpContent{mcpText(serr)}, IsError: true}, nil
+	}
+	return &mcpToolCallResult{Content: content}, nil
+}
+
+func (m *McpServer) initialize(params json.RawMessage) interface{} {
+
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	json.Unmarshal(params, &p)
+
+	version := mcpProtocolVersions[0]
+	if utils.Contains(mcpProtocolVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools": map[string]interface{}{"listChanged": false},
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    m.options.Name,
+			"version": m.options.Version,
+		},
+	}
+}
+
+// Handle processes JSON-RPC message, nil is returned for notifications
+func (m *McpServer) Handle(ctx context.Context, data []byte) []byte {
+
+	var req mcpRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return m.response(nil, nil, &mcpError{Code: mcpErrorParse, Message: err.Error()})
+	}
+	if req.JSONRPC != "2.0" || utils.IsEmpty(req.Method) {
+		return m.response(req.ID, nil, &mcpError{Code: mcpErrorInvalidRequest, Message: "invalid JSON-RPC 2.0 request"})
+	}
+	// notifications have no id and no response
+	if len(req.ID) == 0 {
+		m.logger.Debug("MCP Server notification %s", req.Method)
+		return nil
+	}
+
+	var result interface{}
+	var rerr *mcpError
+	switch req.Method {
+	case "initialize":
+		result = m.initialize(req.Params)
+	case "ping":
+		result = map[string]interface{}{}
+	case "tools/list":
+		result = map[string]interface{}{"tools": m.toolList()}
+	case "tools/call":
+		result, rerr = m.callTool(ctx, req.Params)
+	default:
+		rerr = &mcpError{Code: mcpErrorMethodNotFound, Message: fmt.Sprintf("method %s is not found", req.Method)}
+	}
+	return m.response(req.ID, result, rerr)
+}
+
+func (m *McpServer) response(id json.RawMessage, result interface{}, rerr *mcpError) []byte {
+
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	b, err := json.Marshal(&mcpResponse{JSONRPC: "2.0", ID: id, Result: result, Error: rerr})
+	if err != nil {
+		b, _ = json.Marshal(&mcpResponse{JSONRPC: "2.0", ID: id, Error: &mcpError{Code: mcpErrorInvalidRequest, Message: err.Error()}})
+	}
+	return b
+}
+
+// ServeStdio reads messages from r line by line and writes responses to w until r is closed
+func (m *McpServer) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), mcpMessageMax)
+	for scanner.Scan() {
+
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		resp := m.Handle(ctx, line)
+		if resp == nil {
+			continue
+		}
+		if _, err := w.Write(append(resp, '\n')); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// ServeHTTP is streamable HTTP transport, every POST is answered with JSON, so there are no SSE streams
+func (m *McpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	// origin is checked against DNS rebinding of local servers
+	if origin := r.Header.Get("Origin"); !utils.IsEmpty(origin) {
+		if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+			http.Error(w, "MCP Server origin is not allowed", http.StatusForbidden)
+			return
+		}
+	}
+	if !m.options.Calls.authorized(r) {
+		m.logger.Error(callUnauthorized(w, "MCP Server"))
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, fmt.Sprintf("MCP Server has invalid method: %v", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, mcpMessageMax))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("MCP Server could not read request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := common.ExtractTracingContext(r.Context(), r.Header)
+	resp := m.Handle(ctx, data)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if utils.IsEmpty(r.Header.Get(McpSessionIDHeader)) {
+		w.Header().Set(McpSessionIDHeader, common.NewCorrelationID())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(resp); err != nil {
+		m.logger.Error(err)
+	}
+}
+
+// Start serves streamable HTTP on listen address or stdio, it returns when stdio is closed
+func (m *McpServer) Start(wg *sync.WaitGroup) {
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+
+		defer wg.Done()
+		if err := m.loadTools(); err != nil {
+			m.logger.Panic(err)
+		}
+		m.logger.Info("Start MCP Server with %d tools...", len(m.tools))
+		if len(m.tools) == 0 {
+			m.logger.Warn("MCP Server has no tools, set them by server allowlist")
+		}
+
+		if utils.IsEmpty(m.options.Listen) {
+			if err := m.ServeStdio(context.Background(), os.Stdin, os.Stdout); err != nil && !errors.Is(err, io.EOF) {
+				m.logger.Error(err)
+			}
+			return
+		}
+
+		path := m.options.Path
+		if utils.IsEmpty(path) {
+			path = McpServerDefaultPath
+		}
+		if utils.IsEmpty(m.options.Calls.Token) {
+			m.logger.Warn("MCP Server has no bearer token, calls are not authenticated")
+		}
+		mux := http.NewServeMux()
+		mux.Handle(path, m)
+
+		listener, err := net.Listen("tcp", m.options.Listen)
+		if err != nil {
+			m.logger.Panic(err)
+		}
+		m.logger.Info("MCP Server is up. Listening on %s%s...", m.options.Listen, path)
+
+		if err := (&http.Server{Handler: mux}).Serve(listener); err != nil {
+			m.logger.Panic(err)
+		}
+	}(wg)
+}
+
+func NewMcpServer(options McpServerOptions, logger common.Logger) *McpServer {
+
+	return &McpServer{
+		options: options,
+		logger:  logger,
+	}
+}
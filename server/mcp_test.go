@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/tools/vendors/vendortest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mcpTestCall(t *testing.T, m *McpServer, id int, method, params string) map[string]interface{} {
+
+	b := m.Handle(context.Background(), []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"%s","params":%s}`, id, method, params)))
+	require.NotNil(t, b)
+
+	var r map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &r))
+	return r
+}
+
+func TestMcpServer(t *testing.T) {
+
+	s := vendortest.Start(t, "netbox")
+	s.Put("devices", "1", vendortest.Object{"id": 1, "name": "sw1"})
+
+	m := NewMcpServer(McpServerOptions{Name: "tools", Version: "test", Timeout: 5, Calls: CallOptions{
+		Allow:   []string{"netbox.get-*", "toLower"},
+		Vendors: map[string]interface{}{"netbox": vendors.NetboxOptions{URL: s.URL, Token: "token", Timeout: 5}},
+	}}, common.NewStdout(common.StdoutOptions{}))
+	require.NoError(t, m.loadTools())
+
+	r := mcpTestCall(t, m, 1, "initialize", `{"protocolVersion":"2025-03-26"}`)
+	assert.Equal(t, "2025-03-26", r["result"].(map[string]interface{})["protocolVersion"])
+	assert.Nil(t, m.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)))
+
+	r = mcpTestCall(t, m, 2, "tools/list", `{}`)
+	var names []string
+	for _, tool := range r["result"].(map[string]interface{})["tools"].([]interface{}) {
+		names = append(names, tool.(map[string]interface{})["name"].(string))
+	}
+	assert.Equal(t, []string{"netbox_get_devices", "toLower"}, names)
+
+	r = mcpTestCall(t, m, 3, "tools/call", `{"name":"netbox_get_devices","arguments":{"deviceID":""}}`)
+	result := r["result"].(map[string]interface{})
+	assert.Nil(t, result["isError"])
+	assert.Contains(t, result["content"].([]interface{})[0].(map[string]interface{})["text"], `"sw1"`)
+
+	r = mcpTestCall(t, m, 4, "tools/call", `{"name":"toLower","arguments":{"arg1":"ABC"}}`)
+	assert.Equal(t, "abc", r["result"].(map[string]interface{})["content"].([]interface{})[0].(map[string]interface{})["text"])
+
+	// results are not redacted, even if they contain sensitive values
+	common.AddSensitive("mcp-result-secret")
+	r = mcpTestCall(t, m, 41, "tools/call", `{"name":"toLower","arguments":{"arg1":"MCP-RESULT-SECRET"}}`)
+	assert.Equal(t, "mcp-result-secret", r["result"].(map[string]interface{})["content"].([]interface{})[0].(map[string]interface{})["text"])
+
+	r = mcpTestCall(t, m, 5, "tools/call", `{"name":"netbox_get_devices","arguments":{"deviceID":"404"}}`)
+	assert.Equal(t, true, r["result"].(map[string]interface{})["isError"])
+
+	r = mcpTestCall(t, m, 6, "tools/call", `{"name":"jira_search_issue","arguments":{}}`)
+	assert.EqualValues(t, mcpErrorInvalidParams, r["error"].(map[string]interface{})["code"])
+
+	_, _, err := callVendor(context.Background(), m.options.Calls, "netbox.get-devices", map[string]interface{}{"url": "http://example.com"}, nil, nil)
+	assert.ErrorIs(t, err, errCallNotAllowed)
+	_, err = callTemplate(context.Background(), m.options.Calls, "toUpper", []interface{}{"a"}, nil)
+	assert.ErrorIs(t, err, errCallNotAllowed)
+}
+
+func TestMcpServerAuth(t *testing.T) {
+
+	// nothing is published without allowlist
+	m := NewMcpServer(McpServerOptions{Name: "tools", Version: "test", Timeout: 5}, common.NewStdout(common.StdoutOptions{}))
+	require.NoError(t, m.loadTools())
+	assert.Empty(t, m.tools)
+	r := mcpTestCall(t, m, 1, "tools/call", `{"name":"k8s_resource_delete","arguments":{}}`)
+	assert.EqualValues(t, mcpErrorInvalidParams, r["error"].(map[string]interface{})["code"])
+
+	m.options.Calls = CallOptions{Allow: []string{"toLower"}, Token: "server-token"}
+	require.NoError(t, m.loadTools())
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{}}`
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{name: "No token", status: http.StatusUnauthorized},
+		{name: "Invalid token", header: "Bearer other", status: http.StatusUnauthorized},
+		{name: "Valid token", header: "Bearer server-token", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			r := httptest.NewRequest(http.MethodPost, McpServerDefaultPath, strings.NewReader(body))
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, r)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package vendors
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/json"
@@ -12,11 +13,12 @@ import (
 	"net/url"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	utils "github.com/devopsext/utils"
+	"github.com/devopsext/tools/common"
 )
 
 const (
@@ -25,6 +27,7 @@ const (
 	awsSTSURL               = "https://sts.us-east-1.amazonaws.com/"
 	awsEC2RegionsURL        = "https://ec2.us-east-1.amazonaws.com/?Action=DescribeRegions&Version=2016-11-15"
 	awsRoleRefreshGrace     = 5 * time.Minute
+	awsS3PageSizeMax        = 1000
 )
 
 var lf = []byte{'\n'}
@@ -37,6 +40,7 @@ type AWSOptions struct {
 	Timeout         int
 	Insecure        bool
 	AWSKeys
+	HTTPClient *http.Client
 }
 
 type AWSKeys struct {
@@ -61,12 +65,12 @@ func newAWSBase(account string, opts AWSOptions) *awsBase {
 		account:    account,
 		staticKeys: AWSKeys{AccessKey: opts.AccessKey, SecretKey: opts.SecretKey},
 		opts:       opts,
-		client:     utils.NewHttpClient(opts.Timeout, opts.Insecure),
+		client:     common.VendorHttpClient(opts.HTTPClient, "aws", opts.Timeout, opts.Insecure),
 	}
 }
 
 // keys returns valid credentials. If a role is configured it assumes/refreshes as needed.
-func (b *awsBase) keys() (*AWSKeys, error) {
+func (b *awsBase) keys(ctx context.Context) (*AWSKeys, error) {
 	if b.opts.Role == "" {
 		return &b.staticKeys, nil
 	}
@@ -75,7 +79,7 @@ func (b *awsBase) keys() (*AWSKeys, error) {
 	if !b.roleExpiry.IsZero() && time.Now().Before(b.roleExpiry.Add(-awsRoleRefreshGrace)) {
 		return &b.roleKeys, nil
 	}
-	if err := b.assumeRole(); err != nil {
+	if err := b.assumeRole(ctx); err != nil {
 		return nil, err
 	}
 	return &b.roleKeys, nil
@@ -83,7 +87,7 @@ func (b *awsBase) keys() (*AWSKeys, error) {
 
 // assumeRole calls STS AssumeRole and stores the resulting temporary credentials.
 // Must be called with b.mu held.
-func (b *awsBase) assumeRole() error {
+func (b *awsBase) assumeRole(ctx context.Context) error {
 	sessionName := b.opts.RoleSessionName
 	if sessionName == "" {
 		sessionName = "tools_session"
@@ -96,7 +100,7 @@ func (b *awsBase) assumeRole() error {
 		"%s?Action=AssumeRole&Version=2011-06-15&RoleSessionName=%s&RoleArn=arn:aws:iam::%s:role/%s&DurationSeconds=%d",
 		awsSTSURL, sessionName, b.account, b.opts.Role, duration,
 	)
-	req, err := http.NewRequest("GET", rawURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
 	if err != nil {
 		return err
 	}
@@ -123,12 +127,12 @@ func (b *awsBase) assumeRole() error {
 }
 
 // accountID fetches the caller's AWS account ID via STS GetCallerIdentity.
-func (b *awsBase) accountID() (string, error) {
-	keys, err := b.keys()
+func (b *awsBase) accountID(ctx context.Context) (string, error) {
+	keys, err := b.keys(ctx)
 	if err != nil {
 		return "", err
 	}
-	req, err := http.NewRequest("GET", awsSTSURL+"?Action=GetCallerIdentity&Version=2011-06-15", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", awsSTSURL+"?Action=GetCallerIdentity&Version=2011-06-15", nil)
 	if err != nil {
 		return "", err
 	}
@@ -149,12 +153,12 @@ func (b *awsBase) accountID() (string, error) {
 }
 
 // regions returns the list of available EC2 regions for this account.
-func (b *awsBase) regions() ([]AWSRegion, error) {
-	keys, err := b.keys()
+func (b *awsBase) regions(ctx context.Context) ([]AWSRegion, error) {
+	keys, err := b.keys(ctx)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", awsEC2RegionsURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", awsEC2RegionsURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -214,7 +218,8 @@ func NewAWSEC2(opts AWSOptions) (*AWSEC2, error) {
 	return &AWSEC2{bases: bases}, nil
 }
 
-func (e *AWSEC2) GetAllAWSEC2Instances() ([]AWSEC2Instance, error) {
+func (e *AWSEC2) GetAllAWSEC2InstancesContext(ctx context.Context) (_ []AWSEC2Instance, err error) {
+	defer vendorError("aws", "get-all-awsec2-instances", nil, &err)
 	var (
 		mu        sync.Mutex
 		wg        sync.WaitGroup
@@ -223,11 +228,11 @@ func (e *AWSEC2) GetAllAWSEC2Instances() ([]AWSEC2Instance, error) {
 	)
 
 	for _, base := range e.bases {
-		regions, err := base.regions()
+		regions, err := base.regions(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("account %s: failed to list regions: %w", base.account, err)
 		}
-		accountID, err := base.accountID()
+		accountID, err := base.accountID(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("account %s: failed to get account ID: %w", base.account, err)
 		}
@@ -235,7 +240,7 @@ func (e *AWSEC2) GetAllAWSEC2Instances() ([]AWSEC2Instance, error) {
 			wg.Add(1)
 			go func(b *awsBase, region AWSRegion, accountID string) {
 				defer wg.Done()
-				got, err := fetchEC2Instances(b, region, accountID)
+				got, err := fetchEC2Instances(ctx, b, region, accountID)
 				mu.Lock()
 				defer mu.Unlock()
 				if err != nil {
@@ -254,13 +259,17 @@ func (e *AWSEC2) GetAllAWSEC2Instances() ([]AWSEC2Instance, error) {
 	return instances, nil
 }
 
-func fetchEC2Instances(b *awsBase, region AWSRegion, accountID string) ([]AWSEC2Instance, error) {
-	keys, err := b.keys()
+func (e *AWSEC2) GetAllAWSEC2Instances() ([]AWSEC2Instance, error) {
+	return e.GetAllAWSEC2InstancesContext(context.Background())
+}
+
+func fetchEC2Instances(ctx context.Context, b *awsBase, region AWSRegion, accountID string) ([]AWSEC2Instance, error) {
+	keys, err := b.keys(ctx)
 	if err != nil {
 		return nil, err
 	}
 	rawURL := "https://" + region.RegionEndpoint + "/?Action=DescribeInstances&Version=2016-11-15"
-	req, err := http.NewRequest("GET", rawURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -317,56 +326,89 @@ func NewAWSS3(opts AWSOptions) (*AWSS3, error) {
 	return &AWSS3{base: newAWSBase(account, opts)}, nil
 }
 
-func (s *AWSS3) ListObjects(region, bucket, prefix string) ([]byte, error) {
-	keys, err := s.base.keys()
-	if err != nil {
-		return nil, err
-	}
-	rawURL := fmt.Sprintf("https://s3.%s.amazonaws.com/%s?list-type=2", region, bucket)
-	if prefix != "" {
-		rawURL += "&prefix=" + url.QueryEscape(prefix)
-	}
-	req, err := http.NewRequest("GET", rawURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("x-amz-content-sha256", fmt.Sprintf("%x", sha256.Sum256(nil)))
-	resp, err := s.base.do(req, keys)
+type AWSS3Object struct {
+	Key          string `xml:"Key"`
+	Size         int64  `xml:"Size"`
+	LastModified string `xml:"LastModified"`
+}
+
+// ListObjectsContext returns all objects of bucket with prefix
+func (s *AWSS3) ListObjectsContext(ctx context.Context, region, bucket, prefix string) ([]byte, error) {
+	return s.ListObjectsPagesContext(ctx, region, bucket, prefix, PageOptions{All: true})
+}
+
+// ListObjectsPagesContext returns objects of pages, continuation token of response is cursor of the next page
+func (s *AWSS3) ListObjectsPagesContext(ctx context.Context, region, bucket, prefix string, paging PageOptions) (b []byte, err error) {
+	defer vendorError("aws", "list-objects", &b, &err)
+	keys, err := s.base.keys(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
+
+	objects, err := PagesAll(ctx, paging, func(ctx context.Context, token string) (*Page[AWSS3Object], error) {
+
+		params := url.Values{}
+		params.Set("list-type", "2")
+		params.Set("max-keys", strconv.Itoa(paging.pageSize(awsS3PageSizeMax)))
+		if prefix != "" {
+			params.Set("prefix", prefix)
+		}
+		if token != "" {
+			params.Set("continuation-token", token)
+		}
+		rawURL := fmt.Sprintf("https://s3.%s.amazonaws.com/%s?%s", region, bucket, params.Encode())
+		req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("x-amz-content-sha256", fmt.Sprintf("%x", sha256.Sum256(nil)))
+		resp, err := s.base.do(req, keys)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode >= 400 {
+			return nil, common.NewVendorError("aws", "list-objects", resp.StatusCode, resp.Header, body)
+		}
+
+		type listResult struct {
+			Contents              []AWSS3Object `xml:"Contents"`
+			IsTruncated           bool          `xml:"IsTruncated"`
+			NextContinuationToken string        `xml:"NextContinuationToken"`
+		}
+		var result listResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("S3 ListObjects: failed to parse response: %w", err)
+		}
+		page := &Page[AWSS3Object]{Items: result.Contents}
+		if result.IsTruncated {
+			page.Next = result.NextContinuationToken
+		}
+		return page, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("S3 ListObjects: status %d: %s", resp.StatusCode, string(body))
-	}
+	return json.Marshal(objects)
+}
 
-	type s3Object struct {
-		Key          string `xml:"Key"`
-		Size         int64  `xml:"Size"`
-		LastModified string `xml:"LastModified"`
-	}
-	type listResult struct {
-		Contents []s3Object `xml:"Contents"`
-	}
-	var result listResult
-	if err := xml.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("S3 ListObjects: failed to parse response: %w", err)
-	}
-	return json.Marshal(result.Contents)
+func (s *AWSS3) ListObjects(region, bucket, prefix string) ([]byte, error) {
+	return s.ListObjectsContext(context.Background(), region, bucket, prefix)
 }
 
-// GetObject downloads the object at s3://{bucket}/{key} and returns its body.
-func (s *AWSS3) GetObject(region, bucket, key string) ([]byte, error) {
-	keys, err := s.base.keys()
+// GetObjectContext downloads the object at s3://{bucket}/{key} and returns its body.
+func (s *AWSS3) GetObjectContext(ctx context.Context, region, bucket, key string) (b []byte, err error) {
+	defer vendorError("aws", "get-object", &b, &err)
+	keys, err := s.base.keys(ctx)
 	if err != nil {
 		return nil, err
 	}
 	rawURL := fmt.Sprintf("https://s3.%s.amazonaws.com/%s/%s", region, bucket, key)
-	req, err := http.NewRequest("GET", rawURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -381,19 +423,24 @@ func (s *AWSS3) GetObject(region, bucket, key string) ([]byte, error) {
 		return nil, err
 	}
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("S3 GetObject: status %d: %s", resp.StatusCode, string(body))
+		return nil, common.NewVendorError("aws", "get-object", resp.StatusCode, resp.Header, body)
 	}
 	return body, nil
 }
 
-// PutObject uploads body to s3://{bucket}/{key} in the given region.
-func (s *AWSS3) PutObject(region, bucket, key, contentType string, body []byte) ([]byte, error) {
-	keys, err := s.base.keys()
+func (s *AWSS3) GetObject(region, bucket, key string) ([]byte, error) {
+	return s.GetObjectContext(context.Background(), region, bucket, key)
+}
+
+// PutObjectContext uploads body to s3://{bucket}/{key} in the given region.
+func (s *AWSS3) PutObjectContext(ctx context.Context, region, bucket, key, contentType string, body []byte) (b []byte, err error) {
+	defer vendorError("aws", "put-object", &b, &err)
+	keys, err := s.base.keys(ctx)
 	if err != nil {
 		return nil, err
 	}
 	rawURL := fmt.Sprintf("https://s3.%s.amazonaws.com/%s/%s", region, bucket, key)
-	req, err := http.NewRequest("PUT", rawURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, "PUT", rawURL, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -412,11 +459,37 @@ func (s *AWSS3) PutObject(region, bucket, key, contentType string, body []byte)
 		return nil, err
 	}
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("S3 PutObject: status %d: %s", resp.StatusCode, string(respBody))
+		return nil, common.NewVendorError("aws", "put-object", resp.StatusCode, resp.Header, respBody)
 	}
 	return respBody, nil
 }
 
+func (s *AWSS3) PutObject(region, bucket, key, contentType string, body []byte) ([]byte, error) {
+	return s.PutObjectContext(context.Background(), region, bucket, key, contentType, body)
+}
+
+// OutputSink implements common.OutputSink for targets like s3://bucket/path/key?region=eu-west-1
+func (s *AWSS3) OutputSink(region string) common.OutputSink {
+
+	return common.OutputSinkFunc(func(u *url.URL, data []byte) error {
+
+		if r := u.Query().Get("region"); r != "" {
+			region = r
+		}
+		key := strings.TrimPrefix(u.Path, "/")
+		if u.Host == "" || key == "" {
+			return fmt.Errorf("S3 output requires bucket and key, e.g. s3://bucket/key")
+		}
+
+		contentType := "text/plain"
+		if json.Valid(data) {
+			contentType = "application/json"
+		}
+		_, err := s.PutObject(region, u.Host, key, contentType, data)
+		return err
+	})
+}
+
 // ---- XML response types ------------------------------------------------
 
 type AWSRegion struct {
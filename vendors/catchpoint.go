@@ -3,6 +3,7 @@ package vendors
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -33,6 +34,7 @@ type Catchpoint struct {
 }
 
 type CatchpointOptions struct {
+	URL        string // io.catchpoint.com by default
 	APIToken   string
 	Timeout    int
 	Insecure   bool
@@ -243,8 +245,16 @@ type TestSummary struct {
 	PublicLink   string `json:"public_link"`
 }
 
+func (c *Catchpoint) baseURL() string {
+
+	if utils.IsEmpty(c.options.URL) {
+		return catchpointAPIURL
+	}
+	return strings.TrimSuffix(c.options.URL, "/") + "/api/"
+}
+
 func (c *Catchpoint) apiURL(cmd string) string {
-	return catchpointAPIURL + catchpointAPIVersion + "/" + cmd
+	return c.baseURL() + catchpointAPIVersion + "/" + cmd
 }
 
 func (c *Catchpoint) getAuth(opts CatchpointOptions) string {
@@ -257,29 +267,62 @@ func (c *Catchpoint) getAuth(opts CatchpointOptions) string {
 	return auth
 }
 
+func (c *Catchpoint) messages(messages ...*[]CatchpointMessage) string {
+
+	var r []string
+	for _, m := range messages {
+		if m == nil {
+			continue
+		}
+		for _, v := range *m {
+			r = append(r, v.Message)
+		}
+	}
+	return strings.Join(r, "; ")
+}
+
+// CheckError returns common.VendorError with messages and trace ID of response, status is taken from e
 func (c *Catchpoint) CheckError(data []byte, e error) error {
 	if e != nil && strings.Contains(e.Error(), "max retries exceeded") {
-		return fmt.Errorf("max retries exceeded")
+		return &common.VendorError{Vendor: "catchpoint", Status: http.StatusTooManyRequests, Message: "max retries exceeded", Retryable: true, Err: e}
 	}
 
 	r := &CatchpointReponse{}
 	err := json.Unmarshal(data, &r)
 	if err != nil {
+		if e != nil {
+			return common.VendorErrorFrom("catchpoint", "", data, e)
+		}
 		return fmt.Errorf("error parsing response: %s", err)
 	}
 
 	if r.Errors != nil && len(*r.Errors) != 0 || !r.Completed {
-		return fmt.Errorf("%s", r.Messages)
+		ve := &common.VendorError{Vendor: "catchpoint", Err: e}
+		if e != nil {
+			errors.As(common.VendorErrorFrom("catchpoint", "", nil, e), &ve)
+		}
+		ve.Message = c.messages(r.Errors, r.Messages)
+		if !utils.IsEmpty(r.TraceId) {
+			ve.RequestID = r.TraceId
+		}
+		return ve
 	}
-	return e
+	return common.VendorErrorFrom("catchpoint", "", data, e)
 }
 
 func (c *Catchpoint) GetNodesFromGroup(options CatchpointNodeGroup) ([]byte, error) {
 	return c.CustomGetNodesFromGroup(c.options, options)
 }
 
+func (c *Catchpoint) CustomGetNodesFromGroupContext(ctx context.Context, catchpointOptions CatchpointOptions, options CatchpointNodeGroup) (b []byte, err error) {
+	c = c.withContext(ctx)
+	defer vendorError("catchpoint", "get-nodes-from-group", &b, &err)
+
+	return common.HttpGetRawRetry(c.client, c.apiURL(catchpointAPINodesGroups+fmt.Sprintf("%d", options.ID)), "application/json", c.getAuth(catchpointOptions), catchpointOptions.Retries, catchpointRetryHeader)
+}
+
 func (c *Catchpoint) CustomGetNodesFromGroup(catchpointOptions CatchpointOptions, options CatchpointNodeGroup) ([]byte, error) {
-	return utils.HttpGetRawRetry(c.client, c.apiURL(catchpointAPINodesGroups+fmt.Sprintf("%d", options.ID)), "application/json", c.getAuth(catchpointOptions), catchpointOptions.Retries, catchpointRetryHeader)
+	return c.CustomGetNodesFromGroupContext(context.Background(), catchpointOptions, options)
 }
 
 func (c *Catchpoint) InstantTest(options CatchpointInstantTestOptions) ([]byte, error) {
@@ -346,7 +389,7 @@ func (c *Catchpoint) WaitPollSuccessOrCancelDetailed(ctx context.Context, pollDe
 					}{nodeID, false}
 					return
 				case <-ticker.C:
-					d, err := c.CustomGetInstantTestResult(catchpointOptions, strTestId, nodeID)
+					d, err := c.CustomGetInstantTestResultContext(ctx, catchpointOptions, strTestId, nodeID)
 					err = c.CheckError(d, err)
 					if err != nil {
 						continue
@@ -422,7 +465,7 @@ func (c *Catchpoint) WaitPollSuccessOrCancel(ctx context.Context, pollDelay int,
 					return
 				case <-ticker.C:
 
-					d, err := c.CustomGetInstantTestResult(catchpointOptions, strTestId, nodeID)
+					d, err := c.CustomGetInstantTestResultContext(ctx, catchpointOptions, strTestId, nodeID)
 					err = c.CheckError(d, err)
 					if err != nil {
 						continue
@@ -464,14 +507,17 @@ func (c *Catchpoint) WaitPollSuccessOrCancel(ctx context.Context, pollDelay int,
 	return allReady
 }
 
-func (c *Catchpoint) GetLogReport(catchpointOptions CatchpointOptions, testID int, nodes []*Node) (*[]CatchpointInstantTestResultReponse, error) {
+func (c *Catchpoint) GetLogReportContext(ctx context.Context, catchpointOptions CatchpointOptions, testID int, nodes []*Node) (_ *[]CatchpointInstantTestResultReponse, err error) {
+
+	c = c.withContext(ctx)
+	defer vendorError("catchpoint", "get-log-report", nil, &err)
 
 	var reportOpts []CatchpointInstantTestResultReponse
 	strTestId := strconv.Itoa(testID)
 
 	for _, node := range nodes {
 
-		d, err := c.CustomGetInstantTestResult(catchpointOptions, strTestId, node.ID)
+		d, err := c.CustomGetInstantTestResultContext(ctx, catchpointOptions, strTestId, node.ID)
 		if err != nil {
 			return nil, c.CheckError(d, err)
 		}
@@ -489,6 +535,10 @@ func (c *Catchpoint) GetLogReport(catchpointOptions CatchpointOptions, testID in
 	return &reportOpts, nil
 }
 
+func (c *Catchpoint) GetLogReport(catchpointOptions CatchpointOptions, testID int, nodes []*Node) (*[]CatchpointInstantTestResultReponse, error) {
+	return c.GetLogReportContext(context.Background(), catchpointOptions, testID, nodes)
+}
+
 func (c *Catchpoint) GenerateSummary(results *[]CatchpointInstantTestResultReponse) ([]TestSummary, error) {
 	var summaries []TestSummary
 
@@ -529,9 +579,12 @@ func (c *Catchpoint) GenerateSummary(results *[]CatchpointInstantTestResultRepon
 	return summaries, nil
 }
 
-func (c *Catchpoint) CustomGetInstantTestResult(catchpointOptions CatchpointOptions, testID string, nodeID int) ([]byte, error) {
+func (c *Catchpoint) CustomGetInstantTestResultContext(ctx context.Context, catchpointOptions CatchpointOptions, testID string, nodeID int) (b []byte, err error) {
+
+	c = c.withContext(ctx)
+	defer vendorError("catchpoint", "get-instant-test-result", &b, &err)
 
-	u, err := url.Parse(catchpointAPIURL + catchpointAPIVersion)
+	u, err := url.Parse(c.baseURL() + catchpointAPIVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -542,10 +595,17 @@ func (c *Catchpoint) CustomGetInstantTestResult(catchpointOptions CatchpointOpti
 	params.Add("nodeId", strconv.Itoa(nodeID))
 	u.RawQuery = params.Encode()
 
-	return utils.HttpGetRawRetry(c.client, u.String(), "application/json", c.getAuth(catchpointOptions), catchpointOptions.Retries, catchpointRetryHeader)
+	return common.HttpGetRawRetry(c.client, u.String(), "application/json", c.getAuth(catchpointOptions), catchpointOptions.Retries, catchpointRetryHeader)
 }
 
-func (c *Catchpoint) CustomSearchNodesWithOptions(catchpointOptions CatchpointOptions, catchpointNodesGetAllOptions CatchpointSearchNodesWithOptions) ([]byte, error) {
+func (c *Catchpoint) CustomGetInstantTestResult(catchpointOptions CatchpointOptions, testID string, nodeID int) ([]byte, error) {
+	return c.CustomGetInstantTestResultContext(context.Background(), catchpointOptions, testID, nodeID)
+}
+
+func (c *Catchpoint) CustomSearchNodesWithOptionsContext(ctx context.Context, catchpointOptions CatchpointOptions, catchpointNodesGetAllOptions CatchpointSearchNodesWithOptions) (b []byte, err error) {
+
+	c = c.withContext(ctx)
+	defer vendorError("catchpoint", "search-nodes-with-options", &b, &err)
 
 	params := make(url.Values)
 	if !utils.IsEmpty(catchpointNodesGetAllOptions.Name) {
@@ -585,7 +645,7 @@ func (c *Catchpoint) CustomSearchNodesWithOptions(catchpointOptions CatchpointOp
 		params.Add("pageSize", strconv.Itoa(catchpointNodesGetAllOptions.PageSize))
 	}
 
-	u, err := url.Parse(catchpointAPIURL + catchpointAPIVersion)
+	u, err := url.Parse(c.baseURL() + catchpointAPIVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -593,10 +653,17 @@ func (c *Catchpoint) CustomSearchNodesWithOptions(catchpointOptions CatchpointOp
 	u.Path = path.Join(u.Path, catchpointAPINodesAll)
 	u.RawQuery = params.Encode()
 
-	return utils.HttpGetRawRetry(c.client, u.String(), "application/json", c.getAuth(catchpointOptions), catchpointOptions.Retries, catchpointRetryHeader)
+	return common.HttpGetRawRetry(c.client, u.String(), "application/json", c.getAuth(catchpointOptions), catchpointOptions.Retries, catchpointRetryHeader)
 }
 
-func (c *Catchpoint) CustomInstantTestWithNodeGroup(catchpointOptions CatchpointOptions, catchpointInstantTestWithNodeGroupOptions CatchpointInstantTestWithNodeGroupOptions) ([]byte, error) {
+func (c *Catchpoint) CustomSearchNodesWithOptions(catchpointOptions CatchpointOptions, catchpointNodesGetAllOptions CatchpointSearchNodesWithOptions) ([]byte, error) {
+	return c.CustomSearchNodesWithOptionsContext(context.Background(), catchpointOptions, catchpointNodesGetAllOptions)
+}
+
+func (c *Catchpoint) CustomInstantTestWithNodeGroupContext(ctx context.Context, catchpointOptions CatchpointOptions, catchpointInstantTestWithNodeGroupOptions CatchpointInstantTestWithNodeGroupOptions) (b []byte, err error) {
+
+	c = c.withContext(ctx)
+	defer vendorError("catchpoint", "instant-test-with-node-group", &b, &err)
 
 	nodeIDsBytes, err := c.GetNodesFromGroup(CatchpointNodeGroup{ID: catchpointInstantTestWithNodeGroupOptions.NodeGroupID})
 	if err != nil {
@@ -632,7 +699,7 @@ func (c *Catchpoint) CustomInstantTestWithNodeGroup(catchpointOptions Catchpoint
 	params := make(url.Values)
 	params.Add("onDemand", strconv.FormatBool(catchpointInstantTestWithNodeGroupOptions.OnDemand))
 
-	u, err := url.Parse(catchpointAPIURL + catchpointAPIVersion)
+	u, err := url.Parse(c.baseURL() + catchpointAPIVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -640,15 +707,22 @@ func (c *Catchpoint) CustomInstantTestWithNodeGroup(catchpointOptions Catchpoint
 	u.Path = path.Join(u.Path, catchpointAPIInstantTest)
 	u.RawQuery = params.Encode()
 
-	return utils.HttpPostRawRetry(c.client, u.String(), "application/json", c.getAuth(catchpointOptions), req, catchpointOptions.Retries, catchpointRetryHeader)
+	return common.HttpPostRawRetry(c.client, u.String(), "application/json", c.getAuth(catchpointOptions), req, catchpointOptions.Retries, catchpointRetryHeader)
 }
 
-func (c *Catchpoint) CustomInstantTest(catchpointOptions CatchpointOptions, catchpointInstantTestOptions CatchpointInstantTestOptions) ([]byte, error) {
+func (c *Catchpoint) CustomInstantTestWithNodeGroup(catchpointOptions CatchpointOptions, catchpointInstantTestWithNodeGroupOptions CatchpointInstantTestWithNodeGroupOptions) ([]byte, error) {
+	return c.CustomInstantTestWithNodeGroupContext(context.Background(), catchpointOptions, catchpointInstantTestWithNodeGroupOptions)
+}
+
+func (c *Catchpoint) CustomInstantTestContext(ctx context.Context, catchpointOptions CatchpointOptions, catchpointInstantTestOptions CatchpointInstantTestOptions) (b []byte, err error) {
+
+	c = c.withContext(ctx)
+	defer vendorError("catchpoint", "instant-test", &b, &err)
 
 	params := make(url.Values)
 	params.Add("onDemand", strconv.FormatBool(catchpointInstantTestOptions.OnDemand))
 
-	u, err := url.Parse(catchpointAPIURL + catchpointAPIVersion)
+	u, err := url.Parse(c.baseURL() + catchpointAPIVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -675,15 +749,61 @@ func (c *Catchpoint) CustomInstantTest(catchpointOptions CatchpointOptions, catc
 		return nil, err
 	}
 
-	return utils.HttpPostRawRetry(c.client, u.String(), "application/json", c.getAuth(catchpointOptions), req, catchpointOptions.Retries, catchpointRetryHeader)
+	return common.HttpPostRawRetry(c.client, u.String(), "application/json", c.getAuth(catchpointOptions), req, catchpointOptions.Retries, catchpointRetryHeader)
 }
 
-func NewCatchpoint(options CatchpointOptions, logger common.Logger) *Catchpoint {
+func (c *Catchpoint) CustomInstantTest(catchpointOptions CatchpointOptions, catchpointInstantTestOptions CatchpointInstantTestOptions) ([]byte, error) {
+	return c.CustomInstantTestContext(context.Background(), catchpointOptions, catchpointInstantTestOptions)
+}
+
+func (c *Catchpoint) Name() string {
+	return "catchpoint"
+}
+
+func (c *Catchpoint) Options() interface{} {
+	return c.options
+}
+
+func (c *Catchpoint) Check(ctx context.Context) error {
+
+	_, err := c.CustomSearchNodesWithOptionsContext(ctx, c.options, CatchpointSearchNodesWithOptions{PageNumber: 1, PageSize: 1})
+	return err
+}
+
+func (c *Catchpoint) Operations() []VendorOperation {
 
-	client := options.HTTPClient
-	if client == nil {
-		client = utils.NewHttpClient(options.Timeout, options.Insecure)
+	return []VendorOperation{
+		NewVendorOperation("get-nodes-from-group", "Get nodes of node group", VendorOutputJSON, func(ctx context.Context, input CatchpointNodeGroup) ([]byte, error) {
+			return c.CustomGetNodesFromGroupContext(ctx, c.options, input)
+		}),
+		NewVendorOperation("search-nodes", "Search nodes", VendorOutputJSON, func(ctx context.Context, input CatchpointSearchNodesWithOptions) ([]byte, error) {
+			return c.CustomSearchNodesWithOptionsContext(ctx, c.options, input)
+		}),
+		NewVendorOperation("instant-test", "Run instant test on nodes", VendorOutputJSON, func(ctx context.Context, input CatchpointInstantTestOptions) ([]byte, error) {
+			return c.CustomInstantTestContext(ctx, c.options, input)
+		}),
+		NewVendorOperation("instant-test-with-node-group", "Run instant test on node group", VendorOutputJSON, func(ctx context.Context, input CatchpointInstantTestWithNodeGroupOptions) ([]byte, error) {
+			return c.CustomInstantTestWithNodeGroupContext(ctx, c.options, input)
+		}),
+		NewVendorOperation("get-instant-test-result", "Get instant test result of node", VendorOutputJSON, func(ctx context.Context, input struct {
+			TestID string
+			NodeID int
+		}) ([]byte, error) {
+			return c.CustomGetInstantTestResultContext(ctx, c.options, input.TestID, input.NodeID)
+		}),
 	}
+}
+
+func (c *Catchpoint) withContext(ctx context.Context) *Catchpoint {
+
+	v := *c
+	v.client = common.HttpClientWithContext(ctx, c.client)
+	return &v
+}
+
+func NewCatchpoint(options CatchpointOptions, logger common.Logger) *Catchpoint {
+
+	client := common.VendorHttpClient(options.HTTPClient, "catchpoint", options.Timeout, options.Insecure)
 
 	catchpoint := &Catchpoint{
 		client:  client,
@@ -6,8 +6,13 @@ import (
 	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
 	"errors"
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
 )
 
 type CryptoRCAKeyOptions struct {
@@ -95,6 +100,36 @@ func (c *Crypto) CustomRCADecrypt(decryptOptions CryptoRCADecryptOptions) ([]byt
 	return []byte(text), nil
 }
 
+// RCASecretBackend returns secret backend which decrypts base64 ciphertext with private key (content or file)
+func (c *Crypto) RCASecretBackend(privateKey string) common.SecretBackend {
+
+	return common.SecretBackendFunc(func(ref string) (string, error) {
+
+		if utils.IsEmpty(privateKey) {
+			return "", errors.New("private key is empty")
+		}
+
+		key, err := utils.Content(privateKey)
+		if err != nil {
+			return "", err
+		}
+
+		text, err := base64.StdEncoding.DecodeString(strings.TrimSpace(ref))
+		if err != nil {
+			return "", err
+		}
+
+		b, err := c.CustomRCADecrypt(CryptoRCADecryptOptions{
+			Text:       string(text),
+			PrivateKey: string(key),
+		})
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	})
+}
+
 func NewCrypto() *Crypto {
 	return &Crypto{}
 }
@@ -0,0 +1,60 @@
+package vendors
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devopsext/tools/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRCASecretBackend(t *testing.T) {
+
+	crypto := NewCrypto()
+	keys, err := crypto.CustomRCAGenerateKey(CryptoRCAKeyOptions{Size: 1024})
+	require.NoError(t, err)
+
+	// generated keys are private and public PEM blocks
+	private, rest := pem.Decode(keys)
+	require.NotNil(t, private)
+	public, _ := pem.Decode(rest)
+	require.NotNil(t, public)
+
+	encrypted, err := crypto.CustomRCAEncrypt(CryptoRCAEncryptOptions{Text: "jira-token", PublicKey: string(pem.EncodeToMemory(public))})
+	require.NoError(t, err)
+	ref := base64.StdEncoding.EncodeToString(encrypted)
+
+	file := filepath.Join(t.TempDir(), "private.pem")
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(private), 0600))
+
+	tests := []struct {
+		name     string
+		key      string
+		ref      string
+		expected string
+		err      bool
+	}{
+		{name: "Key content", key: string(pem.EncodeToMemory(private)), ref: ref, expected: "jira-token"},
+		{name: "Key file", key: file, ref: " " + ref + "\n", expected: "jira-token"},
+		{name: "Empty key", ref: ref, err: true},
+		{name: "Public key", key: string(pem.EncodeToMemory(public)), ref: ref, err: true},
+		{name: "Invalid base64", key: file, ref: "not base64!", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			common.RegisterSecretBackend("rsa", crypto.RCASecretBackend(tt.key))
+			r, err := common.ResolveSecret(common.SecretPrefix + "rsa:" + tt.ref)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, r)
+		})
+	}
+}
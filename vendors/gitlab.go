@@ -1,6 +1,7 @@
 package vendors
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,17 +9,22 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
 )
 
+const gitlabPageSizeMax = 100
+
 type GitlabOptions struct {
-	Timeout  int
-	Insecure bool
-	URL      string
-	Token    string
+	Timeout    int
+	Insecure   bool
+	URL        string
+	Token      string
+	HTTPClient *http.Client
 }
 
 type Gitlab struct {
@@ -34,7 +40,7 @@ type GitlabPipelineOptions struct {
 	Ref       string
 	OrderBy   string
 	Sort      string
-	Limit     int
+	PageOptions
 }
 
 type GitlabGetPipelineVariablesOptions struct {
@@ -61,28 +67,33 @@ type GitlabPipelineVariableResp struct {
 }
 
 func (g *Gitlab) get(url string) ([]byte, error) {
+	b, _, err := g.getWithHeader(url, g.options.Token)
+	return b, err
+}
+
+// getWithHeader returns body and headers, Link header has URL of the next page
+func (g *Gitlab) getWithHeader(url, token string) ([]byte, http.Header, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	req.Header.Add("PRIVATE-TOKEN", g.options.Token)
+	req.Header.Add("PRIVATE-TOKEN", token)
 
 	resp, err := g.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("%s", resp.Status)
-	}
-
 	b, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return b, nil
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, common.NewVendorError("gitlab", "", resp.StatusCode, resp.Header, b)
+	}
+	return b, resp.Header, nil
 }
 
 func (g Gitlab) getLastPipeline(project int, ref string) (*GitlabPipelinesResp, error) {
@@ -119,18 +130,25 @@ func (g Gitlab) getLastPipeline(project int, ref string) (*GitlabPipelinesResp,
 	return &pipelines[0], nil
 }
 
-func (g Gitlab) GetLastPipeline(project int, ref string) ([]byte, error) {
+func (g *Gitlab) GetLastPipelineContext(ctx context.Context, project int, ref string) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("gitlab", "get-last-pipeline", &b, &err)
+
 	p, err := g.getLastPipeline(project, ref)
 	if err != nil {
 		return nil, err
 	}
-	b, err := json.Marshal(p)
+	b, err = json.Marshal(p)
 	if err != nil {
 		return nil, err
 	}
 	return b, nil
 }
 
+func (g Gitlab) GetLastPipeline(project int, ref string) ([]byte, error) {
+	return g.GetLastPipelineContext(context.Background(), project, ref)
+}
+
 func (g Gitlab) getPipelineVariables(project int, pipeline int) (map[string]interface{}, error) {
 	u, err := url.Parse(g.options.URL)
 	if err != nil {
@@ -171,7 +189,10 @@ func (g Gitlab) getPipelineVariables(project int, pipeline int) (map[string]inte
 	return data["variables"].(map[string]interface{}), nil
 }
 
-func (g Gitlab) GetLastPipelineVariables(project int, ref string) ([]byte, error) {
+func (g *Gitlab) GetLastPipelineVariablesContext(ctx context.Context, project int, ref string) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("gitlab", "get-last-pipeline-variables", &b, &err)
+
 	pipeline, err := g.getLastPipeline(project, ref)
 	if err != nil {
 		return nil, err
@@ -182,14 +203,19 @@ func (g Gitlab) GetLastPipelineVariables(project int, ref string) ([]byte, error
 		return nil, err
 	}
 
-	b, err := json.Marshal(v)
+	b, err = json.Marshal(v)
 	if err != nil {
 		return nil, err
 	}
 	return b, nil
 }
 
-func (g *Gitlab) getPipelines(gitlabOptions GitlabOptions, pipelineOptions GitlabPipelineOptions) ([]GitlabPipelinesResp, error) {
+func (g Gitlab) GetLastPipelineVariables(project int, ref string) ([]byte, error) {
+	return g.GetLastPipelineVariablesContext(context.Background(), project, ref)
+}
+
+// pipelinesPages returns pages of pipelines, Link header is followed
+func (g *Gitlab) pipelinesPages(gitlabOptions GitlabOptions, pipelineOptions GitlabPipelineOptions) PageFetch[GitlabPipelinesResp] {
 
 	var params = make(url.Values)
 	if !utils.IsEmpty(pipelineOptions.Scope) {
@@ -211,27 +237,55 @@ func (g *Gitlab) getPipelines(gitlabOptions GitlabOptions, pipelineOptions Gitla
 		params.Add("sort", pipelineOptions.Sort)
 	}
 
-	u, err := url.Parse(gitlabOptions.URL)
-	if err != nil {
-		return nil, err
+	if pipelineOptions.Limit > 0 || pipelineOptions.All {
+		params.Add("per_page", strconv.Itoa(pipelineOptions.pageSize(gitlabPageSizeMax)))
 	}
-	u.Path = fmt.Sprintf("/api/v4/projects/%d/pipelines", pipelineOptions.ProjectID)
-	u.RawQuery = params.Encode()
 
-	headers := make(map[string]string)
-	headers["PRIVATE-TOKEN"] = gitlabOptions.Token
+	return func(ctx context.Context, token string) (*Page[GitlabPipelinesResp], error) {
 
-	b, err := utils.HttpGetRawWithHeaders(g.client, u.String(), headers)
-	if err != nil {
-		return nil, err
+		next := token
+		if utils.IsEmpty(next) {
+			u, err := url.Parse(gitlabOptions.URL)
+			if err != nil {
+				return nil, err
+			}
+			u.Path = fmt.Sprintf("/api/v4/projects/%d/pipelines", pipelineOptions.ProjectID)
+			u.RawQuery = params.Encode()
+			next = u.String()
+		}
+
+		b, header, err := g.getWithHeader(next, gitlabOptions.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		var pipelines []GitlabPipelinesResp
+		err = json.Unmarshal(b, &pipelines)
+		if err != nil {
+			return nil, err
+		}
+		return &Page[GitlabPipelinesResp]{Items: pipelines, Next: pageLinkNext(header)}, nil
 	}
+}
 
-	var pipelines []GitlabPipelinesResp
-	err = json.Unmarshal(b, &pipelines)
+func (g *Gitlab) CustomGetPipelinesContext(ctx context.Context, gitlabOptions GitlabOptions, pipelineOptions GitlabPipelineOptions) (b []byte, err error) {
+
+	g = g.withContext(ctx)
+	defer vendorError("gitlab", "get-pipelines", &b, &err)
+
+	pipelines, err := PagesAll(ctx, pipelineOptions.PageOptions, g.pipelinesPages(gitlabOptions, pipelineOptions))
 	if err != nil {
 		return nil, err
 	}
-	return pipelines, err
+	return json.Marshal(pipelines)
+}
+
+func (g *Gitlab) CustomGetPipelines(gitlabOptions GitlabOptions, pipelineOptions GitlabPipelineOptions) ([]byte, error) {
+	return g.CustomGetPipelinesContext(context.Background(), gitlabOptions, pipelineOptions)
+}
+
+func (g *Gitlab) GetPipelines(pipelineOptions GitlabPipelineOptions) ([]byte, error) {
+	return g.CustomGetPipelines(g.options, pipelineOptions)
 }
 
 func (g *Gitlab) getPipelineVariablesEx(gitlabOptions GitlabOptions, projectID, pipelineID int) ([]GitlabPipelineVariableResp, error) {
@@ -245,7 +299,7 @@ func (g *Gitlab) getPipelineVariablesEx(gitlabOptions GitlabOptions, projectID,
 	headers := make(map[string]string)
 	headers["PRIVATE-TOKEN"] = gitlabOptions.Token
 
-	b, err := utils.HttpGetRawWithHeaders(g.client, u.String(), headers)
+	b, err := common.HttpGetRawWithHeaders(g.client, u.String(), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -288,30 +342,23 @@ func (g *Gitlab) variablesExist(variables []GitlabPipelineVariableResp, query st
 	return false
 }
 
-func (g *Gitlab) CustomGetPipelineVariables(gitlabOptions GitlabOptions, pipelineOptions GitlabPipelineOptions,
-	getVariablesOptions GitlabGetPipelineVariablesOptions) ([]byte, error) {
+func (g *Gitlab) CustomGetPipelineVariablesContext(ctx context.Context, gitlabOptions GitlabOptions, pipelineOptions GitlabPipelineOptions,
+	getVariablesOptions GitlabGetPipelineVariablesOptions) (b []byte, err error) {
+
+	g = g.withContext(ctx)
+	defer vendorError("gitlab", "get-pipeline-variables", &b, &err)
 
 	// 1. get pipeline list by pipeline variable key=value
 	// 2. reverse pipeline list and get first success pipeline
 	// 3. get variables and values from
 
-	pipelines, err := g.getPipelines(gitlabOptions, pipelineOptions)
-	if err != nil {
-		return nil, err
-	}
-
-	counter := 0
-
-	for _, pipeline := range pipelines {
-
-		if pipelineOptions.Limit > 0 && counter >= pipelineOptions.Limit {
-			break
-		}
-		counter++
+	// pipelines are streamed page by page until variables are found or limit
+	var found []GitlabPipelineVariableResp
+	err = Pages(ctx, pipelineOptions.PageOptions, g.pipelinesPages(gitlabOptions, pipelineOptions), func(pipeline GitlabPipelinesResp) error {
 
 		variables, err := g.getPipelineVariablesEx(gitlabOptions, pipeline.ProjectID, pipeline.ID)
 		if err != nil {
-			continue
+			return nil
 		}
 
 		r := false
@@ -324,24 +371,85 @@ func (g *Gitlab) CustomGetPipelineVariables(gitlabOptions GitlabOptions, pipelin
 		}
 
 		if r {
-			b, err := json.Marshal(variables)
-			if err != nil {
-				return nil, err
-			}
-			return b, nil
+			found = variables
+			return errPagesStop
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil, errors.New("no pipeline or variables found")
+	if found == nil {
+		return nil, errors.New("no pipeline or variables found")
+	}
+	return json.Marshal(found)
+}
+
+func (g *Gitlab) CustomGetPipelineVariables(gitlabOptions GitlabOptions, pipelineOptions GitlabPipelineOptions,
+	getVariablesOptions GitlabGetPipelineVariablesOptions) ([]byte, error) {
+	return g.CustomGetPipelineVariablesContext(context.Background(), gitlabOptions, pipelineOptions, getVariablesOptions)
 }
 
 func (g *Gitlab) GetPipelineVariables(pipelineOptions GitlabPipelineOptions, getVariablesOptions GitlabGetPipelineVariablesOptions) ([]byte, error) {
 	return g.CustomGetPipelineVariables(g.options, pipelineOptions, getVariablesOptions)
 }
 
+func (g *Gitlab) Name() string {
+	return "gitlab"
+}
+
+func (g *Gitlab) Options() interface{} {
+	return g.options
+}
+
+func (g *Gitlab) Check(ctx context.Context) error {
+
+	u, err := url.Parse(g.options.URL)
+	if err != nil {
+		return err
+	}
+	u.Path = "/api/v4/version"
+	return checkVendorHttp(ctx, g.client, "gitlab", "GET", u.String(), map[string]string{"PRIVATE-TOKEN": g.options.Token}, nil)
+}
+
+func (g *Gitlab) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("get-pipelines", "Get pipelines of project", VendorOutputJSON, func(ctx context.Context, input GitlabPipelineOptions) ([]byte, error) {
+			return g.CustomGetPipelinesContext(ctx, g.options, input)
+		}),
+		NewVendorOperation("get-pipeline-variables", "Get variables of pipelines matching query", VendorOutputJSON, func(ctx context.Context, input struct {
+			Pipeline  GitlabPipelineOptions
+			Variables GitlabGetPipelineVariablesOptions
+		}) ([]byte, error) {
+			return g.CustomGetPipelineVariablesContext(ctx, g.options, input.Pipeline, input.Variables)
+		}),
+		NewVendorOperation("get-last-pipeline", "Get last pipeline of project ref", VendorOutputJSON, func(ctx context.Context, input struct {
+			ProjectID int
+			Ref       string
+		}) ([]byte, error) {
+			return g.GetLastPipelineContext(ctx, input.ProjectID, input.Ref)
+		}),
+		NewVendorOperation("get-last-pipeline-variables", "Get variables of last pipeline of project ref", VendorOutputJSON, func(ctx context.Context, input struct {
+			ProjectID int
+			Ref       string
+		}) ([]byte, error) {
+			return g.GetLastPipelineVariablesContext(ctx, input.ProjectID, input.Ref)
+		}),
+	}
+}
+
+func (g *Gitlab) withContext(ctx context.Context) *Gitlab {
+
+	c := *g
+	c.client = common.HttpClientWithContext(ctx, g.client)
+	return &c
+}
+
 func NewGitlab(options GitlabOptions) *Gitlab {
 
 	gitlab := &Gitlab{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "gitlab", options.Timeout, options.Insecure),
 		options: options,
 	}
 	return gitlab
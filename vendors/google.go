@@ -2,6 +2,7 @@ package vendors
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
@@ -144,6 +145,7 @@ type GoogleOptions struct {
 	RefreshToken      string
 	ServiceAccountKey string
 	ImpersonateEmail  string
+	HTTPClient        *http.Client
 }
 
 type GoogleTokenReponse struct {
@@ -262,7 +264,7 @@ func (g *Google) refreshToken(opts GoogleOptions) (*GoogleTokenReponse, error) {
 	}
 	u.Path = path.Join(u.Path, "/token")
 
-	bytes, err := utils.HttpPostRaw(g.client, u.String(), w.FormDataContentType(), "", body.Bytes())
+	bytes, err := common.HttpPostRaw(g.client, u.String(), w.FormDataContentType(), "", body.Bytes())
 	if err != nil {
 		return nil, err
 	}
@@ -433,7 +435,9 @@ func (g *Google) exchangeJWTForToken(jwt, tokenURI string) (string, error) {
 	}
 
 	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("token exchange failed: %d - %s", resp.StatusCode, tokenResponse.AccessToken)
+		e := common.NewVendorError("google", "get-access-token", resp.StatusCode, resp.Header, nil)
+		e.Message = "token exchange failed"
+		return "", e
 	}
 
 	if utils.IsEmpty(tokenResponse.AccessToken) {
@@ -505,10 +509,13 @@ func (g *Google) calendarGetEvents(token string, calendarOptions GoogleCalendarO
 	u.Path = path.Join(u.Path, fmt.Sprintf(googleCalendarEvents, calendarOptions.ID))
 	u.RawQuery = params.Encode()
 
-	return utils.HttpGetRawWithHeaders(g.client, u.String(), nil)
+	return common.HttpGetRawWithHeaders(g.client, u.String(), nil)
 }
 
-func (g *Google) CustomCalendarGetEvents(googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarGetEventsOptions GoogleCalendarGetEventsOptions) ([]byte, error) {
+func (g *Google) CustomCalendarGetEventsContext(ctx context.Context, googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarGetEventsOptions GoogleCalendarGetEventsOptions) (b []byte, err error) {
+
+	g = g.withContext(ctx)
+	defer vendorError("google", "calendar-get-events", &b, &err)
 
 	r, err := g.refreshToken(googleOptions)
 	if err != nil {
@@ -519,13 +526,20 @@ func (g *Google) CustomCalendarGetEvents(googleOptions GoogleOptions, calendarOp
 	return g.calendarGetEvents(r.AccessToken, calendarOptions, calendarGetEventsOptions)
 }
 
+func (g *Google) CustomCalendarGetEvents(googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarGetEventsOptions GoogleCalendarGetEventsOptions) ([]byte, error) {
+	return g.CustomCalendarGetEventsContext(context.Background(), googleOptions, calendarOptions, calendarGetEventsOptions)
+}
+
 func (g *Google) CalendarGetEvents(calendarOptions GoogleCalendarOptions, calendarGetEventsOptions GoogleCalendarGetEventsOptions) ([]byte, error) {
 	return g.CustomCalendarGetEvents(g.options, calendarOptions, calendarGetEventsOptions)
 }
 
 // https://developers.google.com/calendar/api/v3/reference/events/insert
 
-func (g *Google) CustomCalendarInsertEvent(googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarInsertEventOptions GoogleCalendarInsertEventOptions) ([]byte, error) {
+func (g *Google) CustomCalendarInsertEventContext(ctx context.Context, googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarInsertEventOptions GoogleCalendarInsertEventOptions) (b []byte, err error) {
+
+	g = g.withContext(ctx)
+	defer vendorError("google", "calendar-insert-event", &b, &err)
 
 	r, err := g.refreshToken(googleOptions)
 	if err != nil {
@@ -613,7 +627,11 @@ func (g *Google) CustomCalendarInsertEvent(googleOptions GoogleOptions, calendar
 	u.Path = path.Join(u.Path, fmt.Sprintf(googleCalendarEvents, calendarOptions.ID))
 	u.RawQuery = params.Encode()
 
-	return utils.HttpPostRawWithHeaders(g.client, u.String(), nil, data)
+	return common.HttpPostRawWithHeaders(g.client, u.String(), nil, data)
+}
+
+func (g *Google) CustomCalendarInsertEvent(googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarInsertEventOptions GoogleCalendarInsertEventOptions) ([]byte, error) {
+	return g.CustomCalendarInsertEventContext(context.Background(), googleOptions, calendarOptions, calendarInsertEventOptions)
 }
 
 func (g *Google) CalendarInsertEvent(calendarOptions GoogleCalendarOptions, calendarInsertEventOptions GoogleCalendarInsertEventOptions) ([]byte, error) {
@@ -637,10 +655,13 @@ func (g *Google) calendarDeleteEvent(token string, calendarOptions GoogleCalenda
 	u.Path = path.Join(u.Path, fmt.Sprintf(googleCalendarDeleteEvent, calendarOptions.ID, calendarDeleteEventOptions.ID))
 	u.RawQuery = params.Encode()
 
-	return utils.HttpDeleteRawWithHeaders(g.client, u.String(), nil, nil)
+	return common.HttpDeleteRawWithHeaders(g.client, u.String(), nil, nil)
 }
 
-func (g *Google) CustomCalendarDeleteEvent(googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarDeleteEventOptions GoogleCalendarDeleteEventOptions) ([]byte, error) {
+func (g *Google) CustomCalendarDeleteEventContext(ctx context.Context, googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarDeleteEventOptions GoogleCalendarDeleteEventOptions) (b []byte, err error) {
+
+	g = g.withContext(ctx)
+	defer vendorError("google", "calendar-delete-event", &b, &err)
 
 	r, err := g.refreshToken(googleOptions)
 	if err != nil {
@@ -651,11 +672,18 @@ func (g *Google) CustomCalendarDeleteEvent(googleOptions GoogleOptions, calendar
 	return g.calendarDeleteEvent(r.AccessToken, calendarOptions, calendarDeleteEventOptions)
 }
 
+func (g *Google) CustomCalendarDeleteEvent(googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarDeleteEventOptions GoogleCalendarDeleteEventOptions) ([]byte, error) {
+	return g.CustomCalendarDeleteEventContext(context.Background(), googleOptions, calendarOptions, calendarDeleteEventOptions)
+}
+
 func (g *Google) CalendarDeleteEvent(calendarOptions GoogleCalendarOptions, calendarDeleteEventOptions GoogleCalendarDeleteEventOptions) ([]byte, error) {
 	return g.CustomCalendarDeleteEvent(g.options, calendarOptions, calendarDeleteEventOptions)
 }
 
-func (g *Google) CustomCalendarDeleteEvents(googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarGetEventsOptions GoogleCalendarGetEventsOptions) ([]byte, error) {
+func (g *Google) CustomCalendarDeleteEventsContext(ctx context.Context, googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarGetEventsOptions GoogleCalendarGetEventsOptions) (b []byte, err error) {
+
+	g = g.withContext(ctx)
+	defer vendorError("google", "calendar-delete-events", &b, &err)
 
 	r, err := g.refreshToken(googleOptions)
 	if err != nil {
@@ -686,6 +714,10 @@ func (g *Google) CustomCalendarDeleteEvents(googleOptions GoogleOptions, calenda
 	return nil, nil
 }
 
+func (g *Google) CustomCalendarDeleteEvents(googleOptions GoogleOptions, calendarOptions GoogleCalendarOptions, calendarGetEventsOptions GoogleCalendarGetEventsOptions) ([]byte, error) {
+	return g.CustomCalendarDeleteEventsContext(context.Background(), googleOptions, calendarOptions, calendarGetEventsOptions)
+}
+
 func (g *Google) CalendarDeleteEvents(calendarOptions GoogleCalendarOptions, calendarGetEventsOptions GoogleCalendarGetEventsOptions) ([]byte, error) {
 	return g.CustomCalendarDeleteEvents(g.options, calendarOptions, calendarGetEventsOptions)
 }
@@ -716,10 +748,13 @@ func (g *Google) createMeetSpace(token string, meetOptions GoogleMeetOptions) ([
 	}
 	u.Path = path.Join(u.Path, "/spaces")
 
-	return utils.HttpPostRawWithHeaders(g.client, u.String(), headers, data)
+	return common.HttpPostRawWithHeaders(g.client, u.String(), headers, data)
 }
 
-func (g *Google) CustomCreateMeetSpace(googleOptions GoogleOptions, meetOptions GoogleMeetOptions) (*GoogleMeetSpaceResponse, error) {
+func (g *Google) CustomCreateMeetSpaceContext(ctx context.Context, googleOptions GoogleOptions, meetOptions GoogleMeetOptions) (_ *GoogleMeetSpaceResponse, err error) {
+
+	g = g.withContext(ctx)
+	defer vendorError("google", "create-meet-space", nil, &err)
 
 	accessToken, err := g.getAccessToken(googleOptions)
 	if err != nil {
@@ -741,11 +776,18 @@ func (g *Google) CustomCreateMeetSpace(googleOptions GoogleOptions, meetOptions
 	return &meetResponse, nil
 }
 
+func (g *Google) CustomCreateMeetSpace(googleOptions GoogleOptions, meetOptions GoogleMeetOptions) (*GoogleMeetSpaceResponse, error) {
+	return g.CustomCreateMeetSpaceContext(context.Background(), googleOptions, meetOptions)
+}
+
 func (g *Google) CreateMeetSpace(meetOptions GoogleMeetOptions) (*GoogleMeetSpaceResponse, error) {
 	return g.CustomCreateMeetSpace(g.options, meetOptions)
 }
 
-func (g *Google) DocsCopyDocument(docOptions GoogleDocsOptions) ([]byte, error) {
+func (g *Google) DocsCopyDocumentContext(ctx context.Context, docOptions GoogleDocsOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("google", "docs-copy-document", &b, &err)
+
 	r, err := g.refreshToken(g.options)
 	if err != nil {
 		return nil, err
@@ -763,7 +805,7 @@ func (g *Google) DocsCopyDocument(docOptions GoogleDocsOptions) ([]byte, error)
 	copyURL.Path = path.Join(copyURL.Path, "files", docOptions.ID, "copy")
 	copyURL.RawQuery = params.Encode()
 
-	copyResponseBytes, err := utils.HttpPostRawWithHeaders(g.client, copyURL.String(), nil, nil)
+	copyResponseBytes, err := common.HttpPostRawWithHeaders(g.client, copyURL.String(), nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -804,7 +846,7 @@ func (g *Google) DocsCopyDocument(docOptions GoogleDocsOptions) ([]byte, error)
 			"Content-Type": "application/json",
 		}
 
-		_, err = utils.HttpPostRawWithHeaders(g.client, permissionURL.String(), headers, permissionBodyBytes)
+		_, err = common.HttpPostRawWithHeaders(g.client, permissionURL.String(), headers, permissionBodyBytes)
 		if err != nil {
 			return nil, err
 		}
@@ -813,10 +855,75 @@ func (g *Google) DocsCopyDocument(docOptions GoogleDocsOptions) ([]byte, error)
 	return copyResponseBytes, nil
 }
 
+func (g *Google) DocsCopyDocument(docOptions GoogleDocsOptions) ([]byte, error) {
+	return g.DocsCopyDocumentContext(context.Background(), docOptions)
+}
+
+func (g *Google) Name() string {
+	return "google"
+}
+
+func (g *Google) Options() interface{} {
+	return g.options
+}
+
+func (g *Google) Check(ctx context.Context) error {
+
+	_, err := g.withContext(ctx).getAccessToken(g.options)
+	return err
+}
+
+func (g *Google) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("calendar-get-events", "Get calendar events", VendorOutputJSON, func(ctx context.Context, input struct {
+			Calendar GoogleCalendarOptions
+			Events   GoogleCalendarGetEventsOptions
+		}) ([]byte, error) {
+			return g.CustomCalendarGetEventsContext(ctx, g.options, input.Calendar, input.Events)
+		}),
+		NewVendorOperation("calendar-insert-event", "Insert calendar event", VendorOutputJSON, func(ctx context.Context, input struct {
+			Calendar GoogleCalendarOptions
+			Event    GoogleCalendarInsertEventOptions
+		}) ([]byte, error) {
+			return g.CustomCalendarInsertEventContext(ctx, g.options, input.Calendar, input.Event)
+		}),
+		NewVendorOperation("calendar-delete-event", "Delete calendar event", VendorOutputJSON, func(ctx context.Context, input struct {
+			Calendar GoogleCalendarOptions
+			Event    GoogleCalendarDeleteEventOptions
+		}) ([]byte, error) {
+			return g.CustomCalendarDeleteEventContext(ctx, g.options, input.Calendar, input.Event)
+		}),
+		NewVendorOperation("calendar-delete-events", "Delete calendar events in time range", VendorOutputJSON, func(ctx context.Context, input struct {
+			Calendar GoogleCalendarOptions
+			Events   GoogleCalendarGetEventsOptions
+		}) ([]byte, error) {
+			return g.CustomCalendarDeleteEventsContext(ctx, g.options, input.Calendar, input.Events)
+		}),
+		NewVendorOperation("create-meet-space", "Create Meet space", VendorOutputJSON, func(ctx context.Context, input GoogleMeetOptions) ([]byte, error) {
+			r, err := g.CustomCreateMeetSpaceContext(ctx, g.options, input)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(r)
+		}),
+		NewVendorOperation("docs-copy-document", "Copy document", VendorOutputJSON, func(ctx context.Context, input GoogleDocsOptions) ([]byte, error) {
+			return g.DocsCopyDocumentContext(ctx, input)
+		}),
+	}
+}
+
+func (g *Google) withContext(ctx context.Context) *Google {
+
+	c := *g
+	c.client = common.HttpClientWithContext(ctx, g.client)
+	return &c
+}
+
 func NewGoogle(options GoogleOptions, logger common.Logger) *Google {
 
 	google := &Google{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "google", options.Timeout, options.Insecure),
 		options: options,
 		logger:  logger,
 	}
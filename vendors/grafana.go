@@ -1,6 +1,7 @@
 package vendors
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
 )
 
@@ -244,11 +246,12 @@ type GrafanaAlert struct {
 }
 
 type GrafanaOptions struct {
-	URL      string
-	Timeout  int
-	Insecure bool
-	APIKey   string
-	OrgID    string
+	URL        string
+	Timeout    int
+	Insecure   bool
+	APIKey     string
+	OrgID      string
+	HTTPClient *http.Client
 }
 
 type GrafanaDashboardTime struct {
@@ -367,7 +370,10 @@ func (g *Grafana) getAuth(options GrafanaOptions) string {
 	return auth
 }
 
-func (g *Grafana) CustomRenderImage(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions, renderImageOptions GrafanaRenderImageOptions) ([]byte, error) {
+func (g *Grafana) CustomRenderImageContext(ctx context.Context, grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions, renderImageOptions GrafanaRenderImageOptions) (b []byte, err error) {
+
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "render-image", &b, &err)
 
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
@@ -397,21 +403,28 @@ func (g *Grafana) CustomRenderImage(grafanaOptions GrafanaOptions, grafanaDashbo
 	params.Add("tz", grafanaDashboardOptions.Timezone)
 
 	u.RawQuery = params.Encode()
-	return utils.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+	return common.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+}
+
+func (g *Grafana) CustomRenderImage(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions, renderImageOptions GrafanaRenderImageOptions) ([]byte, error) {
+	return g.CustomRenderImageContext(context.Background(), grafanaOptions, grafanaDashboardOptions, renderImageOptions)
 }
 
 func (g *Grafana) RenderImage(dashboardOptions GrafanaDashboardOptions, renderOptions GrafanaRenderImageOptions) ([]byte, error) {
 	return g.CustomRenderImage(g.options, dashboardOptions, renderOptions)
 }
 
-func (g *Grafana) CustomGetLibraryElement(grafanaOptions GrafanaOptions, grafanaLibraryElementOptions GrafanaLibraryElementOptions) ([]byte, error) {
+func (g *Grafana) CustomGetLibraryElementContext(ctx context.Context, grafanaOptions GrafanaOptions, grafanaLibraryElementOptions GrafanaLibraryElementOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "get-library-element", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
 	}
 
 	u.Path = path.Join(u.Path, fmt.Sprintf("/api/library-elements/%s", grafanaLibraryElementOptions.UID))
-	result, err := utils.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+	result, err := common.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
 	if err != nil {
 		return nil, err
 	}
@@ -426,25 +439,39 @@ func (g *Grafana) CustomGetLibraryElement(grafanaOptions GrafanaOptions, grafana
 	return json.Marshal(libraryElement)
 }
 
+func (g *Grafana) CustomGetLibraryElement(grafanaOptions GrafanaOptions, grafanaLibraryElementOptions GrafanaLibraryElementOptions) ([]byte, error) {
+	return g.CustomGetLibraryElementContext(context.Background(), grafanaOptions, grafanaLibraryElementOptions)
+}
+
 func (g *Grafana) GetLibraryElement(libraryElementOptions GrafanaLibraryElementOptions) ([]byte, error) {
 	return g.CustomGetLibraryElement(g.options, libraryElementOptions)
 }
 
-func (g *Grafana) CustomGetDashboards(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) ([]byte, error) {
+func (g *Grafana) CustomGetDashboardsContext(ctx context.Context, grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "get-dashboards", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
 	}
 
 	u.Path = path.Join(u.Path, fmt.Sprintf("/api/dashboards/uid/%s", grafanaDashboardOptions.UID))
-	return utils.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+	return common.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+}
+
+func (g *Grafana) CustomGetDashboards(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) ([]byte, error) {
+	return g.CustomGetDashboardsContext(context.Background(), grafanaOptions, grafanaDashboardOptions)
 }
 
 func (g *Grafana) GetDashboards(dashboardOptions GrafanaDashboardOptions) ([]byte, error) {
 	return g.CustomGetDashboards(g.options, dashboardOptions)
 }
 
-func (g *Grafana) CustomGetFolder(grafanaOptions GrafanaOptions, grafanaFolderOptions GrafanaFolderOptions) ([]byte, error) {
+func (g *Grafana) CustomGetFolderContext(ctx context.Context, grafanaOptions GrafanaOptions, grafanaFolderOptions GrafanaFolderOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "get-folder", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
@@ -457,28 +484,42 @@ func (g *Grafana) CustomGetFolder(grafanaOptions GrafanaOptions, grafanaFolderOp
 		u.Path = path.Join(u.Path, "/api/folders")
 	}
 
-	return utils.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+	return common.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+}
+
+func (g *Grafana) CustomGetFolder(grafanaOptions GrafanaOptions, grafanaFolderOptions GrafanaFolderOptions) ([]byte, error) {
+	return g.CustomGetFolderContext(context.Background(), grafanaOptions, grafanaFolderOptions)
 }
 
 func (g *Grafana) GetFolder(folderOptions GrafanaFolderOptions) ([]byte, error) {
 	return g.CustomGetFolder(g.options, folderOptions)
 }
 
-func (g *Grafana) CustomDeleteDashboards(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) ([]byte, error) {
+func (g *Grafana) CustomDeleteDashboardsContext(ctx context.Context, grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "delete-dashboards", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
 	}
 
 	u.Path = path.Join(u.Path, fmt.Sprintf("/api/dashboards/uid/%s", grafanaDashboardOptions.UID))
-	return utils.HttpDeleteRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), []byte{})
+	return common.HttpDeleteRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), []byte{})
+}
+
+func (g *Grafana) CustomDeleteDashboards(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) ([]byte, error) {
+	return g.CustomDeleteDashboardsContext(context.Background(), grafanaOptions, grafanaDashboardOptions)
 }
 
 func (g *Grafana) DeleteDashboards(dashboardOptions GrafanaDashboardOptions) ([]byte, error) {
 	return g.CustomDeleteDashboards(g.options, dashboardOptions)
 }
 
-func (g *Grafana) CustomSearchDashboards(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) ([]byte, error) {
+func (g *Grafana) CustomSearchDashboardsContext(ctx context.Context, grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "search-dashboards", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
@@ -499,14 +540,21 @@ func (g *Grafana) CustomSearchDashboards(grafanaOptions GrafanaOptions, grafanaD
 
 	u.RawQuery = params.Encode()
 
-	return utils.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+	return common.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+}
+
+func (g *Grafana) CustomSearchDashboards(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) ([]byte, error) {
+	return g.CustomSearchDashboardsContext(context.Background(), grafanaOptions, grafanaDashboardOptions)
 }
 
 func (g *Grafana) SearchDashboards(dashboardOptions GrafanaDashboardOptions) ([]byte, error) {
 	return g.CustomSearchDashboards(g.options, dashboardOptions)
 }
 
-func (g *Grafana) CustomSearchLibraryElements(grafanaOptions GrafanaOptions, grafanaLibraryElementOptions GrafanaLibraryElementOptions) ([]byte, error) {
+func (g *Grafana) CustomSearchLibraryElementsContext(ctx context.Context, grafanaOptions GrafanaOptions, grafanaLibraryElementOptions GrafanaLibraryElementOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "search-library-elements", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
@@ -525,7 +573,7 @@ func (g *Grafana) CustomSearchLibraryElements(grafanaOptions GrafanaOptions, gra
 
 	u.RawQuery = params.Encode()
 
-	result, err := utils.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+	result, err := common.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
 	if err != nil {
 		return nil, err
 	}
@@ -540,11 +588,18 @@ func (g *Grafana) CustomSearchLibraryElements(grafanaOptions GrafanaOptions, gra
 	return json.Marshal(libraryElement)
 }
 
+func (g *Grafana) CustomSearchLibraryElements(grafanaOptions GrafanaOptions, grafanaLibraryElementOptions GrafanaLibraryElementOptions) ([]byte, error) {
+	return g.CustomSearchLibraryElementsContext(context.Background(), grafanaOptions, grafanaLibraryElementOptions)
+}
+
 func (g *Grafana) SearchLibraryElements(libraryElementOptions GrafanaLibraryElementOptions) ([]byte, error) {
 	return g.CustomSearchLibraryElements(g.options, libraryElementOptions)
 }
 
-func (g Grafana) CustomCopyDashboard(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) ([]byte, error) {
+func (g *Grafana) CustomCopyDashboardContext(ctx context.Context, grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "copy-dashboard", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
@@ -566,7 +621,7 @@ func (g Grafana) CustomCopyDashboard(grafanaOptions GrafanaOptions, grafanaDashb
 			copyOpts.OrgID = grafanaDashboardOptions.Cloned.OrgID
 		}
 
-		b, err := g.CustomGetDashboards(copyOpts, copyDashboardOpts)
+		b, err := g.CustomGetDashboardsContext(ctx, copyOpts, copyDashboardOpts)
 		if err != nil {
 			return nil, err
 		}
@@ -600,19 +655,26 @@ func (g Grafana) CustomCopyDashboard(grafanaOptions GrafanaOptions, grafanaDashb
 		copyDashboard.Dashboard.Tags = grafanaDashboardOptions.Tags
 	}
 
-	b, err := json.Marshal(copyDashboard)
+	b, err = json.Marshal(copyDashboard)
 	if err != nil {
 		return nil, err
 	}
 
-	return utils.HttpPostRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), b)
+	return common.HttpPostRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), b)
+}
+
+func (g Grafana) CustomCopyDashboard(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions) ([]byte, error) {
+	return g.CustomCopyDashboardContext(context.Background(), grafanaOptions, grafanaDashboardOptions)
 }
 
 func (g *Grafana) CopyDashboard(grafanaCreateOptions GrafanaDashboardOptions) ([]byte, error) {
 	return g.CustomCopyDashboard(g.options, grafanaCreateOptions)
 }
 
-func (g Grafana) CustomCopyLibraryElement(grafanaOptions GrafanaOptions, grafanaLibraryElementOptions GrafanaLibraryElementOptions) ([]byte, error) {
+func (g *Grafana) CustomCopyLibraryElementContext(ctx context.Context, grafanaOptions GrafanaOptions, grafanaLibraryElementOptions GrafanaLibraryElementOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "copy-library-element", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
@@ -634,7 +696,7 @@ func (g Grafana) CustomCopyLibraryElement(grafanaOptions GrafanaOptions, grafana
 			copyOpts.OrgID = grafanaLibraryElementOptions.Cloned.OrgID
 		}
 
-		b, err := g.CustomGetLibraryElement(copyOpts, copyLibraryElementsOpts)
+		b, err := g.CustomGetLibraryElementContext(ctx, copyOpts, copyLibraryElementsOpts)
 		if err != nil {
 			return nil, err
 		}
@@ -676,15 +738,22 @@ func (g Grafana) CustomCopyLibraryElement(grafanaOptions GrafanaOptions, grafana
 		return nil, err
 	}
 
-	result, err := utils.HttpPostRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), l)
+	result, err := common.HttpPostRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), l)
 	return result, err
 }
 
+func (g Grafana) CustomCopyLibraryElement(grafanaOptions GrafanaOptions, grafanaLibraryElementOptions GrafanaLibraryElementOptions) ([]byte, error) {
+	return g.CustomCopyLibraryElementContext(context.Background(), grafanaOptions, grafanaLibraryElementOptions)
+}
+
 func (g *Grafana) CopyLibraryElement(grafanaLibraryElementOptions GrafanaLibraryElementOptions) ([]byte, error) {
 	return g.CustomCopyLibraryElement(g.options, grafanaLibraryElementOptions)
 }
 
-func (g Grafana) CustomCreateAnnotation(grafanaOptions GrafanaOptions, createAnnotationOptions GrafanaCreateAnnotationOptions) ([]byte, error) {
+func (g *Grafana) CustomCreateAnnotationContext(ctx context.Context, grafanaOptions GrafanaOptions, createAnnotationOptions GrafanaCreateAnnotationOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "create-annotation", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
@@ -692,11 +761,15 @@ func (g Grafana) CustomCreateAnnotation(grafanaOptions GrafanaOptions, createAnn
 
 	u.Path = path.Join(u.Path, "/api/annotations")
 
-	b, err := json.Marshal(g.createAnnotation(&createAnnotationOptions))
+	b, err = json.Marshal(g.createAnnotation(&createAnnotationOptions))
 	if err != nil {
 		return nil, err
 	}
-	return utils.HttpPostRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), b)
+	return common.HttpPostRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), b)
+}
+
+func (g Grafana) CustomCreateAnnotation(grafanaOptions GrafanaOptions, createAnnotationOptions GrafanaCreateAnnotationOptions) ([]byte, error) {
+	return g.CustomCreateAnnotationContext(context.Background(), grafanaOptions, createAnnotationOptions)
 }
 
 func (g *Grafana) createAnnotation(o *GrafanaCreateAnnotationOptions) *GrafanaAnnotation {
@@ -735,7 +808,10 @@ func (g *Grafana) CreateAnnotation(options GrafanaCreateAnnotationOptions) ([]by
 	return g.CustomCreateAnnotation(g.options, options)
 }
 
-func (g *Grafana) CustomGetAnnotations(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions, getAnnotationsOptions GrafanaGetAnnotationsOptions) ([]byte, error) {
+func (g *Grafana) CustomGetAnnotationsContext(ctx context.Context, grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions, getAnnotationsOptions GrafanaGetAnnotationsOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "get-annotations", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
@@ -779,7 +855,11 @@ func (g *Grafana) CustomGetAnnotations(grafanaOptions GrafanaOptions, grafanaDas
 	params.Add("tz", grafanaDashboardOptions.Timezone)
 
 	u.RawQuery = params.Encode()
-	return utils.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+	return common.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+}
+
+func (g *Grafana) CustomGetAnnotations(grafanaOptions GrafanaOptions, grafanaDashboardOptions GrafanaDashboardOptions, getAnnotationsOptions GrafanaGetAnnotationsOptions) ([]byte, error) {
+	return g.CustomGetAnnotationsContext(context.Background(), grafanaOptions, grafanaDashboardOptions, getAnnotationsOptions)
 }
 
 func (g *Grafana) GetAnnotations(dashboardOptions GrafanaDashboardOptions, annotationsOptions GrafanaGetAnnotationsOptions) ([]byte, error) {
@@ -1037,7 +1117,10 @@ func (g Grafana) arrangePanels(panels *[]interface{}, clonedDashboardOptions Gra
 	}
 }
 
-func (g Grafana) CustomCreateDashboard(grafanaOptions GrafanaOptions, createDashboardOptions GrafanaDashboardOptions) ([]byte, error) {
+func (g *Grafana) CustomCreateDashboardContext(ctx context.Context, grafanaOptions GrafanaOptions, createDashboardOptions GrafanaDashboardOptions) (b []byte, err error) {
+
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "create-dashboard", &b, &err)
 
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
@@ -1060,7 +1143,7 @@ func (g Grafana) CustomCreateDashboard(grafanaOptions GrafanaOptions, createDash
 			clonedOpts.APIKey = createDashboardOptions.Cloned.APIKey
 			clonedOpts.OrgID = createDashboardOptions.Cloned.OrgID
 		}
-		b, err := g.CustomGetDashboards(clonedOpts, clonedDashboardOpts)
+		b, err := g.CustomGetDashboardsContext(ctx, clonedOpts, clonedDashboardOpts)
 		if err != nil {
 			return nil, err
 		}
@@ -1090,18 +1173,25 @@ func (g Grafana) CustomCreateDashboard(grafanaOptions GrafanaOptions, createDash
 		g.arrangePanels(&req.Dashboard.Panels, createDashboardOptions.Cloned)
 	}
 
-	b, err := json.Marshal(&req)
+	b, err = json.Marshal(&req)
 	if err != nil {
 		return nil, err
 	}
-	return utils.HttpPostRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), b)
+	return common.HttpPostRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), b)
+}
+
+func (g Grafana) CustomCreateDashboard(grafanaOptions GrafanaOptions, createDashboardOptions GrafanaDashboardOptions) ([]byte, error) {
+	return g.CustomCreateDashboardContext(context.Background(), grafanaOptions, createDashboardOptions)
 }
 
 func (g *Grafana) CreateDashboard(options GrafanaDashboardOptions) ([]byte, error) {
 	return g.CustomCreateDashboard(g.options, options)
 }
 
-func (g *Grafana) CustomGet(grafanaOptions GrafanaOptions, apiPath string) ([]byte, error) {
+func (g *Grafana) CustomGetContext(ctx context.Context, grafanaOptions GrafanaOptions, apiPath string) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "get", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
@@ -1109,10 +1199,17 @@ func (g *Grafana) CustomGet(grafanaOptions GrafanaOptions, apiPath string) ([]by
 
 	u.Path = path.Join(u.Path, apiPath)
 
-	return utils.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+	return common.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
 }
 
-func (g *Grafana) CustomPost(grafanaOptions GrafanaOptions, apiPath string, headers map[string]string, body []byte) ([]byte, error) {
+func (g *Grafana) CustomGet(grafanaOptions GrafanaOptions, apiPath string) ([]byte, error) {
+	return g.CustomGetContext(context.Background(), grafanaOptions, apiPath)
+}
+
+func (g *Grafana) CustomPostContext(ctx context.Context, grafanaOptions GrafanaOptions, apiPath string, headers map[string]string, body []byte) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "post", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
@@ -1120,10 +1217,17 @@ func (g *Grafana) CustomPost(grafanaOptions GrafanaOptions, apiPath string, head
 
 	u.Path = path.Join(u.Path, apiPath)
 
-	return utils.HttpPostRawWithHeaders(g.client, u.String(), headers, body)
+	return common.HttpPostRawWithHeaders(g.client, u.String(), headers, body)
 }
 
-func (g *Grafana) CustomPut(grafanaOptions GrafanaOptions, apiPath string, headers map[string]string, body []byte) ([]byte, error) {
+func (g *Grafana) CustomPost(grafanaOptions GrafanaOptions, apiPath string, headers map[string]string, body []byte) ([]byte, error) {
+	return g.CustomPostContext(context.Background(), grafanaOptions, apiPath, headers, body)
+}
+
+func (g *Grafana) CustomPutContext(ctx context.Context, grafanaOptions GrafanaOptions, apiPath string, headers map[string]string, body []byte) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "put", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
@@ -1131,10 +1235,17 @@ func (g *Grafana) CustomPut(grafanaOptions GrafanaOptions, apiPath string, heade
 
 	u.Path = path.Join(u.Path, apiPath)
 
-	return utils.HttpPutRawWithHeaders(g.client, u.String(), headers, body)
+	return common.HttpPutRawWithHeaders(g.client, u.String(), headers, body)
 }
 
-func (g *Grafana) CustomGetAlerts(grafanaOptions GrafanaOptions, getAlertsOptions GrafanaGetAlertsOptions) ([]byte, error) {
+func (g *Grafana) CustomPut(grafanaOptions GrafanaOptions, apiPath string, headers map[string]string, body []byte) ([]byte, error) {
+	return g.CustomPutContext(context.Background(), grafanaOptions, apiPath, headers, body)
+}
+
+func (g *Grafana) CustomGetAlertsContext(ctx context.Context, grafanaOptions GrafanaOptions, getAlertsOptions GrafanaGetAlertsOptions) (b []byte, err error) {
+	g = g.withContext(ctx)
+	defer vendorError("grafana", "get-alerts", &b, &err)
+
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
 		return nil, err
@@ -1157,7 +1268,7 @@ func (g *Grafana) CustomGetAlerts(grafanaOptions GrafanaOptions, getAlertsOption
 		headers["Authorization"] = auth
 	}
 
-	body, statusCode, err := utils.HttpRequestRawWithHeadersOutCode(g.client, "GET", u.String(), headers, nil)
+	body, statusCode, err := common.HttpRequestRawWithHeadersOutCode(g.client, "GET", u.String(), headers, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1249,14 +1360,90 @@ func (g *Grafana) CustomGetAlerts(grafanaOptions GrafanaOptions, getAlertsOption
 	return json.Marshal(response)
 }
 
+func (g *Grafana) CustomGetAlerts(grafanaOptions GrafanaOptions, getAlertsOptions GrafanaGetAlertsOptions) ([]byte, error) {
+	return g.CustomGetAlertsContext(context.Background(), grafanaOptions, getAlertsOptions)
+}
+
 func (g *Grafana) GetAlerts(options GrafanaGetAlertsOptions) ([]byte, error) {
 	return g.CustomGetAlerts(g.options, options)
 }
 
+func (g *Grafana) Name() string {
+	return "grafana"
+}
+
+func (g *Grafana) Options() interface{} {
+	return g.options
+}
+
+func (g *Grafana) Check(ctx context.Context) error {
+
+	_, err := g.CustomGetContext(ctx, g.options, "/api/org")
+	return err
+}
+
+func (g *Grafana) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("render-image", "Render dashboard panel as image", VendorOutputPNG, func(ctx context.Context, input struct {
+			Dashboard GrafanaDashboardOptions
+			Render    GrafanaRenderImageOptions
+		}) ([]byte, error) {
+			return g.CustomRenderImageContext(ctx, g.options, input.Dashboard, input.Render)
+		}),
+		NewVendorOperation("get-dashboards", "Get dashboard by UID", VendorOutputJSON, func(ctx context.Context, input GrafanaDashboardOptions) ([]byte, error) {
+			return g.CustomGetDashboardsContext(ctx, g.options, input)
+		}),
+		NewVendorOperation("search-dashboards", "Search dashboards", VendorOutputJSON, func(ctx context.Context, input GrafanaDashboardOptions) ([]byte, error) {
+			return g.CustomSearchDashboardsContext(ctx, g.options, input)
+		}),
+		NewVendorOperation("create-dashboard", "Create dashboard", VendorOutputJSON, func(ctx context.Context, input GrafanaDashboardOptions) ([]byte, error) {
+			return g.CustomCreateDashboardContext(ctx, g.options, input)
+		}),
+		NewVendorOperation("copy-dashboard", "Copy dashboard from cloned Grafana", VendorOutputJSON, func(ctx context.Context, input GrafanaDashboardOptions) ([]byte, error) {
+			return g.CustomCopyDashboardContext(ctx, g.options, input)
+		}),
+		NewVendorOperation("delete-dashboards", "Delete dashboard by UID", VendorOutputJSON, func(ctx context.Context, input GrafanaDashboardOptions) ([]byte, error) {
+			return g.CustomDeleteDashboardsContext(ctx, g.options, input)
+		}),
+		NewVendorOperation("get-library-element", "Get library element", VendorOutputJSON, func(ctx context.Context, input GrafanaLibraryElementOptions) ([]byte, error) {
+			return g.CustomGetLibraryElementContext(ctx, g.options, input)
+		}),
+		NewVendorOperation("search-library-elements", "Search library elements", VendorOutputJSON, func(ctx context.Context, input GrafanaLibraryElementOptions) ([]byte, error) {
+			return g.CustomSearchLibraryElementsContext(ctx, g.options, input)
+		}),
+		NewVendorOperation("copy-library-element", "Copy library element from cloned Grafana", VendorOutputJSON, func(ctx context.Context, input GrafanaLibraryElementOptions) ([]byte, error) {
+			return g.CustomCopyLibraryElementContext(ctx, g.options, input)
+		}),
+		NewVendorOperation("get-folder", "Get folder", VendorOutputJSON, func(ctx context.Context, input GrafanaFolderOptions) ([]byte, error) {
+			return g.CustomGetFolderContext(ctx, g.options, input)
+		}),
+		NewVendorOperation("create-annotation", "Create annotation", VendorOutputJSON, func(ctx context.Context, input GrafanaCreateAnnotationOptions) ([]byte, error) {
+			return g.CustomCreateAnnotationContext(ctx, g.options, input)
+		}),
+		NewVendorOperation("get-annotations", "Get annotations", VendorOutputJSON, func(ctx context.Context, input struct {
+			Dashboard   GrafanaDashboardOptions
+			Annotations GrafanaGetAnnotationsOptions
+		}) ([]byte, error) {
+			return g.CustomGetAnnotationsContext(ctx, g.options, input.Dashboard, input.Annotations)
+		}),
+		NewVendorOperation("get-alerts", "Get alerts", VendorOutputJSON, func(ctx context.Context, input GrafanaGetAlertsOptions) ([]byte, error) {
+			return g.CustomGetAlertsContext(ctx, g.options, input)
+		}),
+	}
+}
+
+func (g *Grafana) withContext(ctx context.Context) *Grafana {
+
+	c := *g
+	c.client = common.HttpClientWithContext(ctx, g.client)
+	return &c
+}
+
 func NewGrafana(options GrafanaOptions) *Grafana {
 
 	grafana := &Grafana{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "grafana", options.Timeout, options.Insecure),
 		options: options,
 	}
 	return grafana
@@ -1,6 +1,7 @@
 package vendors
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -9,23 +10,25 @@ import (
 
 	"encoding/base64"
 
+	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
 )
 
 type GraylogOptions struct {
-	URL       string
-	Timeout   int
-	Insecure  bool
-	User      string
-	Password  string
-	Streams   string
-	Query     string
-	RangeType string
-	Sort      string
-	Limit     int
-	From      string
-	To        string
-	Range     string
+	URL        string
+	Timeout    int
+	Insecure   bool
+	User       string
+	Password   string
+	Streams    string
+	Query      string
+	RangeType  string
+	Sort       string
+	Limit      int
+	From       string
+	To         string
+	Range      string
+	HTTPClient *http.Client
 }
 
 type Graylog struct {
@@ -41,7 +44,7 @@ func (g *Graylog) get(URL string) ([]byte, error) {
 		auth = fmt.Sprintf("Basic %s", basic)
 	}
 
-	return utils.HttpGetRaw(g.client, URL, "application/json", auth)
+	return common.HttpGetRaw(g.client, URL, "application/json", auth)
 }
 
 // https://graylog.some.host/api/search/universal/relative?query=*&range=3600&limit=100&sort=timestamp:desc&pretty=true
@@ -108,7 +111,10 @@ func (g *Graylog) searchAbsolute(URL, streams, query, sort string, from, to stri
 	return g.get(u.String())
 }
 
-func (g *Graylog) GetLogs() ([]byte, error) {
+func (g *Graylog) GetLogsContext(ctx context.Context) (b []byte, err error) {
+
+	g = g.withContext(ctx)
+	defer vendorError("graylog", "get-logs", &b, &err)
 
 	switch g.options.RangeType {
 	case "relative":
@@ -120,10 +126,51 @@ func (g *Graylog) GetLogs() ([]byte, error) {
 	}
 }
 
+func (g *Graylog) GetLogs() ([]byte, error) {
+	return g.GetLogsContext(context.Background())
+}
+
+func (g *Graylog) Name() string {
+	return "graylog"
+}
+
+func (g *Graylog) Options() interface{} {
+	return g.options
+}
+
+func (g *Graylog) Check(ctx context.Context) error {
+
+	u, err := url.Parse(g.options.URL)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, "/api/system")
+
+	_, err = g.withContext(ctx).get(u.String())
+	return err
+}
+
+// Operations of graylog use query of options, so it's set per call with options
+func (g *Graylog) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("get-logs", "Search logs with query of options", VendorOutputJSON, func(ctx context.Context, input struct{}) ([]byte, error) {
+			return g.GetLogsContext(ctx)
+		}),
+	}
+}
+
+func (g *Graylog) withContext(ctx context.Context) *Graylog {
+
+	c := *g
+	c.client = common.HttpClientWithContext(ctx, g.client)
+	return &c
+}
+
 func NewGraylog(options GraylogOptions) *Graylog {
 
 	graylog := &Graylog{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "graylog", options.Timeout, options.Insecure),
 		options: options,
 	}
 	return graylog
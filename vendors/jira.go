@@ -2,6 +2,7 @@ package vendors
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -31,6 +32,7 @@ type JiraOptions struct {
 	User        string
 	Password    string
 	AccessToken string
+	HTTPClient  *http.Client
 }
 
 type JiraIssueOptions struct {
@@ -42,6 +44,7 @@ type JiraIssueOptions struct {
 	Reporter           string
 	Summary            string
 	Description        string
+	Format             string
 	CustomFields       string
 	TransitionID       string
 	Components         string
@@ -52,7 +55,8 @@ type JiraIssueOptions struct {
 }
 
 type JiraAddIssueCommentOptions struct {
-	Body string
+	Body   string
+	Format string
 }
 
 type JiraAddIssueAttachmentOptions struct {
@@ -69,6 +73,7 @@ type JiraSearchIssueOptions struct {
 type JiraSearchAssetOptions struct {
 	SearchPattern string
 	ResultPerPage int
+	PageOptions
 }
 
 type JiraCreateAssetOptions struct {
@@ -340,7 +345,19 @@ func (j *Jira) getAuth(opts JiraOptions) string {
 	return ""
 }
 
-func (j *Jira) CustomCreateIssue(jiraOptions JiraOptions, createOptions JiraIssueOptions) ([]byte, error) {
+// convertText turns markdown into wiki markup used by API v2
+func (j *Jira) convertText(text, format string) string {
+
+	if format != common.MarkdownFormat {
+		return text
+	}
+	return common.MarkdownToJira(text)
+}
+
+func (j *Jira) CustomCreateIssueContext(ctx context.Context, jiraOptions JiraOptions, createOptions JiraIssueOptions) (b []byte, err error) {
+
+	j = j.withContext(ctx)
+	defer vendorError("jira", "create-issue", &b, &err)
 
 	issue := &JiraIssueCreate{
 		Fields: &JiraIssueFields{
@@ -351,7 +368,7 @@ func (j *Jira) CustomCreateIssue(jiraOptions JiraOptions, createOptions JiraIssu
 				Name: createOptions.Type,
 			},
 			Summary:     createOptions.Summary,
-			Description: createOptions.Description,
+			Description: j.convertText(createOptions.Description, createOptions.Format),
 		},
 	}
 
@@ -406,17 +423,24 @@ func (j *Jira) CustomCreateIssue(jiraOptions JiraOptions, createOptions JiraIssu
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, "/rest/api/2/issue")
-	return utils.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+	return common.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+}
+
+func (j *Jira) CustomCreateIssue(jiraOptions JiraOptions, createOptions JiraIssueOptions) ([]byte, error) {
+	return j.CustomCreateIssueContext(context.Background(), jiraOptions, createOptions)
 }
 
 func (j *Jira) CreateIssue(issueCreateOptions JiraIssueOptions) ([]byte, error) {
 	return j.CustomCreateIssue(j.options, issueCreateOptions)
 }
 
-func (j *Jira) CustomAddIssueComment(jiraOptions JiraOptions, issueOptions JiraIssueOptions, addCommentOptions JiraAddIssueCommentOptions) ([]byte, error) {
+func (j *Jira) CustomAddIssueCommentContext(ctx context.Context, jiraOptions JiraOptions, issueOptions JiraIssueOptions, addCommentOptions JiraAddIssueCommentOptions) (b []byte, err error) {
+
+	j = j.withContext(ctx)
+	defer vendorError("jira", "add-issue-comment", &b, &err)
 
 	comment := &JiraIssueAddCommentInner{
-		Body: addCommentOptions.Body,
+		Body: j.convertText(addCommentOptions.Body, addCommentOptions.Format),
 	}
 
 	req, err := json.Marshal(&comment)
@@ -429,14 +453,21 @@ func (j *Jira) CustomAddIssueComment(jiraOptions JiraOptions, issueOptions JiraI
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, fmt.Sprintf("/rest/api/2/issue/%s/comment", issueOptions.IdOrKey))
-	return utils.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+	return common.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+}
+
+func (j *Jira) CustomAddIssueComment(jiraOptions JiraOptions, issueOptions JiraIssueOptions, addCommentOptions JiraAddIssueCommentOptions) ([]byte, error) {
+	return j.CustomAddIssueCommentContext(context.Background(), jiraOptions, issueOptions, addCommentOptions)
 }
 
 func (j *Jira) IssueAddComment(issueOptions JiraIssueOptions, addCommentOptions JiraAddIssueCommentOptions) ([]byte, error) {
 	return j.CustomAddIssueComment(j.options, issueOptions, addCommentOptions)
 }
 
-func (j *Jira) CustomAddIssueAttachment(jiraOptions JiraOptions, issueOptions JiraIssueOptions, addAttachmentOptions JiraAddIssueAttachmentOptions) ([]byte, error) {
+func (j *Jira) CustomAddIssueAttachmentContext(ctx context.Context, jiraOptions JiraOptions, issueOptions JiraIssueOptions, addAttachmentOptions JiraAddIssueAttachmentOptions) (b []byte, err error) {
+
+	j = j.withContext(ctx)
+	defer vendorError("jira", "add-issue-attachment", &b, &err)
 
 	u, err := url.Parse(jiraOptions.URL)
 	if err != nil {
@@ -467,14 +498,21 @@ func (j *Jira) CustomAddIssueAttachment(jiraOptions JiraOptions, issueOptions Ji
 	headers["Content-type"] = w.FormDataContentType()
 	headers["Authorization"] = j.getAuth(jiraOptions)
 	headers["X-Atlassian-Token"] = "no-check"
-	return utils.HttpPostRawWithHeaders(j.client, u.String(), headers, body.Bytes())
+	return common.HttpPostRawWithHeaders(j.client, u.String(), headers, body.Bytes())
+}
+
+func (j *Jira) CustomAddIssueAttachment(jiraOptions JiraOptions, issueOptions JiraIssueOptions, addAttachmentOptions JiraAddIssueAttachmentOptions) ([]byte, error) {
+	return j.CustomAddIssueAttachmentContext(context.Background(), jiraOptions, issueOptions, addAttachmentOptions)
 }
 
 func (j *Jira) AddIssueAttachment(issueOptions JiraIssueOptions, addAttachmentOptions JiraAddIssueAttachmentOptions) ([]byte, error) {
 	return j.CustomAddIssueAttachment(j.options, issueOptions, addAttachmentOptions)
 }
 
-func (j *Jira) CustomUpdateIssue(jiraOptions JiraOptions, issueOptions JiraIssueOptions) ([]byte, error) {
+func (j *Jira) CustomUpdateIssueContext(ctx context.Context, jiraOptions JiraOptions, issueOptions JiraIssueOptions) (b []byte, err error) {
+	j = j.withContext(ctx)
+	defer vendorError("jira", "update-issue", &b, &err)
+
 	labelOperations := make([]JiraIssueUpdateLabelOperation, 0)
 	for _, v := range issueOptions.UpdateAddLabels {
 		labelOperations = append(labelOperations, JiraIssueUpdateLabelOperation{
@@ -499,7 +537,7 @@ func (j *Jira) CustomUpdateIssue(jiraOptions JiraOptions, issueOptions JiraIssue
 	}
 
 	if !utils.IsEmpty(issueOptions.Description) {
-		issue.Fields.Description = issueOptions.Description
+		issue.Fields.Description = j.convertText(issueOptions.Description, issueOptions.Format)
 	}
 
 	if len(issueOptions.Labels) > 0 {
@@ -530,10 +568,17 @@ func (j *Jira) CustomUpdateIssue(jiraOptions JiraOptions, issueOptions JiraIssue
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, fmt.Sprintf("/rest/api/2/issue/%s", issueOptions.IdOrKey))
-	return utils.HttpPutRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+	return common.HttpPutRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
 }
 
-func (j *Jira) CustomMoveIssue(jiraOptions JiraOptions, moveOptions JiraIssueOptions) ([]byte, error) {
+func (j *Jira) CustomUpdateIssue(jiraOptions JiraOptions, issueOptions JiraIssueOptions) ([]byte, error) {
+	return j.CustomUpdateIssueContext(context.Background(), jiraOptions, issueOptions)
+}
+
+func (j *Jira) CustomMoveIssueContext(ctx context.Context, jiraOptions JiraOptions, moveOptions JiraIssueOptions) (b []byte, err error) {
+
+	j = j.withContext(ctx)
+	defer vendorError("jira", "move-issue", &b, &err)
 
 	issue := &JiraIssueUpdate{
 		Fields: &JiraIssueFields{
@@ -556,7 +601,11 @@ func (j *Jira) CustomMoveIssue(jiraOptions JiraOptions, moveOptions JiraIssueOpt
 	}
 
 	u.Path = path.Join(u.Path, fmt.Sprintf("/rest/api/2/issue/%s", moveOptions.IdOrKey))
-	return utils.HttpPutRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+	return common.HttpPutRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+}
+
+func (j *Jira) CustomMoveIssue(jiraOptions JiraOptions, moveOptions JiraIssueOptions) ([]byte, error) {
+	return j.CustomMoveIssueContext(context.Background(), jiraOptions, moveOptions)
 }
 
 func (j *Jira) MoveIssue(options JiraIssueOptions) ([]byte, error) {
@@ -567,7 +616,10 @@ func (j *Jira) UpdateIssue(options JiraIssueOptions) ([]byte, error) {
 	return j.CustomUpdateIssue(j.options, options)
 }
 
-func (j *Jira) GetIssueTransitions(jiraOptions JiraOptions, issueOptions JiraIssueOptions) ([]byte, error) {
+func (j *Jira) GetIssueTransitionsContext(ctx context.Context, jiraOptions JiraOptions, issueOptions JiraIssueOptions) (b []byte, err error) {
+	j = j.withContext(ctx)
+	defer vendorError("jira", "get-issue-transitions", &b, &err)
+
 	u, err := url.Parse(jiraOptions.URL)
 	if err != nil {
 		return nil, err
@@ -577,7 +629,7 @@ func (j *Jira) GetIssueTransitions(jiraOptions JiraOptions, issueOptions JiraIss
 	q.Set("expand", "transitions.fields")
 	u.RawQuery = q.Encode()
 
-	t, err := utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+	t, err := common.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
 	if err != nil {
 		return nil, err
 	}
@@ -585,7 +637,34 @@ func (j *Jira) GetIssueTransitions(jiraOptions JiraOptions, issueOptions JiraIss
 	return t, nil
 }
 
-func (j *Jira) CustomChangeIssueTransitions(jiraOptions JiraOptions, issueOptions JiraIssueOptions) ([]byte, error) {
+func (j *Jira) GetIssueTransitions(jiraOptions JiraOptions, issueOptions JiraIssueOptions) ([]byte, error) {
+	return j.GetIssueTransitionsContext(context.Background(), jiraOptions, issueOptions)
+}
+
+func (j *Jira) CustomGetProjectsContext(ctx context.Context, jiraOptions JiraOptions) (b []byte, err error) {
+	j = j.withContext(ctx)
+	defer vendorError("jira", "get-projects", &b, &err)
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "/rest/api/2/project")
+	return common.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+}
+
+func (j *Jira) CustomGetProjects(jiraOptions JiraOptions) ([]byte, error) {
+	return j.CustomGetProjectsContext(context.Background(), jiraOptions)
+}
+
+func (j *Jira) GetProjects() ([]byte, error) {
+	return j.CustomGetProjects(j.options)
+}
+
+func (j *Jira) CustomChangeIssueTransitionsContext(ctx context.Context, jiraOptions JiraOptions, issueOptions JiraIssueOptions) (b []byte, err error) {
+
+	j = j.withContext(ctx)
+	defer vendorError("jira", "change-issue-transitions", &b, &err)
 
 	transition := &JiraTransition{ID: issueOptions.TransitionID}
 
@@ -593,7 +672,7 @@ func (j *Jira) CustomChangeIssueTransitions(jiraOptions JiraOptions, issueOption
 		Comments: []JiraIssueAddCommentTransition{
 			JiraIssueAddCommentTransition{
 				AddInner: JiraIssueAddCommentInner{
-					Body: issueOptions.UpdateAddComment,
+					Body: j.convertText(issueOptions.UpdateAddComment, issueOptions.Format),
 				},
 			},
 		},
@@ -618,7 +697,7 @@ func (j *Jira) CustomChangeIssueTransitions(jiraOptions JiraOptions, issueOption
 	}
 	u.Path = path.Join(u.Path, fmt.Sprintf("/rest/api/2/issue/%s/transitions", issueOptions.IdOrKey))
 
-	_, c, err := utils.HttpPostRawOutCode(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+	_, c, err := common.HttpPostRawOutCode(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
 	if err != nil {
 		return nil, err
 	}
@@ -633,11 +712,18 @@ func (j *Jira) CustomChangeIssueTransitions(jiraOptions JiraOptions, issueOption
 	return code, nil
 }
 
+func (j *Jira) CustomChangeIssueTransitions(jiraOptions JiraOptions, issueOptions JiraIssueOptions) ([]byte, error) {
+	return j.CustomChangeIssueTransitionsContext(context.Background(), jiraOptions, issueOptions)
+}
+
 func (j *Jira) ChangeIssueTransitions(options JiraIssueOptions) ([]byte, error) {
 	return j.CustomChangeIssueTransitions(j.options, options)
 }
 
-func (j *Jira) CustomSearchIssue(jiraOptions JiraOptions, search JiraSearchIssueOptions) ([]byte, error) {
+func (j *Jira) CustomSearchIssueContext(ctx context.Context, jiraOptions JiraOptions, search JiraSearchIssueOptions) (b []byte, err error) {
+
+	j = j.withContext(ctx)
+	defer vendorError("jira", "search-issue", &b, &err)
 
 	params := make(url.Values)
 	params.Add("jql", search.SearchPattern)
@@ -653,16 +739,20 @@ func (j *Jira) CustomSearchIssue(jiraOptions JiraOptions, search JiraSearchIssue
 	u.Path = path.Join(u.Path, "/rest/api/2/search")
 	u.RawQuery = params.Encode()
 
-	return utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+	return common.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+}
+
+func (j *Jira) CustomSearchIssue(jiraOptions JiraOptions, search JiraSearchIssueOptions) ([]byte, error) {
+	return j.CustomSearchIssueContext(context.Background(), jiraOptions, search)
 }
 
 func (j *Jira) SearchIssue(options JiraSearchIssueOptions) ([]byte, error) {
 	return j.CustomSearchIssue(j.options, options)
 }
 
-func (j *Jira) httpGetStream(url string) (bytes.Buffer, error) {
+func (j *Jira) httpGetStream(ctx context.Context, url string) (bytes.Buffer, error) {
 	res := bytes.Buffer{}
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return res, err
 	}
@@ -680,7 +770,9 @@ func (j *Jira) httpGetStream(url string) (bytes.Buffer, error) {
 			if resp != nil {
 				resp.Body.Close()
 			}
-			time.Sleep(time.Second << attempt)
+			if err := common.SleepContext(ctx, time.Second<<attempt); err != nil {
+				return res, err
+			}
 			continue
 		}
 
@@ -700,13 +792,18 @@ func (j *Jira) httpGetStream(url string) (bytes.Buffer, error) {
 					}
 				}
 			}
-			time.Sleep(duration)
+			if err := common.SleepContext(ctx, duration); err != nil {
+				return res, err
+			}
 			continue
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			resErr = errors.New(resp.Status)
-			time.Sleep(time.Second << attempt)
+			b, _ := io.ReadAll(resp.Body)
+			resErr = common.NewVendorError("jira", "", resp.StatusCode, resp.Header, b)
+			if err := common.SleepContext(ctx, time.Second<<attempt); err != nil {
+				return res, err
+			}
 			continue
 		}
 
@@ -716,10 +813,15 @@ func (j *Jira) httpGetStream(url string) (bytes.Buffer, error) {
 	return res, resErr
 }
 
-func (j *Jira) CustomSearchAssets(jiraOptions JiraOptions, search JiraSearchAssetOptions) ([]byte, error) {
+func (j *Jira) CustomSearchAssetsContext(ctx context.Context, jiraOptions JiraOptions, search JiraSearchAssetOptions) (b []byte, err error) {
+	j = j.withContext(ctx)
+	defer vendorError("jira", "search-assets", &b, &err)
+
+	// assets are complete unless limit is set
+	paging := search.PageOptions.orAll()
 	params := url.Values{
 		"qlQuery":       []string{search.SearchPattern},
-		"resultPerPage": []string{strconv.Itoa(search.ResultPerPage)},
+		"resultPerPage": []string{strconv.Itoa(paging.pageSize(search.ResultPerPage))},
 	}
 
 	u, err := url.Parse(jiraOptions.URL)
@@ -733,11 +835,14 @@ func (j *Jira) CustomSearchAssets(jiraOptions JiraOptions, search JiraSearchAsse
 		ObjectEntries:        make([]IQLObjectEntry, 0, 1024),
 	}
 
-	for page := 1; ; page++ {
+	// page size of response is count of pages
+	err = Pages(ctx, paging, func(ctx context.Context, token string) (*Page[IQLObjectEntry], error) {
+
+		page := pageNumber(token)
 		params.Set("page", strconv.Itoa(page))
 		u.RawQuery = params.Encode()
 
-		response, err := j.httpGetStream(u.String())
+		response, err := j.httpGetStream(ctx, u.String())
 		if err != nil {
 			return nil, err
 		}
@@ -750,22 +855,36 @@ func (j *Jira) CustomSearchAssets(jiraOptions JiraOptions, search JiraSearchAsse
 		if page == 1 {
 			result.ObjectTypeAttributes = parsedResponse.ObjectTypeAttributes
 		}
-
-		result.ObjectEntries = append(result.ObjectEntries, parsedResponse.ObjectEntries...)
-
-		if page >= parsedResponse.PageSize {
-			break
+		return &Page[IQLObjectEntry]{
+			Items: parsedResponse.ObjectEntries,
+			Next:  pageNumberNext(page, parsedResponse.PageSize, len(parsedResponse.ObjectEntries), search.ResultPerPage),
+		}, nil
+	}, func(entry IQLObjectEntry) error {
+		if paging.Stream != nil {
+			return paging.Stream(entry)
 		}
+		result.ObjectEntries = append(result.ObjectEntries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return easyjson.Marshal(result)
 }
 
+func (j *Jira) CustomSearchAssets(jiraOptions JiraOptions, search JiraSearchAssetOptions) ([]byte, error) {
+	return j.CustomSearchAssetsContext(context.Background(), jiraOptions, search)
+}
+
 func (j *Jira) SearchAssets(options JiraSearchAssetOptions) ([]byte, error) {
 	return j.CustomSearchAssets(j.options, options)
 }
 
-func (j *Jira) CustomCreateAsset(jiraOptions JiraOptions, createOptions JiraCreateAssetOptions) ([]byte, error) {
+func (j *Jira) CustomCreateAssetContext(ctx context.Context, jiraOptions JiraOptions, createOptions JiraCreateAssetOptions) (b []byte, err error) {
+	j = j.withContext(ctx)
+	defer vendorError("jira", "create-asset", &b, &err)
+
 	attributes := []JiraAssetAttribute{
 		{
 			ObjectTypeAttributeId: createOptions.NameId,
@@ -852,14 +971,21 @@ func (j *Jira) CustomCreateAsset(jiraOptions JiraOptions, createOptions JiraCrea
 	params.Add("objectSchemaId", createOptions.ObjectSchemeId)
 	u.Path = path.Join(u.Path, "rest/assets/1.0/object/create")
 	u.RawQuery = params.Encode()
-	return utils.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+	return common.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+}
+
+func (j *Jira) CustomCreateAsset(jiraOptions JiraOptions, createOptions JiraCreateAssetOptions) ([]byte, error) {
+	return j.CustomCreateAssetContext(context.Background(), jiraOptions, createOptions)
 }
 
 func (j *Jira) CreateAsset(createOptions JiraCreateAssetOptions) ([]byte, error) {
 	return j.CustomCreateAsset(j.options, createOptions)
 }
 
-func (j *Jira) CustomUpdateAsset(jiraOptions JiraOptions, updateOptions JiraUpdateAssetOptions) ([]byte, error) {
+func (j *Jira) CustomUpdateAssetContext(ctx context.Context, jiraOptions JiraOptions, updateOptions JiraUpdateAssetOptions) (b []byte, err error) {
+
+	j = j.withContext(ctx)
+	defer vendorError("jira", "update-asset", &b, &err)
 
 	u, err := url.Parse(jiraOptions.URL)
 	if err != nil {
@@ -870,14 +996,21 @@ func (j *Jira) CustomUpdateAsset(jiraOptions JiraOptions, updateOptions JiraUpda
 	u.Path = path.Join(u.Path, fmt.Sprintf("rest/assets/1.0/object/%s", updateOptions.ObjectId))
 	u.RawQuery = params.Encode()
 
-	return utils.HttpPutRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), []byte(updateOptions.Json))
+	return common.HttpPutRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), []byte(updateOptions.Json))
+}
+
+func (j *Jira) CustomUpdateAsset(jiraOptions JiraOptions, updateOptions JiraUpdateAssetOptions) ([]byte, error) {
+	return j.CustomUpdateAssetContext(context.Background(), jiraOptions, updateOptions)
 }
 
 func (j *Jira) UpdateAsset(updateOptions JiraUpdateAssetOptions) ([]byte, error) {
 	return j.CustomUpdateAsset(j.options, updateOptions)
 }
 
-func (j *Jira) GetUserByEmail(jiraOptions JiraOptions, email string) (*JiraUser, error) {
+func (j *Jira) GetUserByEmailContext(ctx context.Context, jiraOptions JiraOptions, email string) (_ *JiraUser, err error) {
+
+	j = j.withContext(ctx)
+	defer vendorError("jira", "get-user-by-email", nil, &err)
 
 	u, err := url.Parse(jiraOptions.URL)
 	if err != nil {
@@ -889,7 +1022,7 @@ func (j *Jira) GetUserByEmail(jiraOptions JiraOptions, email string) (*JiraUser,
 	q.Set("maxResults", "50")
 	u.RawQuery = q.Encode()
 
-	resp, err := utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+	resp, err := common.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
 	if err != nil {
 		return nil, err
 	}
@@ -907,10 +1040,87 @@ func (j *Jira) GetUserByEmail(jiraOptions JiraOptions, email string) (*JiraUser,
 	return nil, fmt.Errorf("no Jira user found with email %s", email)
 }
 
+func (j *Jira) GetUserByEmail(jiraOptions JiraOptions, email string) (*JiraUser, error) {
+	return j.GetUserByEmailContext(context.Background(), jiraOptions, email)
+}
+
+func (j *Jira) Name() string {
+	return "jira"
+}
+
+func (j *Jira) Options() interface{} {
+	return j.options
+}
+
+func (j *Jira) Check(ctx context.Context) error {
+
+	u, err := url.Parse(j.options.URL)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, "/rest/api/2/myself")
+	return checkVendorHttp(ctx, j.client, "jira", "GET", u.String(), map[string]string{"Authorization": j.getAuth(j.options)}, nil)
+}
+
+func (j *Jira) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("create-issue", "Create issue", VendorOutputJSON, func(ctx context.Context, input JiraIssueOptions) ([]byte, error) {
+			return j.CustomCreateIssueContext(ctx, j.options, input)
+		}),
+		NewVendorOperation("update-issue", "Update issue fields and labels", VendorOutputJSON, func(ctx context.Context, input JiraIssueOptions) ([]byte, error) {
+			return j.CustomUpdateIssueContext(ctx, j.options, input)
+		}),
+		NewVendorOperation("move-issue", "Move issue to another project", VendorOutputJSON, func(ctx context.Context, input JiraIssueOptions) ([]byte, error) {
+			return j.CustomMoveIssueContext(ctx, j.options, input)
+		}),
+		NewVendorOperation("change-issue-transitions", "Change issue transition", VendorOutputJSON, func(ctx context.Context, input JiraIssueOptions) ([]byte, error) {
+			return j.CustomChangeIssueTransitionsContext(ctx, j.options, input)
+		}),
+		NewVendorOperation("add-issue-comment", "Add comment to issue", VendorOutputJSON, func(ctx context.Context, input struct {
+			Issue   JiraIssueOptions
+			Comment JiraAddIssueCommentOptions
+		}) ([]byte, error) {
+			return j.CustomAddIssueCommentContext(ctx, j.options, input.Issue, input.Comment)
+		}),
+		NewVendorOperation("add-issue-attachment", "Add attachment to issue", VendorOutputJSON, func(ctx context.Context, input struct {
+			Issue      JiraIssueOptions
+			Attachment JiraAddIssueAttachmentOptions
+		}) ([]byte, error) {
+			return j.CustomAddIssueAttachmentContext(ctx, j.options, input.Issue, input.Attachment)
+		}),
+		NewVendorOperation("get-issue-transitions", "Get transitions available for issue", VendorOutputJSON, func(ctx context.Context, input JiraIssueOptions) ([]byte, error) {
+			return j.GetIssueTransitionsContext(ctx, j.options, input)
+		}),
+		NewVendorOperation("get-projects", "Get projects visible to user", VendorOutputJSON, func(ctx context.Context, input struct{}) ([]byte, error) {
+			return j.CustomGetProjectsContext(ctx, j.options)
+		}),
+		NewVendorOperation("search-issue", "Search issues by JQL", VendorOutputJSON, func(ctx context.Context, input JiraSearchIssueOptions) ([]byte, error) {
+			return j.CustomSearchIssueContext(ctx, j.options, input)
+		}),
+		NewVendorOperation("search-assets", "Search assets by AQL", VendorOutputJSON, func(ctx context.Context, input JiraSearchAssetOptions) ([]byte, error) {
+			return j.CustomSearchAssetsContext(ctx, j.options, input)
+		}),
+		NewVendorOperation("create-asset", "Create asset", VendorOutputJSON, func(ctx context.Context, input JiraCreateAssetOptions) ([]byte, error) {
+			return j.CustomCreateAssetContext(ctx, j.options, input)
+		}),
+		NewVendorOperation("update-asset", "Update asset", VendorOutputJSON, func(ctx context.Context, input JiraUpdateAssetOptions) ([]byte, error) {
+			return j.CustomUpdateAssetContext(ctx, j.options, input)
+		}),
+	}
+}
+
+func (j *Jira) withContext(ctx context.Context) *Jira {
+
+	c := *j
+	c.client = common.HttpClientWithContext(ctx, j.client)
+	return &c
+}
+
 func NewJira(options JiraOptions) *Jira {
 
 	jira := &Jira{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "jira", options.Timeout, options.Insecure),
 		options: options,
 	}
 	return jira
@@ -1,6 +1,7 @@
 package vendors
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -127,7 +128,7 @@ func TestHttpGetStream(t *testing.T) {
 				url = server.URL
 			}
 
-			res, err := j.httpGetStream(url)
+			res, err := j.httpGetStream(context.Background(), url)
 
 			if tt.expectedError != "" {
 				require.Error(t, err)
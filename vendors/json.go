@@ -1,15 +1,17 @@
 package vendors
 
 import (
+	"context"
 	"net/http"
 
-	"github.com/devopsext/utils"
+	"github.com/devopsext/tools/common"
 )
 
 type JSONOptions struct {
-	Timeout  int
-	Insecure bool
-	URL      string
+	Timeout    int
+	Insecure   bool
+	URL        string
+	HTTPClient *http.Client
 }
 type JSONOutputOptions struct {
 	Output      string // path to output if empty to stdout
@@ -21,13 +23,27 @@ type JSON struct {
 	options JSONOptions
 }
 
+func (c *JSON) GetContext(ctx context.Context) (b []byte, err error) {
+	c = c.withContext(ctx)
+	defer vendorError("json", "get", &b, &err)
+
+	return common.HttpGetRaw(c.client, c.options.URL, "", "")
+}
+
 func (c *JSON) Get() ([]byte, error) {
-	return utils.HttpGetRaw(c.client, c.options.URL, "", "")
+	return c.GetContext(context.Background())
+}
+
+func (c *JSON) withContext(ctx context.Context) *JSON {
+
+	v := *c
+	v.client = common.HttpClientWithContext(ctx, c.client)
+	return &v
 }
 
 func NewJSON(options JSONOptions) *JSON {
 	return &JSON{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "json", options.Timeout, options.Insecure),
 		options: options,
 	}
 }
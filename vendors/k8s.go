@@ -22,6 +22,10 @@ type K8sResourceOptions struct {
 	Name      string
 }
 
+type K8sResourceListOptions struct {
+	K8sResourceOptions
+}
+
 type K8sResourceDescribeOptions struct {
 	K8sResourceOptions
 }
@@ -91,7 +95,7 @@ func newK8sClient(options K8sOptions) (*kubernetes.Clientset, error) {
 	return clientset, nil
 }
 
-func (k *K8s) getClientCtx(options K8sOptions) (*kubernetes.Clientset, context.Context, context.CancelFunc, error) {
+func (k *K8s) getClientCtx(parent context.Context, options K8sOptions) (*kubernetes.Clientset, context.Context, context.CancelFunc, error) {
 
 	clientset := k.clientset
 	if clientset == nil || options != k.options {
@@ -102,7 +106,7 @@ func (k *K8s) getClientCtx(options K8sOptions) (*kubernetes.Clientset, context.C
 		clientset = cs
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(options.Timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(parent, time.Duration(options.Timeout)*time.Second)
 	return clientset, ctx, cancel, nil
 }
 
@@ -130,9 +134,48 @@ func (k *K8s) resourceDescribe(clientset *kubernetes.Clientset, ctx context.Cont
 	return r
 }
 
-func (k *K8s) CustomResourceDescribe(options K8sOptions, describeOptions K8sResourceDescribeOptions) ([]byte, error) {
+// CustomResourceListContext lists resources of kind in namespace, namespaces are listed by kind namespaces
+func (k *K8s) CustomResourceListContext(ctx context.Context, options K8sOptions, listOptions K8sResourceListOptions) (b []byte, err error) {
 
-	clientset, ctx, cancel, err := k.getClientCtx(options)
+	defer vendorError("k8s", "resource-list", &b, &err)
+
+	clientset, ctx, cancel, err := k.getClientCtx(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var rClient rest.Interface
+
+	switch listOptions.Kind {
+	case K8sResourceDeployments, K8sResourceReplicaSets, K8sResourceStatefulSets, K8sResourceDaemonSets:
+		rClient = clientset.AppsV1().RESTClient()
+	default:
+		rClient = clientset.CoreV1().RESTClient()
+	}
+
+	return rClient.Get().
+		UseProtobufAsDefaultIfPreferred(false).
+		NamespaceIfScoped(listOptions.Namespace, !utils.IsEmpty(listOptions.Namespace)).
+		Resource(listOptions.Kind).
+		VersionedParams(&metav1.ListOptions{}, scheme.ParameterCodec).
+		Do(ctx).
+		Raw()
+}
+
+func (k *K8s) CustomResourceList(options K8sOptions, listOptions K8sResourceListOptions) ([]byte, error) {
+	return k.CustomResourceListContext(context.Background(), options, listOptions)
+}
+
+func (k *K8s) ResourceList(options K8sResourceListOptions) ([]byte, error) {
+	return k.CustomResourceList(k.options, options)
+}
+
+func (k *K8s) CustomResourceDescribeContext(ctx context.Context, options K8sOptions, describeOptions K8sResourceDescribeOptions) (b []byte, err error) {
+
+	defer vendorError("k8s", "resource-describe", &b, &err)
+
+	clientset, ctx, cancel, err := k.getClientCtx(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -141,13 +184,19 @@ func (k *K8s) CustomResourceDescribe(options K8sOptions, describeOptions K8sReso
 	return k.resourceDescribe(clientset, ctx, describeOptions).Raw()
 }
 
+func (k *K8s) CustomResourceDescribe(options K8sOptions, describeOptions K8sResourceDescribeOptions) ([]byte, error) {
+	return k.CustomResourceDescribeContext(context.Background(), options, describeOptions)
+}
+
 func (k *K8s) ResourceDescribe(options K8sResourceDescribeOptions) ([]byte, error) {
 	return k.CustomResourceDescribe(k.options, options)
 }
 
-func (k *K8s) CustomResourceDelete(options K8sOptions, deleteOptions K8sResourceDeleteOptions) ([]byte, error) {
+func (k *K8s) CustomResourceDeleteContext(ctx context.Context, options K8sOptions, deleteOptions K8sResourceDeleteOptions) (b []byte, err error) {
+
+	defer vendorError("k8s", "resource-delete", &b, &err)
 
-	clientset, ctx, cancel, err := k.getClientCtx(options)
+	clientset, ctx, cancel, err := k.getClientCtx(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -178,6 +227,10 @@ func (k *K8s) CustomResourceDelete(options K8sOptions, deleteOptions K8sResource
 	return r.Raw()
 }
 
+func (k *K8s) CustomResourceDelete(options K8sOptions, deleteOptions K8sResourceDeleteOptions) ([]byte, error) {
+	return k.CustomResourceDeleteContext(context.Background(), options, deleteOptions)
+}
+
 func (k *K8s) ResourceDelete(options K8sResourceDeleteOptions) ([]byte, error) {
 	return k.CustomResourceDelete(k.options, options)
 }
@@ -260,9 +313,9 @@ func (k *K8s) replicasAreReady(obj interface{}, kind string, replicas int32) boo
 	return false
 }
 
-func (k *K8s) resourceScale(options K8sOptions, scaleOptions K8sResourceScaleOptions) (*rest.Result, error) {
+func (k *K8s) resourceScale(ctx context.Context, options K8sOptions, scaleOptions K8sResourceScaleOptions) (*rest.Result, error) {
 
-	clientset, ctx, cancel, err := k.getClientCtx(options)
+	clientset, ctx, cancel, err := k.getClientCtx(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -346,22 +399,31 @@ func (k *K8s) resourceScale(options K8sOptions, scaleOptions K8sResourceScaleOpt
 	return &rPut, nil
 }
 
-func (k *K8s) CustomResourceScale(options K8sOptions, scaleOptions K8sResourceScaleOptions) ([]byte, error) {
+func (k *K8s) CustomResourceScaleContext(ctx context.Context, options K8sOptions, scaleOptions K8sResourceScaleOptions) (b []byte, err error) {
 
-	rPut, err := k.resourceScale(options, scaleOptions)
+	defer vendorError("k8s", "resource-scale", &b, &err)
+
+	rPut, err := k.resourceScale(ctx, options, scaleOptions)
 	if err != nil {
 		return nil, err
 	}
 	return rPut.Raw()
 }
 
+func (k *K8s) CustomResourceScale(options K8sOptions, scaleOptions K8sResourceScaleOptions) ([]byte, error) {
+	return k.CustomResourceScaleContext(context.Background(), options, scaleOptions)
+}
+
 func (k *K8s) ResourceScale(options K8sResourceScaleOptions) ([]byte, error) {
 	return k.CustomResourceScale(k.options, options)
 }
 
-func (k *K8s) CustomResourceRestart(options K8sOptions, restartOptions K8sResourceRestartOptions) ([]byte, error) {
+func (k *K8s) CustomResourceRestartContext(ctx context.Context, options K8sOptions, restartOptions K8sResourceRestartOptions) (b []byte, err error) {
+
+	defer vendorError("k8s", "resource-restart", &b, &err)
 
-	clientset, ctx, cancel, err := k.getClientCtx(options)
+	// scaling waits with its own timeout, so only describe is limited by client timeout
+	clientset, clientCtx, cancel, err := k.getClientCtx(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -372,7 +434,7 @@ func (k *K8s) CustomResourceRestart(options K8sOptions, restartOptions K8sResour
 		K8sResourceOptions: restartOptions.K8sResourceOptions,
 	}
 
-	oldReplicas, _, err := k.getResourceReplicas(clientset, ctx, describeOpts)
+	oldReplicas, _, err := k.getResourceReplicas(clientset, clientCtx, describeOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -388,24 +450,69 @@ func (k *K8s) CustomResourceRestart(options K8sOptions, restartOptions K8sResour
 		PollTimeout:        restartOptions.PollTimeout,
 	}
 
-	_, err = k.resourceScale(options, scaleOptions)
+	_, err = k.resourceScale(ctx, options, scaleOptions)
 	if err != nil {
 		return nil, err
 	}
 
 	// scale back to old replicas
 	scaleOptions.Replicas = int(oldReplicas)
-	rPut, err := k.resourceScale(options, scaleOptions)
+	rPut, err := k.resourceScale(ctx, options, scaleOptions)
 	if err != nil {
 		return nil, err
 	}
 	return rPut.Raw()
 }
 
+func (k *K8s) CustomResourceRestart(options K8sOptions, restartOptions K8sResourceRestartOptions) ([]byte, error) {
+	return k.CustomResourceRestartContext(context.Background(), options, restartOptions)
+}
+
 func (k *K8s) ResourceRestart(options K8sResourceRestartOptions) ([]byte, error) {
 	return k.CustomResourceRestart(k.options, options)
 }
 
+func (k *K8s) Name() string {
+	return "k8s"
+}
+
+func (k *K8s) Options() interface{} {
+	return k.options
+}
+
+// Check requests API discovery, it needs authenticated user only
+func (k *K8s) Check(ctx context.Context) error {
+
+	clientset, clientCtx, cancel, err := k.getClientCtx(ctx, k.options)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return clientset.Discovery().RESTClient().Get().AbsPath("/api").Do(clientCtx).Error()
+}
+
+func (k *K8s) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("resource-list", "List resources of kind", VendorOutputJSON, func(ctx context.Context, input K8sResourceListOptions) ([]byte, error) {
+			return k.CustomResourceListContext(ctx, k.options, input)
+		}),
+		NewVendorOperation("resource-describe", "Describe resource", VendorOutputJSON, func(ctx context.Context, input K8sResourceDescribeOptions) ([]byte, error) {
+			return k.CustomResourceDescribeContext(ctx, k.options, input)
+		}),
+		NewVendorOperation("resource-delete", "Delete resource", VendorOutputJSON, func(ctx context.Context, input K8sResourceDeleteOptions) ([]byte, error) {
+			return k.CustomResourceDeleteContext(ctx, k.options, input)
+		}),
+		NewVendorOperation("resource-scale", "Scale resource and wait replicas", VendorOutputJSON, func(ctx context.Context, input K8sResourceScaleOptions) ([]byte, error) {
+			return k.CustomResourceScaleContext(ctx, k.options, input)
+		}),
+		NewVendorOperation("resource-restart", "Restart resource by scaling to zero and back", VendorOutputJSON, func(ctx context.Context, input K8sResourceRestartOptions) ([]byte, error) {
+			return k.CustomResourceRestartContext(ctx, k.options, input)
+		}),
+	}
+}
+
 func NewK8s(options K8sOptions, logger common.Logger) *K8s {
 
 	clientset, _ := newK8sClient(options)
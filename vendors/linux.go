@@ -21,7 +21,7 @@ type SSHOptions struct {
 	Timeout    int
 }
 
-func (s *SSH) Run(options SSHOptions) ([]byte, error) {
+func (s *SSH) RunContext(ctx context.Context, options SSHOptions) ([]byte, error) {
 	key, err := ssh.ParsePrivateKey(options.PrivateKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse private key: %w", err)
@@ -57,8 +57,7 @@ func (s *SSH) Run(options SSHOptions) ([]byte, error) {
 	var b bytes.Buffer
 	session.Stdout = &b
 
-	ctx := context.Background()
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(options.Timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(options.Timeout)*time.Second)
 	defer cancel()
 
 	done := make(chan error, 1)
@@ -69,6 +68,9 @@ func (s *SSH) Run(options SSHOptions) ([]byte, error) {
 
 	select {
 	case <-ctx.Done():
+		if ctx.Err() == context.Canceled {
+			return nil, ctx.Err()
+		}
 		return nil, fmt.Errorf("SSH command timed out after %d seconds", options.Timeout)
 	case err := <-done:
 		if err != nil {
@@ -79,6 +81,10 @@ func (s *SSH) Run(options SSHOptions) ([]byte, error) {
 	return b.Bytes(), err
 }
 
+func (s *SSH) Run(options SSHOptions) ([]byte, error) {
+	return s.RunContext(context.Background(), options)
+}
+
 func NewSSH(options SSHOptions) *SSH {
 
 	ssh := &SSH{
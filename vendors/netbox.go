@@ -1,37 +1,33 @@
 package vendors
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"path"
-	"sync"
+	"strconv"
 	"time"
 
+	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
 )
 
-var bufferPool = sync.Pool{
-	New: func() interface{} {
-		return new(bytes.Buffer)
-	},
-}
-
 type NetboxOptions struct {
-	Timeout  int
-	Insecure bool
-	URL      string
-	Token    string
-	Limit    string
-	Brief    bool
-	Filter   map[string]string
+	Timeout    int
+	Insecure   bool
+	URL        string
+	Token      string
+	Limit      string
+	Brief      bool
+	Filter     map[string]string
+	HTTPClient *http.Client
 }
 
 type NetboxDeviceOptions struct {
 	DeviceID string
+	PageOptions
 }
 
 type Netbox struct {
@@ -244,7 +240,10 @@ func (n *Netbox) setParams(options NetboxOptions) url.Values {
 	return params
 }
 
-func (n *Netbox) CustomGetDevices(options NetboxOptions, netboxDeviceOptions NetboxDeviceOptions) ([]byte, error) {
+func (n *Netbox) CustomGetDevicesContext(ctx context.Context, options NetboxOptions, netboxDeviceOptions NetboxDeviceOptions) (b []byte, err error) {
+
+	n = n.withContext(ctx)
+	defer vendorError("netbox", "get-devices", &b, &err)
 
 	u, err := url.Parse(options.URL)
 	if err != nil {
@@ -258,67 +257,88 @@ func (n *Netbox) CustomGetDevices(options NetboxOptions, netboxDeviceOptions Net
 	if !utils.IsEmpty(netboxDeviceOptions.DeviceID) {
 		u.Path = path.Join(u.Path, fmt.Sprintf("%s/", netboxDeviceOptions.DeviceID))
 
-		return utils.HttpGetRaw(n.client, u.String(), "application/json", n.getAuth(options))
+		return common.HttpGetRaw(n.client, u.String(), "application/json", n.getAuth(options))
 	}
 
-	var devices []NetboxDevice
-
-	for {
-		buf := bufferPool.Get().(*bytes.Buffer)
-		defer bufferPool.Put(buf)
-		buf.Reset()
-
-		req, err := http.NewRequest("GET", u.String(), nil)
-		if err != nil {
-			return nil, err
+	// devices are complete unless limit is set, next links are followed
+	paging := netboxDeviceOptions.PageOptions.orAll()
+	if paging.Limit > 0 {
+		if size, err := strconv.Atoi(options.Limit); err != nil || paging.Limit < size {
+			params := u.Query()
+			params.Set("limit", strconv.Itoa(paging.Limit))
+			u.RawQuery = params.Encode()
 		}
+	}
 
-		req.Header.Set("Content-Type", "application/json")
-		if auth := n.getAuth(n.options); auth != "" {
-			req.Header.Set("Authorization", auth)
-		}
+	devices, err := PagesAll(ctx, paging, func(ctx context.Context, token string) (*Page[NetboxDevice], error) {
 
-		resp, err := n.client.Do(req)
+		next := u.String()
+		if !utils.IsEmpty(token) {
+			next = token
+		}
+		b, err := common.HttpGetRaw(n.client, next, "application/json", n.getAuth(options))
 		if err != nil {
-			return nil, err
+			return nil, common.VendorErrorFrom("netbox", "get-devices", b, err)
 		}
-		defer resp.Body.Close()
 
-		_, err = io.Copy(buf, resp.Body)
-		if err != nil {
+		var r NetxboxAPIResponse
+		if err := json.Unmarshal(b, &r); err != nil {
 			return nil, err
 		}
+		return &Page[NetboxDevice]{Items: r.Results, Next: r.Next}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(devices)
+}
 
-		var apiResp NetxboxAPIResponse
+func (n *Netbox) CustomGetDevices(options NetboxOptions, netboxDeviceOptions NetboxDeviceOptions) ([]byte, error) {
+	return n.CustomGetDevicesContext(context.Background(), options, netboxDeviceOptions)
+}
 
-		err = json.NewDecoder(buf).Decode(&apiResp)
-		if err != nil {
-			return nil, err
-		}
+func (n *Netbox) GetDevices(deviceOptions NetboxDeviceOptions) ([]byte, error) {
+	return n.CustomGetDevices(n.options, deviceOptions)
+}
 
-		devices = append(devices, apiResp.Results...)
+func (n *Netbox) Name() string {
+	return "netbox"
+}
 
-		if apiResp.Next == "" {
-			break
-		}
+func (n *Netbox) Options() interface{} {
+	return n.options
+}
 
-		u, err = url.Parse(apiResp.Next)
-		if err != nil {
-			return nil, err
-		}
+func (n *Netbox) Check(ctx context.Context) error {
+
+	u, err := url.Parse(n.options.URL)
+	if err != nil {
+		return err
 	}
+	u.Path = path.Join(u.Path, "/api/status/")
+	return checkVendorHttp(ctx, n.client, "netbox", "GET", u.String(), map[string]string{"Authorization": n.getAuth(n.options)}, nil)
+}
 
-	return json.Marshal(devices)
+func (n *Netbox) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("get-devices", "Get devices, all pages if device ID is empty", VendorOutputJSON, func(ctx context.Context, input NetboxDeviceOptions) ([]byte, error) {
+			return n.CustomGetDevicesContext(ctx, n.options, input)
+		}),
+	}
 }
 
-func (n *Netbox) GetDevices(deviceOptions NetboxDeviceOptions) ([]byte, error) {
-	return n.CustomGetDevices(n.options, deviceOptions)
+func (n *Netbox) withContext(ctx context.Context) *Netbox {
+
+	c := *n
+	c.client = common.HttpClientWithContext(ctx, n.client)
+	return &c
 }
 
 func NewNetbox(options NetboxOptions) *Netbox {
 
 	return &Netbox{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "netbox", options.Timeout, options.Insecure),
 		options: options,
 	}
 }
@@ -1,22 +1,25 @@
 package vendors
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
 
+	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
 )
 
 type ObserviumOptions struct {
-	Timeout  int
-	Insecure bool
-	URL      string
-	User     string
-	Password string
-	Token    string
+	Timeout    int
+	Insecure   bool
+	URL        string
+	User       string
+	Password   string
+	Token      string
+	HTTPClient *http.Client
 }
 
 type Observium struct {
@@ -39,7 +42,10 @@ func (o *Observium) getAuth(opts ObserviumOptions) string {
 	return auth
 }
 
-func (o *Observium) CustomGetDevices(options ObserviumOptions) ([]byte, error) {
+func (o *Observium) CustomGetDevicesContext(ctx context.Context, options ObserviumOptions) (b []byte, err error) {
+
+	o = o.withContext(ctx)
+	defer vendorError("observium", "get-devices", &b, &err)
 
 	u, err := url.Parse(options.URL)
 	if err != nil {
@@ -48,17 +54,51 @@ func (o *Observium) CustomGetDevices(options ObserviumOptions) ([]byte, error) {
 
 	u.Path = path.Join(u.Path, "/api/v0/devices/")
 
-	return utils.HttpGetRaw(o.client, u.String(), "application/json", o.getAuth(options))
+	return common.HttpGetRaw(o.client, u.String(), "application/json", o.getAuth(options))
+}
+
+func (o *Observium) CustomGetDevices(options ObserviumOptions) ([]byte, error) {
+	return o.CustomGetDevicesContext(context.Background(), options)
 }
 
 func (o *Observium) GetDevices() ([]byte, error) {
 	return o.CustomGetDevices(o.options)
 }
 
+func (o *Observium) Name() string {
+	return "observium"
+}
+
+func (o *Observium) Options() interface{} {
+	return o.options
+}
+
+func (o *Observium) Check(ctx context.Context) error {
+
+	_, err := o.CustomGetDevicesContext(ctx, o.options)
+	return err
+}
+
+func (o *Observium) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("get-devices", "Get devices", VendorOutputJSON, func(ctx context.Context, input struct{}) ([]byte, error) {
+			return o.CustomGetDevicesContext(ctx, o.options)
+		}),
+	}
+}
+
+func (o *Observium) withContext(ctx context.Context) *Observium {
+
+	c := *o
+	c.client = common.HttpClientWithContext(ctx, o.client)
+	return &c
+}
+
 func NewObservium(options ObserviumOptions) *Observium {
 
 	return &Observium{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "observium", options.Timeout, options.Insecure),
 		options: options,
 	}
 }
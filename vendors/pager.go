@@ -0,0 +1,144 @@
+package vendors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/devopsext/utils"
+)
+
+// PageOptions are options of list operations, Limit caps items and All fetches every page,
+// without them the first page is fetched only
+type PageOptions struct {
+	Limit  int
+	All    bool
+	Stream func(item interface{}) error `json:"-"` // gets items as pages are fetched, list operations return no items then
+}
+
+// Page is items of one response, Next is page number, cursor or URL of the next page and empty for the last one
+type Page[T any] struct {
+	Items []T
+	Next  string
+}
+
+// PageFetch gets page by token of previous page, empty token is the first page
+type PageFetch[T any] func(ctx context.Context, token string) (*Page[T], error)
+
+var errPagesStop = errors.New("pages stop")
+
+var pageLinkNextRegex = regexp.MustCompile(`<([^>]+)>\s*;[^,]*rel="?next"?`)
+
+// pageSize returns size of requested page, it's not bigger than limit, so limited lists are fetched with one request
+func (o PageOptions) pageSize(size int) int {
+
+	if o.Limit > 0 && o.Limit < size {
+		return o.Limit
+	}
+	return size
+}
+
+// orAll returns options which fetch every page unless limit is set, it's used by inventories which were complete before
+func (o PageOptions) orAll() PageOptions {
+
+	if o.Limit <= 0 {
+		o.All = true
+	}
+	return o
+}
+
+// Pages calls fn for items of pages until the last page or limit, so results are streamed page by page
+func Pages[T any](ctx context.Context, options PageOptions, fetch PageFetch[T], fn func(item T) error) error {
+
+	count := 0
+	token := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := fetch(ctx, token)
+		if err != nil {
+			return err
+		}
+		if page == nil {
+			return nil
+		}
+		for _, item := range page.Items {
+			if options.Limit > 0 && count >= options.Limit {
+				return nil
+			}
+			if err := fn(item); err != nil {
+				if errors.Is(err, errPagesStop) {
+					return nil
+				}
+				return err
+			}
+			count++
+		}
+		if utils.IsEmpty(page.Next) || page.Next == token || len(page.Items) == 0 {
+			return nil
+		}
+		if !options.All && (options.Limit <= 0 || count >= options.Limit) {
+			return nil
+		}
+		token = page.Next
+	}
+}
+
+// PagesAll returns items of pages, items are passed to Stream of options instead if it's set
+func PagesAll[T any](ctx context.Context, options PageOptions, fetch PageFetch[T]) ([]T, error) {
+
+	r := make([]T, 0)
+	err := Pages(ctx, options, fetch, func(item T) error {
+		if options.Stream != nil {
+			return options.Stream(item)
+		}
+		r = append(r, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// pageNumber returns page number of token, the first page is 1
+func pageNumber(token string) int {
+
+	n, err := strconv.Atoi(token)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// pageNumberNext returns token of page after n, total is count of pages and 0 if it's unknown, then full page has next
+func pageNumberNext(n, total, items, size int) string {
+
+	if (total > 0 && n >= total) || (total <= 0 && (items == 0 || items < size)) {
+		return ""
+	}
+	return strconv.Itoa(n + 1)
+}
+
+// pageOffsetNext returns offset of the next page if there are more items
+func pageOffsetNext(offset, items int, more bool) string {
+
+	if !more || items == 0 {
+		return ""
+	}
+	return strconv.Itoa(offset + items)
+}
+
+// pageLinkNext returns URL of rel="next" of Link header
+func pageLinkNext(header http.Header) string {
+
+	for _, link := range header.Values("Link") {
+		if m := pageLinkNextRegex.FindStringSubmatch(link); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package vendors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPages(t *testing.T) {
+
+	// three pages of three items
+	fetch := func(calls *int) PageFetch[int] {
+		return func(ctx context.Context, token string) (*Page[int], error) {
+			*calls++
+			n := pageNumber(token)
+			items := []int{n*10 + 1, n*10 + 2, n*10 + 3}
+			return &Page[int]{Items: items, Next: pageNumberNext(n, 3, len(items), 3)}, nil
+		}
+	}
+
+	tests := []struct {
+		name    string
+		options PageOptions
+		items   []int
+		calls   int
+	}{
+		{"first", PageOptions{}, []int{11, 12, 13}, 1},
+		{"limit", PageOptions{Limit: 5}, []int{11, 12, 13, 21, 22}, 2},
+		{"all", PageOptions{All: true}, []int{11, 12, 13, 21, 22, 23, 31, 32, 33}, 3},
+		{"all limit", PageOptions{Limit: 4, All: true}, []int{11, 12, 13, 21}, 2},
+		{"or all", PageOptions{}.orAll(), []int{11, 12, 13, 21, 22, 23, 31, 32, 33}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			calls := 0
+			items, err := PagesAll(context.Background(), tt.options, fetch(&calls))
+			require.NoError(t, err)
+			assert.Equal(t, tt.items, items)
+			assert.Equal(t, tt.calls, calls)
+		})
+	}
+
+	calls := 0
+	var items []int
+	err := Pages(context.Background(), PageOptions{All: true}, fetch(&calls), func(item int) error {
+		if item == 22 {
+			return errPagesStop
+		}
+		items = append(items, item)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{11, 12, 13, 21}, items)
+
+	// items are streamed instead of collected
+	var streamed []interface{}
+	all, err := PagesAll(context.Background(), PageOptions{Limit: 4, Stream: func(item interface{}) error {
+		streamed = append(streamed, item)
+		return nil
+	}}, fetch(&calls))
+	require.NoError(t, err)
+	assert.Empty(t, all)
+	assert.Equal(t, []interface{}{11, 12, 13, 21}, streamed)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = PagesAll(ctx, PageOptions{All: true}, fetch(&calls))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPagerDutyIncidentsPages(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset := r.URL.Query().Get("offset")
+		if offset == "" {
+			offset = "0"
+		}
+		more := offset == "0"
+		fmt.Fprintf(w, `{"incidents":[{"id":"P%s"}],"offset":%s,"limit":1,"more":%t}`, offset, offset, more)
+	}))
+	defer server.Close()
+
+	pd := NewPagerDuty(PagerDutyOptions{URL: server.URL, Token: "token", Timeout: 5}, nil)
+	b, err := pd.GetIncidents(PagerDutyGetIncidentsOptions{PageOptions: PageOptions{All: true, Limit: 10}})
+	require.NoError(t, err)
+
+	var r struct {
+		Incidents []struct {
+			ID string `json:"id"`
+		} `json:"incidents"`
+		Offset int  `json:"offset"`
+		More   bool `json:"more"`
+	}
+	require.NoError(t, json.Unmarshal(b, &r))
+	assert.Len(t, r.Incidents, 2)
+	assert.Equal(t, 0, r.Offset)
+	assert.False(t, r.More)
+}
+
+func TestPageNext(t *testing.T) {
+
+	header := http.Header{}
+	header.Add("Link", `<https://gitlab.example.com/api/v4/projects/1/pipelines?page=1>; rel="prev", <https://gitlab.example.com/api/v4/projects/1/pipelines?page=3>; rel="next"`)
+	assert.Equal(t, "https://gitlab.example.com/api/v4/projects/1/pipelines?page=3", pageLinkNext(header))
+	assert.Equal(t, "", pageLinkNext(http.Header{}))
+
+	assert.Equal(t, "150", pageOffsetNext(100, 50, true))
+	assert.Equal(t, "", pageOffsetNext(100, 50, false))
+	assert.Equal(t, "2", pageNumberNext(1, 0, 25, 25))
+	assert.Equal(t, "", pageNumberNext(2, 0, 10, 25))
+}
@@ -1,11 +1,13 @@
 package vendors
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
@@ -29,8 +31,8 @@ type PagerDutyIncidentNoteOptions struct {
 }
 
 type PagerDutyGetIncidentsOptions struct {
-	Key   string
-	Limit int
+	Key string
+	PageOptions
 }
 
 type PagerDutyService struct {
@@ -75,10 +77,11 @@ type PagerDutyIncidentNoteRequest struct {
 }
 
 type PagerDutyOptions struct {
-	Timeout  int
-	Insecure bool
-	URL      string
-	Token    string
+	Timeout    int
+	Insecure   bool
+	URL        string
+	Token      string
+	HTTPClient *http.Client
 }
 
 type PagerDuty struct {
@@ -91,6 +94,7 @@ const (
 	pagerDutyContentType       = "application/json"
 	pagerDutyIncidentsPath     = "/incidents"
 	pagerDutyIncidentNotesPath = "/notes"
+	pagerDutyPageSizeMax       = 100
 )
 
 func (pd *PagerDuty) getAuth(options PagerDutyOptions) string {
@@ -101,7 +105,10 @@ func (pd *PagerDuty) getAuth(options PagerDutyOptions) string {
 	return auth
 }
 
-func (pd *PagerDuty) CustomCreateIncident(options PagerDutyOptions, incidentOptions PagerDutyIncidentOptions, createOptions PagerDutyCreateIncidentOptions) ([]byte, error) {
+func (pd *PagerDuty) CustomCreateIncidentContext(ctx context.Context, options PagerDutyOptions, incidentOptions PagerDutyIncidentOptions, createOptions PagerDutyCreateIncidentOptions) (b []byte, err error) {
+
+	pd = pd.withContext(ctx)
+	defer vendorError("pagerduty", "create-incident", &b, &err)
 
 	u, err := url.Parse(options.URL)
 	if err != nil {
@@ -160,14 +167,21 @@ func (pd *PagerDuty) CustomCreateIncident(options PagerDutyOptions, incidentOpti
 		return nil, err
 	}
 
-	return utils.HttpPostRaw(pd.client, u.String(), pagerDutyContentType, pd.getAuth(options), data)
+	return common.HttpPostRaw(pd.client, u.String(), pagerDutyContentType, pd.getAuth(options), data)
+}
+
+func (pd *PagerDuty) CustomCreateIncident(options PagerDutyOptions, incidentOptions PagerDutyIncidentOptions, createOptions PagerDutyCreateIncidentOptions) ([]byte, error) {
+	return pd.CustomCreateIncidentContext(context.Background(), options, incidentOptions, createOptions)
 }
 
 func (pd *PagerDuty) CreateIncident(incidentOptions PagerDutyIncidentOptions, createOptions PagerDutyCreateIncidentOptions) ([]byte, error) {
 	return pd.CustomCreateIncident(pd.options, incidentOptions, createOptions)
 }
 
-func (pd *PagerDuty) CustomCreateIncidentNote(options PagerDutyOptions, noteOptions PagerDutyIncidentNoteOptions, createOptions PagerDutyCreateIncidentOptions) ([]byte, error) {
+func (pd *PagerDuty) CustomCreateIncidentNoteContext(ctx context.Context, options PagerDutyOptions, noteOptions PagerDutyIncidentNoteOptions, createOptions PagerDutyCreateIncidentOptions) (b []byte, err error) {
+
+	pd = pd.withContext(ctx)
+	defer vendorError("pagerduty", "create-incident-note", &b, &err)
 
 	u, err := url.Parse(options.URL)
 	if err != nil {
@@ -194,14 +208,21 @@ func (pd *PagerDuty) CustomCreateIncidentNote(options PagerDutyOptions, noteOpti
 		return nil, err
 	}
 
-	return utils.HttpPostRaw(pd.client, u.String(), pagerDutyContentType, pd.getAuth(options), data)
+	return common.HttpPostRaw(pd.client, u.String(), pagerDutyContentType, pd.getAuth(options), data)
+}
+
+func (pd *PagerDuty) CustomCreateIncidentNote(options PagerDutyOptions, noteOptions PagerDutyIncidentNoteOptions, createOptions PagerDutyCreateIncidentOptions) ([]byte, error) {
+	return pd.CustomCreateIncidentNoteContext(context.Background(), options, noteOptions, createOptions)
 }
 
 func (pd *PagerDuty) CreateIncidentNote(noteOptions PagerDutyIncidentNoteOptions, createOptions PagerDutyCreateIncidentOptions) ([]byte, error) {
 	return pd.CustomCreateIncidentNote(pd.options, noteOptions, createOptions)
 }
 
-func (pd *PagerDuty) CustomGetIncidents(options PagerDutyOptions, getOptions PagerDutyGetIncidentsOptions) ([]byte, error) {
+func (pd *PagerDuty) CustomGetIncidentsContext(ctx context.Context, options PagerDutyOptions, getOptions PagerDutyGetIncidentsOptions) (b []byte, err error) {
+
+	pd = pd.withContext(ctx)
+	defer vendorError("pagerduty", "get-incidents", &b, &err)
 
 	u, err := url.Parse(options.URL)
 	if err != nil {
@@ -212,22 +233,117 @@ func (pd *PagerDuty) CustomGetIncidents(options PagerDutyOptions, getOptions Pag
 	if !utils.IsEmpty(getOptions.Key) {
 		params.Add("incident_key", getOptions.Key)
 	}
-	if getOptions.Limit > 0 {
-		params.Add("limit", fmt.Sprintf("%d", getOptions.Limit))
+	paging := getOptions.PageOptions
+	if paging.Limit > 0 {
+		params.Add("limit", fmt.Sprintf("%d", paging.pageSize(pagerDutyPageSizeMax)))
 	}
-	u.RawQuery = params.Encode()
 	u.Path = path.Join(u.Path, pagerDutyIncidentsPath)
 
-	return utils.HttpGetRaw(pd.client, u.String(), pagerDutyContentType, pd.getAuth(options))
+	// incidents of pages are merged into response of the first page, offset pagination is used
+	var first, last map[string]json.RawMessage
+	incidents, err := PagesAll(ctx, paging, func(ctx context.Context, token string) (*Page[json.RawMessage], error) {
+
+		offset := 0
+		if !utils.IsEmpty(token) {
+			offset, _ = strconv.Atoi(token)
+			params.Set("offset", token)
+		}
+		u.RawQuery = params.Encode()
+
+		b, err := common.HttpGetRaw(pd.client, u.String(), pagerDutyContentType, pd.getAuth(options))
+		if err != nil {
+			return nil, common.VendorErrorFrom("pagerduty", "get-incidents", b, err)
+		}
+
+		var r struct {
+			Incidents []json.RawMessage `json:"incidents"`
+			More      bool              `json:"more"`
+		}
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, err
+		}
+		// every page is decoded into new map, so fields of the first page are kept
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, err
+		}
+		if first == nil {
+			first = m
+		}
+		last = m
+		return &Page[json.RawMessage]{Items: r.Incidents, Next: pageOffsetNext(offset, len(r.Incidents), r.More)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	first["incidents"], err = json.Marshal(incidents)
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := last["more"]; ok {
+		first["more"] = v
+	}
+	return json.Marshal(first)
+}
+
+func (pd *PagerDuty) CustomGetIncidents(options PagerDutyOptions, getOptions PagerDutyGetIncidentsOptions) ([]byte, error) {
+	return pd.CustomGetIncidentsContext(context.Background(), options, getOptions)
 }
 func (pd *PagerDuty) GetIncidents(getOptions PagerDutyGetIncidentsOptions) ([]byte, error) {
 	return pd.CustomGetIncidents(pd.options, getOptions)
 }
 
+func (pd *PagerDuty) Name() string {
+	return "pagerduty"
+}
+
+func (pd *PagerDuty) Options() interface{} {
+	return pd.options
+}
+
+func (pd *PagerDuty) Check(ctx context.Context) error {
+
+	u, err := url.Parse(pd.options.URL)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, "/abilities")
+	return checkVendorHttp(ctx, pd.client, "pagerduty", "GET", u.String(), map[string]string{"Authorization": pd.getAuth(pd.options)}, nil)
+}
+
+func (pd *PagerDuty) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("create-incident", "Create incident", VendorOutputJSON, func(ctx context.Context, input struct {
+			Incident PagerDutyIncidentOptions
+			Create   PagerDutyCreateIncidentOptions
+		}) ([]byte, error) {
+			return pd.CustomCreateIncidentContext(ctx, pd.options, input.Incident, input.Create)
+		}),
+		NewVendorOperation("create-incident-note", "Add note to incident", VendorOutputJSON, func(ctx context.Context, input struct {
+			Note   PagerDutyIncidentNoteOptions
+			Create PagerDutyCreateIncidentOptions
+		}) ([]byte, error) {
+			return pd.CustomCreateIncidentNoteContext(ctx, pd.options, input.Note, input.Create)
+		}),
+		NewVendorOperation("get-incidents", "Get incidents", VendorOutputJSON, func(ctx context.Context, input PagerDutyGetIncidentsOptions) ([]byte, error) {
+			return pd.CustomGetIncidentsContext(ctx, pd.options, input)
+		}),
+	}
+}
+
+func (pd *PagerDuty) withContext(ctx context.Context) *PagerDuty {
+
+	c := *pd
+	c.client = common.HttpClientWithContext(ctx, pd.client)
+	return &c
+}
+
 func NewPagerDuty(options PagerDutyOptions, logger common.Logger) *PagerDuty {
 
 	return &PagerDuty{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "pagerduty", options.Timeout, options.Insecure),
 		options: options,
 		logger:  logger,
 	}
@@ -1,6 +1,7 @@
 package vendors
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -13,16 +14,17 @@ import (
 )
 
 type PrometheusOptions struct {
-	URL      string
-	User     string
-	Password string
-	Timeout  int
-	Insecure bool
-	Query    string
-	From     string
-	To       string
-	Step     string
-	Params   string
+	URL        string
+	User       string
+	Password   string
+	Timeout    int
+	Insecure   bool
+	Query      string
+	From       string
+	To         string
+	Step       string
+	Params     string
+	HTTPClient *http.Client
 }
 type PrometheusOutputOptions struct {
 	Output      string
@@ -56,7 +58,10 @@ func (p *Prometheus) toPrometheusTimestamp(ts string) string {
 	return fmt.Sprintf("%d", i)
 }
 
-func (p *Prometheus) CustomGet(options PrometheusOptions) ([]byte, error) {
+func (p *Prometheus) CustomGetContext(ctx context.Context, options PrometheusOptions) (b []byte, err error) {
+
+	p = p.withContext(ctx)
+	defer vendorError("prometheus", "get", &b, &err)
 
 	params := make(url.Values)
 	params.Add("query", options.Query)
@@ -98,17 +103,61 @@ func (p *Prometheus) CustomGet(options PrometheusOptions) ([]byte, error) {
 		authorization = common.FormatBasicAuth(options.User, options.Password)
 	}
 
-	return utils.HttpGetRaw(p.client, u.String(), "application/json", authorization)
+	return common.HttpGetRaw(p.client, u.String(), "application/json", authorization)
+}
+
+func (p *Prometheus) CustomGet(options PrometheusOptions) ([]byte, error) {
+	return p.CustomGetContext(context.Background(), options)
 }
 
 func (p *Prometheus) Get() ([]byte, error) {
 	return p.CustomGet(p.options)
 }
 
+func (p *Prometheus) Name() string {
+	return "prometheus"
+}
+
+func (p *Prometheus) Options() interface{} {
+	return p.options
+}
+
+func (p *Prometheus) Check(ctx context.Context) error {
+
+	u, err := url.Parse(p.options.URL)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, "/api/v1/status/buildinfo")
+
+	headers := make(map[string]string)
+	if !utils.IsEmpty(p.options.User) && !utils.IsEmpty(p.options.Password) {
+		headers["Authorization"] = common.FormatBasicAuth(p.options.User, p.options.Password)
+	}
+	return checkVendorHttp(ctx, p.client, "prometheus", "GET", u.String(), headers, nil)
+}
+
+// Operations of prometheus use query of options, so it's set per call with options
+func (p *Prometheus) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("get", "Query instant or range of options", VendorOutputJSON, func(ctx context.Context, input struct{}) ([]byte, error) {
+			return p.CustomGetContext(ctx, p.options)
+		}),
+	}
+}
+
+func (p *Prometheus) withContext(ctx context.Context) *Prometheus {
+
+	c := *p
+	c.client = common.HttpClientWithContext(ctx, p.client)
+	return &c
+}
+
 func NewPrometheus(options PrometheusOptions) *Prometheus {
 
 	return &Prometheus{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "prometheus", options.Timeout, options.Insecure),
 		options: options,
 	}
 }
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -214,6 +216,8 @@ type Site24x7LogReportReponse struct {
 }
 
 type Site24x7Options struct {
+	URL          string // www.site24x7.com by default
+	AuthURL      string // accounts.zoho.com by default
 	Timeout      int
 	Insecure     bool
 	ClientID     string
@@ -249,27 +253,38 @@ const (
 	Site24x7DataCollectionTypePollNow = "3"
 )
 
+// CheckResponse returns common.VendorError with code and message of response
 func (s *Site24x7) CheckResponse(resp Site24x7Reponse) error {
 
 	if resp.Code != 0 {
-		return fmt.Errorf("%s [%d]", resp.Message, resp.Code)
+		return &common.VendorError{Vendor: "site24x7", Code: strconv.Itoa(resp.Code), Message: resp.Message}
 	}
 	return nil
 }
 
+// CheckError returns common.VendorError with error code and message of response, status is taken from e
 func (s *Site24x7) CheckError(data []byte, e error) error {
 
 	r := Site24x7ErrorReponse{}
 
 	err := json.Unmarshal(data, &r)
 	if err != nil {
+		if e != nil {
+			return common.VendorErrorFrom("site24x7", "", data, e)
+		}
 		return err
 	}
 
 	if r.ErrorCode != 0 {
-		return fmt.Errorf("%s [%d]", r.Message, r.ErrorCode)
+		ve := &common.VendorError{Vendor: "site24x7", Err: e}
+		if e != nil {
+			errors.As(common.VendorErrorFrom("site24x7", "", nil, e), &ve)
+		}
+		ve.Code = strconv.Itoa(r.ErrorCode)
+		ve.Message = r.Message
+		return ve
 	}
-	return e
+	return common.VendorErrorFrom("site24x7", "", data, e)
 }
 
 // go to https://api-console.zoho.com/ and generate code with Site24x7.Admin.All scope
@@ -307,12 +322,12 @@ func (s *Site24x7) getTokenAuth(opts Site24x7Options) (*Site24x7AuthReponse, err
 		return nil, err
 	}
 
-	u, err := url.Parse(ZohoOAuthV2TokenURL)
+	u, err := url.Parse(s.tokenURL(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	d, err := utils.HttpPostRaw(s.client, u.String(), w.FormDataContentType(), "", body.Bytes())
+	d, err := common.HttpPostRaw(s.client, u.String(), w.FormDataContentType(), "", body.Bytes())
 	if err != nil {
 		return nil, s.CheckError(d, err)
 	}
@@ -325,6 +340,22 @@ func (s *Site24x7) getTokenAuth(opts Site24x7Options) (*Site24x7AuthReponse, err
 	return &r, nil
 }
 
+func (s *Site24x7) apiURL(opts Site24x7Options) string {
+
+	if utils.IsEmpty(opts.URL) {
+		return Site24x7ApiURL
+	}
+	return strings.TrimSuffix(opts.URL, "/") + "/api"
+}
+
+func (s *Site24x7) tokenURL(opts Site24x7Options) string {
+
+	if utils.IsEmpty(opts.AuthURL) {
+		return ZohoOAuthV2TokenURL
+	}
+	return strings.TrimSuffix(opts.AuthURL, "/") + "/oauth/v2/token"
+}
+
 func (s *Site24x7) getAuth(token string) string {
 	return fmt.Sprintf("Zoho-oauthtoken %s", token)
 }
@@ -345,7 +376,10 @@ func (s *Site24x7) cloneSite24x7Options(opts Site24x7Options) Site24x7Options {
 	return r
 }
 
-func (s *Site24x7) CustomGetAccessToken(opts Site24x7Options) (string, error) {
+func (s *Site24x7) CustomGetAccessTokenContext(ctx context.Context, opts Site24x7Options) (_ string, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "get-access-token", nil, &err)
 
 	if !utils.IsEmpty(opts.AccessToken) {
 		return opts.AccessToken, nil
@@ -353,49 +387,70 @@ func (s *Site24x7) CustomGetAccessToken(opts Site24x7Options) (string, error) {
 	return s.getAccessToken(opts)
 }
 
-func (s *Site24x7) CustomGetLocationTemplate(site24x7Options Site24x7Options) ([]byte, error) {
+func (s *Site24x7) CustomGetAccessToken(opts Site24x7Options) (string, error) {
+	return s.CustomGetAccessTokenContext(context.Background(), opts)
+}
+
+func (s *Site24x7) CustomGetLocationTemplateContext(ctx context.Context, site24x7Options Site24x7Options) (b []byte, err error) {
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "get-location-template", &b, &err)
+
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, Site24x7LocationTemplate)
 
-	return utils.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+	return common.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+}
+
+func (s *Site24x7) CustomGetLocationTemplate(site24x7Options Site24x7Options) ([]byte, error) {
+	return s.CustomGetLocationTemplateContext(context.Background(), site24x7Options)
 }
 
 func (s *Site24x7) GetLocationTemplate() ([]byte, error) {
 	return s.CustomGetLocationTemplate(s.options)
 }
 
-func (s *Site24x7) CustomGetLocationProfiles(site24x7Options Site24x7Options) ([]byte, error) {
+func (s *Site24x7) CustomGetLocationProfilesContext(ctx context.Context, site24x7Options Site24x7Options) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "get-location-profiles", &b, &err)
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, Site24x7LocationProfiles)
 
-	return utils.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+	return common.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+}
+
+func (s *Site24x7) CustomGetLocationProfiles(site24x7Options Site24x7Options) ([]byte, error) {
+	return s.CustomGetLocationProfilesContext(context.Background(), site24x7Options)
 }
 
 func (s *Site24x7) GetLocationProfiles() ([]byte, error) {
 	return s.CustomGetLocationProfiles(s.options)
 }
 
-func (s *Site24x7) FindLocationProfileByName(site24x7Options Site24x7Options, name string) (string, error) {
+func (s *Site24x7) FindLocationProfileByNameContext(ctx context.Context, site24x7Options Site24x7Options, name string) (_ string, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "find-location-profile-by-name", nil, &err)
 
-	d, err := s.CustomGetLocationProfiles(site24x7Options)
+	d, err := s.CustomGetLocationProfilesContext(ctx, site24x7Options)
 	if err != nil {
 		return "", s.CheckError(d, err)
 	}
@@ -425,14 +480,21 @@ func (s *Site24x7) FindLocationProfileByName(site24x7Options Site24x7Options, na
 	return lp.ProfileID, nil
 }
 
-func (s *Site24x7) CustomCreateLocationProfile(site24x7Options Site24x7Options, createLocationOptions Site24x7LocationProfileOptions) ([]byte, error) {
+func (s *Site24x7) FindLocationProfileByName(site24x7Options Site24x7Options, name string) (string, error) {
+	return s.FindLocationProfileByNameContext(context.Background(), site24x7Options, name)
+}
+
+func (s *Site24x7) CustomCreateLocationProfileContext(ctx context.Context, site24x7Options Site24x7Options, createLocationOptions Site24x7LocationProfileOptions) (b []byte, err error) {
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "create-location-profile", &b, &err)
+
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
@@ -449,27 +511,38 @@ func (s *Site24x7) CustomCreateLocationProfile(site24x7Options Site24x7Options,
 		return nil, err
 	}
 
-	return utils.HttpPostRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), req)
+	return common.HttpPostRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), req)
+}
+
+func (s *Site24x7) CustomCreateLocationProfile(site24x7Options Site24x7Options, createLocationOptions Site24x7LocationProfileOptions) ([]byte, error) {
+	return s.CustomCreateLocationProfileContext(context.Background(), site24x7Options, createLocationOptions)
 }
 
 func (s *Site24x7) CreateLocationProfile(options Site24x7LocationProfileOptions) ([]byte, error) {
 	return s.CustomCreateLocationProfile(s.options, options)
 }
 
-func (s *Site24x7) CustomDeleteLocationProfile(site24x7Options Site24x7Options, deleteLocationOptions Site24x7LocationProfileOptions) ([]byte, error) {
+func (s *Site24x7) CustomDeleteLocationProfileContext(ctx context.Context, site24x7Options Site24x7Options, deleteLocationOptions Site24x7LocationProfileOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "delete-location-profile", &b, &err)
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, Site24x7LocationProfiles, deleteLocationOptions.ID)
 
-	return utils.HttpDeleteRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), nil)
+	return common.HttpDeleteRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), nil)
+}
+
+func (s *Site24x7) CustomDeleteLocationProfile(site24x7Options Site24x7Options, deleteLocationOptions Site24x7LocationProfileOptions) ([]byte, error) {
+	return s.CustomDeleteLocationProfileContext(context.Background(), site24x7Options, deleteLocationOptions)
 }
 
 func (s *Site24x7) DeleteLocationProfile(options Site24x7LocationProfileOptions) ([]byte, error) {
@@ -492,33 +565,43 @@ func (s *Site24x7) FindLocationByCountry(locations []*Site24x7LocationTemplateDa
 	return nil
 }
 
-func (s *Site24x7) CustomRetrieveMonitorByName(site24x7Options Site24x7Options, name string) ([]byte, error) {
+func (s *Site24x7) CustomRetrieveMonitorByNameContext(ctx context.Context, site24x7Options Site24x7Options, name string) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "retrieve-monitor-by-name", &b, &err)
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, Site24x7MonitorsName, name)
 
-	return utils.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+	return common.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+}
+
+func (s *Site24x7) CustomRetrieveMonitorByName(site24x7Options Site24x7Options, name string) ([]byte, error) {
+	return s.CustomRetrieveMonitorByNameContext(context.Background(), site24x7Options, name)
 }
 
 func (s *Site24x7) RetrieveMonitorByName(name string) ([]byte, error) {
 	return s.CustomRetrieveMonitorByName(s.options, name)
 }
 
-func (s *Site24x7) CustomCreateWebsiteMonitor(site24x7Options Site24x7Options, createMonitorOptions Site24x7WebsiteMonitorOptions) ([]byte, error) {
+func (s *Site24x7) CustomCreateWebsiteMonitorContext(ctx context.Context, site24x7Options Site24x7Options, createMonitorOptions Site24x7WebsiteMonitorOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "create-website-monitor", &b, &err)
 
 	if len(createMonitorOptions.Countries) == 0 {
 		return nil, fmt.Errorf("no countries defined")
 	}
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
@@ -532,7 +615,7 @@ func (s *Site24x7) CustomCreateWebsiteMonitor(site24x7Options Site24x7Options, c
 
 	if utils.IsEmpty(profileID) {
 
-		d, err := s.CustomGetLocationTemplate(opts)
+		d, err := s.CustomGetLocationTemplateContext(ctx, opts)
 		if err != nil {
 			return nil, s.CheckError(d, err)
 		}
@@ -567,7 +650,7 @@ func (s *Site24x7) CustomCreateWebsiteMonitor(site24x7Options Site24x7Options, c
 			SecondaryLocationIDs: secondaryIDs,
 		}
 
-		d, err = s.CustomCreateLocationProfile(opts, lopts)
+		d, err = s.CustomCreateLocationProfileContext(ctx, opts, lopts)
 		if err != nil {
 			return nil, s.CheckError(d, err)
 		}
@@ -585,7 +668,7 @@ func (s *Site24x7) CustomCreateWebsiteMonitor(site24x7Options Site24x7Options, c
 		profileID = lr.Data.ProfileID
 	}
 
-	d, err := s.CustomRetrieveMonitorByName(opts, name)
+	d, err := s.CustomRetrieveMonitorByNameContext(ctx, opts, name)
 	if err == nil {
 		rr := Site24x7WebsiteMonitorResponse{}
 		err = json.Unmarshal(d, &rr)
@@ -600,12 +683,12 @@ func (s *Site24x7) CustomCreateWebsiteMonitor(site24x7Options Site24x7Options, c
 
 		if rr.Data != nil {
 
-			s.CustomActivateMonitor(opts, Site24x7MonitorOptions{ID: rr.Data.MonitorID})
+			s.CustomActivateMonitorContext(ctx, opts, Site24x7MonitorOptions{ID: rr.Data.MonitorID})
 			return d, nil
 		}
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
@@ -640,87 +723,119 @@ func (s *Site24x7) CustomCreateWebsiteMonitor(site24x7Options Site24x7Options, c
 		return nil, err
 	}
 
-	return utils.HttpPostRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), req)
+	return common.HttpPostRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), req)
+}
+
+func (s *Site24x7) CustomCreateWebsiteMonitor(site24x7Options Site24x7Options, createMonitorOptions Site24x7WebsiteMonitorOptions) ([]byte, error) {
+	return s.CustomCreateWebsiteMonitorContext(context.Background(), site24x7Options, createMonitorOptions)
 }
 
 func (s *Site24x7) CreateWebsiteMonitor(options Site24x7WebsiteMonitorOptions) ([]byte, error) {
 	return s.CustomCreateWebsiteMonitor(s.options, options)
 }
 
-func (s *Site24x7) CustomDeleteMonitor(site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) ([]byte, error) {
+func (s *Site24x7) CustomDeleteMonitorContext(ctx context.Context, site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "delete-monitor", &b, &err)
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, Site24x7Monitors, monitorOptions.ID)
 
-	return utils.HttpDeleteRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), nil)
+	return common.HttpDeleteRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), nil)
+}
+
+func (s *Site24x7) CustomDeleteMonitor(site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) ([]byte, error) {
+	return s.CustomDeleteMonitorContext(context.Background(), site24x7Options, monitorOptions)
 }
 
 func (s *Site24x7) DeleteMonitor(options Site24x7MonitorOptions) ([]byte, error) {
 	return s.CustomDeleteMonitor(s.options, options)
 }
 
-func (s *Site24x7) CustomActivateMonitor(site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) ([]byte, error) {
+func (s *Site24x7) CustomActivateMonitorContext(ctx context.Context, site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "activate-monitor", &b, &err)
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, Site24x7MonitorsActivate, monitorOptions.ID)
 
-	return utils.HttpDeleteRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), nil)
+	return common.HttpDeleteRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), nil)
+}
+
+func (s *Site24x7) CustomActivateMonitor(site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) ([]byte, error) {
+	return s.CustomActivateMonitorContext(context.Background(), site24x7Options, monitorOptions)
 }
 
 func (s *Site24x7) ActivateMonitor(options Site24x7MonitorOptions) ([]byte, error) {
 	return s.CustomActivateMonitor(s.options, options)
 }
 
-func (s *Site24x7) CustomSuspendMonitor(site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) ([]byte, error) {
+func (s *Site24x7) CustomSuspendMonitorContext(ctx context.Context, site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) (b []byte, err error) {
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "suspend-monitor", &b, &err)
+
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, Site24x7MonitorsSuspend, monitorOptions.ID)
 
-	return utils.HttpDeleteRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), nil)
+	return common.HttpDeleteRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at), nil)
+}
+
+func (s *Site24x7) CustomSuspendMonitor(site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) ([]byte, error) {
+	return s.CustomSuspendMonitorContext(context.Background(), site24x7Options, monitorOptions)
 }
 
 func (s *Site24x7) SuspendMonitor(options Site24x7MonitorOptions) ([]byte, error) {
 	return s.CustomSuspendMonitor(s.options, options)
 }
 
-func (s *Site24x7) CustomPollMonitor(site24x7Options Site24x7Options, pollMonitorOptions Site24x7MonitorOptions) ([]byte, error) {
+func (s *Site24x7) CustomPollMonitorContext(ctx context.Context, site24x7Options Site24x7Options, pollMonitorOptions Site24x7MonitorOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "poll-monitor", &b, &err)
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, Site24x7MonitorPollNow, pollMonitorOptions.ID)
 
-	return utils.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+	return common.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+}
+
+func (s *Site24x7) CustomPollMonitor(site24x7Options Site24x7Options, pollMonitorOptions Site24x7MonitorOptions) ([]byte, error) {
+	return s.CustomPollMonitorContext(context.Background(), site24x7Options, pollMonitorOptions)
 }
 
 func (s *Site24x7) PollMonitor(options Site24x7MonitorOptions) ([]byte, error) {
@@ -737,7 +852,7 @@ func (s *Site24x7) PollMonitorWait(ctx context.Context, site24x7Options Site24x7
 			return false
 		case <-time.After(t):
 
-			d, err := s.CustomGetPollingStatus(site24x7Options, monitorOptions)
+			d, err := s.CustomGetPollingStatusContext(ctx, site24x7Options, monitorOptions)
 			if err != nil {
 				continue
 			}
@@ -761,34 +876,44 @@ func (s *Site24x7) PollMonitorWait(ctx context.Context, site24x7Options Site24x7
 	}
 }
 
-func (s *Site24x7) CustomGetPollingStatus(site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) ([]byte, error) {
+func (s *Site24x7) CustomGetPollingStatusContext(ctx context.Context, site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "get-polling-status", &b, &err)
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
 	u.Path = path.Join(u.Path, Site24x7MonitorStatusPollNow, monitorOptions.ID)
 
-	return utils.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+	return common.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+}
+
+func (s *Site24x7) CustomGetPollingStatus(site24x7Options Site24x7Options, monitorOptions Site24x7MonitorOptions) ([]byte, error) {
+	return s.CustomGetPollingStatusContext(context.Background(), site24x7Options, monitorOptions)
 }
 
 func (s *Site24x7) GetPollingStatus(options Site24x7MonitorOptions) ([]byte, error) {
 	return s.CustomGetPollingStatus(s.options, options)
 }
 
-func (s *Site24x7) CustomGetLogReport(site24x7Options Site24x7Options, logReportOptions Site24x7LogReportOptions) ([]byte, error) {
+func (s *Site24x7) CustomGetLogReportContext(ctx context.Context, site24x7Options Site24x7Options, logReportOptions Site24x7LogReportOptions) (b []byte, err error) {
 
-	at, err := s.CustomGetAccessToken(site24x7Options)
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "get-log-report", &b, &err)
+
+	at, err := s.CustomGetAccessTokenContext(ctx, site24x7Options)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := url.Parse(Site24x7ApiURL)
+	u, err := url.Parse(s.apiURL(site24x7Options))
 	if err != nil {
 		return nil, err
 	}
@@ -806,19 +931,90 @@ func (s *Site24x7) CustomGetLogReport(site24x7Options Site24x7Options, logReport
 
 	u.Path = path.Join(u.Path, Site24x7LogReports, logReportOptions.ID)
 	u.RawQuery = params.Encode()
-	return utils.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+	return common.HttpGetRaw(s.client, u.String(), Site24x7ContentType, s.getAuth(at))
+}
+
+func (s *Site24x7) CustomGetLogReport(site24x7Options Site24x7Options, logReportOptions Site24x7LogReportOptions) ([]byte, error) {
+	return s.CustomGetLogReportContext(context.Background(), site24x7Options, logReportOptions)
+}
+
+func (s *Site24x7) GetLogReportContext(ctx context.Context, options Site24x7LogReportOptions) (b []byte, err error) {
+	s = s.withContext(ctx)
+	defer vendorError("site24x7", "get-log-report", &b, &err)
+
+	return s.CustomGetLogReportContext(ctx, s.options, options)
 }
 
 func (s *Site24x7) GetLogReport(options Site24x7LogReportOptions) ([]byte, error) {
-	return s.CustomGetLogReport(s.options, options)
+	return s.GetLogReportContext(context.Background(), options)
 }
 
-func NewSite24x7(options Site24x7Options, logger common.Logger) *Site24x7 {
+func (s *Site24x7) Name() string {
+	return "site24x7"
+}
 
-	client := options.HTTPClient
-	if client == nil {
-		client = utils.NewHttpClient(options.Timeout, options.Insecure)
+func (s *Site24x7) Options() interface{} {
+	return s.options
+}
+
+func (s *Site24x7) Check(ctx context.Context) error {
+
+	_, err := s.CustomGetAccessTokenContext(ctx, s.options)
+	return err
+}
+
+func (s *Site24x7) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("get-location-template", "Get location template", VendorOutputJSON, func(ctx context.Context, input struct{}) ([]byte, error) {
+			return s.CustomGetLocationTemplateContext(ctx, s.options)
+		}),
+		NewVendorOperation("get-location-profiles", "Get location profiles", VendorOutputJSON, func(ctx context.Context, input struct{}) ([]byte, error) {
+			return s.CustomGetLocationProfilesContext(ctx, s.options)
+		}),
+		NewVendorOperation("create-location-profile", "Create location profile", VendorOutputJSON, func(ctx context.Context, input Site24x7LocationProfileOptions) ([]byte, error) {
+			return s.CustomCreateLocationProfileContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("delete-location-profile", "Delete location profile", VendorOutputJSON, func(ctx context.Context, input Site24x7LocationProfileOptions) ([]byte, error) {
+			return s.CustomDeleteLocationProfileContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("retrieve-monitor-by-name", "Find monitor by name", VendorOutputJSON, func(ctx context.Context, input struct{ Name string }) ([]byte, error) {
+			return s.CustomRetrieveMonitorByNameContext(ctx, s.options, input.Name)
+		}),
+		NewVendorOperation("create-website-monitor", "Create website monitor", VendorOutputJSON, func(ctx context.Context, input Site24x7WebsiteMonitorOptions) ([]byte, error) {
+			return s.CustomCreateWebsiteMonitorContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("delete-monitor", "Delete monitor", VendorOutputJSON, func(ctx context.Context, input Site24x7MonitorOptions) ([]byte, error) {
+			return s.CustomDeleteMonitorContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("activate-monitor", "Activate monitor", VendorOutputJSON, func(ctx context.Context, input Site24x7MonitorOptions) ([]byte, error) {
+			return s.CustomActivateMonitorContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("suspend-monitor", "Suspend monitor", VendorOutputJSON, func(ctx context.Context, input Site24x7MonitorOptions) ([]byte, error) {
+			return s.CustomSuspendMonitorContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("poll-monitor", "Poll monitor", VendorOutputJSON, func(ctx context.Context, input Site24x7MonitorOptions) ([]byte, error) {
+			return s.CustomPollMonitorContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("get-polling-status", "Get polling status of monitor", VendorOutputJSON, func(ctx context.Context, input Site24x7MonitorOptions) ([]byte, error) {
+			return s.CustomGetPollingStatusContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("get-log-report", "Get log report of monitor", VendorOutputJSON, func(ctx context.Context, input Site24x7LogReportOptions) ([]byte, error) {
+			return s.CustomGetLogReportContext(ctx, s.options, input)
+		}),
 	}
+}
+
+func (s *Site24x7) withContext(ctx context.Context) *Site24x7 {
+
+	c := *s
+	c.client = common.HttpClientWithContext(ctx, s.client)
+	return &c
+}
+
+func NewSite24x7(options Site24x7Options, logger common.Logger) *Site24x7 {
+
+	client := common.VendorHttpClient(options.HTTPClient, "site24x7", options.Timeout, options.Insecure)
 
 	return &Site24x7{
 		client:  client,
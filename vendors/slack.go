@@ -2,6 +2,7 @@ package vendors
 
 import (
 	"bytes"
+	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
@@ -9,7 +10,10 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
 )
 
@@ -21,13 +25,22 @@ const slackBaseURL = "https://slack.com/api/"
 const (
 	slackFilesUpload           = "files.upload"
 	slackChatPostMessage       = "chat.postMessage"
+	slackChatUpdate            = "chat.update"
+	slackChatDelete            = "chat.delete"
+	slackChatScheduleMessage   = "chat.scheduleMessage"
+	slackChatDeleteScheduled   = "chat.deleteScheduledMessage"
+	slackChatPostEphemeral     = "chat.postEphemeral"
+	slackChatGetPermalink      = "chat.getPermalink"
 	slackReactionsAdd          = "reactions.add"
 	slackUsersLookupByEmail    = "users.lookupByEmail"
 	slackUsergroupsUsersUpdate = "usergroups.users.update"
 	slackConversationsHistory  = "conversations.history"
+	slackConversationsList     = "conversations.list"
+	slackAuthTest              = "auth.test"
 )
 
 type SlackOptions struct {
+	URL        string // slack.com by default
 	Timeout    int
 	Insecure   bool
 	Token      string
@@ -41,6 +54,7 @@ type SlackMessageOptions struct {
 	Text        string
 	Attachments string
 	Blocks      string
+	Format      string
 }
 
 type SlackFileOptions struct {
@@ -53,6 +67,35 @@ type SlackFileOptions struct {
 	Type    string
 }
 
+// SlackMessageRef is channel and ts of message, responses of message operations have both, so they can be chained
+type SlackMessageRef struct {
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+type SlackUpdateMessageOptions struct {
+	SlackMessageRef
+	Text        string
+	Attachments string
+	Blocks      string
+	Format      string
+}
+
+type SlackScheduleMessageOptions struct {
+	SlackMessageOptions
+	PostAt string // unix time, RFC3339 time or duration from now, e.g. 30m
+}
+
+type SlackScheduledMessageOptions struct {
+	Channel            string
+	ScheduledMessageID string
+}
+
+type SlackEphemeralOptions struct {
+	SlackMessageOptions
+	User string
+}
+
 type SlackReactionOptions struct {
 	Channel string
 	Thread  string
@@ -86,9 +129,15 @@ type GetConversationHistoryParameters struct {
 	Cursor             string
 	Inclusive          bool
 	Latest             string
-	Limit              int
 	Oldest             string
 	IncludeAllMetadata bool
+	PageOptions
+}
+
+type SlackConversationsOptions struct {
+	Types           string // public_channel by default, e.g. public_channel,private_channel
+	ExcludeArchived bool
+	PageOptions
 }
 
 type GetConversationHistoryResponse struct {
@@ -144,7 +193,11 @@ type Slack struct {
 }
 
 func (s *Slack) apiURL(cmd string) string {
-	return slackBaseURL + cmd
+
+	if utils.IsEmpty(s.options.URL) {
+		return slackBaseURL + cmd
+	}
+	return strings.TrimSuffix(s.options.URL, "/") + "/api/" + cmd
 }
 
 func (s *Slack) getAuth(opts SlackOptions) string {
@@ -361,7 +414,10 @@ func (s *Slack) post(token string, URL string, query url.Values, contentType str
 	}
 */
 
-func (s *Slack) CustomSendMessage(slackOptions SlackOptions, messageOptions SlackMessageOptions) ([]byte, error) {
+func (s *Slack) CustomSendMessageContext(ctx context.Context, slackOptions SlackOptions, messageOptions SlackMessageOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "send-message", &b, &err)
 
 	var body bytes.Buffer
 	w := multipart.NewWriter(&body)
@@ -380,7 +436,11 @@ func (s *Slack) CustomSendMessage(slackOptions SlackOptions, messageOptions Slac
 	}
 
 	if !utils.IsEmpty(messageOptions.Text) {
-		if err := w.WriteField("text", messageOptions.Text); err != nil {
+		text := messageOptions.Text
+		if messageOptions.Format == common.MarkdownFormat {
+			text = common.MarkdownToSlack(text)
+		}
+		if err := w.WriteField("text", text); err != nil {
 			return nil, err
 		}
 	}
@@ -405,14 +465,25 @@ func (s *Slack) CustomSendMessage(slackOptions SlackOptions, messageOptions Slac
 		return nil, err
 	}
 
-	return utils.HttpPostRaw(s.client, s.apiURL(slackChatPostMessage), w.FormDataContentType(), s.getAuth(slackOptions), body.Bytes())
+	b, err = common.HttpPostRaw(s.client, s.apiURL(slackChatPostMessage), w.FormDataContentType(), s.getAuth(slackOptions), body.Bytes())
+	if err != nil {
+		return b, err
+	}
+	return b, slackError("send-message", b)
+}
+
+func (s *Slack) CustomSendMessage(slackOptions SlackOptions, messageOptions SlackMessageOptions) ([]byte, error) {
+	return s.CustomSendMessageContext(context.Background(), slackOptions, messageOptions)
 }
 
 func (s *Slack) SendMessage(messageOptions SlackMessageOptions) ([]byte, error) {
 	return s.CustomSendMessage(s.options, messageOptions)
 }
 
-func (s *Slack) CustomSendFile(slackOptions SlackOptions, fileOptions SlackFileOptions) ([]byte, error) {
+func (s *Slack) CustomSendFileContext(ctx context.Context, slackOptions SlackOptions, fileOptions SlackFileOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "send-file", &b, &err)
 
 	var body bytes.Buffer
 	w := multipart.NewWriter(&body)
@@ -457,14 +528,25 @@ func (s *Slack) CustomSendFile(slackOptions SlackOptions, fileOptions SlackFileO
 		return nil, err
 	}
 
-	return utils.HttpPostRaw(s.client, s.apiURL(slackFilesUpload), w.FormDataContentType(), s.getAuth(slackOptions), body.Bytes())
+	b, err = common.HttpPostRaw(s.client, s.apiURL(slackFilesUpload), w.FormDataContentType(), s.getAuth(slackOptions), body.Bytes())
+	if err != nil {
+		return b, err
+	}
+	return b, slackError("send-file", b)
+}
+
+func (s *Slack) CustomSendFile(slackOptions SlackOptions, fileOptions SlackFileOptions) ([]byte, error) {
+	return s.CustomSendFileContext(context.Background(), slackOptions, fileOptions)
 }
 
 func (s *Slack) SendFile(fileOptions SlackFileOptions) ([]byte, error) {
 	return s.CustomSendFile(s.options, fileOptions)
 }
 
-func (s *Slack) CustomAddReaction(slackOptions SlackOptions, reactionOptions SlackReactionOptions) ([]byte, error) {
+func (s *Slack) CustomAddReactionContext(ctx context.Context, slackOptions SlackOptions, reactionOptions SlackReactionOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "add-reaction", &b, &err)
 
 	var body bytes.Buffer
 	w := multipart.NewWriter(&body)
@@ -489,14 +571,25 @@ func (s *Slack) CustomAddReaction(slackOptions SlackOptions, reactionOptions Sla
 	if err := w.Close(); err != nil {
 		return nil, err
 	}
-	return utils.HttpPostRaw(s.client, s.apiURL(slackReactionsAdd), w.FormDataContentType(), s.getAuth(slackOptions), body.Bytes())
+	b, err = common.HttpPostRaw(s.client, s.apiURL(slackReactionsAdd), w.FormDataContentType(), s.getAuth(slackOptions), body.Bytes())
+	if err != nil {
+		return b, err
+	}
+	return b, slackError("add-reaction", b)
+}
+
+func (s *Slack) CustomAddReaction(slackOptions SlackOptions, reactionOptions SlackReactionOptions) ([]byte, error) {
+	return s.CustomAddReactionContext(context.Background(), slackOptions, reactionOptions)
 }
 
 func (s *Slack) AddReaction(options SlackReactionOptions) ([]byte, error) {
 	return s.CustomAddReaction(s.options, options)
 }
 
-func (s *Slack) CustomGetUser(slackOptions SlackOptions, slackUser SlackUserEmail) ([]byte, error) {
+func (s *Slack) CustomGetUserContext(ctx context.Context, slackOptions SlackOptions, slackUser SlackUserEmail) (b []byte, err error) {
+	s = s.withContext(ctx)
+	defer vendorError("slack", "get-user", &b, &err)
+
 	params := make(url.Values)
 	params.Add("email", slackUser.Email)
 
@@ -506,14 +599,25 @@ func (s *Slack) CustomGetUser(slackOptions SlackOptions, slackUser SlackUserEmai
 	}
 
 	u.RawQuery = params.Encode()
-	return utils.HttpGetRaw(s.client, u.String(), "application/x-www-form-urlencoded", s.getAuth(slackOptions))
+	b, err = common.HttpGetRaw(s.client, u.String(), "application/x-www-form-urlencoded", s.getAuth(slackOptions))
+	if err != nil {
+		return b, err
+	}
+	return b, slackError("get-user", b)
+}
+
+func (s *Slack) CustomGetUser(slackOptions SlackOptions, slackUser SlackUserEmail) ([]byte, error) {
+	return s.CustomGetUserContext(context.Background(), slackOptions, slackUser)
 }
 
 func (s *Slack) GetUser(options SlackUserEmail) ([]byte, error) {
 	return s.CustomGetUser(s.options, options)
 }
 
-func (s *Slack) CustomUpdateUsergroup(slackOptions SlackOptions, slackUpdateUsergroup SlackUsergroupUsers) ([]byte, error) {
+func (s *Slack) CustomUpdateUsergroupContext(ctx context.Context, slackOptions SlackOptions, slackUpdateUsergroup SlackUsergroupUsers) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "update-usergroup", &b, &err)
 
 	body := &SlackUsergroupUsers{
 		Usergroup: slackUpdateUsergroup.Usergroup,
@@ -524,7 +628,15 @@ func (s *Slack) CustomUpdateUsergroup(slackOptions SlackOptions, slackUpdateUser
 	if err != nil {
 		return nil, err
 	}
-	return utils.HttpPostRaw(s.client, s.apiURL(slackUsergroupsUsersUpdate), "application/json", s.getAuth(slackOptions), req)
+	b, err = common.HttpPostRaw(s.client, s.apiURL(slackUsergroupsUsersUpdate), "application/json", s.getAuth(slackOptions), req)
+	if err != nil {
+		return b, err
+	}
+	return b, slackError("update-usergroup", b)
+}
+
+func (s *Slack) CustomUpdateUsergroup(slackOptions SlackOptions, slackUpdateUsergroup SlackUsergroupUsers) ([]byte, error) {
+	return s.CustomUpdateUsergroupContext(context.Background(), slackOptions, slackUpdateUsergroup)
 }
 
 func (s *Slack) UpdateUsergroup(options SlackUsergroupUsers) ([]byte, error) {
@@ -535,7 +647,7 @@ func (s *Slack) GetConversationHistory(options GetConversationHistoryParameters)
 	return s.CustomGetConversationHistory(s.options, options)
 }
 
-func (s *Slack) CustomGetConversationHistory(slackOptions SlackOptions, getConversationHistoryParameters GetConversationHistoryParameters) ([]byte, error) {
+func (s *Slack) conversationHistoryPage(slackOptions SlackOptions, getConversationHistoryParameters GetConversationHistoryParameters, cursor string, limit int) ([]byte, error) {
 
 	var body bytes.Buffer
 	w := multipart.NewWriter(&body)
@@ -557,8 +669,8 @@ func (s *Slack) CustomGetConversationHistory(slackOptions SlackOptions, getConve
 		}
 	}
 
-	if getConversationHistoryParameters.Cursor != "" {
-		if err := w.WriteField("cursor", getConversationHistoryParameters.Cursor); err != nil {
+	if cursor != "" {
+		if err := w.WriteField("cursor", cursor); err != nil {
 			return nil, err
 		}
 	}
@@ -576,8 +688,8 @@ func (s *Slack) CustomGetConversationHistory(slackOptions SlackOptions, getConve
 			return nil, err
 		}
 	}
-	if getConversationHistoryParameters.Limit != 0 {
-		if err := w.WriteField("limit", strconv.Itoa(getConversationHistoryParameters.Limit)); err != nil {
+	if limit != 0 {
+		if err := w.WriteField("limit", strconv.Itoa(limit)); err != nil {
 			return nil, err
 		}
 	}
@@ -600,16 +712,564 @@ func (s *Slack) CustomGetConversationHistory(slackOptions SlackOptions, getConve
 		return nil, err
 	}
 
-	return utils.HttpPostRaw(s.client, s.apiURL(slackConversationsHistory), w.FormDataContentType(), s.getAuth(slackOptions), body.Bytes())
+	return common.HttpPostRaw(s.client, s.apiURL(slackConversationsHistory), w.FormDataContentType(), s.getAuth(slackOptions), body.Bytes())
 
 }
 
-func NewSlack(options SlackOptions) *Slack {
+// CustomGetConversationHistoryContext follows cursors of pages, messages of pages are merged into response of the first page
+func (s *Slack) CustomGetConversationHistoryContext(ctx context.Context, slackOptions SlackOptions, getConversationHistoryParameters GetConversationHistoryParameters) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "get-conversation-history", &b, &err)
+
+	if utils.IsEmpty(getConversationHistoryParameters.ChannelID) {
+		return nil, fmt.Errorf("channel_id is required")
+	}
+
+	// limit is page size of API, it's 100 by default and 200 is recommended as max
+	var first, last map[string]json.RawMessage
+	paging := getConversationHistoryParameters.PageOptions
+	limit := 0
+	if paging.Limit > 0 {
+		limit = paging.pageSize(200)
+	}
+	messages, err := PagesAll(ctx, paging, func(ctx context.Context, token string) (*Page[json.RawMessage], error) {
+
+		cursor := getConversationHistoryParameters.Cursor
+		if !utils.IsEmpty(token) {
+			cursor = token
+		}
+		b, err := s.conversationHistoryPage(slackOptions, getConversationHistoryParameters, cursor, limit)
+		if err != nil {
+			return nil, common.VendorErrorFrom("slack", "get-conversation-history", b, err)
+		}
+
+		if err := slackError("get-conversation-history", b); err != nil {
+			return nil, err
+		}
+
+		var r struct {
+			Messages         []json.RawMessage `json:"messages"`
+			HasMore          bool              `json:"has_more"`
+			ResponseMetadata struct {
+				NextCursor string `json:"next_cursor"`
+			} `json:"response_metadata"`
+		}
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, err
+		}
+		// every page is decoded into new map, so fields of the first page are kept
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, err
+		}
+		if first == nil {
+			first = m
+		}
+		last = m
+
+		page := &Page[json.RawMessage]{Items: r.Messages}
+		if r.HasMore {
+			page.Next = r.ResponseMetadata.NextCursor
+		}
+		return page, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	first["messages"], err = json.Marshal(messages)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range []string{"has_more", "response_metadata"} {
+		if v, ok := last[k]; ok {
+			first[k] = v
+		}
+	}
+	return json.Marshal(first)
+}
+
+func (s *Slack) CustomGetConversationHistory(slackOptions SlackOptions, getConversationHistoryParameters GetConversationHistoryParameters) ([]byte, error) {
+	return s.CustomGetConversationHistoryContext(context.Background(), slackOptions, getConversationHistoryParameters)
+}
+
+// CustomGetConversationsContext follows cursors of pages, channels of pages are merged into one response
+func (s *Slack) CustomGetConversationsContext(ctx context.Context, slackOptions SlackOptions, conversationsOptions SlackConversationsOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "get-conversations", &b, &err)
+
+	paging := conversationsOptions.PageOptions
+	limit := paging.pageSize(1000)
+	channels, err := PagesAll(ctx, paging, func(ctx context.Context, token string) (*Page[json.RawMessage], error) {
+
+		params := make(url.Values)
+		if !utils.IsEmpty(conversationsOptions.Types) {
+			params.Add("types", conversationsOptions.Types)
+		}
+		if conversationsOptions.ExcludeArchived {
+			params.Add("exclude_archived", "true")
+		}
+		if limit > 0 {
+			params.Add("limit", strconv.Itoa(limit))
+		}
+		if !utils.IsEmpty(token) {
+			params.Add("cursor", token)
+		}
+
+		b, err := common.HttpPostRaw(s.client, s.apiURL(slackConversationsList), "application/x-www-form-urlencoded", s.getAuth(slackOptions), []byte(params.Encode()))
+		if err != nil {
+			return nil, common.VendorErrorFrom("slack", "get-conversations", b, err)
+		}
+
+		if err := slackError("get-conversations", b); err != nil {
+			return nil, err
+		}
+
+		var r struct {
+			Channels         []json.RawMessage `json:"channels"`
+			ResponseMetadata struct {
+				NextCursor string `json:"next_cursor"`
+			} `json:"response_metadata"`
+		}
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, err
+		}
+		return &Page[json.RawMessage]{Items: r.Channels, Next: r.ResponseMetadata.NextCursor}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"ok":       true,
+		"channels": channels,
+	})
+}
+
+func (s *Slack) CustomGetConversations(slackOptions SlackOptions, conversationsOptions SlackConversationsOptions) ([]byte, error) {
+	return s.CustomGetConversationsContext(context.Background(), slackOptions, conversationsOptions)
+}
+
+func (s *Slack) GetConversations(options SlackConversationsOptions) ([]byte, error) {
+	return s.CustomGetConversations(s.options, options)
+}
+
+// SlackMessageRefFrom returns channel and ts of response of message operation, ts of ephemeral or nested message is used as well
+func SlackMessageRefFrom(b []byte) (SlackMessageRef, error) {
+
+	var r struct {
+		SlackMessageRef
+		MessageTS string `json:"message_ts"`
+		Message   struct {
+			TS string `json:"ts"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return SlackMessageRef{}, err
+	}
+	ref := r.SlackMessageRef
+	for _, ts := range []string{r.MessageTS, r.Message.TS} {
+		if utils.IsEmpty(ref.TS) {
+			ref.TS = ts
+		}
+	}
+	return ref, nil
+}
+
+// slackPostAt returns unix time of post at, it's unix time, RFC3339 time or duration from now
+func slackPostAt(postAt string, now time.Time) (int64, error) {
+
+	postAt = strings.TrimSpace(postAt)
+	if utils.IsEmpty(postAt) {
+		return 0, fmt.Errorf("post at is required")
+	}
+	if n, err := strconv.ParseInt(postAt, 10, 64); err == nil {
+		return n, nil
+	}
+	if t, err := time.Parse(time.RFC3339, postAt); err == nil {
+		return t.Unix(), nil
+	}
+	d, err := time.ParseDuration(strings.TrimPrefix(postAt, "+"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid post at %s, it should be unix time, RFC3339 time or duration", postAt)
+	}
+	return now.Add(d).Unix(), nil
+}
 
-	client := options.HTTPClient
-	if client == nil {
-		client = utils.NewHttpClient(options.Timeout, options.Insecure)
+func slackText(text, format string) string {
+
+	if format == common.MarkdownFormat {
+		return common.MarkdownToSlack(text)
+	}
+	return text
+}
+
+// slackPostFields posts non empty fields as form of method
+func (s *Slack) slackPostFields(slackOptions SlackOptions, method string, fields [][2]string) ([]byte, error) {
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, f := range fields {
+		if utils.IsEmpty(f[1]) {
+			continue
+		}
+		if err := w.WriteField(f[0], f[1]); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return common.HttpPostRaw(s.client, s.apiURL(method), w.FormDataContentType(), s.getAuth(slackOptions), body.Bytes())
+}
+
+// slackChained returns error of response which is not ok, otherwise response has channel and ts of message
+func slackChained(operation string, b []byte, ref SlackMessageRef) ([]byte, error) {
+
+	if err := slackError(operation, b); err != nil {
+		return b, err
 	}
+	var r map[string]interface{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return b, err
+	}
+
+	got, _ := SlackMessageRefFrom(b)
+	if utils.IsEmpty(got.Channel) && !utils.IsEmpty(ref.Channel) {
+		r["channel"] = ref.Channel
+	}
+	if utils.IsEmpty(common.ValueToString(r["ts"])) {
+		if !utils.IsEmpty(got.TS) {
+			r["ts"] = got.TS
+		} else if !utils.IsEmpty(ref.TS) {
+			r["ts"] = ref.TS
+		}
+	}
+	return json.Marshal(r)
+}
+
+func (s *Slack) CustomUpdateMessageContext(ctx context.Context, slackOptions SlackOptions, updateOptions SlackUpdateMessageOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "update-message", &b, &err)
+
+	if utils.IsEmpty(updateOptions.Channel) || utils.IsEmpty(updateOptions.TS) {
+		return nil, fmt.Errorf("channel and ts are required")
+	}
+	b, err = s.slackPostFields(slackOptions, slackChatUpdate, [][2]string{
+		{"channel", updateOptions.Channel},
+		{"ts", updateOptions.TS},
+		{"text", slackText(updateOptions.Text, updateOptions.Format)},
+		{"attachments", updateOptions.Attachments},
+		{"blocks", updateOptions.Blocks},
+	})
+	if err != nil {
+		return b, err
+	}
+	return slackChained("update-message", b, updateOptions.SlackMessageRef)
+}
+
+func (s *Slack) CustomUpdateMessage(slackOptions SlackOptions, updateOptions SlackUpdateMessageOptions) ([]byte, error) {
+	return s.CustomUpdateMessageContext(context.Background(), slackOptions, updateOptions)
+}
+
+func (s *Slack) UpdateMessage(options SlackUpdateMessageOptions) ([]byte, error) {
+	return s.CustomUpdateMessage(s.options, options)
+}
+
+func (s *Slack) CustomDeleteMessageContext(ctx context.Context, slackOptions SlackOptions, ref SlackMessageRef) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "delete-message", &b, &err)
+
+	if utils.IsEmpty(ref.Channel) || utils.IsEmpty(ref.TS) {
+		return nil, fmt.Errorf("channel and ts are required")
+	}
+	b, err = s.slackPostFields(slackOptions, slackChatDelete, [][2]string{
+		{"channel", ref.Channel},
+		{"ts", ref.TS},
+	})
+	if err != nil {
+		return b, err
+	}
+	return slackChained("delete-message", b, ref)
+}
+
+func (s *Slack) CustomDeleteMessage(slackOptions SlackOptions, ref SlackMessageRef) ([]byte, error) {
+	return s.CustomDeleteMessageContext(context.Background(), slackOptions, ref)
+}
+
+func (s *Slack) DeleteMessage(ref SlackMessageRef) ([]byte, error) {
+	return s.CustomDeleteMessage(s.options, ref)
+}
+
+func (s *Slack) CustomScheduleMessageContext(ctx context.Context, slackOptions SlackOptions, scheduleOptions SlackScheduleMessageOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "schedule-message", &b, &err)
+
+	postAt, err := slackPostAt(scheduleOptions.PostAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	b, err = s.slackPostFields(slackOptions, slackChatScheduleMessage, [][2]string{
+		{"channel", scheduleOptions.Channel},
+		{"post_at", strconv.FormatInt(postAt, 10)},
+		{"thread_ts", scheduleOptions.Thread},
+		{"text", slackText(scheduleOptions.Text, scheduleOptions.Format)},
+		{"attachments", scheduleOptions.Attachments},
+		{"blocks", scheduleOptions.Blocks},
+	})
+	if err != nil {
+		return b, err
+	}
+	return slackChained("schedule-message", b, SlackMessageRef{Channel: scheduleOptions.Channel})
+}
+
+func (s *Slack) CustomScheduleMessage(slackOptions SlackOptions, scheduleOptions SlackScheduleMessageOptions) ([]byte, error) {
+	return s.CustomScheduleMessageContext(context.Background(), slackOptions, scheduleOptions)
+}
+
+func (s *Slack) ScheduleMessage(options SlackScheduleMessageOptions) ([]byte, error) {
+	return s.CustomScheduleMessage(s.options, options)
+}
+
+func (s *Slack) CustomDeleteScheduledMessageContext(ctx context.Context, slackOptions SlackOptions, scheduledOptions SlackScheduledMessageOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "delete-scheduled-message", &b, &err)
+
+	if utils.IsEmpty(scheduledOptions.Channel) || utils.IsEmpty(scheduledOptions.ScheduledMessageID) {
+		return nil, fmt.Errorf("channel and scheduled message id are required")
+	}
+	b, err = s.slackPostFields(slackOptions, slackChatDeleteScheduled, [][2]string{
+		{"channel", scheduledOptions.Channel},
+		{"scheduled_message_id", scheduledOptions.ScheduledMessageID},
+	})
+	if err != nil {
+		return b, err
+	}
+	return slackChained("delete-scheduled-message", b, SlackMessageRef{Channel: scheduledOptions.Channel})
+}
+
+func (s *Slack) CustomDeleteScheduledMessage(slackOptions SlackOptions, scheduledOptions SlackScheduledMessageOptions) ([]byte, error) {
+	return s.CustomDeleteScheduledMessageContext(context.Background(), slackOptions, scheduledOptions)
+}
+
+func (s *Slack) DeleteScheduledMessage(options SlackScheduledMessageOptions) ([]byte, error) {
+	return s.CustomDeleteScheduledMessage(s.options, options)
+}
+
+// CustomSendEphemeralContext posts message which is visible to user only, response ts is message_ts of Slack
+func (s *Slack) CustomSendEphemeralContext(ctx context.Context, slackOptions SlackOptions, ephemeralOptions SlackEphemeralOptions) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "send-ephemeral", &b, &err)
+
+	if utils.IsEmpty(ephemeralOptions.User) {
+		return nil, fmt.Errorf("user is required")
+	}
+	b, err = s.slackPostFields(slackOptions, slackChatPostEphemeral, [][2]string{
+		{"channel", ephemeralOptions.Channel},
+		{"user", ephemeralOptions.User},
+		{"thread_ts", ephemeralOptions.Thread},
+		{"text", slackText(ephemeralOptions.Text, ephemeralOptions.Format)},
+		{"attachments", ephemeralOptions.Attachments},
+		{"blocks", ephemeralOptions.Blocks},
+	})
+	if err != nil {
+		return b, err
+	}
+	return slackChained("send-ephemeral", b, SlackMessageRef{Channel: ephemeralOptions.Channel})
+}
+
+func (s *Slack) CustomSendEphemeral(slackOptions SlackOptions, ephemeralOptions SlackEphemeralOptions) ([]byte, error) {
+	return s.CustomSendEphemeralContext(context.Background(), slackOptions, ephemeralOptions)
+}
+
+func (s *Slack) SendEphemeral(options SlackEphemeralOptions) ([]byte, error) {
+	return s.CustomSendEphemeral(s.options, options)
+}
+
+func (s *Slack) CustomGetPermalinkContext(ctx context.Context, slackOptions SlackOptions, ref SlackMessageRef) (b []byte, err error) {
+
+	s = s.withContext(ctx)
+	defer vendorError("slack", "get-permalink", &b, &err)
+
+	if utils.IsEmpty(ref.Channel) || utils.IsEmpty(ref.TS) {
+		return nil, fmt.Errorf("channel and ts are required")
+	}
+
+	params := make(url.Values)
+	params.Add("channel", ref.Channel)
+	params.Add("message_ts", ref.TS)
+
+	u, err := url.Parse(s.apiURL(slackChatGetPermalink))
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = params.Encode()
+
+	b, err = common.HttpGetRaw(s.client, u.String(), "application/x-www-form-urlencoded", s.getAuth(slackOptions))
+	if err != nil {
+		return b, err
+	}
+	return slackChained("get-permalink", b, ref)
+}
+
+func (s *Slack) CustomGetPermalink(slackOptions SlackOptions, ref SlackMessageRef) ([]byte, error) {
+	return s.CustomGetPermalinkContext(context.Background(), slackOptions, ref)
+}
+
+func (s *Slack) GetPermalink(ref SlackMessageRef) ([]byte, error) {
+	return s.CustomGetPermalink(s.options, ref)
+}
+
+// slackErrorKind returns kind of error code, slack responds 200 with ok false and code for most errors
+func slackErrorKind(code string) string {
+
+	switch code {
+	case "invalid_auth", "not_authed", "account_inactive", "token_revoked", "token_expired", "no_permission", "missing_scope", "not_allowed_token_type", "ekm_access_denied":
+		return common.VendorErrorAuth
+	case "ratelimited", "rate_limited":
+		return common.VendorErrorRateLimit
+	case "request_timeout":
+		return common.VendorErrorTimeout
+	case "internal_error", "fatal_error", "service_unavailable":
+		return common.VendorErrorUnavailable
+	}
+	if strings.HasSuffix(code, "_not_found") {
+		return common.VendorErrorNotFound
+	}
+	return common.VendorErrorInvalid
+}
+
+// slackError returns VendorError of response with ok false, nil for ok response
+func slackError(operation string, b []byte) error {
+
+	var r struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return fmt.Errorf("slack %s response is invalid: %v", operation, err)
+	}
+	if r.OK {
+		return nil
+	}
+	return common.NewVendorErrorKind("slack", operation, slackErrorKind(r.Error), r.Error, "")
+}
+
+// OutputSink implements common.OutputSink for targets like slack://channel?thread=<ts>&file=<name>
+func (s *Slack) OutputSink() common.OutputSink {
+
+	return common.OutputSinkFunc(func(u *url.URL, data []byte) error {
+
+		channel := u.Host
+		if utils.IsEmpty(channel) {
+			return fmt.Errorf("slack output requires channel, e.g. slack://channel")
+		}
+		query := u.Query()
+
+		var err error
+		if name := query.Get("file"); !utils.IsEmpty(name) {
+			_, err = s.SendFile(SlackFileOptions{
+				Channel: channel,
+				Thread:  query.Get("thread"),
+				Title:   query.Get("title"),
+				Name:    name,
+				Content: string(data),
+				Type:    "auto",
+			})
+		} else {
+			_, err = s.SendMessage(SlackMessageOptions{
+				Channel: channel,
+				Thread:  query.Get("thread"),
+				Title:   query.Get("title"),
+				Text:    string(data),
+				Format:  query.Get("format"),
+			})
+		}
+		return err
+	})
+}
+
+func (s *Slack) Name() string {
+	return "slack"
+}
+
+func (s *Slack) Options() interface{} {
+	return s.options
+}
+
+// Check calls auth.test, slack responds with 200 and ok false for invalid tokens
+func (s *Slack) Check(ctx context.Context) error {
+
+	v := s.withContext(ctx)
+	b, err := common.HttpPostRaw(v.client, v.apiURL(slackAuthTest), "application/x-www-form-urlencoded", v.getAuth(v.options), nil)
+	if err != nil {
+		return common.VendorErrorFrom("slack", "check", b, err)
+	}
+	return slackError("check", b)
+}
+
+func (s *Slack) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("send-message", "Send message to channel or thread", VendorOutputJSON, func(ctx context.Context, input SlackMessageOptions) ([]byte, error) {
+			return s.CustomSendMessageContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("send-file", "Upload file to channel or thread", VendorOutputJSON, func(ctx context.Context, input SlackFileOptions) ([]byte, error) {
+			return s.CustomSendFileContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("add-reaction", "Add reaction to message", VendorOutputJSON, func(ctx context.Context, input SlackReactionOptions) ([]byte, error) {
+			return s.CustomAddReactionContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("get-user", "Get user by email", VendorOutputJSON, func(ctx context.Context, input SlackUserEmail) ([]byte, error) {
+			return s.CustomGetUserContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("update-usergroup", "Update usergroup users", VendorOutputJSON, func(ctx context.Context, input SlackUsergroupUsers) ([]byte, error) {
+			return s.CustomUpdateUsergroupContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("update-message", "Update message by channel and ts", VendorOutputJSON, func(ctx context.Context, input SlackUpdateMessageOptions) ([]byte, error) {
+			return s.CustomUpdateMessageContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("delete-message", "Delete message by channel and ts", VendorOutputJSON, func(ctx context.Context, input SlackMessageRef) ([]byte, error) {
+			return s.CustomDeleteMessageContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("schedule-message", "Schedule message to channel or thread", VendorOutputJSON, func(ctx context.Context, input SlackScheduleMessageOptions) ([]byte, error) {
+			return s.CustomScheduleMessageContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("delete-scheduled-message", "Delete scheduled message", VendorOutputJSON, func(ctx context.Context, input SlackScheduledMessageOptions) ([]byte, error) {
+			return s.CustomDeleteScheduledMessageContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("send-ephemeral", "Send message visible to user only", VendorOutputJSON, func(ctx context.Context, input SlackEphemeralOptions) ([]byte, error) {
+			return s.CustomSendEphemeralContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("get-permalink", "Get permalink of message", VendorOutputJSON, func(ctx context.Context, input SlackMessageRef) ([]byte, error) {
+			return s.CustomGetPermalinkContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("get-conversation-history", "Get conversation history", VendorOutputJSON, func(ctx context.Context, input GetConversationHistoryParameters) ([]byte, error) {
+			return s.CustomGetConversationHistoryContext(ctx, s.options, input)
+		}),
+		NewVendorOperation("get-conversations", "Get channels of workspace", VendorOutputJSON, func(ctx context.Context, input SlackConversationsOptions) ([]byte, error) {
+			return s.CustomGetConversationsContext(ctx, s.options, input)
+		}),
+	}
+}
+
+func (s *Slack) withContext(ctx context.Context) *Slack {
+
+	c := *s
+	c.client = common.HttpClientWithContext(ctx, s.client)
+	return &c
+}
+
+func NewSlack(options SlackOptions) *Slack {
+
+	client := common.VendorHttpClient(options.HTTPClient, "slack", options.Timeout, options.Insecure)
 
 	slack := &Slack{
 		client:  client,
@@ -0,0 +1,175 @@
+package vendors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/devopsext/tools/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackGetConversations(t *testing.T) {
+
+	pages := map[string]string{
+		"":   `{"ok":true,"channels":[{"id":"C1","name":"general"}],"response_metadata":{"next_cursor":"p2"}}`,
+		"p2": `{"ok":true,"channels":[{"id":"C2","name":"alerts"}],"response_metadata":{"next_cursor":""}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/conversations.list", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		fmt.Fprint(w, pages[r.PostForm.Get("cursor")])
+	}))
+	defer server.Close()
+
+	s := NewSlack(SlackOptions{URL: server.URL, Token: "token", Timeout: 5})
+
+	tests := []struct {
+		name     string
+		options  SlackConversationsOptions
+		channels []string
+	}{
+		{"first page", SlackConversationsOptions{}, []string{"general"}},
+		{"all", SlackConversationsOptions{PageOptions: PageOptions{All: true}}, []string{"general", "alerts"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			b, err := s.CustomGetConversationsContext(context.Background(), s.options, tt.options)
+			require.NoError(t, err)
+
+			var r struct {
+				Channels []struct {
+					Name string `json:"name"`
+				} `json:"channels"`
+			}
+			require.NoError(t, json.Unmarshal(b, &r))
+			var names []string
+			for _, c := range r.Channels {
+				names = append(names, c.Name)
+			}
+			assert.Equal(t, tt.channels, names)
+		})
+	}
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ok":false,"error":"invalid_auth"}`)
+	})
+	_, err := s.GetConversations(SlackConversationsOptions{})
+	assert.Error(t, err)
+}
+
+func TestSlackMessageRefFrom(t *testing.T) {
+
+	tests := []struct {
+		name string
+		body string
+		ref  SlackMessageRef
+	}{
+		{"post message", `{"ok":true,"channel":"C1","ts":"1.1","message":{"ts":"1.1"}}`, SlackMessageRef{Channel: "C1", TS: "1.1"}},
+		{"ephemeral", `{"ok":true,"channel":"C1","message_ts":"2.2"}`, SlackMessageRef{Channel: "C1", TS: "2.2"}},
+		{"nested ts", `{"ok":true,"channel":"C1","message":{"ts":"3.3"}}`, SlackMessageRef{Channel: "C1", TS: "3.3"}},
+	}
+	for _, tt := range tests {
+		ref, err := SlackMessageRefFrom([]byte(tt.body))
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.ref, ref, tt.name)
+	}
+}
+
+func TestSlackPostAt(t *testing.T) {
+
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		postAt string
+		unix   int64
+		err    bool
+	}{
+		{"1700000600", 1700000600, false},
+		{"30m", 1700001800, false},
+		{"+1h", 1700003600, false},
+		{now.Add(time.Hour).UTC().Format(time.RFC3339), 1700003600, false},
+		{"", 0, true},
+		{"tomorrow", 0, true},
+	}
+	for _, tt := range tests {
+		unix, err := slackPostAt(tt.postAt, now)
+		if tt.err {
+			assert.Error(t, err, tt.postAt)
+			continue
+		}
+		require.NoError(t, err, tt.postAt)
+		assert.Equal(t, tt.unix, unix, tt.postAt)
+	}
+}
+
+func TestSlackErrors(t *testing.T) {
+
+	code := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"ok":false,"error":"%s"}`, code)
+	}))
+	defer server.Close()
+
+	s := NewSlack(SlackOptions{URL: server.URL, Token: "token", Timeout: 5})
+	ctx := context.Background()
+
+	calls := map[string]func() ([]byte, error){
+		"send-message": func() ([]byte, error) {
+			return s.CustomSendMessageContext(ctx, s.options, SlackMessageOptions{Channel: "C1", Text: "text"})
+		},
+		"send-file": func() ([]byte, error) {
+			return s.CustomSendFileContext(ctx, s.options, SlackFileOptions{Channel: "C1", Name: "a.txt", Content: "a"})
+		},
+		"add-reaction": func() ([]byte, error) {
+			return s.CustomAddReactionContext(ctx, s.options, SlackReactionOptions{Channel: "C1", Name: "eyes", Thread: "1.1"})
+		},
+		"get-user": func() ([]byte, error) {
+			return s.CustomGetUserContext(ctx, s.options, SlackUserEmail{Email: "a@b.c"})
+		},
+		"update-usergroup": func() ([]byte, error) {
+			return s.CustomUpdateUsergroupContext(ctx, s.options, SlackUsergroupUsers{Usergroup: "S1", Users: []string{"U1"}})
+		},
+		"get-conversation-history": func() ([]byte, error) {
+			return s.CustomGetConversationHistoryContext(ctx, s.options, GetConversationHistoryParameters{ChannelID: "C1"})
+		},
+		"get-conversations": func() ([]byte, error) {
+			return s.CustomGetConversationsContext(ctx, s.options, SlackConversationsOptions{})
+		},
+		"get-permalink": func() ([]byte, error) {
+			return s.CustomGetPermalinkContext(ctx, s.options, SlackMessageRef{Channel: "C1", TS: "1.1"})
+		},
+	}
+
+	kinds := map[string]string{
+		"invalid_auth":      common.VendorErrorAuth,
+		"not_authed":        common.VendorErrorAuth,
+		"channel_not_found": common.VendorErrorNotFound,
+		"users_not_found":   common.VendorErrorNotFound,
+		"ratelimited":       common.VendorErrorRateLimit,
+		"invalid_blocks":    common.VendorErrorInvalid,
+	}
+
+	for operation, call := range calls {
+		for c, kind := range kinds {
+			code = c
+			_, err := call()
+			var e *common.VendorError
+			require.True(t, errors.As(err, &e), "%s %s", operation, c)
+			assert.Equal(t, operation, e.Operation)
+			assert.Equal(t, c, e.Code)
+			assert.Equal(t, kind, e.Kind(), "%s %s", operation, c)
+		}
+	}
+
+	code = "invalid_auth"
+	assert.Equal(t, common.ExitCodeAuth, common.ExitCode(s.Check(ctx)))
+	assert.Error(t, s.OutputSink().Write(&url.URL{Scheme: "slack", Host: "C1"}, []byte("text")))
+}
@@ -2,24 +2,30 @@ package vendors
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"mime/multipart"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
 )
 
 // assume that url is => https://api.telegram.org/botID:botToken/sendMessage?chat_id=%s
 
 const (
-	telegramSendMessageURL  = "https://api.telegram.org/bot%s/sendMessage?chat_id=%s"
-	telegramSendPhotoURL    = "https://api.telegram.org/bot%s/sendPhoto?chat_id=%s"
-	telegramSendDocumentURL = "https://api.telegram.org/bot%s/sendDocument?chat_id=%s"
+	telegramBaseURL         = "https://api.telegram.org"
+	telegramSendMessageURL  = "%s/bot%s/sendMessage?chat_id=%s"
+	telegramSendPhotoURL    = "%s/bot%s/sendPhoto?chat_id=%s"
+	telegramSendDocumentURL = "%s/bot%s/sendDocument?chat_id=%s"
+	telegramGetMeURL        = "%s/bot%s/getMe"
 )
 
 type TelegramMessageOptions struct {
-	Text string
+	Text   string
+	Format string
 }
 
 type TelegramPhotoOptions struct {
@@ -35,6 +41,7 @@ type TelegramDocumentOptions struct {
 }
 
 type TelegramOptions struct {
+	URL                   string // api.telegram.org by default
 	IDToken               string
 	ChatID                string
 	Timeout               int
@@ -42,6 +49,7 @@ type TelegramOptions struct {
 	DisableNotification   bool
 	ParseMode             string
 	DisableWebPagePreview bool
+	HTTPClient            *http.Client
 }
 
 type Telegram struct {
@@ -49,16 +57,24 @@ type Telegram struct {
 	options TelegramOptions
 }
 
+func (t *Telegram) baseURL(opts TelegramOptions) string {
+
+	if utils.IsEmpty(opts.URL) {
+		return telegramBaseURL
+	}
+	return strings.TrimSuffix(opts.URL, "/")
+}
+
 func (t *Telegram) getSendMessageURL(opts TelegramOptions) string {
-	return fmt.Sprintf(telegramSendMessageURL, opts.IDToken, opts.ChatID)
+	return fmt.Sprintf(telegramSendMessageURL, t.baseURL(opts), opts.IDToken, opts.ChatID)
 }
 
 func (t *Telegram) getSendPhotoURL(opts TelegramOptions) string {
-	return fmt.Sprintf(telegramSendPhotoURL, opts.IDToken, opts.ChatID)
+	return fmt.Sprintf(telegramSendPhotoURL, t.baseURL(opts), opts.IDToken, opts.ChatID)
 }
 
 func (t *Telegram) getSendDocumentURL(opts TelegramOptions) string {
-	return fmt.Sprintf(telegramSendDocumentURL, opts.IDToken, opts.ChatID)
+	return fmt.Sprintf(telegramSendDocumentURL, t.baseURL(opts), opts.IDToken, opts.ChatID)
 }
 
 func (t *Telegram) getDefaultParseMode(parseMode string) string {
@@ -69,7 +85,26 @@ func (t *Telegram) getDefaultParseMode(parseMode string) string {
 	return parseMode
 }
 
-func (t *Telegram) CustomSendMessage(telegramOptions TelegramOptions, messageOptions TelegramMessageOptions) ([]byte, error) {
+func (t *Telegram) convertText(text, format, parseMode string) string {
+
+	if format != common.MarkdownFormat {
+		return text
+	}
+	switch strings.ToLower(parseMode) {
+	case "html":
+		return common.MarkdownToTelegramHTML(text)
+	case "markdownv2":
+		return common.MarkdownToTelegram(text)
+	case "markdown":
+		return common.MarkdownToTelegramLegacy(text)
+	}
+	return text
+}
+
+func (t *Telegram) CustomSendMessageContext(ctx context.Context, telegramOptions TelegramOptions, messageOptions TelegramMessageOptions) (b []byte, err error) {
+
+	t = t.withContext(ctx)
+	defer vendorError("telegram", "send-message", &b, &err)
 
 	var body bytes.Buffer
 	w := multipart.NewWriter(&body)
@@ -77,11 +112,14 @@ func (t *Telegram) CustomSendMessage(telegramOptions TelegramOptions, messageOpt
 		w.Close()
 	}()
 
-	if err := w.WriteField("text", messageOptions.Text); err != nil {
+	parseMode := t.getDefaultParseMode(telegramOptions.ParseMode)
+	text := t.convertText(messageOptions.Text, messageOptions.Format, parseMode)
+
+	if err := w.WriteField("text", text); err != nil {
 		return nil, err
 	}
 
-	if err := w.WriteField("parse_mode", t.getDefaultParseMode(telegramOptions.ParseMode)); err != nil {
+	if err := w.WriteField("parse_mode", parseMode); err != nil {
 		return nil, err
 	}
 
@@ -96,14 +134,21 @@ func (t *Telegram) CustomSendMessage(telegramOptions TelegramOptions, messageOpt
 	if err := w.Close(); err != nil {
 		return nil, err
 	}
-	return utils.HttpPostRaw(t.client, t.getSendMessageURL(telegramOptions), w.FormDataContentType(), "", body.Bytes())
+	return common.HttpPostRaw(t.client, t.getSendMessageURL(telegramOptions), w.FormDataContentType(), "", body.Bytes())
+}
+
+func (t *Telegram) CustomSendMessage(telegramOptions TelegramOptions, messageOptions TelegramMessageOptions) ([]byte, error) {
+	return t.CustomSendMessageContext(context.Background(), telegramOptions, messageOptions)
 }
 
 func (t *Telegram) SendMessage(options TelegramMessageOptions) ([]byte, error) {
 	return t.CustomSendMessage(t.options, options)
 }
 
-func (t *Telegram) CustomSendPhoto(telegramOptions TelegramOptions, photoOptions TelegramPhotoOptions) ([]byte, error) {
+func (t *Telegram) CustomSendPhotoContext(ctx context.Context, telegramOptions TelegramOptions, photoOptions TelegramPhotoOptions) (b []byte, err error) {
+
+	t = t.withContext(ctx)
+	defer vendorError("telegram", "send-photo", &b, &err)
 
 	var body bytes.Buffer
 	w := multipart.NewWriter(&body)
@@ -139,14 +184,21 @@ func (t *Telegram) CustomSendPhoto(telegramOptions TelegramOptions, photoOptions
 	if err := w.Close(); err != nil {
 		return nil, err
 	}
-	return utils.HttpPostRaw(t.client, t.getSendPhotoURL(telegramOptions), w.FormDataContentType(), "", body.Bytes())
+	return common.HttpPostRaw(t.client, t.getSendPhotoURL(telegramOptions), w.FormDataContentType(), "", body.Bytes())
+}
+
+func (t *Telegram) CustomSendPhoto(telegramOptions TelegramOptions, photoOptions TelegramPhotoOptions) ([]byte, error) {
+	return t.CustomSendPhotoContext(context.Background(), telegramOptions, photoOptions)
 }
 
 func (t *Telegram) SendPhoto(options TelegramPhotoOptions) ([]byte, error) {
 	return t.CustomSendPhoto(t.options, options)
 }
 
-func (t *Telegram) CustomSendDocument(telegramOptions TelegramOptions, documentOptions TelegramDocumentOptions) ([]byte, error) {
+func (t *Telegram) CustomSendDocumentContext(ctx context.Context, telegramOptions TelegramOptions, documentOptions TelegramDocumentOptions) (b []byte, err error) {
+
+	t = t.withContext(ctx)
+	defer vendorError("telegram", "send-document", &b, &err)
 
 	var body bytes.Buffer
 	w := multipart.NewWriter(&body)
@@ -182,17 +234,55 @@ func (t *Telegram) CustomSendDocument(telegramOptions TelegramOptions, documentO
 	if err := w.Close(); err != nil {
 		return nil, err
 	}
-	return utils.HttpPostRaw(t.client, t.getSendDocumentURL(telegramOptions), w.FormDataContentType(), "", body.Bytes())
+	return common.HttpPostRaw(t.client, t.getSendDocumentURL(telegramOptions), w.FormDataContentType(), "", body.Bytes())
+}
+
+func (t *Telegram) CustomSendDocument(telegramOptions TelegramOptions, documentOptions TelegramDocumentOptions) ([]byte, error) {
+	return t.CustomSendDocumentContext(context.Background(), telegramOptions, documentOptions)
 }
 
 func (t *Telegram) SendDocument(options TelegramDocumentOptions) ([]byte, error) {
 	return t.CustomSendDocument(t.options, options)
 }
 
+func (t *Telegram) Name() string {
+	return "telegram"
+}
+
+func (t *Telegram) Options() interface{} {
+	return t.options
+}
+
+func (t *Telegram) Check(ctx context.Context) error {
+	return checkVendorHttp(ctx, t.client, "telegram", "GET", fmt.Sprintf(telegramGetMeURL, t.baseURL(t.options), t.options.IDToken), nil, nil)
+}
+
+func (t *Telegram) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("send-message", "Send message to chat", VendorOutputJSON, func(ctx context.Context, input TelegramMessageOptions) ([]byte, error) {
+			return t.CustomSendMessageContext(ctx, t.options, input)
+		}),
+		NewVendorOperation("send-photo", "Send photo to chat", VendorOutputJSON, func(ctx context.Context, input TelegramPhotoOptions) ([]byte, error) {
+			return t.CustomSendPhotoContext(ctx, t.options, input)
+		}),
+		NewVendorOperation("send-document", "Send document to chat", VendorOutputJSON, func(ctx context.Context, input TelegramDocumentOptions) ([]byte, error) {
+			return t.CustomSendDocumentContext(ctx, t.options, input)
+		}),
+	}
+}
+
+func (t *Telegram) withContext(ctx context.Context) *Telegram {
+
+	c := *t
+	c.client = common.HttpClientWithContext(ctx, t.client)
+	return &c
+}
+
 func NewTelegram(options TelegramOptions) *Telegram {
 
 	telegram := &Telegram{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "telegram", options.Timeout, options.Insecure),
 		options: options,
 	}
 	return telegram
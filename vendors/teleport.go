@@ -59,9 +59,9 @@ func newTeleportClient(options TeleportOptions, ctx context.Context) (*teleport.
 	return client, nil
 }
 
-func (t *Teleport) getClientCtx(options TeleportOptions) (*teleport.Client, context.Context, context.CancelFunc, error) {
+func (t *Teleport) getClientCtx(parent context.Context, options TeleportOptions) (*teleport.Client, context.Context, context.CancelFunc, error) {
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(options.Timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(parent, time.Duration(options.Timeout)*time.Second)
 
 	client := t.client
 	if client == nil || options != t.options {
@@ -76,9 +76,11 @@ func (t *Teleport) getClientCtx(options TeleportOptions) (*teleport.Client, cont
 	return client, ctx, cancel, nil
 }
 
-func (t *Teleport) CustomPing(options TeleportOptions) ([]byte, error) {
+func (t *Teleport) CustomPingContext(ctx context.Context, options TeleportOptions) (b []byte, err error) {
+
+	defer vendorError("teleport", "ping", &b, &err)
 
-	client, ctx, cancel, err := t.getClientCtx(options)
+	client, ctx, cancel, err := t.getClientCtx(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -91,13 +93,19 @@ func (t *Teleport) CustomPing(options TeleportOptions) ([]byte, error) {
 	return json.Marshal(resp)
 }
 
+func (t *Teleport) CustomPing(options TeleportOptions) ([]byte, error) {
+	return t.CustomPingContext(context.Background(), options)
+}
+
 func (t *Teleport) Ping() ([]byte, error) {
 	return t.CustomPing(t.options)
 }
 
-func (t *Teleport) CustomResourceList(options TeleportOptions, listOptions TeleportResourceListOptions) ([]byte, error) {
+func (t *Teleport) CustomResourceListContext(ctx context.Context, options TeleportOptions, listOptions TeleportResourceListOptions) (b []byte, err error) {
 
-	client, ctx, cancel, err := t.getClientCtx(options)
+	defer vendorError("teleport", "resource-list", &b, &err)
+
+	client, ctx, cancel, err := t.getClientCtx(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -124,10 +132,37 @@ func (t *Teleport) CustomResourceList(options TeleportOptions, listOptions Telep
 	return data, nil
 }
 
+func (t *Teleport) CustomResourceList(options TeleportOptions, listOptions TeleportResourceListOptions) ([]byte, error) {
+	return t.CustomResourceListContext(context.Background(), options, listOptions)
+}
+
 func (t *Teleport) ResourceList(options TeleportResourceListOptions) ([]byte, error) {
 	return t.CustomResourceList(t.options, options)
 }
 
+func (t *Teleport) Name() string {
+	return "teleport"
+}
+
+func (t *Teleport) Options() interface{} {
+	return t.options
+}
+
+func (t *Teleport) Check(ctx context.Context) error {
+
+	_, err := t.CustomPingContext(ctx, t.options)
+	return err
+}
+
+func (t *Teleport) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("resource-list", "List resources of kind", VendorOutputJSON, func(ctx context.Context, input TeleportResourceListOptions) ([]byte, error) {
+			return t.CustomResourceListContext(ctx, t.options, input)
+		}),
+	}
+}
+
 func NewTeleport(options TeleportOptions, logger common.Logger) *Teleport {
 
 	return &Teleport{
@@ -0,0 +1,184 @@
+package vendors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
+)
+
+const vaultAPIVersion = "v1"
+
+type VaultOptions struct {
+	URL        string
+	Token      string
+	Namespace  string
+	Timeout    int
+	Insecure   bool
+	HTTPClient *http.Client
+}
+
+type VaultSecretOptions struct {
+	Path string
+	Key  string
+}
+
+type VaultSecretResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+type Vault struct {
+	client  *http.Client
+	options VaultOptions
+}
+
+func (v *Vault) CustomGetSecretContext(ctx context.Context, vaultOptions VaultOptions, secretOptions VaultSecretOptions) (b []byte, err error) {
+
+	v = v.withContext(ctx)
+	defer vendorError("vault", "get-secret", &b, &err)
+
+	if utils.IsEmpty(vaultOptions.URL) {
+		return nil, errors.New("vault url is empty")
+	}
+
+	u, err := url.Parse(vaultOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, vaultAPIVersion, secretOptions.Path)
+
+	headers := make(map[string]string)
+	headers["X-Vault-Token"] = vaultOptions.Token
+	headers["Accept"] = "application/json"
+	if !utils.IsEmpty(vaultOptions.Namespace) {
+		headers["X-Vault-Namespace"] = vaultOptions.Namespace
+	}
+
+	b, code, err := common.HttpRequestRawWithHeadersOutCode(v.client, "GET", u.String(), headers, nil)
+	if code >= 400 {
+		// 403 of token without policy should not look like missing key of secret
+		return nil, common.NewVendorError("vault", "get-secret", code, nil, b)
+	}
+	return b, err
+}
+
+func (v *Vault) CustomGetSecret(vaultOptions VaultOptions, secretOptions VaultSecretOptions) ([]byte, error) {
+	return v.CustomGetSecretContext(context.Background(), vaultOptions, secretOptions)
+}
+
+func (v *Vault) GetSecret(options VaultSecretOptions) ([]byte, error) {
+	return v.CustomGetSecret(v.options, options)
+}
+
+// CustomGetSecretValueContext returns key value from KV v1 or v2 secret, whole data json if key is empty
+func (v *Vault) CustomGetSecretValueContext(ctx context.Context, vaultOptions VaultOptions, secretOptions VaultSecretOptions) (_ string, err error) {
+
+	v = v.withContext(ctx)
+	defer vendorError("vault", "get-secret-value", nil, &err)
+
+	b, err := v.CustomGetSecretContext(ctx, vaultOptions, secretOptions)
+	if err != nil {
+		return "", err
+	}
+
+	var r VaultSecretResponse
+	if err := json.Unmarshal(b, &r); err != nil {
+		return "", err
+	}
+
+	data := r.Data
+	// KV v2 keeps secret in data.data next to data.metadata
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	if utils.IsEmpty(secretOptions.Key) {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	value, ok := data[secretOptions.Key]
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no key %s", secretOptions.Path, secretOptions.Key)
+	}
+	return common.ValueToString(value), nil
+}
+
+func (v *Vault) CustomGetSecretValue(vaultOptions VaultOptions, secretOptions VaultSecretOptions) (string, error) {
+	return v.CustomGetSecretValueContext(context.Background(), vaultOptions, secretOptions)
+}
+
+// Resolve implements common.SecretBackend for references like "secret/data/jira#token"
+func (v *Vault) Resolve(ref string) (string, error) {
+
+	p, key, _ := strings.Cut(ref, "#")
+	return v.CustomGetSecretValue(v.options, VaultSecretOptions{
+		Path: strings.TrimPrefix(p, "/"),
+		Key:  key,
+	})
+}
+
+func (v *Vault) Name() string {
+	return "vault"
+}
+
+func (v *Vault) Options() interface{} {
+	return v.options
+}
+
+func (v *Vault) Check(ctx context.Context) error {
+
+	u, err := url.Parse(v.options.URL)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, vaultAPIVersion, "/auth/token/lookup-self")
+
+	headers := make(map[string]string)
+	headers["X-Vault-Token"] = v.options.Token
+	headers["X-Vault-Namespace"] = v.options.Namespace
+	return checkVendorHttp(ctx, v.client, "vault", "GET", u.String(), headers, nil)
+}
+
+func (v *Vault) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("get-secret", "Get secret", VendorOutputJSON, func(ctx context.Context, input VaultSecretOptions) ([]byte, error) {
+			return v.CustomGetSecretContext(ctx, v.options, input)
+		}),
+		NewVendorOperation("get-secret-value", "Get secret key value", VendorOutputText, func(ctx context.Context, input VaultSecretOptions) ([]byte, error) {
+			s, err := v.CustomGetSecretValueContext(ctx, v.options, input)
+			if err != nil {
+				return nil, err
+			}
+			return []byte(s), nil
+		}),
+	}
+}
+
+func (v *Vault) withContext(ctx context.Context) *Vault {
+
+	c := *v
+	c.client = common.HttpClientWithContext(ctx, v.client)
+	return &c
+}
+
+func NewVault(options VaultOptions) *Vault {
+
+	return &Vault{
+		client:  common.VendorHttpClient(options.HTTPClient, "vault", options.Timeout, options.Insecure),
+		options: options,
+	}
+}
@@ -0,0 +1,95 @@
+package vendors
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devopsext/tools/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupVaultMockServer(t *testing.T) *httptest.Server {
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/secret/data/jira":
+			w.Write([]byte(`{"data":{"data":{"token":"jira-token"},"metadata":{"version":1}}}`))
+		case "/v1/kv/slack":
+			w.Write([]byte(`{"data":{"token":"slack-token"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	})
+	return httptest.NewServer(handler)
+}
+
+func TestVaultResolve(t *testing.T) {
+
+	server := setupVaultMockServer(t)
+	defer server.Close()
+
+	vault := NewVault(VaultOptions{URL: server.URL, Token: "root", Timeout: 5})
+
+	tests := []struct {
+		name     string
+		ref      string
+		expected string
+		err      bool
+	}{
+		{name: "KV v2", ref: "secret/data/jira#token", expected: "jira-token"},
+		{name: "KV v1", ref: "/kv/slack#token", expected: "slack-token"},
+		{name: "Whole secret", ref: "kv/slack", expected: `{"token":"slack-token"}`},
+		{name: "Missing key", ref: "kv/slack#password", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := vault.Resolve(tt.ref)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, r)
+		})
+	}
+}
+
+func TestVaultResolveErrors(t *testing.T) {
+
+	server := setupVaultMockServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		token string
+		ref   string
+		kind  string
+	}{
+		{name: "Forbidden", token: "wrong", ref: "kv/slack#token", kind: common.VendorErrorAuth},
+		{name: "Missing path", token: "root", ref: "kv/unknown#token", kind: common.VendorErrorNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := NewVault(VaultOptions{URL: server.URL, Token: tt.token, Timeout: 5})
+			_, err := vault.Resolve(tt.ref)
+			require.Error(t, err)
+			assert.NotContains(t, err.Error(), "has no key")
+
+			var ve *common.VendorError
+			require.True(t, errors.As(err, &ve))
+			assert.Equal(t, tt.kind, ve.Kind())
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package vendors
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"net/url"
 	"path"
 
+	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
 )
 
@@ -25,12 +27,13 @@ type VCenterVMOptions struct {
 }
 
 type VCenterOptions struct {
-	Timeout  int
-	Insecure bool
-	URL      string
-	User     string
-	Password string
-	Session  string
+	Timeout    int
+	Insecure   bool
+	URL        string
+	User       string
+	Password   string
+	Session    string
+	HTTPClient *http.Client
 }
 
 type VCenterVMNameOptions struct {
@@ -84,7 +87,7 @@ func (vc *VCenter) getSession(opts VCenterOptions) (string, error) {
 	}
 	u.Path = path.Join(u.Path, VCenterRestSessionPath)
 
-	res, err := utils.HttpPostRaw(vc.client, u.String(), VCenterContentType, vc.getAuth(opts), nil)
+	res, err := common.HttpPostRaw(vc.client, u.String(), VCenterContentType, vc.getAuth(opts), nil)
 	if err != nil {
 		return "", err
 	}
@@ -105,7 +108,10 @@ func (vc *VCenter) getHeaders(session string) map[string]string {
 	return headers
 }
 
-func (vc *VCenter) CustomGetSession(options VCenterOptions) (string, error) {
+func (vc *VCenter) CustomGetSessionContext(ctx context.Context, options VCenterOptions) (_ string, err error) {
+	vc = vc.withContext(ctx)
+	defer vendorError("vcenter", "get-session", nil, &err)
+
 	if utils.IsEmpty(options.Session) {
 		s, err := vc.getSession(options)
 		if err != nil {
@@ -116,8 +122,15 @@ func (vc *VCenter) CustomGetSession(options VCenterOptions) (string, error) {
 	return options.Session, nil
 }
 
-func (vc *VCenter) CustomGetClusters(options VCenterOptions) ([]byte, error) {
-	session, err := vc.CustomGetSession(options)
+func (vc *VCenter) CustomGetSession(options VCenterOptions) (string, error) {
+	return vc.CustomGetSessionContext(context.Background(), options)
+}
+
+func (vc *VCenter) CustomGetClustersContext(ctx context.Context, options VCenterOptions) (b []byte, err error) {
+	vc = vc.withContext(ctx)
+	defer vendorError("vcenter", "get-clusters", &b, &err)
+
+	session, err := vc.CustomGetSessionContext(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -128,15 +141,22 @@ func (vc *VCenter) CustomGetClusters(options VCenterOptions) ([]byte, error) {
 	}
 
 	u.Path = path.Join(u.Path, VCenterRestClusterPath)
-	return utils.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
+	return common.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
+}
+
+func (vc *VCenter) CustomGetClusters(options VCenterOptions) ([]byte, error) {
+	return vc.CustomGetClustersContext(context.Background(), options)
 }
 
 func (vc *VCenter) GetClusters() ([]byte, error) {
 	return vc.CustomGetClusters(vc.options)
 }
 
-func (vc *VCenter) CustomGetHosts(options VCenterOptions, hostOptions VCenterHostOptions) ([]byte, error) {
-	session, err := vc.CustomGetSession(options)
+func (vc *VCenter) CustomGetHostsContext(ctx context.Context, options VCenterOptions, hostOptions VCenterHostOptions) (b []byte, err error) {
+	vc = vc.withContext(ctx)
+	defer vendorError("vcenter", "get-hosts", &b, &err)
+
+	session, err := vc.CustomGetSessionContext(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -154,15 +174,22 @@ func (vc *VCenter) CustomGetHosts(options VCenterOptions, hostOptions VCenterHos
 
 	u.Path = path.Join(u.Path, VCenterRestHostPath)
 
-	return utils.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
+	return common.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
+}
+
+func (vc *VCenter) CustomGetHosts(options VCenterOptions, hostOptions VCenterHostOptions) ([]byte, error) {
+	return vc.CustomGetHostsContext(context.Background(), options, hostOptions)
 }
 
 func (vc *VCenter) GetHosts(options VCenterHostOptions) ([]byte, error) {
 	return vc.CustomGetHosts(vc.options, options)
 }
 
-func (vc *VCenter) CustomGetVMs(options VCenterOptions, vmOptions VCenterVMOptions) ([]byte, error) {
-	session, err := vc.CustomGetSession(options)
+func (vc *VCenter) CustomGetVMsContext(ctx context.Context, options VCenterOptions, vmOptions VCenterVMOptions) (b []byte, err error) {
+	vc = vc.withContext(ctx)
+	defer vendorError("vcenter", "get-v-ms", &b, &err)
+
+	session, err := vc.CustomGetSessionContext(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -181,15 +208,22 @@ func (vc *VCenter) CustomGetVMs(options VCenterOptions, vmOptions VCenterVMOptio
 
 	u.Path = path.Join(u.Path, VCenterRestVMPath)
 
-	return utils.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
+	return common.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
+}
+
+func (vc *VCenter) CustomGetVMs(options VCenterOptions, vmOptions VCenterVMOptions) ([]byte, error) {
+	return vc.CustomGetVMsContext(context.Background(), options, vmOptions)
 }
 
 func (vc *VCenter) GetVMs(options VCenterVMOptions) ([]byte, error) {
 	return vc.CustomGetVMs(vc.options, options)
 }
 
-func (vc *VCenter) CustomGetVMGuestIdentity(options VCenterOptions, vmGuestidentity VCenterVMGuestIdentityOptions) ([]byte, error) {
-	session, err := vc.CustomGetSession(options)
+func (vc *VCenter) CustomGetVMGuestIdentityContext(ctx context.Context, options VCenterOptions, vmGuestidentity VCenterVMGuestIdentityOptions) (b []byte, err error) {
+	vc = vc.withContext(ctx)
+	defer vendorError("vcenter", "get-vm-guest-identity", &b, &err)
+
+	session, err := vc.CustomGetSessionContext(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -201,7 +235,11 @@ func (vc *VCenter) CustomGetVMGuestIdentity(options VCenterOptions, vmGuestident
 
 	u.Path = path.Join(u.Path, fmt.Sprintf(VCenterRestVMGuestIdentityPathFmt, vmGuestidentity.VM))
 
-	return utils.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
+	return common.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
+}
+
+func (vc *VCenter) CustomGetVMGuestIdentity(options VCenterOptions, vmGuestidentity VCenterVMGuestIdentityOptions) ([]byte, error) {
+	return vc.CustomGetVMGuestIdentityContext(context.Background(), options, vmGuestidentity)
 }
 
 func (vc *VCenter) GetVMGuestIdentity(options VCenterVMGuestIdentityOptions) ([]byte, error) {
@@ -213,8 +251,11 @@ func (vc *VCenter) GetVMsByName(options VCenterVMNameOptions) ([]byte, error) {
 	return vc.CustomGetVMsByName(vc.options, options)
 }
 
-func (vc *VCenter) CustomGetVMsByName(options VCenterOptions, vmNameOptions VCenterVMNameOptions) ([]byte, error) {
-	session, err := vc.CustomGetSession(options)
+func (vc *VCenter) CustomGetVMsByNameContext(ctx context.Context, options VCenterOptions, vmNameOptions VCenterVMNameOptions) (b []byte, err error) {
+	vc = vc.withContext(ctx)
+	defer vendorError("vcenter", "get-v-ms-by-name", &b, &err)
+
+	session, err := vc.CustomGetSessionContext(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -234,11 +275,18 @@ func (vc *VCenter) CustomGetVMsByName(options VCenterOptions, vmNameOptions VCen
 
 	u.Path = path.Join(u.Path, VCenterRestVMPath)
 
-	return utils.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
+	return common.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
 }
 
-func (vc *VCenter) CustomControlVMPower(options VCenterOptions, vmID string, action string) ([]byte, error) {
-	session, err := vc.CustomGetSession(options)
+func (vc *VCenter) CustomGetVMsByName(options VCenterOptions, vmNameOptions VCenterVMNameOptions) ([]byte, error) {
+	return vc.CustomGetVMsByNameContext(context.Background(), options, vmNameOptions)
+}
+
+func (vc *VCenter) CustomControlVMPowerContext(ctx context.Context, options VCenterOptions, vmID string, action string) (b []byte, err error) {
+	vc = vc.withContext(ctx)
+	defer vendorError("vcenter", "control-vm-power", &b, &err)
+
+	session, err := vc.CustomGetSessionContext(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -251,11 +299,18 @@ func (vc *VCenter) CustomControlVMPower(options VCenterOptions, vmID string, act
 	p := fmt.Sprintf(VCenterRestVMPowerPathFmt, vmID)
 	u.Path = path.Join(u.Path, fmt.Sprintf("%s/%s", p, action))
 
-	return utils.HttpPostRawWithHeaders(vc.client, u.String(), vc.getHeaders(session), nil)
+	return common.HttpPostRawWithHeaders(vc.client, u.String(), vc.getHeaders(session), nil)
 }
 
-func (vc *VCenter) CustomControlVMGuestPower(options VCenterOptions, vmID string, action string) ([]byte, error) {
-	session, err := vc.CustomGetSession(options)
+func (vc *VCenter) CustomControlVMPower(options VCenterOptions, vmID string, action string) ([]byte, error) {
+	return vc.CustomControlVMPowerContext(context.Background(), options, vmID, action)
+}
+
+func (vc *VCenter) CustomControlVMGuestPowerContext(ctx context.Context, options VCenterOptions, vmID string, action string) (b []byte, err error) {
+	vc = vc.withContext(ctx)
+	defer vendorError("vcenter", "control-vm-guest-power", &b, &err)
+
+	session, err := vc.CustomGetSessionContext(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -272,11 +327,18 @@ func (vc *VCenter) CustomControlVMGuestPower(options VCenterOptions, vmID string
 	q.Set("action", action)
 	u.RawQuery = q.Encode()
 
-	return utils.HttpPostRawWithHeaders(vc.client, u.String(), vc.getHeaders(session), nil)
+	return common.HttpPostRawWithHeaders(vc.client, u.String(), vc.getHeaders(session), nil)
 }
 
-func (vc *VCenter) CustomGetVM(options VCenterOptions, vmID string) ([]byte, error) {
-	session, err := vc.CustomGetSession(options)
+func (vc *VCenter) CustomControlVMGuestPower(options VCenterOptions, vmID string, action string) ([]byte, error) {
+	return vc.CustomControlVMGuestPowerContext(context.Background(), options, vmID, action)
+}
+
+func (vc *VCenter) CustomGetVMContext(ctx context.Context, options VCenterOptions, vmID string) (b []byte, err error) {
+	vc = vc.withContext(ctx)
+	defer vendorError("vcenter", "get-vm", &b, &err)
+
+	session, err := vc.CustomGetSessionContext(ctx, options)
 	if err != nil {
 		return nil, err
 	}
@@ -288,7 +350,11 @@ func (vc *VCenter) CustomGetVM(options VCenterOptions, vmID string) ([]byte, err
 
 	u.Path = path.Join(u.Path, fmt.Sprintf("%s/%s", VCenterRestVMPath, vmID))
 
-	return utils.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
+	return common.HttpGetRawWithHeaders(vc.client, u.String(), vc.getHeaders(session))
+}
+
+func (vc *VCenter) CustomGetVM(options VCenterOptions, vmID string) ([]byte, error) {
+	return vc.CustomGetVMContext(context.Background(), options, vmID)
 }
 
 func (vc *VCenter) StartVM(vmID string) ([]byte, error) {
@@ -315,16 +381,74 @@ func (vc *VCenter) ShutdownVM(vmID string) ([]byte, error) {
 	return vc.CustomControlVMGuestPower(vc.options, vmID, "shutdown")
 }
 
+func (vc *VCenter) Name() string {
+	return "vcenter"
+}
+
+func (vc *VCenter) Options() interface{} {
+	return vc.options
+}
+
+func (vc *VCenter) Check(ctx context.Context) error {
+
+	_, err := vc.CustomGetSessionContext(ctx, vc.options)
+	return err
+}
+
+func (vc *VCenter) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("get-clusters", "Get clusters", VendorOutputJSON, func(ctx context.Context, input struct{}) ([]byte, error) {
+			return vc.CustomGetClustersContext(ctx, vc.options)
+		}),
+		NewVendorOperation("get-hosts", "Get hosts of cluster", VendorOutputJSON, func(ctx context.Context, input VCenterHostOptions) ([]byte, error) {
+			return vc.CustomGetHostsContext(ctx, vc.options, input)
+		}),
+		NewVendorOperation("get-vms", "Get VMs of cluster or host", VendorOutputJSON, func(ctx context.Context, input VCenterVMOptions) ([]byte, error) {
+			return vc.CustomGetVMsContext(ctx, vc.options, input)
+		}),
+		NewVendorOperation("get-vms-by-name", "Get VMs by names", VendorOutputJSON, func(ctx context.Context, input VCenterVMNameOptions) ([]byte, error) {
+			return vc.CustomGetVMsByNameContext(ctx, vc.options, input)
+		}),
+		NewVendorOperation("get-vm", "Get VM", VendorOutputJSON, func(ctx context.Context, input struct{ VM string }) ([]byte, error) {
+			return vc.CustomGetVMContext(ctx, vc.options, input.VM)
+		}),
+		NewVendorOperation("get-vm-guest-identity", "Get guest identity of VM", VendorOutputJSON, func(ctx context.Context, input VCenterVMGuestIdentityOptions) ([]byte, error) {
+			return vc.CustomGetVMGuestIdentityContext(ctx, vc.options, input)
+		}),
+		NewVendorOperation("control-vm-power", "Start, stop, reset or suspend VM", VendorOutputJSON, func(ctx context.Context, input struct {
+			VM     string
+			Action string
+		}) ([]byte, error) {
+			return vc.CustomControlVMPowerContext(ctx, vc.options, input.VM, input.Action)
+		}),
+		NewVendorOperation("control-vm-guest-power", "Shutdown, reboot or standby VM guest", VendorOutputJSON, func(ctx context.Context, input struct {
+			VM     string
+			Action string
+		}) ([]byte, error) {
+			return vc.CustomControlVMGuestPowerContext(ctx, vc.options, input.VM, input.Action)
+		}),
+	}
+}
+
+func (vc *VCenter) withContext(ctx context.Context) *VCenter {
+
+	c := *vc
+	c.client = common.HttpClientWithContext(ctx, vc.client)
+	return &c
+}
+
 func NewVCenter(options VCenterOptions) *VCenter {
 
 	return &VCenter{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "vcenter", options.Timeout, options.Insecure),
 		options: options,
 	}
 }
 
-func InitializeVCenterSession(options VCenterOptions) (VCenterOptions, error) {
-	client := utils.NewHttpClient(options.Timeout, options.Insecure)
+func InitializeVCenterSession(options VCenterOptions) (_ VCenterOptions, err error) {
+	defer vendorError("vcenter", "with", nil, &err)
+	client := common.VendorHttpClient(options.HTTPClient, "vcenter", options.Timeout, options.Insecure)
 
 	tempVC := &VCenter{
 		client:  client,
@@ -0,0 +1,484 @@
+package vendors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	VendorOutputJSON = "application/json"
+	VendorOutputText = "text/plain"
+	VendorOutputPNG  = "image/png"
+)
+
+// Vendor is implemented by registered vendors, so vendors command, vendorCall template function and servers can call them generically
+type Vendor interface {
+	Name() string
+	Options() interface{}
+	Check(ctx context.Context) error
+	Operations() []VendorOperation
+}
+
+// VendorField describes option or input field, it's derived from struct field
+type VendorField struct {
+	Name      string        `json:"name"`
+	Type      string        `json:"type"`
+	Sensitive bool          `json:"sensitive,omitempty"`
+	Fields    []VendorField `json:"fields,omitempty"` // nested struct fields
+}
+
+type VendorOperation struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Input       []VendorField `json:"input,omitempty"`
+	Output      string        `json:"output"`
+	call        func(ctx context.Context, input interface{}) ([]byte, error)
+}
+
+type VendorInfo struct {
+	Name       string            `json:"name"`
+	Options    []VendorField     `json:"options"`
+	Operations []VendorOperation `json:"operations"`
+}
+
+type vendorFactory struct {
+	options    interface{}
+	operations []VendorOperation // metadata of operations, they are called on created vendor only
+	new        func(options interface{}, logger common.Logger) (Vendor, error)
+}
+
+var vendorRegistry = struct {
+	mutex     sync.RWMutex
+	factories map[string]vendorFactory
+}{factories: make(map[string]vendorFactory)}
+
+// decodeVendorValue decodes JSON bytes, string or any value marshalable to JSON into out, field names are case insensitive
+func decodeVendorValue(v interface{}, out interface{}) error {
+
+	var data []byte
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = t
+	case string:
+		data = []byte(t)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		data = b
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+func vendorFieldType(t reflect.Type) string {
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "bytes"
+		}
+		return "[]" + vendorFieldType(t.Elem())
+	case reflect.Map:
+		return "map"
+	case reflect.Ptr:
+		return vendorFieldType(t.Elem())
+	default:
+		return "object"
+	}
+}
+
+var httpClientType = reflect.TypeOf(http.Client{})
+
+// VendorFields returns fields of options or input struct, embedded structs are flattened, clients and funcs are skipped
+func VendorFields(v interface{}) []VendorField {
+	return vendorFields(reflect.TypeOf(v), 0)
+}
+
+func vendorFields(t reflect.Type, depth int) []VendorField {
+
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || depth > 3 {
+		return nil
+	}
+
+	var r []VendorField
+	for i := 0; i < t.NumField(); i++ {
+
+		f := t.Field(i)
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && ft.Kind() == reflect.Struct {
+			r = append(r, vendorFields(ft, depth)...)
+			continue
+		}
+		if !f.IsExported() || ft == httpClientType {
+			continue
+		}
+		switch ft.Kind() {
+		case reflect.Func, reflect.Chan, reflect.Interface:
+			continue
+		}
+		name := f.Name
+		if tag, _, _ := strings.Cut(f.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		field := VendorField{Name: name, Type: vendorFieldType(ft), Sensitive: common.IsSensitiveKey(name)}
+		if ft.Kind() == reflect.Struct {
+			field.Fields = vendorFields(ft, depth+1)
+		}
+		r = append(r, field)
+	}
+	return r
+}
+
+// NewVendorOperation creates operation with typed input, input is decoded from JSON or map
+func NewVendorOperation[I any](name, description, output string, call func(ctx context.Context, input I) ([]byte, error)) VendorOperation {
+
+	var zero I
+	return VendorOperation{
+		Name:        name,
+		Description: description,
+		Input:       VendorFields(zero),
+		Output:      output,
+		call: func(ctx context.Context, v interface{}) ([]byte, error) {
+			input, ok := v.(I)
+			if !ok {
+				if err := decodeVendorValue(v, &input); err != nil {
+					return nil, fmt.Errorf("invalid %s input: %v", name, err)
+				}
+			}
+			return call(ctx, input)
+		},
+	}
+}
+
+// Call decodes input and calls operation, input is typed value, map, JSON string or bytes
+func (o VendorOperation) Call(ctx context.Context, input interface{}) ([]byte, error) {
+
+	if o.call == nil {
+		return nil, fmt.Errorf("operation %s is not callable", o.Name)
+	}
+	return o.call(ctx, input)
+}
+
+// vendorOperations returns operations of vendor type without creating it, e.g. k8s vendor reads kubeconfig on creation
+func vendorOperations[V Vendor]() []VendorOperation {
+
+	t := reflect.TypeOf((*V)(nil)).Elem()
+	if t.Kind() != reflect.Ptr {
+		return nil
+	}
+	v, ok := reflect.New(t.Elem()).Interface().(Vendor)
+	if !ok {
+		return nil
+	}
+	var r []VendorOperation
+	for _, o := range v.Operations() {
+		o.call = nil
+		r = append(r, o)
+	}
+	return r
+}
+
+// RegisterVendor adds or replaces vendor, options are typed value or decoded from map or JSON
+func RegisterVendor[O any, V Vendor](name string, new func(options O, logger common.Logger) (V, error)) {
+
+	var zero O
+	operations := vendorOperations[V]()
+
+	vendorRegistry.mutex.Lock()
+	defer vendorRegistry.mutex.Unlock()
+	vendorRegistry.factories[strings.ToLower(name)] = vendorFactory{
+		options:    zero,
+		operations: operations,
+		new: func(v interface{}, logger common.Logger) (Vendor, error) {
+			var options O
+			switch t := v.(type) {
+			case O:
+				options = t
+			case *O:
+				options = *t
+			default:
+				if err := decodeVendorValue(v, &options); err != nil {
+					return nil, fmt.Errorf("invalid %s options: %v", name, err)
+				}
+			}
+			return new(options, logger)
+		},
+	}
+}
+
+func VendorNames() []string {
+
+	vendorRegistry.mutex.RLock()
+	defer vendorRegistry.mutex.RUnlock()
+
+	var r []string
+	for name := range vendorRegistry.factories {
+		r = append(r, name)
+	}
+	sort.Strings(r)
+	return r
+}
+
+func vendorFactoryOf(name string) (vendorFactory, error) {
+
+	vendorRegistry.mutex.RLock()
+	factory, ok := vendorRegistry.factories[strings.ToLower(name)]
+	vendorRegistry.mutex.RUnlock()
+
+	if !ok {
+		return vendorFactory{}, fmt.Errorf("vendor %s is not found, use one of: %s", name, strings.Join(VendorNames(), ", "))
+	}
+	return factory, nil
+}
+
+func NewVendor(name string, options interface{}, logger common.Logger) (Vendor, error) {
+
+	factory, err := vendorFactoryOf(name)
+	if err != nil {
+		return nil, err
+	}
+	return factory.new(options, logger)
+}
+
+// VendorOperations returns operations of registered vendor without creating it, they describe operations and are not callable
+func VendorOperations(name string) ([]VendorOperation, error) {
+
+	factory, err := vendorFactoryOf(name)
+	if err != nil {
+		return nil, err
+	}
+	return factory.operations, nil
+}
+
+// VendorOptionFields returns option fields of registered vendor without creating it
+func VendorOptionFields(name string) []VendorField {
+
+	vendorRegistry.mutex.RLock()
+	defer vendorRegistry.mutex.RUnlock()
+	return VendorFields(vendorRegistry.factories[strings.ToLower(name)].options)
+}
+
+func DescribeVendor(v Vendor) VendorInfo {
+
+	return VendorInfo{
+		Name:       v.Name(),
+		Options:    VendorFields(v.Options()),
+		Operations: v.Operations(),
+	}
+}
+
+// DescribeVendorName describes registered vendor without creating it
+func DescribeVendorName(name string) (VendorInfo, error) {
+
+	factory, err := vendorFactoryOf(name)
+	if err != nil {
+		return VendorInfo{}, err
+	}
+	return VendorInfo{
+		Name:       strings.ToLower(name),
+		Options:    VendorFields(factory.options),
+		Operations: factory.operations,
+	}, nil
+}
+
+func vendorOperationKey(name string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(name))
+}
+
+func findVendorOperation(vendor string, operations []VendorOperation, name string) (VendorOperation, error) {
+
+	key := vendorOperationKey(name)
+	var names []string
+	for _, o := range operations {
+		if vendorOperationKey(o.Name) == key {
+			return o, nil
+		}
+		names = append(names, o.Name)
+	}
+	return VendorOperation{}, fmt.Errorf("%s operation %s is not found, use one of: %s", vendor, name, strings.Join(names, ", "))
+}
+
+// FindVendorOperation finds operation by name, search-issue, searchIssue and search_issue are the same
+func FindVendorOperation(v Vendor, name string) (VendorOperation, error) {
+	return findVendorOperation(v.Name(), v.Operations(), name)
+}
+
+// FindVendorOperationName finds operation of registered vendor without creating it, e.g. to check allowlist before
+func FindVendorOperationName(vendor, name string) (VendorOperation, error) {
+
+	operations, err := VendorOperations(vendor)
+	if err != nil {
+		return VendorOperation{}, err
+	}
+	return findVendorOperation(strings.ToLower(vendor), operations, name)
+}
+
+// CallVendor creates vendor with options and calls its operation with input
+func CallVendor(ctx context.Context, name, operation string, options, input interface{}, logger common.Logger) ([]byte, error) {
+
+	v, err := NewVendor(name, options, logger)
+	if err != nil {
+		return nil, err
+	}
+	o, err := FindVendorOperation(v, operation)
+	if err != nil {
+		return nil, err
+	}
+	return o.Call(ctx, input)
+}
+
+// vendorError is deferred by vendor methods with named results, it converts error to common.VendorError
+func vendorError(vendor, operation string, body *[]byte, err *error) {
+
+	if *err == nil {
+		return
+	}
+	// kubernetes API errors have status and reason
+	var se apierrors.APIStatus
+	if errors.As(*err, &se) && se.Status().Code > 0 {
+		s := se.Status()
+		e := common.NewVendorError(vendor, operation, int(s.Code), nil, nil)
+		e.Code = string(s.Reason)
+		e.Message = s.Message
+		e.Err = *err
+		*err = e
+		return
+	}
+
+	var b []byte
+	if body != nil {
+		b = *body
+	}
+	*err = common.VendorErrorFrom(vendor, operation, b, *err)
+}
+
+// checkVendorHttp requests URL and returns common.VendorError on transport error or error status, it's used by health checks
+func checkVendorHttp(ctx context.Context, client *http.Client, vendor, method, URL string, headers map[string]string, body []byte) error {
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, URL, reader)
+	if err != nil {
+		return err
+	}
+	if utils.IsEmpty(req.URL.Host) {
+		return fmt.Errorf("URL host is empty")
+	}
+	for k, v := range headers {
+		if !utils.IsEmpty(v) {
+			req.Header.Set(k, v)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return common.VendorErrorFrom(vendor, "check", nil, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		e := common.NewVendorError(vendor, "check", resp.StatusCode, resp.Header, b)
+		e.Message = common.RedactSensitive(e.Message)
+		return e
+	}
+	return nil
+}
+
+func init() {
+
+	RegisterVendor("catchpoint", func(options CatchpointOptions, logger common.Logger) (*Catchpoint, error) {
+		return NewCatchpoint(options, logger), nil
+	})
+	RegisterVendor("gitlab", func(options GitlabOptions, logger common.Logger) (*Gitlab, error) {
+		return NewGitlab(options), nil
+	})
+	RegisterVendor("google", func(options GoogleOptions, logger common.Logger) (*Google, error) {
+		return NewGoogle(options, logger), nil
+	})
+	RegisterVendor("grafana", func(options GrafanaOptions, logger common.Logger) (*Grafana, error) {
+		return NewGrafana(options), nil
+	})
+	RegisterVendor("graylog", func(options GraylogOptions, logger common.Logger) (*Graylog, error) {
+		return NewGraylog(options), nil
+	})
+	RegisterVendor("jira", func(options JiraOptions, logger common.Logger) (*Jira, error) {
+		return NewJira(options), nil
+	})
+	RegisterVendor("k8s", func(options K8sOptions, logger common.Logger) (*K8s, error) {
+		return NewK8s(options, logger), nil
+	})
+	RegisterVendor("netbox", func(options NetboxOptions, logger common.Logger) (*Netbox, error) {
+		return NewNetbox(options), nil
+	})
+	RegisterVendor("observium", func(options ObserviumOptions, logger common.Logger) (*Observium, error) {
+		return NewObservium(options), nil
+	})
+	RegisterVendor("pagerduty", func(options PagerDutyOptions, logger common.Logger) (*PagerDuty, error) {
+		return NewPagerDuty(options, logger), nil
+	})
+	RegisterVendor("prometheus", func(options PrometheusOptions, logger common.Logger) (*Prometheus, error) {
+		return NewPrometheus(options), nil
+	})
+	RegisterVendor("site24x7", func(options Site24x7Options, logger common.Logger) (*Site24x7, error) {
+		return NewSite24x7(options, logger), nil
+	})
+	RegisterVendor("slack", func(options SlackOptions, logger common.Logger) (*Slack, error) {
+		return NewSlack(options), nil
+	})
+	RegisterVendor("telegram", func(options TelegramOptions, logger common.Logger) (*Telegram, error) {
+		return NewTelegram(options), nil
+	})
+	RegisterVendor("teleport", func(options TeleportOptions, logger common.Logger) (*Teleport, error) {
+		return NewTeleport(options, logger), nil
+	})
+	RegisterVendor("vault", func(options VaultOptions, logger common.Logger) (*Vault, error) {
+		return NewVault(options), nil
+	})
+	RegisterVendor("vcenter", func(options VCenterOptions, logger common.Logger) (*VCenter, error) {
+		return NewVCenter(options), nil
+	})
+	RegisterVendor("virustotal", func(options VirusTotalOptions, logger common.Logger) (*VirusTotal, error) {
+		return NewVirusTotal(options, logger), nil
+	})
+	RegisterVendor("zabbix", func(options ZabbixOptions, logger common.Logger) (*Zabbix, error) {
+		return NewZabbix(options), nil
+	})
+}
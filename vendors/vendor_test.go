@@ -0,0 +1,70 @@
+package vendors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devopsext/tools/vendors/vendortest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVendorRegistry(t *testing.T) {
+
+	ctx := context.Background()
+	s := vendortest.Start(t, "netbox")
+	s.Put("devices", "1", vendortest.Object{"id": 1, "name": "sw1"})
+
+	options := map[string]interface{}{"url": s.URL, "token": "token", "timeout": 5}
+	v, err := NewVendor("netbox", options, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "netbox", v.Name())
+	assert.NoError(t, v.Check(ctx))
+
+	o, err := FindVendorOperation(v, "getDevices")
+	require.NoError(t, err)
+	assert.Equal(t, "get-devices", o.Name)
+
+	b, err := CallVendor(ctx, "netbox", "get-devices", options, `{"deviceID":""}`, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"sw1"`)
+
+	v, err = NewVendor("netbox", NetboxOptions{URL: s.URL}, nil)
+	require.NoError(t, err)
+	assert.Error(t, v.Check(ctx))
+
+	_, err = NewVendor("unknown", nil, nil)
+	assert.Error(t, err)
+	_, err = FindVendorOperation(v, "unknown")
+	assert.Error(t, err)
+
+	fields := VendorOptionFields("netbox")
+	assert.Contains(t, fields, VendorField{Name: "Token", Type: "string", Sensitive: true})
+	assert.NotContains(t, fields, VendorField{Name: "HTTPClient", Type: "object"})
+}
+
+func TestVendorOperations(t *testing.T) {
+
+	// operations of every vendor are known without creating it
+	for _, name := range VendorNames() {
+		operations, err := VendorOperations(name)
+		require.NoError(t, err)
+		assert.NotEmpty(t, operations, name)
+		for _, o := range operations {
+			_, err := o.Call(context.Background(), nil)
+			assert.Error(t, err, "%s.%s", name, o.Name)
+		}
+	}
+
+	o, err := FindVendorOperationName("K8s", "resourceDelete")
+	require.NoError(t, err)
+	assert.Equal(t, "resource-delete", o.Name)
+	_, err = FindVendorOperationName("k8s", "unknown")
+	assert.Error(t, err)
+
+	info, err := DescribeVendorName("netbox")
+	require.NoError(t, err)
+	assert.Equal(t, "netbox", info.Name)
+	assert.Equal(t, "get-devices", info.Operations[0].Name)
+	assert.Equal(t, VendorOptionFields("netbox"), info.Options)
+}
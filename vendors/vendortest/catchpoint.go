@@ -0,0 +1,105 @@
+package vendortest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	catchpointKindNodes  = "nodes"
+	catchpointKindGroups = "node_groups"
+	catchpointKindTests  = "instant_tests"
+)
+
+var catchpointNodes = []Object{
+	{"id": 1, "name": "New York - Level3", "networkType": Object{"id": 0, "name": "Backbone"}, "country": Object{"id": 1, "name": "United States"}},
+	{"id": 2, "name": "London - Cogent", "networkType": Object{"id": 0, "name": "Backbone"}, "country": Object{"id": 2, "name": "United Kingdom"}},
+	{"id": 3, "name": "Frankfurt - AWS", "networkType": Object{"id": 6, "name": "Cloud"}, "country": Object{"id": 3, "name": "Germany"}},
+}
+
+// catchpoint wraps results into {"data", "completed", "errors", "messages"}, nodes are preset unless loaded
+func catchpoint(e *Emulator) {
+
+	e.auth = func(r *http.Request) bool {
+		return headerPrefix(r, "Authorization", "Bearer ")
+	}
+
+	ok := func(w http.ResponseWriter, data interface{}) {
+		writeJson(w, http.StatusOK, Object{"data": data, "completed": true, "errors": []Object{}, "messages": []Object{}})
+	}
+	fail := func(w http.ResponseWriter, status int, msg string) {
+		writeJson(w, status, Object{"data": nil, "completed": false, "errors": []Object{{"message": msg}}, "messages": []Object{{"message": msg}}})
+	}
+	nodes := func() []Object {
+		if list := e.List(catchpointKindNodes); len(list) > 0 {
+			return list
+		}
+		return catchpointNodes
+	}
+	const prefix = "/api/v3.2/"
+
+	e.handle("GET "+prefix+"nodes/all", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.ToLower(r.URL.Query().Get("name"))
+		r2 := make([]Object, 0)
+		for _, n := range nodes() {
+			if name == "" || strings.Contains(strings.ToLower(str(n["name"])), name) {
+				r2 = append(r2, n)
+			}
+		}
+		ok(w, Object{"nodes": r2})
+	})
+
+	e.handle("GET "+prefix+"nodes/groups/{id}", func(w http.ResponseWriter, r *http.Request) {
+		group, found := e.Get(catchpointKindGroups, r.PathValue("id"))
+		if !found {
+			// group with all nodes
+			id, _ := strconv.Atoi(r.PathValue("id"))
+			group = Object{"id": id, "name": "All", "divisionId": 1, "nodes": nodes(), "nodeLocations": []Object{}}
+		}
+		ok(w, Object{"nodeGroups": []Object{group}})
+	})
+
+	e.handle("POST "+prefix+"instanttests", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		if str(p["url"]) == "" {
+			fail(w, http.StatusBadRequest, "Test URL is required")
+			return
+		}
+		ids, _ := p["nodesIds"].([]interface{})
+		if len(ids) == 0 {
+			fail(w, http.StatusBadRequest, "At least one node is required")
+			return
+		}
+		id := strconv.Itoa(e.NextID())
+		test := Object{"id": id, "url": p["url"], "nodesIds": ids, "onDemand": r.URL.Query().Get("onDemand") == "true", "created": now()}
+		e.Put(catchpointKindTests, id, test)
+		ok(w, Object{"id": id, "nodes": ids})
+	})
+
+	e.handle("GET "+prefix+"instanttests/{id}", func(w http.ResponseWriter, r *http.Request) {
+		test, found := e.Get(catchpointKindTests, r.PathValue("id"))
+		if !found {
+			fail(w, http.StatusNotFound, "Instant test not found")
+			return
+		}
+		node := r.URL.Query().Get("nodeId")
+		ok(w, Object{
+			"id":     test["id"],
+			"nodeId": node,
+			"url":    test["url"],
+			"hosts": Object{
+				"fields":  []Object{{"name": "Response (ms)", "index": 0}, {"name": "Response Code", "index": 1}},
+				"metrics": []Object{{"hostName": test["url"], "items": []float64{120, 200}}},
+			},
+		})
+	})
+
+	e.handle("DELETE "+prefix+"instanttests/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !e.Delete(catchpointKindTests, r.PathValue("id")) {
+			fail(w, http.StatusNotFound, "Instant test not found")
+			return
+		}
+		ok(w, Object{"id": r.PathValue("id")})
+	})
+}
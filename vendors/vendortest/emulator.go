@@ -0,0 +1,685 @@
+// Package vendortest provides stateful fake vendor APIs for tests which must run without network access.
+package vendortest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// AdminPath serves objects, faults and recorded requests of emulator
+	AdminPath = "/_emulator/"
+
+	contentTypeJson = "application/json"
+)
+
+type Object = map[string]interface{}
+
+// Fault makes emulator respond with status instead of handling request
+type Fault struct {
+	Method     string        `json:"method,omitempty"`
+	Path       string        `json:"path,omitempty"` // prefix, empty matches all
+	Status     int           `json:"status"`
+	Body       string        `json:"body,omitempty"`
+	RetryAfter time.Duration `json:"retryAfter,omitempty"`
+	Times      int           `json:"times,omitempty"` // 0 fails forever
+}
+
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Emulator is http.Handler with in-memory objects per kind, e.g. "issues" or "messages"
+type Emulator struct {
+	vendor   string
+	mux      *http.ServeMux
+	auth     func(r *http.Request) bool
+	mutex    sync.Mutex
+	objects  map[string]map[string]Object
+	order    map[string][]string
+	seq      int
+	faults   []*Fault
+	requests []Request
+}
+
+type vendorFunc func(e *Emulator)
+
+var vendors = map[string]vendorFunc{
+	"catchpoint": catchpoint,
+	"gitlab":     gitlab,
+	"grafana":    grafana,
+	"jira":       jira,
+	"netbox":     netbox,
+	"pagerduty":  pagerduty,
+	"site24x7":   site24x7,
+	"slack":      slack,
+	"telegram":   telegram,
+	"vcenter":    vcenter,
+	"zabbix":     zabbix,
+}
+
+// Vendors returns sorted list of emulated vendors
+func Vendors() []string {
+
+	var r []string
+	for k := range vendors {
+		r = append(r, k)
+	}
+	sort.Strings(r)
+	return r
+}
+
+// ParseFault parses "[METHOD ]/path=status[xTimes][@retryAfter]", e.g. "POST /api/chat.postMessage=429x2@1s" or "=503"
+func ParseFault(s string) (Fault, error) {
+
+	var f Fault
+	target, spec, ok := strings.Cut(strings.TrimSpace(s), "=")
+	if !ok {
+		return f, fmt.Errorf("invalid fault %s, expected [METHOD ]/path=status[xTimes][@retryAfter]", s)
+	}
+	if method, p, ok := strings.Cut(strings.TrimSpace(target), " "); ok {
+		f.Method = strings.ToUpper(method)
+		target = p
+	}
+	f.Path = strings.TrimSpace(target)
+
+	s, after, ok := strings.Cut(spec, "@")
+	if ok {
+		d, err := time.ParseDuration(after)
+		if err != nil {
+			return f, fmt.Errorf("invalid fault retry after %s: %v", after, err)
+		}
+		f.RetryAfter = d
+	}
+	if status, times, ok := strings.Cut(s, "x"); ok {
+		n, err := strconv.Atoi(times)
+		if err != nil {
+			return f, fmt.Errorf("invalid fault times %s", times)
+		}
+		f.Times = n
+		s = status
+	}
+	status, err := strconv.Atoi(s)
+	if err != nil || status < 100 {
+		return f, fmt.Errorf("invalid fault status %s", s)
+	}
+	f.Status = status
+	return f, nil
+}
+
+// AddFault adds fault, faults are checked in order of adding
+func (e *Emulator) AddFault(f Fault) {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.faults = append(e.faults, &f)
+}
+
+// RateLimit responds 429 with Retry-After to next times requests to path
+func (e *Emulator) RateLimit(path string, times int, retryAfter time.Duration) {
+	e.AddFault(Fault{Path: path, Status: http.StatusTooManyRequests, Times: times, RetryAfter: retryAfter})
+}
+
+func (e *Emulator) ClearFaults() {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.faults = nil
+}
+
+func (e *Emulator) fault(r *http.Request) *Fault {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for i, f := range e.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+			continue
+		}
+		if !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		fault := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				e.faults = append(e.faults[:i], e.faults[i+1:]...)
+			}
+		}
+		return &fault
+	}
+	return nil
+}
+
+// Requests returns recorded requests, admin requests are not recorded
+func (e *Emulator) Requests() []Request {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return append([]Request(nil), e.requests...)
+}
+
+// NextID returns sequence shared by all kinds
+func (e *Emulator) NextID() int {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.seq++
+	return e.seq
+}
+
+// Put adds or replaces object
+func (e *Emulator) Put(kind, id string, obj Object) {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	m, ok := e.objects[kind]
+	if !ok {
+		m = make(map[string]Object)
+		e.objects[kind] = m
+	}
+	if _, ok := m[id]; !ok {
+		e.order[kind] = append(e.order[kind], id)
+	}
+	m[id] = obj
+}
+
+func (e *Emulator) Get(kind, id string) (Object, bool) {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	obj, ok := e.objects[kind][id]
+	return obj, ok
+}
+
+// Update calls fn for object under lock, so read-modify-write is atomic
+func (e *Emulator) Update(kind, id string, fn func(obj Object)) bool {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	obj, ok := e.objects[kind][id]
+	if ok {
+		fn(obj)
+	}
+	return ok
+}
+
+func (e *Emulator) Delete(kind, id string) bool {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if _, ok := e.objects[kind][id]; !ok {
+		return false
+	}
+	delete(e.objects[kind], id)
+	ids := e.order[kind]
+	for i, v := range ids {
+		if v == id {
+			e.order[kind] = append(ids[:i], ids[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// List returns objects of kind in order of creation
+func (e *Emulator) List(kind string) []Object {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	r := make([]Object, 0, len(e.order[kind]))
+	for _, id := range e.order[kind] {
+		r = append(r, e.objects[kind][id])
+	}
+	return r
+}
+
+// Find returns objects of kind for which fn is true
+func (e *Emulator) Find(kind string, fn func(obj Object) bool) []Object {
+
+	r := make([]Object, 0)
+	for _, obj := range e.List(kind) {
+		if fn(obj) {
+			r = append(r, obj)
+		}
+	}
+	return r
+}
+
+// Reset removes objects, faults and recorded requests
+func (e *Emulator) Reset() {
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.objects = make(map[string]map[string]Object)
+	e.order = make(map[string][]string)
+	e.faults = nil
+	e.requests = nil
+}
+
+// objectIDs are fields which vendors use to address objects, first found is used
+var objectIDs = []string{"key", "uid", "dashboard.uid", "vm", "hostid", "monitor_id", "profile_id", "id"}
+
+func objectID(obj Object) string {
+
+	for _, field := range objectIDs {
+		if v := str(path(obj, field)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Load puts objects from {"kind": {"id": {...}}} or {"kind": [{"id": ...}]}, see objectIDs
+func (e *Emulator) Load(data []byte) error {
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	for kind, raw := range m {
+		var byID map[string]Object
+		if err := json.Unmarshal(raw, &byID); err == nil {
+			ids := make([]string, 0, len(byID))
+			for id := range byID {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			for _, id := range ids {
+				e.Put(kind, id, byID[id])
+			}
+			continue
+		}
+		var list []Object
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return fmt.Errorf("kind %s: %v", kind, err)
+		}
+		for _, obj := range list {
+			id := objectID(obj)
+			if id == "" {
+				id = strconv.Itoa(e.NextID())
+				obj["id"] = id
+			}
+			e.Put(kind, id, obj)
+		}
+	}
+	return nil
+}
+
+func (e *Emulator) handle(pattern string, fn http.HandlerFunc) {
+	e.mux.HandleFunc(pattern, fn)
+}
+
+func (e *Emulator) record(r *http.Request) {
+
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.requests = append(e.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   string(body),
+	})
+}
+
+func (e *Emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if strings.HasPrefix(r.URL.Path, AdminPath) {
+		e.admin(w, r)
+		return
+	}
+	e.record(r)
+
+	if f := e.fault(r); f != nil {
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int((f.RetryAfter+time.Second-1)/time.Second)))
+		}
+		body := f.Body
+		if body == "" {
+			body = fmt.Sprintf(`{"error":%q}`, http.StatusText(f.Status))
+		}
+		w.Header().Set("Content-Type", contentTypeJson)
+		w.WriteHeader(f.Status)
+		w.Write([]byte(body))
+		return
+	}
+
+	if e.auth != nil && !e.auth(r) {
+		writeJson(w, http.StatusUnauthorized, Object{"error": "unauthorized"})
+		return
+	}
+	e.mux.ServeHTTP(w, r)
+}
+
+// admin serves GET|DELETE /_emulator/objects[/kind[/id]], PUT /_emulator/objects/kind/id, GET|POST|DELETE /_emulator/faults, GET /_emulator/requests
+func (e *Emulator) admin(w http.ResponseWriter, r *http.Request) {
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, AdminPath), "/"), "/")
+	switch {
+	case parts[0] == "objects" && len(parts) == 1 && r.Method == http.MethodGet:
+		e.mutex.Lock()
+		objects := make(map[string][]Object)
+		for kind, ids := range e.order {
+			for _, id := range ids {
+				objects[kind] = append(objects[kind], e.objects[kind][id])
+			}
+		}
+		e.mutex.Unlock()
+		writeJson(w, http.StatusOK, objects)
+	case parts[0] == "objects" && len(parts) == 1 && r.Method == http.MethodDelete:
+		e.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	case parts[0] == "objects" && len(parts) == 1 && r.Method == http.MethodPost:
+		b, _ := io.ReadAll(r.Body)
+		if err := e.Load(b); err != nil {
+			writeJson(w, http.StatusBadRequest, Object{"error": err.Error()})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case parts[0] == "objects" && len(parts) == 2 && r.Method == http.MethodGet:
+		writeJson(w, http.StatusOK, e.List(parts[1]))
+	case parts[0] == "objects" && len(parts) == 3 && r.Method == http.MethodGet:
+		obj, ok := e.Get(parts[1], parts[2])
+		if !ok {
+			writeJson(w, http.StatusNotFound, Object{"error": "not found"})
+			return
+		}
+		writeJson(w, http.StatusOK, obj)
+	case parts[0] == "objects" && len(parts) == 3 && r.Method == http.MethodPut:
+		var obj Object
+		if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+			writeJson(w, http.StatusBadRequest, Object{"error": err.Error()})
+			return
+		}
+		e.Put(parts[1], parts[2], obj)
+		writeJson(w, http.StatusOK, obj)
+	case parts[0] == "objects" && len(parts) == 3 && r.Method == http.MethodDelete:
+		if !e.Delete(parts[1], parts[2]) {
+			writeJson(w, http.StatusNotFound, Object{"error": "not found"})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case parts[0] == "faults" && r.Method == http.MethodGet:
+		e.mutex.Lock()
+		faults := make([]Fault, 0, len(e.faults))
+		for _, f := range e.faults {
+			faults = append(faults, *f)
+		}
+		e.mutex.Unlock()
+		writeJson(w, http.StatusOK, faults)
+	case parts[0] == "faults" && r.Method == http.MethodPost:
+		b, _ := io.ReadAll(r.Body)
+		var f Fault
+		err := json.Unmarshal(b, &f)
+		if err != nil {
+			f, err = ParseFault(string(b))
+		}
+		if err != nil {
+			writeJson(w, http.StatusBadRequest, Object{"error": err.Error()})
+			return
+		}
+		e.AddFault(f)
+		writeJson(w, http.StatusCreated, f)
+	case parts[0] == "faults" && r.Method == http.MethodDelete:
+		e.ClearFaults()
+		w.WriteHeader(http.StatusNoContent)
+	case parts[0] == "requests" && r.Method == http.MethodGet:
+		writeJson(w, http.StatusOK, e.Requests())
+	default:
+		writeJson(w, http.StatusNotFound, Object{"error": "not found"})
+	}
+}
+
+func (e *Emulator) Vendor() string {
+	return e.vendor
+}
+
+// NewEmulator creates handler for vendor, see Vendors
+func NewEmulator(vendor string) (*Emulator, error) {
+
+	fn, ok := vendors[strings.ToLower(vendor)]
+	if !ok {
+		return nil, fmt.Errorf("vendor %s is not emulated, use one of: %s", vendor, strings.Join(Vendors(), ", "))
+	}
+	e := &Emulator{
+		vendor:  strings.ToLower(vendor),
+		mux:     http.NewServeMux(),
+		objects: make(map[string]map[string]Object),
+		order:   make(map[string][]string),
+	}
+	e.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusNotFound, Object{"error": fmt.Sprintf("%s %s is not emulated", r.Method, r.URL.Path)})
+	})
+	fn(e)
+	return e, nil
+}
+
+// Server is emulator listening on local address
+type Server struct {
+	*httptest.Server
+	*Emulator
+}
+
+// redirectTransport sends requests to any host to server, so vendors with fixed API hosts are emulated as well
+type redirectTransport struct {
+	target    *url.URL
+	transport http.RoundTripper
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return t.transport.RoundTrip(req)
+}
+
+// Client returns client which sends all requests to server, it's passed to vendor options as HTTPClient
+func (s *Server) Client() *http.Client {
+
+	u, _ := url.Parse(s.URL)
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &redirectTransport{target: u, transport: s.Server.Client().Transport},
+	}
+}
+
+func NewServer(vendor string) (*Server, error) {
+
+	e, err := NewEmulator(vendor)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{Server: httptest.NewServer(e), Emulator: e}, nil
+}
+
+// Start starts server for test, it's closed on test cleanup
+func Start(tb testing.TB, vendor string) *Server {
+
+	tb.Helper()
+	s, err := NewServer(vendor)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// helpers for vendors
+
+func writeJson(w http.ResponseWriter, status int, v interface{}) {
+
+	w.Header().Set("Content-Type", contentTypeJson)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// params merges query, form, multipart and JSON object body, files are put as their content and "<name>_filename"
+func params(r *http.Request) Object {
+
+	p := make(Object)
+	for k, v := range r.URL.Query() {
+		p[k] = v[0]
+	}
+
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case ct == "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return p
+		}
+		for k, v := range r.MultipartForm.Value {
+			p[k] = v[0]
+		}
+		for k, files := range r.MultipartForm.File {
+			f, err := files[0].Open()
+			if err != nil {
+				continue
+			}
+			b, _ := io.ReadAll(f)
+			f.Close()
+			p[k] = string(b)
+			p[k+"_filename"] = files[0].Filename
+		}
+	case ct == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return p
+		}
+		for k, v := range r.PostForm {
+			p[k] = v[0]
+		}
+	default:
+		var body Object
+		if err := json.NewDecoder(r.Body).Decode(&body); err == nil {
+			for k, v := range body {
+				p[k] = v
+			}
+		}
+	}
+	return p
+}
+
+func str(v interface{}) string {
+
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// path returns value of nested field like "fields.project.key"
+func path(obj Object, field string) interface{} {
+
+	var v interface{} = obj
+	for _, k := range strings.Split(field, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
+
+func intParam(p Object, name string, def int) int {
+
+	n, err := strconv.Atoi(str(p[name]))
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// page returns items from offset up to limit
+func page(items []Object, offset, limit int) []Object {
+
+	if offset > len(items) {
+		offset = len(items)
+	}
+	end := len(items)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end]
+}
+
+func headerPrefix(r *http.Request, name, prefix string) bool {
+
+	v := r.Header.Get(name)
+	return strings.HasPrefix(v, prefix) && len(v) > len(prefix)
+}
+
+func toFloat(v interface{}) float64 {
+
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	}
+	f, _ := strconv.ParseFloat(str(v), 64)
+	return f
+}
+
+func toStrings(v interface{}) []string {
+
+	switch l := v.(type) {
+	case []string:
+		return l
+	case []interface{}:
+		r := make([]string, 0, len(l))
+		for _, s := range l {
+			r = append(r, str(s))
+		}
+		return r
+	case string:
+		return strings.Split(l, ",")
+	}
+	return nil
+}
+
+func removeEmpty(items []string) []string {
+
+	var r []string
+	for _, s := range items {
+		if s != "" {
+			r = append(r, s)
+		}
+	}
+	return r
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,215 @@
+package vendortest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func do(t *testing.T, client *http.Client, method, url, auth string, body interface{}) (int, Object) {
+
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if auth != "" {
+		name, value, _ := strings.Cut(auth, ": ")
+		req.Header.Set(name, value)
+	}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var r Object
+	b, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(b, &r); err != nil {
+		var list []interface{}
+		if json.Unmarshal(b, &list) == nil {
+			r = Object{"list": list}
+		}
+	}
+	return resp.StatusCode, r
+}
+
+func TestEmulatorCreateReadDelete(t *testing.T) {
+
+	tests := []struct {
+		vendor string
+		auth   string
+		create func(t *testing.T, c *http.Client, base, auth string) string
+		read   string
+		delete string
+	}{
+		{
+			vendor: "jira",
+			auth:   "Authorization: Bearer token",
+			create: func(t *testing.T, c *http.Client, base, auth string) string {
+				status, r := do(t, c, "POST", base+"/rest/api/2/issue", auth, Object{"fields": Object{"project": Object{"key": "OPS"}, "summary": "test"}})
+				require.Equal(t, http.StatusCreated, status)
+				return str(r["key"])
+			},
+			read:   "/rest/api/2/issue/%s",
+			delete: "/rest/api/2/issue/%s",
+		},
+		{
+			vendor: "grafana",
+			auth:   "Authorization: Bearer token",
+			create: func(t *testing.T, c *http.Client, base, auth string) string {
+				status, r := do(t, c, "POST", base+"/api/dashboards/db", auth, Object{"dashboard": Object{"title": "Test"}})
+				require.Equal(t, http.StatusOK, status)
+				return str(r["uid"])
+			},
+			read:   "/api/dashboards/uid/%s",
+			delete: "/api/dashboards/uid/%s",
+		},
+		{
+			vendor: "netbox",
+			auth:   "Authorization: Token token",
+			create: func(t *testing.T, c *http.Client, base, auth string) string {
+				status, r := do(t, c, "POST", base+"/api/dcim/devices/", auth, Object{"name": "sw1"})
+				require.Equal(t, http.StatusCreated, status)
+				return str(r["id"])
+			},
+			read:   "/api/dcim/devices/%s/",
+			delete: "/api/dcim/devices/%s/",
+		},
+		{
+			vendor: "gitlab",
+			auth:   "PRIVATE-TOKEN: token",
+			create: func(t *testing.T, c *http.Client, base, auth string) string {
+				status, r := do(t, c, "POST", base+"/api/v4/projects/7/pipeline", auth, Object{"ref": "main"})
+				require.Equal(t, http.StatusCreated, status)
+				return str(r["id"])
+			},
+			read:   "/api/v4/projects/7/pipelines/%s",
+			delete: "/api/v4/projects/7/pipelines/%s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vendor, func(t *testing.T) {
+
+			s := Start(t, tt.vendor)
+			c := s.Client()
+			// vendor host is rewritten by client
+			base := "https://" + tt.vendor + ".example.com"
+
+			status, _ := do(t, c, "GET", base+strings.ReplaceAll(tt.read, "%s", "1"), "", nil)
+			assert.Equal(t, http.StatusUnauthorized, status)
+
+			id := tt.create(t, c, base, tt.auth)
+			require.NotEmpty(t, id)
+
+			status, _ = do(t, c, "GET", base+strings.ReplaceAll(tt.read, "%s", id), tt.auth, nil)
+			assert.Equal(t, http.StatusOK, status)
+
+			status, _ = do(t, c, "DELETE", base+strings.ReplaceAll(tt.delete, "%s", id), tt.auth, nil)
+			assert.Less(t, status, 300)
+
+			status, _ = do(t, c, "GET", base+strings.ReplaceAll(tt.read, "%s", id), tt.auth, nil)
+			assert.Equal(t, http.StatusNotFound, status)
+		})
+	}
+}
+
+func TestEmulatorSlack(t *testing.T) {
+
+	s := Start(t, "slack")
+	c := s.Client()
+	auth := "Authorization: Bearer xoxb"
+
+	_, r := do(t, c, "POST", "https://slack.com/api/chat.postMessage", auth, Object{"channel": "C1", "text": "hello"})
+	require.Equal(t, true, r["ok"])
+	ts := str(r["ts"])
+
+	_, r = do(t, c, "POST", "https://slack.com/api/chat.update", auth, Object{"channel": "C1", "ts": ts, "text": "updated"})
+	assert.Equal(t, true, r["ok"])
+
+	_, r = do(t, c, "POST", "https://slack.com/api/conversations.history", auth, Object{"channel": "C1"})
+	require.Len(t, r["messages"], 1)
+	assert.Equal(t, "updated", r["messages"].([]interface{})[0].(map[string]interface{})["text"])
+
+	_, r = do(t, c, "POST", "https://slack.com/api/chat.delete", auth, Object{"channel": "C1", "ts": ts})
+	assert.Equal(t, true, r["ok"])
+
+	// errors are 200 with ok false
+	status, r := do(t, c, "POST", "https://slack.com/api/chat.delete", auth, Object{"channel": "C1", "ts": ts})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "message_not_found", r["error"])
+}
+
+func TestEmulatorFaults(t *testing.T) {
+
+	s := Start(t, "pagerduty")
+	c := s.Client()
+	auth := "Authorization: Token token=abc"
+
+	s.RateLimit("/incidents", 1, 2*time.Second)
+	f, err := ParseFault("GET /incidents=503x1")
+	require.NoError(t, err)
+	s.AddFault(f)
+
+	req, _ := http.NewRequest("GET", s.URL+"/incidents", nil)
+	req.Header.Set("Authorization", "Token token=abc")
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+
+	status, _ := do(t, c, "GET", s.URL+"/incidents", auth, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+
+	status, r := do(t, c, "GET", s.URL+"/incidents", auth, nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(0), r["total"])
+	assert.Len(t, s.Requests(), 3)
+}
+
+func TestParseFault(t *testing.T) {
+
+	tests := []struct {
+		in    string
+		fault Fault
+		err   bool
+	}{
+		{in: "=503", fault: Fault{Status: 503}},
+		{in: "/api=429x2@1s", fault: Fault{Path: "/api", Status: 429, Times: 2, RetryAfter: time.Second}},
+		{in: "post /rest/api/2/issue=500", fault: Fault{Method: "POST", Path: "/rest/api/2/issue", Status: 500}},
+		{in: "/api", err: true},
+		{in: "/api=abc", err: true},
+		{in: "/api=500@soon", err: true},
+	}
+	for _, tt := range tests {
+		f, err := ParseFault(tt.in)
+		if tt.err {
+			assert.Error(t, err, tt.in)
+			continue
+		}
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.fault, f, tt.in)
+	}
+}
+
+func TestVendors(t *testing.T) {
+
+	for _, v := range Vendors() {
+		_, err := NewEmulator(v)
+		assert.NoError(t, err, v)
+	}
+	_, err := NewEmulator("unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,145 @@
+package vendortest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const (
+	gitlabKindPipelines = "pipelines"
+	gitlabKindVariables = "variables"
+)
+
+func gitlab(e *Emulator) {
+
+	e.auth = func(r *http.Request) bool {
+		return r.Header.Get("PRIVATE-TOKEN") != "" || headerPrefix(r, "Authorization", "Bearer ")
+	}
+
+	fail := func(w http.ResponseWriter, status int, msg string) {
+		writeJson(w, status, Object{"message": msg})
+	}
+	pipeline := func(r *http.Request) (string, Object, bool) {
+		id := r.PathValue("pipeline")
+		obj, ok := e.Get(gitlabKindPipelines, id)
+		if !ok || str(obj["project_id"]) != r.PathValue("project") {
+			return id, nil, false
+		}
+		return id, obj, true
+	}
+
+	e.handle("GET /api/v4/version", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, Object{"version": "17.0.0", "revision": "emulator"})
+	})
+
+	e.handle("GET /api/v4/projects/{project}/pipelines", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		pipelines := e.Find(gitlabKindPipelines, func(obj Object) bool {
+			if str(obj["project_id"]) != r.PathValue("project") {
+				return false
+			}
+			for _, f := range []string{"ref", "status", "source", "sha"} {
+				if v := q.Get(f); v != "" && str(obj[f]) != v {
+					return false
+				}
+			}
+			return true
+		})
+		// newest first by default as GitLab does
+		orderBy := q.Get("order_by")
+		if orderBy == "" {
+			orderBy = "id"
+		}
+		desc := q.Get("sort") != "asc"
+		sort.SliceStable(pipelines, func(i, j int) bool {
+			a, b := str(pipelines[i][orderBy]), str(pipelines[j][orderBy])
+			if orderBy == "id" {
+				return (toFloat(pipelines[i]["id"]) < toFloat(pipelines[j]["id"])) != desc
+			}
+			return (a < b) != desc
+		})
+		n := intParam(params(r), "page", 1)
+		perPage := intParam(params(r), "per_page", 20)
+		if n < 1 {
+			n = 1
+		}
+		w.Header().Set("X-Total", strconv.Itoa(len(pipelines)))
+		writeJson(w, http.StatusOK, page(pipelines, (n-1)*perPage, perPage))
+	})
+
+	e.handle("POST /api/v4/projects/{project}/pipeline", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		if str(p["ref"]) == "" {
+			fail(w, http.StatusBadRequest, "ref is missing")
+			return
+		}
+		id := e.NextID()
+		project, _ := strconv.Atoi(r.PathValue("project"))
+		obj := Object{
+			"id":         id,
+			"iid":        id,
+			"project_id": project,
+			"ref":        p["ref"],
+			"sha":        fmt.Sprintf("%040d", id),
+			"status":     "created",
+			"source":     "api",
+			"created_at": now(),
+			"updated_at": now(),
+			"web_url":    fmt.Sprintf("http://%s/project/%d/-/pipelines/%d", r.Host, project, id),
+		}
+		e.Put(gitlabKindPipelines, strconv.Itoa(id), obj)
+		if vars, ok := p["variables"].([]interface{}); ok {
+			e.Put(gitlabKindVariables, strconv.Itoa(id), Object{"pipeline_id": id, "variables": vars})
+		}
+		writeJson(w, http.StatusCreated, obj)
+	})
+
+	e.handle("GET /api/v4/projects/{project}/pipelines/{pipeline}", func(w http.ResponseWriter, r *http.Request) {
+		_, obj, ok := pipeline(r)
+		if !ok {
+			fail(w, http.StatusNotFound, "404 Not found")
+			return
+		}
+		writeJson(w, http.StatusOK, obj)
+	})
+
+	e.handle("DELETE /api/v4/projects/{project}/pipelines/{pipeline}", func(w http.ResponseWriter, r *http.Request) {
+		id, _, ok := pipeline(r)
+		if !ok {
+			fail(w, http.StatusNotFound, "404 Not found")
+			return
+		}
+		e.Delete(gitlabKindPipelines, id)
+		e.Delete(gitlabKindVariables, id)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	e.handle("POST /api/v4/projects/{project}/pipelines/{pipeline}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		id, _, ok := pipeline(r)
+		if !ok {
+			fail(w, http.StatusNotFound, "404 Not found")
+			return
+		}
+		e.Update(gitlabKindPipelines, id, func(obj Object) {
+			obj["status"] = "canceled"
+			obj["updated_at"] = now()
+		})
+		obj, _ := e.Get(gitlabKindPipelines, id)
+		writeJson(w, http.StatusOK, obj)
+	})
+
+	e.handle("GET /api/v4/projects/{project}/pipelines/{pipeline}/variables", func(w http.ResponseWriter, r *http.Request) {
+		id, _, ok := pipeline(r)
+		if !ok {
+			fail(w, http.StatusNotFound, "404 Not found")
+			return
+		}
+		vars := make([]interface{}, 0)
+		if obj, ok := e.Get(gitlabKindVariables, id); ok {
+			vars, _ = obj["variables"].([]interface{})
+		}
+		writeJson(w, http.StatusOK, vars)
+	})
+}
@@ -0,0 +1,252 @@
+package vendortest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	grafanaKindFolders     = "folders"
+	grafanaKindDashboards  = "dashboards"
+	grafanaKindLibrary     = "library-elements"
+	grafanaKindAnnotations = "annotations"
+	grafanaKindAlerts      = "alerts"
+)
+
+// 1x1 transparent PNG returned by renderer
+var grafanaPNG, _ = base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII=")
+
+func grafana(e *Emulator) {
+
+	e.auth = func(r *http.Request) bool {
+		return headerPrefix(r, "Authorization", "Bearer ") || headerPrefix(r, "Authorization", "Basic ")
+	}
+
+	fail := func(w http.ResponseWriter, status int, msg string) {
+		writeJson(w, status, Object{"message": msg})
+	}
+	uid := func(v interface{}) string {
+		if s := str(v); s != "" {
+			return s
+		}
+		return fmt.Sprintf("uid%06d", e.NextID())
+	}
+
+	e.handle("GET /api/org", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, Object{"id": 1, "name": "Main Org."})
+	})
+
+	e.handle("GET /api/folders", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, e.List(grafanaKindFolders))
+	})
+
+	e.handle("POST /api/folders", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		if str(p["title"]) == "" {
+			fail(w, http.StatusBadRequest, "folder title cannot be empty")
+			return
+		}
+		folder := Object{"id": e.NextID(), "uid": uid(p["uid"]), "title": p["title"], "version": 1}
+		e.Put(grafanaKindFolders, str(folder["uid"]), folder)
+		writeJson(w, http.StatusOK, folder)
+	})
+
+	e.handle("GET /api/folders/{uid}", func(w http.ResponseWriter, r *http.Request) {
+		folder, ok := e.Get(grafanaKindFolders, r.PathValue("uid"))
+		if !ok {
+			fail(w, http.StatusNotFound, "folder not found")
+			return
+		}
+		writeJson(w, http.StatusOK, folder)
+	})
+
+	e.handle("DELETE /api/folders/{uid}", func(w http.ResponseWriter, r *http.Request) {
+		if !e.Delete(grafanaKindFolders, r.PathValue("uid")) {
+			fail(w, http.StatusNotFound, "folder not found")
+			return
+		}
+		writeJson(w, http.StatusOK, Object{"message": "Folder deleted"})
+	})
+
+	// dashboards are stored as {"dashboard": ..., "meta": ...} as returned by uid endpoint
+	e.handle("POST /api/dashboards/db", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		dashboard, _ := p["dashboard"].(map[string]interface{})
+		if dashboard == nil || str(dashboard["title"]) == "" {
+			fail(w, http.StatusBadRequest, "Dashboard title cannot be empty")
+			return
+		}
+		u := uid(dashboard["uid"])
+		version := 1
+		if existing, ok := e.Get(grafanaKindDashboards, u); ok {
+			if p["overwrite"] != true {
+				writeJson(w, http.StatusPreconditionFailed, Object{"message": "A dashboard with the same uid already exists", "status": "name-exists"})
+				return
+			}
+			version = int(toFloat(path(existing, "dashboard.version"))) + 1
+		}
+		dashboard["uid"] = u
+		dashboard["version"] = version
+		if dashboard["id"] == nil {
+			dashboard["id"] = e.NextID()
+		}
+		slug := strings.ToLower(strings.ReplaceAll(str(dashboard["title"]), " ", "-"))
+		folderUID := str(p["folderUid"])
+		e.Put(grafanaKindDashboards, u, Object{
+			"dashboard": dashboard,
+			"meta":      Object{"slug": slug, "folderUid": folderUID, "folderId": p["folderId"], "url": "/d/" + u + "/" + slug, "created": now()},
+		})
+		writeJson(w, http.StatusOK, Object{"id": dashboard["id"], "uid": u, "url": "/d/" + u + "/" + slug, "status": "success", "version": version, "slug": slug})
+	})
+
+	e.handle("GET /api/dashboards/uid/{uid}", func(w http.ResponseWriter, r *http.Request) {
+		board, ok := e.Get(grafanaKindDashboards, r.PathValue("uid"))
+		if !ok {
+			fail(w, http.StatusNotFound, "Dashboard not found")
+			return
+		}
+		writeJson(w, http.StatusOK, board)
+	})
+
+	e.handle("DELETE /api/dashboards/uid/{uid}", func(w http.ResponseWriter, r *http.Request) {
+		board, ok := e.Get(grafanaKindDashboards, r.PathValue("uid"))
+		if !ok {
+			fail(w, http.StatusNotFound, "Dashboard not found")
+			return
+		}
+		e.Delete(grafanaKindDashboards, r.PathValue("uid"))
+		title := path(board, "dashboard.title")
+		writeJson(w, http.StatusOK, Object{"title": title, "message": fmt.Sprintf("Dashboard %s deleted", title), "id": path(board, "dashboard.id")})
+	})
+
+	e.handle("GET /api/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		query := strings.ToLower(q.Get("query"))
+		folders := strings.Join(q["folderUIDs"], ",")
+		uids := strings.Join(q["dashboardUIDs"], ",")
+		r2 := make([]Object, 0)
+		for _, board := range e.List(grafanaKindDashboards) {
+			title := str(path(board, "dashboard.title"))
+			u := str(path(board, "dashboard.uid"))
+			folder := str(path(board, "meta.folderUid"))
+			if query != "" && !strings.Contains(strings.ToLower(title), query) {
+				continue
+			}
+			if folders != "" && !strings.Contains(","+folders+",", ","+folder+",") {
+				continue
+			}
+			if uids != "" && !strings.Contains(","+uids+",", ","+u+",") {
+				continue
+			}
+			r2 = append(r2, Object{
+				"id":        path(board, "dashboard.id"),
+				"uid":       u,
+				"title":     title,
+				"url":       path(board, "meta.url"),
+				"type":      "dash-db",
+				"tags":      path(board, "dashboard.tags"),
+				"folderUid": folder,
+			})
+		}
+		writeJson(w, http.StatusOK, r2)
+	})
+
+	e.handle("GET /api/library-elements", func(w http.ResponseWriter, r *http.Request) {
+		folder := r.URL.Query().Get("folderFilter")
+		elements := e.Find(grafanaKindLibrary, func(obj Object) bool {
+			return folder == "" || str(obj["folderUid"]) == folder || str(obj["folderId"]) == folder
+		})
+		writeJson(w, http.StatusOK, Object{"result": Object{"elements": elements, "totalCount": len(elements), "page": 1, "perPage": 100}})
+	})
+
+	e.handle("POST /api/library-elements", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		if str(p["name"]) == "" {
+			fail(w, http.StatusBadRequest, "library element name cannot be empty")
+			return
+		}
+		u := uid(p["uid"])
+		if _, ok := e.Get(grafanaKindLibrary, u); ok {
+			fail(w, http.StatusBadRequest, "library element with that name or UID already exists")
+			return
+		}
+		p["uid"] = u
+		p["id"] = e.NextID()
+		p["version"] = 1
+		e.Put(grafanaKindLibrary, u, p)
+		writeJson(w, http.StatusOK, Object{"result": p})
+	})
+
+	e.handle("GET /api/library-elements/{uid}", func(w http.ResponseWriter, r *http.Request) {
+		element, ok := e.Get(grafanaKindLibrary, r.PathValue("uid"))
+		if !ok {
+			fail(w, http.StatusNotFound, "library element could not be found")
+			return
+		}
+		writeJson(w, http.StatusOK, Object{"result": element})
+	})
+
+	e.handle("DELETE /api/library-elements/{uid}", func(w http.ResponseWriter, r *http.Request) {
+		if !e.Delete(grafanaKindLibrary, r.PathValue("uid")) {
+			fail(w, http.StatusNotFound, "library element could not be found")
+			return
+		}
+		writeJson(w, http.StatusOK, Object{"message": "Library element deleted"})
+	})
+
+	e.handle("POST /api/annotations", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		id := e.NextID()
+		p["id"] = id
+		if p["time"] == nil {
+			p["time"] = time.Now().UnixMilli()
+		}
+		e.Put(grafanaKindAnnotations, strconv.Itoa(id), p)
+		writeJson(w, http.StatusOK, Object{"id": id, "message": "Annotation added"})
+	})
+
+	e.handle("GET /api/annotations", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		from, _ := strconv.ParseFloat(q.Get("from"), 64)
+		to, _ := strconv.ParseFloat(q.Get("to"), 64)
+		annotations := e.Find(grafanaKindAnnotations, func(obj Object) bool {
+			t := toFloat(obj["time"])
+			if (from > 0 && t < from) || (to > 0 && t > to) {
+				return false
+			}
+			tags := fmt.Sprintf(",%s,", strings.Join(toStrings(obj["tags"]), ","))
+			for _, tag := range q["tags"] {
+				if !strings.Contains(tags, ","+tag+",") {
+					return false
+				}
+			}
+			return true
+		})
+		writeJson(w, http.StatusOK, page(annotations, 0, intParam(params(r), "limit", 100)))
+	})
+
+	e.handle("DELETE /api/annotations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !e.Delete(grafanaKindAnnotations, r.PathValue("id")) {
+			fail(w, http.StatusNotFound, "Annotation not found")
+			return
+		}
+		writeJson(w, http.StatusOK, Object{"message": "Annotation deleted"})
+	})
+
+	e.handle("GET /render/d-solo/{uid}/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := e.Get(grafanaKindDashboards, r.PathValue("uid")); !ok {
+			fail(w, http.StatusNotFound, "Dashboard not found")
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(grafanaPNG)
+	})
+
+	e.handle("GET /api/alertmanager/grafana/api/v2/alerts", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, e.List(grafanaKindAlerts))
+	})
+}
@@ -0,0 +1,323 @@
+package vendortest
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	jiraKindIssues   = "issues"
+	jiraKindComments = "comments"
+	jiraKindAssets   = "assets"
+	jiraKindUsers    = "users"
+	jiraKindProjects = "projects"
+)
+
+var jiraTransitions = []Object{
+	{"id": "11", "name": "To Do", "to": Object{"name": "To Do"}},
+	{"id": "21", "name": "In Progress", "to": Object{"name": "In Progress"}},
+	{"id": "31", "name": "Done", "to": Object{"name": "Done"}},
+}
+
+// jiraClause matches simple JQL like `project = OPS AND status = "In Progress"`
+var jiraClause = regexp.MustCompile(`(?i)^\s*(\w+)\s*=\s*"?([^"]*?)"?\s*$`)
+
+var jiraFields = map[string]string{
+	"project":   "fields.project.key",
+	"status":    "fields.status.name",
+	"issuetype": "fields.issuetype.name",
+	"type":      "fields.issuetype.name",
+	"assignee":  "fields.assignee.name",
+	"priority":  "fields.priority.name",
+}
+
+func jiraMatch(issue Object, jql string) bool {
+
+	// ordering is ignored
+	if i := strings.Index(strings.ToLower(jql), "order by"); i >= 0 {
+		jql = jql[:i]
+	}
+	for _, clause := range regexp.MustCompile(`(?i)\s+and\s+`).Split(jql, -1) {
+		if strings.TrimSpace(clause) == "" {
+			continue
+		}
+		m := jiraClause.FindStringSubmatch(clause)
+		if m == nil {
+			return false
+		}
+		field := strings.ToLower(m[1])
+		var v interface{}
+		if field == "key" {
+			v = issue["key"]
+		} else if p, ok := jiraFields[field]; ok {
+			v = path(issue, p)
+		} else {
+			v = path(issue, "fields."+m[1])
+		}
+		if !strings.EqualFold(str(v), m[2]) {
+			return false
+		}
+	}
+	return true
+}
+
+// jiraAssetLabel returns first value of first attribute, it's name attribute in tools
+func jiraAssetLabel(attributes interface{}) string {
+
+	attrs, _ := attributes.([]interface{})
+	if len(attrs) == 0 {
+		return ""
+	}
+	attr, _ := attrs[0].(map[string]interface{})
+	values, _ := attr["objectAttributeValues"].([]interface{})
+	if len(values) == 0 {
+		return ""
+	}
+	value, _ := values[0].(map[string]interface{})
+	return str(value["value"])
+}
+
+func jira(e *Emulator) {
+
+	e.auth = func(r *http.Request) bool {
+		return headerPrefix(r, "Authorization", "Basic ") || headerPrefix(r, "Authorization", "Bearer ")
+	}
+
+	fail := func(w http.ResponseWriter, status int, msg string) {
+		writeJson(w, status, Object{"errorMessages": []string{msg}, "errors": Object{}})
+	}
+	// issues are stored by key, id is accepted as well
+	issue := func(r *http.Request) (string, Object, bool) {
+		idOrKey := r.PathValue("issue")
+		if obj, ok := e.Get(jiraKindIssues, idOrKey); ok {
+			return idOrKey, obj, true
+		}
+		for _, obj := range e.List(jiraKindIssues) {
+			if str(obj["id"]) == idOrKey {
+				return str(obj["key"]), obj, true
+			}
+		}
+		return "", nil, false
+	}
+	self := func(r *http.Request, p string) string {
+		return fmt.Sprintf("http://%s%s", r.Host, p)
+	}
+
+	e.handle("GET /rest/api/2/myself", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, Object{"name": "emulator", "emailAddress": "emulator@example.com", "active": true})
+	})
+
+	e.handle("GET /rest/api/2/project", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, e.List(jiraKindProjects))
+	})
+
+	e.handle("POST /rest/api/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		fields, _ := p["fields"].(map[string]interface{})
+		project := str(path(p, "fields.project.key"))
+		if fields == nil || project == "" {
+			fail(w, http.StatusBadRequest, "project is required")
+			return
+		}
+		if path(p, "fields.status.name") == nil {
+			fields["status"] = Object{"name": "To Do"}
+		}
+		id := strconv.Itoa(e.NextID())
+		key := fmt.Sprintf("%s-%s", project, id)
+		e.Put(jiraKindIssues, key, Object{"id": id, "key": key, "fields": fields})
+		writeJson(w, http.StatusCreated, Object{"id": id, "key": key, "self": self(r, "/rest/api/2/issue/"+id)})
+	})
+
+	e.handle("GET /rest/api/2/issue/{issue}", func(w http.ResponseWriter, r *http.Request) {
+		_, obj, ok := issue(r)
+		if !ok {
+			fail(w, http.StatusNotFound, "Issue Does Not Exist")
+			return
+		}
+		writeJson(w, http.StatusOK, obj)
+	})
+
+	e.handle("PUT /rest/api/2/issue/{issue}", func(w http.ResponseWriter, r *http.Request) {
+		key, _, ok := issue(r)
+		if !ok {
+			fail(w, http.StatusNotFound, "Issue Does Not Exist")
+			return
+		}
+		fields, _ := params(r)["fields"].(map[string]interface{})
+		e.Update(jiraKindIssues, key, func(obj Object) {
+			existing, _ := obj["fields"].(map[string]interface{})
+			for k, v := range fields {
+				existing[k] = v
+			}
+		})
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	e.handle("DELETE /rest/api/2/issue/{issue}", func(w http.ResponseWriter, r *http.Request) {
+		key, _, ok := issue(r)
+		if !ok {
+			fail(w, http.StatusNotFound, "Issue Does Not Exist")
+			return
+		}
+		e.Delete(jiraKindIssues, key)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	e.handle("POST /rest/api/2/issue/{issue}/comment", func(w http.ResponseWriter, r *http.Request) {
+		key, _, ok := issue(r)
+		if !ok {
+			fail(w, http.StatusNotFound, "Issue Does Not Exist")
+			return
+		}
+		id := strconv.Itoa(e.NextID())
+		comment := Object{"id": id, "issue": key, "body": params(r)["body"], "created": now()}
+		e.Put(jiraKindComments, id, comment)
+		writeJson(w, http.StatusCreated, comment)
+	})
+
+	e.handle("GET /rest/api/2/issue/{issue}/comment", func(w http.ResponseWriter, r *http.Request) {
+		key, _, ok := issue(r)
+		if !ok {
+			fail(w, http.StatusNotFound, "Issue Does Not Exist")
+			return
+		}
+		comments := e.Find(jiraKindComments, func(obj Object) bool {
+			return obj["issue"] == key
+		})
+		writeJson(w, http.StatusOK, Object{"comments": comments, "total": len(comments), "startAt": 0, "maxResults": len(comments)})
+	})
+
+	e.handle("POST /rest/api/2/issue/{issue}/attachments", func(w http.ResponseWriter, r *http.Request) {
+		key, _, ok := issue(r)
+		if !ok {
+			fail(w, http.StatusNotFound, "Issue Does Not Exist")
+			return
+		}
+		p := params(r)
+		id := strconv.Itoa(e.NextID())
+		attachment := Object{"id": id, "issue": key, "filename": p["file_filename"], "size": len(str(p["file"]))}
+		e.Put("attachments", id, attachment)
+		writeJson(w, http.StatusOK, []Object{attachment})
+	})
+
+	e.handle("GET /rest/api/2/issue/{issue}/transitions", func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := issue(r); !ok {
+			fail(w, http.StatusNotFound, "Issue Does Not Exist")
+			return
+		}
+		writeJson(w, http.StatusOK, Object{"transitions": jiraTransitions})
+	})
+
+	e.handle("POST /rest/api/2/issue/{issue}/transitions", func(w http.ResponseWriter, r *http.Request) {
+		key, _, ok := issue(r)
+		if !ok {
+			fail(w, http.StatusNotFound, "Issue Does Not Exist")
+			return
+		}
+		id := str(path(params(r), "transition.id"))
+		for _, t := range jiraTransitions {
+			if t["id"] == id {
+				e.Update(jiraKindIssues, key, func(obj Object) {
+					fields, _ := obj["fields"].(map[string]interface{})
+					fields["status"] = Object{"name": t["to"].(Object)["name"]}
+				})
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		fail(w, http.StatusBadRequest, fmt.Sprintf("Transition id '%s' is not valid for this issue.", id))
+	})
+
+	e.handle("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		jql := str(p["jql"])
+		issues := e.Find(jiraKindIssues, func(obj Object) bool {
+			return jiraMatch(obj, jql)
+		})
+		startAt := intParam(p, "startAt", 0)
+		maxResults := intParam(p, "maxResults", 50)
+		writeJson(w, http.StatusOK, Object{
+			"startAt":    startAt,
+			"maxResults": maxResults,
+			"total":      len(issues),
+			"issues":     page(issues, startAt, maxResults),
+		})
+	})
+
+	e.handle("GET /rest/api/2/user/search", func(w http.ResponseWriter, r *http.Request) {
+		username := r.URL.Query().Get("username")
+		users := e.Find(jiraKindUsers, func(obj Object) bool {
+			return strings.EqualFold(str(obj["name"]), username) || strings.EqualFold(str(obj["emailAddress"]), username)
+		})
+		writeJson(w, http.StatusOK, users)
+	})
+
+	e.handle("POST /rest/assets/1.0/object/create", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		id := strconv.Itoa(e.NextID())
+		asset := Object{
+			"id":              id,
+			"objectKey":       fmt.Sprintf("ASSET-%s", id),
+			"objectTypeId":    p["objectTypeId"],
+			"objectSchemaId":  r.URL.Query().Get("objectSchemaId"),
+			"attributes":      p["attributes"],
+			"label":           jiraAssetLabel(p["attributes"]),
+			"created":         now(),
+			"objectEntryType": "object",
+		}
+		e.Put(jiraKindAssets, id, asset)
+		writeJson(w, http.StatusCreated, asset)
+	})
+
+	e.handle("PUT /rest/assets/1.0/object/{id}", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		updated := e.Update(jiraKindAssets, r.PathValue("id"), func(obj Object) {
+			for k, v := range p {
+				obj[k] = v
+			}
+		})
+		if !updated {
+			fail(w, http.StatusNotFound, "Object not found")
+			return
+		}
+		obj, _ := e.Get(jiraKindAssets, r.PathValue("id"))
+		writeJson(w, http.StatusOK, obj)
+	})
+
+	e.handle("DELETE /rest/assets/1.0/object/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !e.Delete(jiraKindAssets, r.PathValue("id")) {
+			fail(w, http.StatusNotFound, "Object not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	// pageSize is number of pages as Insight returns
+	e.handle("GET /rest/insight/1.0/aql/objects", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		perPage := intParam(p, "resultPerPage", 25)
+		if perPage <= 0 {
+			perPage = 25
+		}
+		n := intParam(p, "page", 1)
+		if n < 1 {
+			n = 1
+		}
+		assets := e.List(jiraKindAssets)
+		pages := (len(assets) + perPage - 1) / perPage
+		if pages == 0 {
+			pages = 1
+		}
+		writeJson(w, http.StatusOK, Object{
+			"objectEntries":        page(assets, (n-1)*perPage, perPage),
+			"objectTypeAttributes": []Object{},
+			"totalFilterCount":     len(assets),
+			"pageNumber":           n,
+			"pageSize":             pages,
+			"qlQuery":              p["qlQuery"],
+		})
+	})
+}
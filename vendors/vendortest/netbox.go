@@ -0,0 +1,140 @@
+package vendortest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const netboxKindDevices = "devices"
+
+// netboxFilters maps query filters to device fields
+var netboxFilters = map[string]string{
+	"name":   "name",
+	"site":   "site.slug",
+	"role":   "role.slug",
+	"status": "status.value",
+	"tenant": "tenant.slug",
+	"tag":    "tags",
+}
+
+func netbox(e *Emulator) {
+
+	e.auth = func(r *http.Request) bool {
+		return headerPrefix(r, "Authorization", "Token ") || headerPrefix(r, "Authorization", "Bearer ")
+	}
+
+	fail := func(w http.ResponseWriter, status int, msg string) {
+		writeJson(w, status, Object{"detail": msg})
+	}
+
+	e.handle("GET /api/status/", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, Object{"netbox-version": "4.0.0"})
+	})
+
+	// next is absolute URL as Netbox returns
+	e.handle("GET /api/dcim/devices/", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		devices := e.Find(netboxKindDevices, func(obj Object) bool {
+			for param, field := range netboxFilters {
+				v := q.Get(param)
+				if v == "" {
+					continue
+				}
+				if !strings.Contains(","+strings.Join(toStrings(netboxValue(obj, field)), ",")+",", ","+v+",") {
+					return false
+				}
+			}
+			return true
+		})
+		limit := intParam(params(r), "limit", 50)
+		offset := intParam(params(r), "offset", 0)
+		items := page(devices, offset, limit)
+
+		var next interface{}
+		if limit > 0 && offset+len(items) < len(devices) {
+			u := url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path}
+			if r.TLS != nil {
+				u.Scheme = "https"
+			}
+			q.Set("offset", strconv.Itoa(offset+limit))
+			q.Set("limit", strconv.Itoa(limit))
+			u.RawQuery = q.Encode()
+			next = u.String()
+		}
+		writeJson(w, http.StatusOK, Object{"count": len(devices), "next": next, "previous": nil, "results": items})
+	})
+
+	e.handle("POST /api/dcim/devices/", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		if str(p["name"]) == "" {
+			writeJson(w, http.StatusBadRequest, Object{"name": []string{"This field is required."}})
+			return
+		}
+		id := e.NextID()
+		p["id"] = id
+		p["url"] = fmt.Sprintf("http://%s/api/dcim/devices/%d/", r.Host, id)
+		p["created"] = now()
+		e.Put(netboxKindDevices, strconv.Itoa(id), p)
+		writeJson(w, http.StatusCreated, p)
+	})
+
+	e.handle("GET /api/dcim/devices/{id}/", func(w http.ResponseWriter, r *http.Request) {
+		device, ok := e.Get(netboxKindDevices, r.PathValue("id"))
+		if !ok {
+			fail(w, http.StatusNotFound, "Not found.")
+			return
+		}
+		writeJson(w, http.StatusOK, device)
+	})
+
+	e.handle("PATCH /api/dcim/devices/{id}/", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		updated := e.Update(netboxKindDevices, r.PathValue("id"), func(obj Object) {
+			for k, v := range p {
+				if k != "id" {
+					obj[k] = v
+				}
+			}
+		})
+		if !updated {
+			fail(w, http.StatusNotFound, "Not found.")
+			return
+		}
+		device, _ := e.Get(netboxKindDevices, r.PathValue("id"))
+		writeJson(w, http.StatusOK, device)
+	})
+
+	e.handle("DELETE /api/dcim/devices/{id}/", func(w http.ResponseWriter, r *http.Request) {
+		if !e.Delete(netboxKindDevices, r.PathValue("id")) {
+			fail(w, http.StatusNotFound, "Not found.")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// netboxValue returns nested field, tags are matched by slug or name
+func netboxValue(obj Object, field string) interface{} {
+
+	if field != "tags" {
+		v := path(obj, field)
+		if v == nil && strings.Contains(field, ".") {
+			// plain value, e.g. {"site": "dc1"} in loaded data
+			v = path(obj, strings.Split(field, ".")[0])
+		}
+		return v
+	}
+	tags, _ := obj["tags"].([]interface{})
+	r := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if m, ok := t.(map[string]interface{}); ok {
+			r = append(r, str(m["slug"]), str(m["name"]))
+			continue
+		}
+		r = append(r, str(t))
+	}
+	return r
+}
@@ -0,0 +1,133 @@
+package vendortest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	pagerDutyKindIncidents = "incidents"
+	pagerDutyKindNotes     = "notes"
+)
+
+func pagerduty(e *Emulator) {
+
+	e.auth = func(r *http.Request) bool {
+		return headerPrefix(r, "Authorization", "Token token=")
+	}
+
+	fail := func(w http.ResponseWriter, status int, msg string) {
+		writeJson(w, status, Object{"error": Object{"message": msg, "code": 2001, "errors": []string{msg}}})
+	}
+	from := func(r *http.Request) bool {
+		return r.URL.Query().Get("from") != "" || r.Header.Get("From") != ""
+	}
+
+	e.handle("GET /abilities", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, Object{"abilities": []string{}})
+	})
+
+	e.handle("POST /incidents", func(w http.ResponseWriter, r *http.Request) {
+		if !from(r) {
+			fail(w, http.StatusBadRequest, "Requester User Not Found")
+			return
+		}
+		incident, _ := params(r)["incident"].(map[string]interface{})
+		if incident == nil || str(incident["title"]) == "" {
+			fail(w, http.StatusBadRequest, "Incident title is required")
+			return
+		}
+		if str(path(incident, "service.id")) == "" {
+			fail(w, http.StatusBadRequest, "Service is required")
+			return
+		}
+		n := e.NextID()
+		id := fmt.Sprintf("P%06d", n)
+		incident["id"] = id
+		incident["incident_number"] = n
+		incident["status"] = "triggered"
+		incident["created_at"] = now()
+		if incident["incident_key"] == nil {
+			incident["incident_key"] = id
+		}
+		e.Put(pagerDutyKindIncidents, id, incident)
+		writeJson(w, http.StatusCreated, Object{"incident": incident})
+	})
+
+	e.handle("GET /incidents", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		key := str(p["incident_key"])
+		statuses := r.URL.Query()["statuses[]"]
+		incidents := e.Find(pagerDutyKindIncidents, func(obj Object) bool {
+			if key != "" && str(obj["incident_key"]) != key {
+				return false
+			}
+			return len(statuses) == 0 || strings.Contains(","+strings.Join(statuses, ",")+",", ","+str(obj["status"])+",")
+		})
+		offset := intParam(p, "offset", 0)
+		limit := intParam(p, "limit", 25)
+		items := page(incidents, offset, limit)
+		writeJson(w, http.StatusOK, Object{
+			"incidents": items,
+			"limit":     limit,
+			"offset":    offset,
+			"total":     len(incidents),
+			"more":      offset+len(items) < len(incidents),
+		})
+	})
+
+	e.handle("GET /incidents/{id}", func(w http.ResponseWriter, r *http.Request) {
+		incident, ok := e.Get(pagerDutyKindIncidents, r.PathValue("id"))
+		if !ok {
+			fail(w, http.StatusNotFound, "Incident Not Found")
+			return
+		}
+		writeJson(w, http.StatusOK, Object{"incident": incident})
+	})
+
+	e.handle("PUT /incidents/{id}", func(w http.ResponseWriter, r *http.Request) {
+		update, _ := params(r)["incident"].(map[string]interface{})
+		updated := e.Update(pagerDutyKindIncidents, r.PathValue("id"), func(obj Object) {
+			for k, v := range update {
+				if k != "id" && k != "type" {
+					obj[k] = v
+				}
+			}
+		})
+		if !updated {
+			fail(w, http.StatusNotFound, "Incident Not Found")
+			return
+		}
+		incident, _ := e.Get(pagerDutyKindIncidents, r.PathValue("id"))
+		writeJson(w, http.StatusOK, Object{"incident": incident})
+	})
+
+	e.handle("POST /incidents/{id}/notes", func(w http.ResponseWriter, r *http.Request) {
+		incident := r.PathValue("id")
+		if _, ok := e.Get(pagerDutyKindIncidents, incident); !ok {
+			fail(w, http.StatusNotFound, "Incident Not Found")
+			return
+		}
+		if !from(r) {
+			fail(w, http.StatusBadRequest, "Requester User Not Found")
+			return
+		}
+		id := fmt.Sprintf("N%06d", e.NextID())
+		note := Object{"id": id, "incident": incident, "content": path(params(r), "note.content"), "created_at": now()}
+		e.Put(pagerDutyKindNotes, id, note)
+		writeJson(w, http.StatusCreated, Object{"note": note})
+	})
+
+	e.handle("GET /incidents/{id}/notes", func(w http.ResponseWriter, r *http.Request) {
+		incident := r.PathValue("id")
+		if _, ok := e.Get(pagerDutyKindIncidents, incident); !ok {
+			fail(w, http.StatusNotFound, "Incident Not Found")
+			return
+		}
+		notes := e.Find(pagerDutyKindNotes, func(obj Object) bool {
+			return obj["incident"] == incident
+		})
+		writeJson(w, http.StatusOK, Object{"notes": notes})
+	})
+}
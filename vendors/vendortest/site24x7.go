@@ -0,0 +1,184 @@
+package vendortest
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	site24x7KindMonitors = "monitors"
+	site24x7KindProfiles = "location_profiles"
+	site24x7KindReports  = "log_reports"
+	site24x7KindTokens   = "tokens"
+)
+
+var site24x7Locations = []Object{
+	{"location_id": "1", "display_name": "Dallas - US", "city_name": "Dallas", "city_short": "DAL", "country_name": "United States", "continent": "North America"},
+	{"location_id": "2", "display_name": "London - UK", "city_name": "London", "city_short": "LON", "country_name": "United Kingdom", "continent": "Europe"},
+	{"location_id": "3", "display_name": "Frankfurt - DE", "city_name": "Frankfurt", "city_short": "FRA", "country_name": "Germany", "continent": "Europe"},
+	{"location_id": "4", "display_name": "Singapore - SG", "city_name": "Singapore", "city_short": "SIN", "country_name": "Singapore", "continent": "Asia"},
+}
+
+// site24x7 serves Zoho token endpoint as well, so URL and AuthURL may point to the same emulator
+func site24x7(e *Emulator) {
+
+	e.auth = func(r *http.Request) bool {
+		if r.URL.Path == "/oauth/v2/token" {
+			return true
+		}
+		return headerPrefix(r, "Authorization", "Zoho-oauthtoken ")
+	}
+
+	ok := func(w http.ResponseWriter, status int, data interface{}) {
+		writeJson(w, status, Object{"code": 0, "message": "success", "data": data})
+	}
+	fail := func(w http.ResponseWriter, status, code int, msg string) {
+		writeJson(w, status, Object{"error_code": code, "message": msg})
+	}
+	monitor := func(w http.ResponseWriter, r *http.Request) (Object, bool) {
+		m, found := e.Get(site24x7KindMonitors, r.PathValue("id"))
+		if !found {
+			fail(w, http.StatusNotFound, 1030, "Monitor does not exist")
+		}
+		return m, found
+	}
+
+	e.handle("POST /oauth/v2/token", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		if str(p["refresh_token"]) == "" || str(p["client_id"]) == "" {
+			writeJson(w, http.StatusOK, Object{"error": "invalid_code"})
+			return
+		}
+		token := fmt.Sprintf("1000.%032x", e.NextID())
+		e.Put(site24x7KindTokens, token, Object{"client_id": p["client_id"]})
+		writeJson(w, http.StatusOK, Object{"access_token": token, "api_domain": "https://www.zohoapis.com", "token_type": "Bearer", "expires_in": 3600})
+	})
+
+	e.handle("GET /api/location_template", func(w http.ResponseWriter, r *http.Request) {
+		ok(w, http.StatusOK, Object{"locations": site24x7Locations})
+	})
+
+	e.handle("GET /api/location_profiles", func(w http.ResponseWriter, r *http.Request) {
+		ok(w, http.StatusOK, e.List(site24x7KindProfiles))
+	})
+
+	e.handle("POST /api/location_profiles", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		if str(p["profile_name"]) == "" || str(p["primary_location"]) == "" {
+			fail(w, http.StatusBadRequest, 2202, "Profile name and primary location are required")
+			return
+		}
+		id := fmt.Sprintf("%d", 100000000+e.NextID())
+		p["profile_id"] = id
+		e.Put(site24x7KindProfiles, id, p)
+		ok(w, http.StatusCreated, p)
+	})
+
+	e.handle("DELETE /api/location_profiles/{id}", func(w http.ResponseWriter, r *http.Request) {
+		profile, found := e.Get(site24x7KindProfiles, r.PathValue("id"))
+		if !found {
+			fail(w, http.StatusNotFound, 1030, "Location profile does not exist")
+			return
+		}
+		e.Delete(site24x7KindProfiles, r.PathValue("id"))
+		ok(w, http.StatusOK, Object{"resource_name": profile["profile_name"]})
+	})
+
+	e.handle("GET /api/monitors", func(w http.ResponseWriter, r *http.Request) {
+		ok(w, http.StatusOK, e.List(site24x7KindMonitors))
+	})
+
+	e.handle("POST /api/monitors", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		if str(p["display_name"]) == "" {
+			fail(w, http.StatusBadRequest, 2202, "Display name is required")
+			return
+		}
+		id := fmt.Sprintf("%d", 200000000+e.NextID())
+		p["monitor_id"] = id
+		p["state"] = 0
+		e.Put(site24x7KindMonitors, id, p)
+		ok(w, http.StatusCreated, p)
+	})
+
+	e.handle("GET /api/monitors/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if m, found := monitor(w, r); found {
+			ok(w, http.StatusOK, m)
+		}
+	})
+
+	e.handle("DELETE /api/monitors/{id}", func(w http.ResponseWriter, r *http.Request) {
+		m, found := monitor(w, r)
+		if !found {
+			return
+		}
+		e.Delete(site24x7KindMonitors, r.PathValue("id"))
+		e.Delete(site24x7KindReports, r.PathValue("id"))
+		ok(w, http.StatusOK, Object{"resource_name": m["display_name"]})
+	})
+
+	e.handle("GET /api/monitors/name/{name}", func(w http.ResponseWriter, r *http.Request) {
+		monitors := e.Find(site24x7KindMonitors, func(obj Object) bool {
+			return str(obj["display_name"]) == r.PathValue("name")
+		})
+		if len(monitors) == 0 {
+			fail(w, http.StatusNotFound, 1030, "Monitor does not exist")
+			return
+		}
+		ok(w, http.StatusOK, monitors[0])
+	})
+
+	// tools activates monitor with DELETE, so any method is accepted
+	state := func(value int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			m, found := monitor(w, r)
+			if !found {
+				return
+			}
+			e.Update(site24x7KindMonitors, r.PathValue("id"), func(obj Object) { obj["state"] = value })
+			ok(w, http.StatusOK, Object{"resource_name": m["display_name"]})
+		}
+	}
+	e.handle("/api/monitors/activate/{id}", state(0))
+	e.handle("/api/monitors/suspend/{id}", state(5))
+
+	// poll adds log report which is returned by log_reports
+	e.handle("/api/monitor/poll_now/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if _, found := monitor(w, r); !found {
+			return
+		}
+		id := r.PathValue("id")
+		e.Put(site24x7KindReports, id, Object{"report": []Object{{
+			"collection_time":      time.Now().UTC().Format("2006-01-02T15:04:05-0700"),
+			"availability":         1,
+			"response_time":        "120",
+			"response_code":        "200",
+			"location_id":          site24x7Locations[0]["location_id"],
+			"data_collection_type": 3,
+		}}})
+		ok(w, http.StatusOK, Object{"status": "polling", "monitor_id": id})
+	})
+
+	e.handle("GET /api/monitor/status_poll_now/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if _, found := monitor(w, r); !found {
+			return
+		}
+		status := "initiated"
+		if _, polled := e.Get(site24x7KindReports, r.PathValue("id")); polled {
+			status = "completed"
+		}
+		ok(w, http.StatusOK, Object{"status": status, "monitor_id": r.PathValue("id")})
+	})
+
+	e.handle("GET /api/reports/log_reports/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if _, found := monitor(w, r); !found {
+			return
+		}
+		report, found := e.Get(site24x7KindReports, r.PathValue("id"))
+		if !found {
+			report = Object{"report": []Object{}}
+		}
+		ok(w, http.StatusOK, report)
+	})
+}
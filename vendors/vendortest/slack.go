@@ -0,0 +1,222 @@
+package vendortest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	slackKindMessages  = "messages"
+	slackKindChannels  = "channels"
+	slackKindScheduled = "scheduled"
+)
+
+// slack answers 200 with ok false on errors as Slack API does
+func slack(e *Emulator) {
+
+	e.auth = func(r *http.Request) bool {
+		return headerPrefix(r, "Authorization", "Bearer ")
+	}
+
+	ok := func(w http.ResponseWriter, obj Object) {
+		obj["ok"] = true
+		writeJson(w, http.StatusOK, obj)
+	}
+	fail := func(w http.ResponseWriter, err string) {
+		writeJson(w, http.StatusOK, Object{"ok": false, "error": err})
+	}
+	ts := func() string {
+		return fmt.Sprintf("%d.%06d", time.Now().Unix(), e.NextID())
+	}
+	message := func(channel string, p Object) Object {
+		return Object{
+			"type":        "message",
+			"channel":     channel,
+			"ts":          ts(),
+			"text":        str(p["text"]),
+			"thread_ts":   str(p["thread_ts"]),
+			"blocks":      p["blocks"],
+			"attachments": p["attachments"],
+		}
+	}
+	key := func(channel, ts string) string {
+		return channel + "/" + ts
+	}
+
+	e.handle("POST /api/auth.test", func(w http.ResponseWriter, r *http.Request) {
+		ok(w, Object{"team": "emulator", "user": "emulator"})
+	})
+
+	e.handle("POST /api/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		channel := str(p["channel"])
+		if channel == "" {
+			fail(w, "channel_not_found")
+			return
+		}
+		m := message(channel, p)
+		e.Put(slackKindMessages, key(channel, str(m["ts"])), m)
+		ok(w, Object{"channel": channel, "ts": m["ts"], "message": m})
+	})
+
+	e.handle("POST /api/chat.update", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		channel, ts := str(p["channel"]), str(p["ts"])
+		updated := e.Update(slackKindMessages, key(channel, ts), func(m Object) {
+			m["text"] = str(p["text"])
+			if p["blocks"] != nil {
+				m["blocks"] = p["blocks"]
+			}
+		})
+		if !updated {
+			fail(w, "message_not_found")
+			return
+		}
+		ok(w, Object{"channel": channel, "ts": ts, "text": p["text"]})
+	})
+
+	e.handle("POST /api/chat.delete", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		channel, ts := str(p["channel"]), str(p["ts"])
+		if !e.Delete(slackKindMessages, key(channel, ts)) {
+			fail(w, "message_not_found")
+			return
+		}
+		ok(w, Object{"channel": channel, "ts": ts})
+	})
+
+	e.handle("POST /api/chat.scheduleMessage", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		channel := str(p["channel"])
+		if channel == "" {
+			fail(w, "channel_not_found")
+			return
+		}
+		postAt := intParam(p, "post_at", 0)
+		if int64(postAt) <= time.Now().Unix() {
+			fail(w, "time_in_past")
+			return
+		}
+		id := fmt.Sprintf("Q%06d", e.NextID())
+		m := message(channel, p)
+		delete(m, "ts")
+		e.Put(slackKindScheduled, key(channel, id), Object{"id": id, "channel": channel, "post_at": postAt, "message": m})
+		ok(w, Object{"channel": channel, "scheduled_message_id": id, "post_at": postAt, "message": m})
+	})
+
+	e.handle("POST /api/chat.deleteScheduledMessage", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		if !e.Delete(slackKindScheduled, key(str(p["channel"]), str(p["scheduled_message_id"]))) {
+			fail(w, "invalid_scheduled_message_id")
+			return
+		}
+		ok(w, Object{})
+	})
+
+	// ephemeral messages are visible to user only, so they are not stored
+	e.handle("POST /api/chat.postEphemeral", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		if str(p["channel"]) == "" || str(p["user"]) == "" {
+			fail(w, "user_not_in_channel")
+			return
+		}
+		ok(w, Object{"message_ts": ts()})
+	})
+
+	e.handle("/api/chat.getPermalink", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		channel, ts := str(p["channel"]), str(p["message_ts"])
+		if _, found := e.Get(slackKindMessages, key(channel, ts)); !found {
+			fail(w, "message_not_found")
+			return
+		}
+		link := fmt.Sprintf("http://%s/archives/%s/p%s", r.Host, channel, strings.ReplaceAll(ts, ".", ""))
+		ok(w, Object{"channel": channel, "permalink": link})
+	})
+
+	e.handle("POST /api/files.upload", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		channels := str(p["channels"])
+		if channels == "" {
+			fail(w, "channel_not_found")
+			return
+		}
+		id := fmt.Sprintf("F%06d", e.NextID())
+		file := Object{"id": id, "name": p["file_filename"], "title": p["title"], "size": len(str(p["file"]))}
+		for _, channel := range strings.Split(channels, ",") {
+			m := message(channel, Object{"text": p["initial_comment"], "thread_ts": p["thread_ts"]})
+			m["files"] = []Object{file}
+			e.Put(slackKindMessages, key(channel, str(m["ts"])), m)
+		}
+		ok(w, Object{"file": file})
+	})
+
+	e.handle("POST /api/reactions.add", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		updated := e.Update(slackKindMessages, key(str(p["channel"]), str(p["timestamp"])), func(m Object) {
+			reactions, _ := m["reactions"].([]Object)
+			m["reactions"] = append(reactions, Object{"name": p["name"], "count": 1})
+		})
+		if !updated {
+			fail(w, "message_not_found")
+			return
+		}
+		ok(w, Object{})
+	})
+
+	e.handle("/api/users.lookupByEmail", func(w http.ResponseWriter, r *http.Request) {
+		email := str(params(r)["email"])
+		for _, u := range e.List("users") {
+			if strings.EqualFold(str(path(u, "profile.email")), email) {
+				ok(w, Object{"user": u})
+				return
+			}
+		}
+		fail(w, "users_not_found")
+	})
+
+	e.handle("POST /api/usergroups.users.update", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		id := str(p["usergroup"])
+		users := strings.Split(str(p["users"]), ",")
+		if list, ok := p["users"].([]interface{}); ok {
+			users = nil
+			for _, u := range list {
+				users = append(users, str(u))
+			}
+		}
+		group := Object{"id": id, "users": users, "user_count": len(users)}
+		e.Put("usergroups", id, group)
+		ok(w, Object{"usergroup": group})
+	})
+
+	e.handle("POST /api/conversations.list", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		channels := e.List(slackKindChannels)
+		offset := intParam(p, "cursor", 0)
+		limit := intParam(p, "limit", 100)
+		items := page(channels, offset, limit)
+		next := ""
+		if offset+len(items) < len(channels) {
+			next = fmt.Sprintf("%d", offset+len(items))
+		}
+		ok(w, Object{"channels": items, "response_metadata": Object{"next_cursor": next}})
+	})
+
+	e.handle("POST /api/conversations.history", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		channel := str(p["channel"])
+		messages := e.Find(slackKindMessages, func(m Object) bool {
+			return m["channel"] == channel
+		})
+		// newest first as Slack returns
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
+		limit := intParam(p, "limit", 100)
+		items := page(messages, 0, limit)
+		ok(w, Object{"messages": items, "has_more": len(items) < len(messages)})
+	})
+}
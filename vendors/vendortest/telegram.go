@@ -0,0 +1,75 @@
+package vendortest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const telegramKindMessages = "messages"
+
+// telegram token is path part, so bot{token} is checked by handler
+func telegram(e *Emulator) {
+
+	fail := func(w http.ResponseWriter, status int, description string) {
+		writeJson(w, status, Object{"ok": false, "error_code": status, "description": description})
+	}
+	send := func(kind string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.PathValue("bot"), "bot") || len(r.PathValue("bot")) < 4 {
+				fail(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+			p := params(r)
+			chat := str(p["chat_id"])
+			if chat == "" {
+				fail(w, http.StatusBadRequest, "Bad Request: chat_id is empty")
+				return
+			}
+			id := e.NextID()
+			m := Object{
+				"message_id": id,
+				"date":       time.Now().Unix(),
+				"chat":       Object{"id": chat},
+			}
+			switch kind {
+			case "photo":
+				m["photo"] = []Object{{"file_id": fmt.Sprintf("photo%d", id), "file_size": len(str(p["photo"]))}}
+				m["caption"] = p["caption"]
+			case "document":
+				m["document"] = Object{"file_id": fmt.Sprintf("document%d", id), "file_name": p["document_filename"], "file_size": len(str(p["document"]))}
+				m["caption"] = p["caption"]
+			default:
+				if str(p["text"]) == "" {
+					fail(w, http.StatusBadRequest, "Bad Request: message text is empty")
+					return
+				}
+				m["text"] = p["text"]
+			}
+			e.Put(telegramKindMessages, fmt.Sprintf("%s/%d", chat, id), m)
+			writeJson(w, http.StatusOK, Object{"ok": true, "result": m})
+		}
+	}
+
+	e.handle("GET /{bot}/getMe", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.PathValue("bot"), "bot") || len(r.PathValue("bot")) < 4 {
+			fail(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		writeJson(w, http.StatusOK, Object{"ok": true, "result": Object{"id": 1, "is_bot": true, "username": "emulator_bot"}})
+	})
+
+	e.handle("POST /{bot}/sendMessage", send("text"))
+	e.handle("POST /{bot}/sendPhoto", send("photo"))
+	e.handle("POST /{bot}/sendDocument", send("document"))
+
+	e.handle("POST /{bot}/deleteMessage", func(w http.ResponseWriter, r *http.Request) {
+		p := params(r)
+		if !e.Delete(telegramKindMessages, fmt.Sprintf("%s/%s", str(p["chat_id"]), str(p["message_id"]))) {
+			fail(w, http.StatusBadRequest, "Bad Request: message to delete not found")
+			return
+		}
+		writeJson(w, http.StatusOK, Object{"ok": true, "result": true})
+	})
+}
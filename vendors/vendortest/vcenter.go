@@ -0,0 +1,168 @@
+package vendortest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	vcenterKindSessions = "sessions"
+	vcenterKindClusters = "clusters"
+	vcenterKindHosts    = "hosts"
+	vcenterKindVMs      = "vms"
+	vcenterSessionID    = "vmware-api-session-id"
+)
+
+var vcenterPowerStates = map[string]string{
+	"start":    "POWERED_ON",
+	"stop":     "POWERED_OFF",
+	"suspend":  "SUSPENDED",
+	"reset":    "POWERED_ON",
+	"shutdown": "POWERED_OFF",
+	"reboot":   "POWERED_ON",
+	"standby":  "SUSPENDED",
+}
+
+// vcenter serves REST API with {"value": ...} and Automation API guest power
+func vcenter(e *Emulator) {
+
+	e.auth = func(r *http.Request) bool {
+		if r.URL.Path == "/rest/com/vmware/cis/session" || r.URL.Path == "/api/session" {
+			return headerPrefix(r, "Authorization", "Basic ")
+		}
+		_, ok := e.Get(vcenterKindSessions, r.Header.Get(vcenterSessionID))
+		return ok
+	}
+
+	fail := func(w http.ResponseWriter, status int, msg string) {
+		writeJson(w, status, Object{"type": "com.vmware.vapi.std.errors.not_found", "value": Object{"messages": []Object{{"default_message": msg}}}})
+	}
+	value := func(w http.ResponseWriter, v interface{}) {
+		writeJson(w, http.StatusOK, Object{"value": v})
+	}
+	in := func(values []string, v interface{}) bool {
+		return len(values) == 0 || strings.Contains(","+strings.Join(values, ",")+",", ","+str(v)+",")
+	}
+	session := func() string {
+		id := fmt.Sprintf("%032x", e.NextID())
+		e.Put(vcenterKindSessions, id, Object{"created": now()})
+		return id
+	}
+
+	e.handle("POST /rest/com/vmware/cis/session", func(w http.ResponseWriter, r *http.Request) {
+		value(w, session())
+	})
+
+	e.handle("POST /api/session", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusCreated, session())
+	})
+
+	e.handle("DELETE /rest/com/vmware/cis/session", func(w http.ResponseWriter, r *http.Request) {
+		e.Delete(vcenterKindSessions, r.Header.Get(vcenterSessionID))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	e.handle("GET /rest/vcenter/cluster", func(w http.ResponseWriter, r *http.Request) {
+		names := r.URL.Query()["filter.names"]
+		value(w, e.Find(vcenterKindClusters, func(obj Object) bool {
+			return in(names, obj["name"])
+		}))
+	})
+
+	e.handle("GET /rest/vcenter/host", func(w http.ResponseWriter, r *http.Request) {
+		clusters := r.URL.Query()["filter.clusters"]
+		value(w, e.Find(vcenterKindHosts, func(obj Object) bool {
+			return in(clusters, obj["cluster"])
+		}))
+	})
+
+	e.handle("GET /rest/vcenter/vm", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		clusters, hosts, names := q["filter.clusters"], removeEmpty(q["filter.hosts"]), q["filter.names"]
+		value(w, e.Find(vcenterKindVMs, func(obj Object) bool {
+			return in(clusters, obj["cluster"]) && in(hosts, obj["host"]) && in(names, obj["name"])
+		}))
+	})
+
+	e.handle("POST /rest/vcenter/vm", func(w http.ResponseWriter, r *http.Request) {
+		spec, _ := params(r)["spec"].(map[string]interface{})
+		if str(spec["name"]) == "" {
+			writeJson(w, http.StatusBadRequest, Object{"type": "com.vmware.vapi.std.errors.invalid_argument"})
+			return
+		}
+		id := fmt.Sprintf("vm-%d", e.NextID())
+		vm := Object{"vm": id, "name": spec["name"], "power_state": "POWERED_OFF", "cpu_count": 1, "memory_size_MiB": 1024}
+		if placement, ok := spec["placement"].(map[string]interface{}); ok {
+			vm["cluster"] = placement["cluster"]
+			vm["host"] = placement["host"]
+		}
+		e.Put(vcenterKindVMs, id, vm)
+		value(w, id)
+	})
+
+	e.handle("GET /rest/vcenter/vm/{vm}", func(w http.ResponseWriter, r *http.Request) {
+		vm, ok := e.Get(vcenterKindVMs, r.PathValue("vm"))
+		if !ok {
+			fail(w, http.StatusNotFound, "VM not found")
+			return
+		}
+		value(w, vm)
+	})
+
+	e.handle("DELETE /rest/vcenter/vm/{vm}", func(w http.ResponseWriter, r *http.Request) {
+		if !e.Delete(vcenterKindVMs, r.PathValue("vm")) {
+			fail(w, http.StatusNotFound, "VM not found")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	e.handle("GET /rest/vcenter/vm/{vm}/guest/identity", func(w http.ResponseWriter, r *http.Request) {
+		vm, ok := e.Get(vcenterKindVMs, r.PathValue("vm"))
+		if !ok {
+			fail(w, http.StatusNotFound, "VM not found")
+			return
+		}
+		if vm["power_state"] != "POWERED_ON" {
+			writeJson(w, http.StatusServiceUnavailable, Object{"type": "com.vmware.vapi.std.errors.service_unavailable"})
+			return
+		}
+		value(w, Object{"name": vm["name"], "host_name": vm["name"], "ip_address": vm["ip_address"], "family": "LINUX"})
+	})
+
+	e.handle("GET /rest/vcenter/vm/{vm}/power", func(w http.ResponseWriter, r *http.Request) {
+		vm, ok := e.Get(vcenterKindVMs, r.PathValue("vm"))
+		if !ok {
+			fail(w, http.StatusNotFound, "VM not found")
+			return
+		}
+		value(w, Object{"state": vm["power_state"]})
+	})
+
+	e.handle("POST /rest/vcenter/vm/{vm}/power/{action}", func(w http.ResponseWriter, r *http.Request) {
+		state, ok := vcenterPowerStates[r.PathValue("action")]
+		if !ok {
+			fail(w, http.StatusBadRequest, "unknown power action")
+			return
+		}
+		if !e.Update(vcenterKindVMs, r.PathValue("vm"), func(vm Object) { vm["power_state"] = state }) {
+			fail(w, http.StatusNotFound, "VM not found")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	e.handle("POST /api/vcenter/vm/{vm}/guest/power", func(w http.ResponseWriter, r *http.Request) {
+		state, ok := vcenterPowerStates[r.URL.Query().Get("action")]
+		if !ok {
+			fail(w, http.StatusBadRequest, "unknown guest power action")
+			return
+		}
+		if !e.Update(vcenterKindVMs, r.PathValue("vm"), func(vm Object) { vm["power_state"] = state }) {
+			fail(w, http.StatusNotFound, "VM not found")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,120 @@
+package vendortest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	zabbixKindHosts    = "hosts"
+	zabbixKindSessions = "sessions"
+	zabbixVersion      = "6.0.0"
+)
+
+// zabbix is JSON-RPC, errors are returned with 200 as Zabbix does
+func zabbix(e *Emulator) {
+
+	type request struct {
+		JsonRPC string          `json:"jsonrpc"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
+		Auth    string          `json:"auth"`
+		ID      interface{}     `json:"id"`
+	}
+
+	e.handle("POST /api_jsonrpc.php", func(w http.ResponseWriter, r *http.Request) {
+
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJson(w, http.StatusOK, Object{"jsonrpc": "2.0", "error": Object{"code": -32700, "message": "Parse error.", "data": err.Error()}, "id": nil})
+			return
+		}
+		// params is object for most methods and array of ids for delete
+		var params Object
+		json.Unmarshal(req.Params, &params)
+
+		result := func(v interface{}) {
+			writeJson(w, http.StatusOK, Object{"jsonrpc": "2.0", "result": v, "id": req.ID})
+		}
+		fail := func(code int, msg, data string) {
+			writeJson(w, http.StatusOK, Object{"jsonrpc": "2.0", "error": Object{"code": code, "message": msg, "data": data}, "id": req.ID})
+		}
+
+		switch req.Method {
+		case "apiinfo.version":
+			result(zabbixVersion)
+			return
+		case "user.login":
+			user := str(params["username"])
+			if user == "" {
+				user = str(params["user"])
+			}
+			if user == "" || str(params["password"]) == "" {
+				fail(-32602, "Invalid params.", "Incorrect user name or password or account is temporarily blocked.")
+				return
+			}
+			token := fmt.Sprintf("%032d", e.NextID())
+			e.Put(zabbixKindSessions, token, Object{"user": user})
+			result(token)
+			return
+		}
+
+		auth := req.Auth
+		if auth == "" {
+			auth = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if _, ok := e.Get(zabbixKindSessions, auth); !ok {
+			fail(-32602, "Invalid params.", "Session terminated, re-login, please.")
+			return
+		}
+
+		switch req.Method {
+		case "host.get":
+			hosts := e.Find(zabbixKindHosts, func(obj Object) bool {
+				filter, _ := params["filter"].(map[string]interface{})
+				for k, v := range filter {
+					if !strings.EqualFold(str(obj[k]), str(v)) {
+						return false
+					}
+				}
+				ids := toStrings(params["hostids"])
+				return len(ids) == 0 || strings.Contains(","+strings.Join(ids, ",")+",", ","+str(obj["hostid"])+",")
+			})
+			result(hosts)
+		case "host.create":
+			if str(params["host"]) == "" {
+				fail(-32602, "Invalid params.", `Invalid parameter "/1": the parameter "host" is missing.`)
+				return
+			}
+			id := strconv.Itoa(e.NextID())
+			host := Object{"hostid": id, "status": "0"}
+			for k, v := range params {
+				host[k] = v
+			}
+			if host["name"] == nil {
+				host["name"] = host["host"]
+			}
+			e.Put(zabbixKindHosts, id, host)
+			result(Object{"hostids": []string{id}})
+		case "host.delete":
+			var ids []string
+			var list []interface{}
+			json.Unmarshal(req.Params, &list)
+			for _, v := range list {
+				ids = append(ids, str(v))
+			}
+			for _, id := range ids {
+				if !e.Delete(zabbixKindHosts, id) {
+					fail(-32602, "Invalid params.", "No permissions to referred object or it does not exist!")
+					return
+				}
+			}
+			result(Object{"hostids": ids})
+		default:
+			fail(-32601, "Method not found.", fmt.Sprintf("Incorrect method %q.", req.Method))
+		}
+	})
+}
@@ -1,12 +1,12 @@
 package vendors
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"path"
 
 	"github.com/devopsext/tools/common"
-	"github.com/devopsext/utils"
 )
 
 const virustotalAPIURL = "https://www.virustotal.com/api/"
@@ -22,9 +22,10 @@ type VirusTotal struct {
 }
 
 type VirusTotalOptions struct {
-	APIKey   string
-	Timeout  int
-	Insecure bool
+	APIKey     string
+	Timeout    int
+	Insecure   bool
+	HTTPClient *http.Client
 }
 
 type VirusTotalDomainReportOptions struct {
@@ -61,7 +62,10 @@ func (v *VirusTotal) DomainReport(options VirusTotalDomainReportOptions) ([]byte
 	return v.CustomDomainReport(v.options, options)
 }
 
-func (v *VirusTotal) CustomDomainReport(virusTotalOptions VirusTotalOptions, virusTotalDomainReportOptions VirusTotalDomainReportOptions) ([]byte, error) {
+func (v *VirusTotal) CustomDomainReportContext(ctx context.Context, virusTotalOptions VirusTotalOptions, virusTotalDomainReportOptions VirusTotalDomainReportOptions) (b []byte, err error) {
+
+	v = v.withContext(ctx)
+	defer vendorError("virustotal", "domain-report", &b, &err)
 
 	u, err := url.Parse(virustotalAPIURL + virustotalAPIVersion + virustotalGetDomainReport)
 	if err != nil {
@@ -74,13 +78,49 @@ func (v *VirusTotal) CustomDomainReport(virusTotalOptions VirusTotalOptions, vir
 	headers["x-apikey"] = virusTotalOptions.APIKey
 	headers["accept"] = "application/json"
 
-	return utils.HttpGetRawWithHeaders(v.client, u.String(), headers)
+	return common.HttpGetRawWithHeaders(v.client, u.String(), headers)
+
+}
+
+func (v *VirusTotal) CustomDomainReport(virusTotalOptions VirusTotalOptions, virusTotalDomainReportOptions VirusTotalDomainReportOptions) ([]byte, error) {
+	return v.CustomDomainReportContext(context.Background(), virusTotalOptions, virusTotalDomainReportOptions)
+}
+
+func (v *VirusTotal) Name() string {
+	return "virustotal"
+}
+
+func (v *VirusTotal) Options() interface{} {
+	return v.options
+}
+
+func (v *VirusTotal) Check(ctx context.Context) error {
+
+	headers := make(map[string]string)
+	headers["x-apikey"] = v.options.APIKey
+	headers["accept"] = "application/json"
+	return checkVendorHttp(ctx, v.client, "virustotal", "GET", virustotalAPIURL+virustotalAPIVersion+"/users/"+v.options.APIKey, headers, nil)
+}
+
+func (v *VirusTotal) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("domain-report", "Get domain report", VendorOutputJSON, func(ctx context.Context, input VirusTotalDomainReportOptions) ([]byte, error) {
+			return v.CustomDomainReportContext(ctx, v.options, input)
+		}),
+	}
+}
+
+func (v *VirusTotal) withContext(ctx context.Context) *VirusTotal {
 
+	c := *v
+	c.client = common.HttpClientWithContext(ctx, v.client)
+	return &c
 }
 
 func NewVirusTotal(options VirusTotalOptions, logger common.Logger) *VirusTotal {
 	virustotal := &VirusTotal{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "virustotal", options.Timeout, options.Insecure),
 		options: options,
 	}
 	return virustotal
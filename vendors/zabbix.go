@@ -1,6 +1,7 @@
 package vendors
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/url"
@@ -10,19 +11,22 @@ import (
 	"github.com/devopsext/utils"
 )
 
+// ZabbixHostOptions has page options, but host.get has no offset, so hosts come in one response and limit caps them
 type ZabbixHostOptions struct {
 	Fields     []string
 	Inventory  []string
 	Interfaces []string
+	PageOptions
 }
 
 type ZabbixOptions struct {
-	Timeout  int
-	Insecure bool
-	URL      string
-	User     string
-	Password string
-	Auth     string
+	Timeout    int
+	Insecure   bool
+	URL        string
+	User       string
+	Password   string
+	Auth       string
+	HTTPClient *http.Client
 }
 
 type Zabbix struct {
@@ -50,6 +54,7 @@ type ZabbixHostGetParams struct {
 	Output           []string `json:"output"`
 	SelectInventory  []string `json:"selectInventory"`
 	SelectInterfaces []string `json:"selectInterfaces"`
+	Limit            int      `json:"limit,omitempty"`
 }
 
 type ZabbixHostGet struct {
@@ -90,7 +95,7 @@ func (o *Zabbix) getZabbixAuth(opts ZabbixOptions) (*ZabbixUserLoginResponse, er
 		return nil, err
 	}
 
-	res, err := utils.HttpPostRaw(o.client, u.String(), zabbixContentType, "", req)
+	res, err := common.HttpPostRaw(o.client, u.String(), zabbixContentType, "", req)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +108,10 @@ func (o *Zabbix) getZabbixAuth(opts ZabbixOptions) (*ZabbixUserLoginResponse, er
 	return &zr, nil
 }
 
-func (o *Zabbix) CustomGetHosts(options ZabbixOptions, hostOptions ZabbixHostOptions) ([]byte, error) {
+func (o *Zabbix) CustomGetHostsContext(ctx context.Context, options ZabbixOptions, hostOptions ZabbixHostOptions) (b []byte, err error) {
+
+	o = o.withContext(ctx)
+	defer vendorError("zabbix", "get-hosts", &b, &err)
 
 	auth := options.Auth
 	if utils.IsEmpty(auth) {
@@ -131,6 +139,7 @@ func (o *Zabbix) CustomGetHosts(options ZabbixOptions, hostOptions ZabbixHostOpt
 			Output:           common.RemoveEmptyStrings(hostOptions.Fields),
 			SelectInventory:  common.RemoveEmptyStrings(hostOptions.Inventory),
 			SelectInterfaces: common.RemoveEmptyStrings(hostOptions.Interfaces),
+			Limit:            hostOptions.Limit,
 		},
 	}
 
@@ -139,17 +148,56 @@ func (o *Zabbix) CustomGetHosts(options ZabbixOptions, hostOptions ZabbixHostOpt
 		return nil, err
 	}
 
-	return utils.HttpPostRaw(o.client, u.String(), zabbixContentType, "", req)
+	return common.HttpPostRaw(o.client, u.String(), zabbixContentType, "", req)
+}
+
+func (o *Zabbix) CustomGetHosts(options ZabbixOptions, hostOptions ZabbixHostOptions) ([]byte, error) {
+	return o.CustomGetHostsContext(context.Background(), options, hostOptions)
 }
 
 func (o *Zabbix) GetHosts(options ZabbixHostOptions) ([]byte, error) {
 	return o.CustomGetHosts(o.options, options)
 }
 
+func (o *Zabbix) Name() string {
+	return "zabbix"
+}
+
+func (o *Zabbix) Options() interface{} {
+	return o.options
+}
+
+// Check logs in if user is set, otherwise hosts are requested with auth of options
+func (o *Zabbix) Check(ctx context.Context) error {
+
+	if !utils.IsEmpty(o.options.User) {
+		_, err := o.withContext(ctx).getZabbixAuth(o.options)
+		return err
+	}
+	_, err := o.CustomGetHostsContext(ctx, o.options, ZabbixHostOptions{})
+	return err
+}
+
+func (o *Zabbix) Operations() []VendorOperation {
+
+	return []VendorOperation{
+		NewVendorOperation("get-hosts", "Get hosts", VendorOutputJSON, func(ctx context.Context, input ZabbixHostOptions) ([]byte, error) {
+			return o.CustomGetHostsContext(ctx, o.options, input)
+		}),
+	}
+}
+
+func (o *Zabbix) withContext(ctx context.Context) *Zabbix {
+
+	c := *o
+	c.client = common.HttpClientWithContext(ctx, o.client)
+	return &c
+}
+
 func NewZabbix(options ZabbixOptions) *Zabbix {
 
 	return &Zabbix{
-		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		client:  common.VendorHttpClient(options.HTTPClient, "zabbix", options.Timeout, options.Insecure),
 		options: options,
 	}
 }